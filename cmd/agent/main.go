@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"syscall"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/distributed"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/engine"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/metrics"
+	"go.uber.org/zap"
+)
+
+func main() {
+	hostname, _ := os.Hostname()
+
+	controllerURL := flag.String("controller", getEnv("VOLCANION_CONTROLLER", "http://localhost:8080"), "controller base URL (env VOLCANION_CONTROLLER)")
+	name := flag.String("name", getEnv("VOLCANION_AGENT_NAME", fmt.Sprintf("%s-%d", hostname, os.Getpid())), "agent name (env VOLCANION_AGENT_NAME)")
+	apiKey := flag.String("api-key", os.Getenv("VOLCANION_API_KEY"), "admin API key when the controller requires auth (env VOLCANION_API_KEY)")
+	capacity := flag.Int("capacity", getEnvAsInt("VOLCANION_AGENT_CAPACITY", runtime.NumCPU()), "relative share of load this agent takes (env VOLCANION_AGENT_CAPACITY)")
+	logLevel := flag.String("log-level", getEnv("LOG_LEVEL", "info"), "log level (env LOG_LEVEL)")
+	flag.Parse()
+
+	if err := logger.Init(*logLevel); err != nil {
+		fmt.Printf("Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	logger.Log.Info("Starting Volcanion load agent",
+		zap.String("name", *name),
+		zap.String("controller", *controllerURL),
+		zap.Int("capacity", *capacity))
+
+	generator := engine.NewLoadGenerator(metrics.NewCollector())
+	agent := distributed.NewAgent(distributed.AgentConfig{
+		ControllerURL: *controllerURL,
+		Name:          *name,
+		APIKey:        *apiKey,
+		Capacity:      *capacity,
+	}, generator)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := agent.Run(ctx); err != nil && ctx.Err() == nil {
+		logger.Log.Error("Agent stopped", zap.Error(err))
+		os.Exit(1)
+	}
+
+	logger.Log.Info("Agent exited")
+}
+
+// getEnv returns an environment variable or a default value
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsInt returns an environment variable as an integer or a default value
+func getEnvAsInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
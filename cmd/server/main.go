@@ -16,6 +16,7 @@ import (
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/audit"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/auth"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/config"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/distributed"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/service"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/engine"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
@@ -77,6 +78,10 @@ func main() {
 	var testPlanRepo repository.TestPlanRepository
 	var testRunRepo repository.TestRunRepository
 	var metricsRepo repository.MetricsRepository
+	var dataSetRepo repository.DataSetRepository
+	var secretRepo repository.SecretRepository
+	var bodyFileRepo repository.BodyFileRepository
+	var protoFileRepo repository.ProtoFileRepository
 
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	scenarioExecutionRepo := repository.NewMemoryScenarioExecutionRepository()
@@ -85,11 +90,19 @@ func main() {
 		testPlanRepo = postgres.NewPostgresTestPlanRepository(db)
 		testRunRepo = postgres.NewPostgresTestRunRepository(db)
 		metricsRepo = postgres.NewPostgresMetricsRepository(db)
+		dataSetRepo = postgres.NewPostgresDataSetRepository(db)
+		secretRepo = postgres.NewPostgresSecretRepository(db)
+		bodyFileRepo = postgres.NewPostgresBodyFileRepository(db)
+		protoFileRepo = postgres.NewPostgresProtoFileRepository(db)
 		logger.Log.Info("Using PostgreSQL repositories")
 	} else {
 		testPlanRepo = repository.NewMemoryTestPlanRepository()
 		testRunRepo = repository.NewMemoryTestRunRepository()
 		metricsRepo = repository.NewMemoryMetricsRepository()
+		dataSetRepo = repository.NewMemoryDataSetRepository()
+		secretRepo = repository.NewMemorySecretRepository()
+		bodyFileRepo = repository.NewMemoryBodyFileRepository()
+		protoFileRepo = repository.NewMemoryProtoFileRepository()
 		logger.Log.Info("Using in-memory repositories")
 	}
 
@@ -102,17 +115,38 @@ func main() {
 	logger.Log.Info("Load generator initialized")
 
 	// Initialize service
-	testService := service.NewTestService(testPlanRepo, testRunRepo, metricsRepo, loadGenerator, cfg)
+	testService := service.NewTestService(
+		testPlanRepo,
+		testRunRepo,
+		metricsRepo,
+		scenarioRepo,
+		dataSetRepo,
+		secretRepo,
+		bodyFileRepo,
+		protoFileRepo,
+		loadGenerator,
+		cfg,
+	)
 	logger.Log.Info("Test service initialized")
 
+	// Initialize the controller that splits distributed runs across agents
+	agentController := distributed.NewController()
+	testService.SetController(agentController)
+	logger.Log.Info("Distributed controller initialized")
+
 	// Create individual services for report handler
 	testPlanService := service.NewTestPlanService(testPlanRepo)
 	testRunService := service.NewTestRunService(testRunRepo)
 	metricsService := service.NewMetricsService(metricsRepo)
 
-	scenarioService := service.NewScenarioService(scenarioRepo, scenarioExecutionRepo, scenarioExecutor)
+	scenarioService := service.NewScenarioService(scenarioRepo, scenarioExecutionRepo, bodyFileRepo, protoFileRepo, scenarioExecutor)
 	logger.Log.Info("Scenario service initialized")
 
+	dataSetService := service.NewDataSetService(dataSetRepo)
+	secretService := service.NewSecretService(secretRepo)
+	bodyFileService := service.NewBodyFileService(bodyFileRepo)
+	protoFileService := service.NewProtoFileService(protoFileRepo)
+
 	// Initialize auth services
 	jwtService := auth.NewJWTService(cfg.JWTSecret, time.Duration(cfg.JWTDuration)*time.Hour)
 	apiKeyService := auth.NewAPIKeyService()
@@ -130,8 +164,13 @@ func main() {
 	testPlanHandler := handler.NewTestPlanHandler(testService)
 	testRunHandler := handler.NewTestRunHandler(testService)
 	scenarioHandler := handler.NewScenarioHandler(scenarioService)
+	dataSetHandler := handler.NewDataSetHandler(dataSetService)
+	secretHandler := handler.NewSecretHandler(secretService)
+	bodyFileHandler := handler.NewBodyFileHandler(bodyFileService)
+	protoFileHandler := handler.NewProtoFileHandler(protoFileService)
 	authHandler := handler.NewAuthHandler(jwtService, apiKeyService)
 	auditHandler := handler.NewAuditHandler(auditLogger)
+	agentHandler := handler.NewAgentHandler(agentController)
 	reportHandler := handler.NewReportHandler(
 		testRunService,
 		testPlanService,
@@ -166,10 +205,15 @@ func main() {
 		TestPlanHandler:     testPlanHandler,
 		TestRunHandler:      testRunHandler,
 		ScenarioHandler:     scenarioHandler,
+		DataSetHandler:      dataSetHandler,
+		SecretHandler:       secretHandler,
+		BodyFileHandler:     bodyFileHandler,
+		ProtoFileHandler:    protoFileHandler,
 		ReportHandler:       reportHandler,
 		WebSocketHandler:    websocketHandler,
 		AuthHandler:         authHandler,
 		AuditHandler:        auditHandler,
+		AgentHandler:        agentHandler,
 		JWTService:          jwtService,
 		APIKeyService:       apiKeyService,
 		AuditMiddleware:     auditMiddleware,
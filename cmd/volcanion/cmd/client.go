@@ -184,3 +184,40 @@ func (c *APIClient) GetLiveMetrics(runID string) (map[string]interface{}, error)
 	}
 	return metrics, nil
 }
+
+// ControlTest sends a live control command to a running test and returns the
+// recorded event
+func (c *APIClient) ControlTest(runID, action string, value int) (map[string]interface{}, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"action": action,
+		"value":  value,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, c.baseURL+"/api/test-runs/"+runID+"/control", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("API error: status %d (failed to read body): %w", resp.StatusCode, err)
+		}
+		return nil, fmt.Errorf("API error: %s", string(body))
+	}
+
+	var event map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// controlActions maps CLI action names to API control actions, and whether
+// each takes a value
+var controlActions = map[string]struct {
+	action   string
+	hasValue bool
+}{
+	"pause":      {"pause", false},
+	"resume":     {"resume", false},
+	"set-rps":    {"set_rps", true},
+	"add-vus":    {"add_vus", true},
+	"remove-vus": {"remove_vus", true},
+	"extend":     {"extend_duration", true},
+	"shorten":    {"extend_duration", true},
+}
+
+var controlCmd = &cobra.Command{
+	Use:   "control <run-id> <action> [value]",
+	Short: "Pause, resume or retarget a running test",
+	Long: `Adjust the load of a running test on the fly. Every adjustment is
+recorded as a timestamped event on the run.
+
+Actions:
+  pause              stop starting new iterations
+  resume             start iterations again after a pause
+  set-rps <rps>      switch to a fixed target rate
+  add-vus <n>        add n virtual users
+  remove-vus <n>     remove n virtual users
+  extend <seconds>   run the test for longer
+  shorten <seconds>  end the test sooner
+
+Examples:
+  # Pause and resume a test
+  volcanion control 9b1d... pause
+  volcanion control 9b1d... resume
+
+  # Double the load
+  volcanion control 9b1d... set-rps 200
+  volcanion control 9b1d... add-vus 20
+
+  # Run for 5 more minutes
+  volcanion control 9b1d... extend 300`,
+	Args: cobra.RangeArgs(2, 3),
+	RunE: controlTest,
+}
+
+func init() {
+	rootCmd.AddCommand(controlCmd)
+}
+
+func controlTest(_ *cobra.Command, args []string) error {
+	runID, name := args[0], args[1]
+
+	spec, ok := controlActions[name]
+	if !ok {
+		return fmt.Errorf("unknown action: %s (use pause, resume, set-rps, add-vus, remove-vus, extend or shorten)", name)
+	}
+
+	value := 0
+	if spec.hasValue {
+		if len(args) != 3 {
+			return fmt.Errorf("%s requires a value", name)
+		}
+		n, err := strconv.Atoi(args[2])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid value for %s: %s (must be a positive integer)", name, args[2])
+		}
+		value = n
+		if name == "shorten" {
+			value = -n
+		}
+	} else if len(args) == 3 {
+		return fmt.Errorf("%s does not take a value", name)
+	}
+
+	client := NewAPIClient(GetAPIBaseURL())
+	if _, err := client.ControlTest(runID, spec.action, value); err != nil {
+		return fmt.Errorf("failed to control test: %w", err)
+	}
+
+	if spec.hasValue {
+		printSuccess(fmt.Sprintf("Applied %s %s to test run %s", name, args[2], runID))
+	} else {
+		printSuccess(fmt.Sprintf("Applied %s to test run %s", name, runID))
+	}
+	return nil
+}
@@ -95,6 +95,12 @@ func listPlans(client *APIClient) error {
 		id := plan["id"].(string)
 		name := plan["name"].(string)
 		targetURL := plan["target_url"].(string)
+		if scenarioID, ok := plan["scenario_id"].(string); ok && scenarioID != "" {
+			targetURL = "scenario:" + scenarioID
+		}
+		if requests, ok := plan["requests"].([]interface{}); ok && len(requests) > 0 {
+			targetURL = fmt.Sprintf("mix:%d requests", len(requests))
+		}
 		vus := int(plan["concurrent_users"].(float64))
 		duration := int(plan["duration_sec"].(float64))
 		createdAt := plan["created_at"].(string)
@@ -71,7 +71,7 @@ func watchTest(client *APIClient, runID string) error {
 		}
 
 		// Print live stats
-		clearLines(6)
+		clearLines(10)
 		printLiveStats(metrics)
 
 		// Check if completed
@@ -112,6 +112,28 @@ func printLiveStats(metrics map[string]interface{}) {
 	if p99, ok := metrics["p99_latency_ms"]; ok {
 		fmt.Printf("  P99 Latency:  %s\n", color.YellowString("%.2f ms", p99.(float64)))
 	}
+
+	printWindowStats(metrics)
+}
+
+// printWindowStats prints the rolling 1s/10s/60s windows of a live run
+func printWindowStats(metrics map[string]interface{}) {
+	windows, _ := metrics["windows"].(map[string]interface{})
+
+	fmt.Printf("  %-8s %10s %8s %10s %10s %10s\n", "Window", "RPS", "Errors", "P50", "P95", "P99")
+	for _, name := range []string{"1s", "10s", "60s"} {
+		window, ok := windows[name].(map[string]interface{})
+		if !ok {
+			fmt.Printf("  %-8s %10s %8s %10s %10s %10s\n", name, "-", "-", "-", "-", "-")
+			continue
+		}
+		fmt.Printf("  %-8s %s %s %s %s %s\n", name,
+			color.MagentaString("%10.1f", window["rps"].(float64)),
+			color.RedString("%7.2f%%", window["error_rate"].(float64)),
+			color.YellowString("%8.2fms", window["p50_latency_ms"].(float64)),
+			color.YellowString("%8.2fms", window["p95_latency_ms"].(float64)),
+			color.YellowString("%8.2fms", window["p99_latency_ms"].(float64)))
+	}
 }
 
 func clearLines(n int) {
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/distributed"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"go.uber.org/zap"
+)
+
+// AgentHandler handles the endpoints distributed load agents talk to
+type AgentHandler struct {
+	controller *distributed.Controller
+}
+
+// NewAgentHandler creates a new agent handler
+func NewAgentHandler(controller *distributed.Controller) *AgentHandler {
+	return &AgentHandler{
+		controller: controller,
+	}
+}
+
+// RegisterAgent handles POST /api/agents/register
+func (h *AgentHandler) RegisterAgent(c *gin.Context) {
+	var req model.RegisterAgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Log.Warn("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, h.controller.Register(&req))
+}
+
+// Heartbeat handles POST /api/agents/:id/heartbeat
+// Records the agent's run reports and returns the work queued for it
+func (h *AgentHandler) Heartbeat(c *gin.Context) {
+	id := c.Param("id")
+
+	var hb model.AgentHeartbeat
+	if err := c.ShouldBindJSON(&hb); err != nil {
+		logger.Log.Warn("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp, err := h.controller.Heartbeat(id, &hb)
+	if err != nil {
+		MapErrorToHTTP(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetAgents handles GET /api/agents
+func (h *AgentHandler) GetAgents(c *gin.Context) {
+	c.JSON(http.StatusOK, h.controller.Agents())
+}
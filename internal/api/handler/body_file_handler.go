@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/service"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"go.uber.org/zap"
+)
+
+// maxBodyFileUploadBytes limits the size of an uploaded body file
+const maxBodyFileUploadBytes = 64 << 20
+
+// BodyFileHandler handles HTTP requests for body file operations
+type BodyFileHandler struct {
+	service *service.BodyFileService
+}
+
+// NewBodyFileHandler creates a new body file handler
+func NewBodyFileHandler(service *service.BodyFileService) *BodyFileHandler {
+	return &BodyFileHandler{service: service}
+}
+
+// CreateBodyFile handles POST /api/body-files
+// Expects a multipart form with a "file" part and optional "name" and
+// "content_type" fields. The name defaults to the file name and the content
+// type to the one the file part was sent with.
+func (h *BodyFileHandler) CreateBodyFile(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyFileUploadBytes)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required: " + err.Error()})
+		return
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		name = fileHeader.Filename
+	}
+	contentType := c.PostForm("content_type")
+	if contentType == "" {
+		contentType = fileHeader.Header.Get("Content-Type")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	bodyFile, err := h.service.CreateBodyFile(name, contentType, file)
+	if err != nil {
+		logger.Log.Warn("Failed to create body file", zap.Error(err))
+		MapErrorToHTTP(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, bodyFile)
+}
+
+// GetBodyFile handles GET /api/body-files/:id
+func (h *BodyFileHandler) GetBodyFile(c *gin.Context) {
+	id := c.Param("id")
+
+	bodyFile, err := h.service.GetBodyFile(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "body file not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, bodyFile)
+}
+
+// GetAllBodyFiles handles GET /api/body-files
+func (h *BodyFileHandler) GetAllBodyFiles(c *gin.Context) {
+	bodyFiles, err := h.service.GetAllBodyFiles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"body_files": bodyFiles,
+		"count":      len(bodyFiles),
+	})
+}
+
+// DeleteBodyFile handles DELETE /api/body-files/:id
+func (h *BodyFileHandler) DeleteBodyFile(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.DeleteBodyFile(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "body file not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "body file deleted successfully"})
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/service"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"go.uber.org/zap"
+)
+
+// maxDataSetUploadBytes limits the size of an uploaded data set file
+const maxDataSetUploadBytes = 64 << 20
+
+// DataSetHandler handles HTTP requests for data set operations
+type DataSetHandler struct {
+	service *service.DataSetService
+}
+
+// NewDataSetHandler creates a new data set handler
+func NewDataSetHandler(service *service.DataSetService) *DataSetHandler {
+	return &DataSetHandler{service: service}
+}
+
+// CreateDataSet handles POST /api/data-sets
+// Expects a multipart form with a "file" part and optional "name" and
+// "format" fields. The format defaults to the file extension (.csv or .jsonl).
+func (h *DataSetHandler) CreateDataSet(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDataSetUploadBytes)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required: " + err.Error()})
+		return
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		name = strings.TrimSuffix(fileHeader.Filename, filepath.Ext(fileHeader.Filename))
+	}
+	format := model.DataSetFormat(strings.ToLower(c.PostForm("format")))
+	if format == "" {
+		format = model.DataSetFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), "."))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	dataSet, err := h.service.CreateDataSet(name, format, file)
+	if err != nil {
+		logger.Log.Warn("Failed to create data set", zap.Error(err))
+		MapErrorToHTTP(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dataSet)
+}
+
+// GetDataSet handles GET /api/data-sets/:id
+func (h *DataSetHandler) GetDataSet(c *gin.Context) {
+	id := c.Param("id")
+
+	dataSet, err := h.service.GetDataSet(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "data set not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dataSet)
+}
+
+// GetAllDataSets handles GET /api/data-sets
+func (h *DataSetHandler) GetAllDataSets(c *gin.Context) {
+	dataSets, err := h.service.GetAllDataSets()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data_sets": dataSets,
+		"count":     len(dataSets),
+	})
+}
+
+// DeleteDataSet handles DELETE /api/data-sets/:id
+func (h *DataSetHandler) DeleteDataSet(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.DeleteDataSet(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "data set not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "data set deleted successfully"})
+}
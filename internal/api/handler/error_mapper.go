@@ -45,6 +45,12 @@ func MapErrorToHTTP(c *gin.Context, err error) {
 			Message: "Test is already running",
 		})
 
+	case errors.Is(err, domain.ErrNotRunning):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "conflict",
+			Message: "Test is not running",
+		})
+
 	case errors.Is(err, domain.ErrAlreadyExists):
 		c.JSON(http.StatusConflict, ErrorResponse{
 			Error:   "conflict",
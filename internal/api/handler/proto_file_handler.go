@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/service"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"go.uber.org/zap"
+)
+
+// maxProtoFileUploadBytes limits the size of an uploaded proto file
+const maxProtoFileUploadBytes = 4 << 20
+
+// ProtoFileHandler handles HTTP requests for proto file operations
+type ProtoFileHandler struct {
+	service *service.ProtoFileService
+}
+
+// NewProtoFileHandler creates a new proto file handler
+func NewProtoFileHandler(service *service.ProtoFileService) *ProtoFileHandler {
+	return &ProtoFileHandler{service: service}
+}
+
+// CreateProtoFile handles POST /api/proto-files
+// Expects a multipart form with a "file" part and an optional "name" field,
+// the path other proto files import it by. The name defaults to the file name.
+func (h *ProtoFileHandler) CreateProtoFile(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxProtoFileUploadBytes)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required: " + err.Error()})
+		return
+	}
+
+	name := c.PostForm("name")
+	if name == "" {
+		name = fileHeader.Filename
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	protoFile, err := h.service.CreateProtoFile(name, file)
+	if err != nil {
+		logger.Log.Warn("Failed to create proto file", zap.Error(err))
+		MapErrorToHTTP(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, protoFile)
+}
+
+// GetProtoFile handles GET /api/proto-files/:id
+func (h *ProtoFileHandler) GetProtoFile(c *gin.Context) {
+	id := c.Param("id")
+
+	protoFile, err := h.service.GetProtoFile(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "proto file not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, protoFile)
+}
+
+// GetAllProtoFiles handles GET /api/proto-files
+func (h *ProtoFileHandler) GetAllProtoFiles(c *gin.Context) {
+	protoFiles, err := h.service.GetAllProtoFiles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"proto_files": protoFiles,
+		"count":       len(protoFiles),
+	})
+}
+
+// DeleteProtoFile handles DELETE /api/proto-files/:id
+func (h *ProtoFileHandler) DeleteProtoFile(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.DeleteProtoFile(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "proto file not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "proto file deleted successfully"})
+}
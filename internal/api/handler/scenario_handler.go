@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/service"
 )
@@ -26,9 +28,37 @@ func (h *ScenarioHandler) CreateScenario(c *gin.Context) {
 		return
 	}
 
+	validator := domain.NewValidator()
+	if err := validator.ValidateDataFeed(req.Data); err != nil {
+		MapErrorToHTTP(c, err)
+		return
+	}
+	for i := range req.Steps {
+		if err := validator.ValidateRetry(fmt.Sprintf("steps[%d].retry", i), req.Steps[i].Retry); err != nil {
+			MapErrorToHTTP(c, err)
+			return
+		}
+		if err := validator.ValidateBodySource(fmt.Sprintf("steps[%d].body_source", i), req.Steps[i].BodySource, req.Steps[i].Body); err != nil {
+			MapErrorToHTTP(c, err)
+			return
+		}
+		if err := validator.ValidateGRPCStep(fmt.Sprintf("steps[%d]", i), &req.Steps[i]); err != nil {
+			MapErrorToHTTP(c, err)
+			return
+		}
+		if err := validator.ValidateWebSocketStep(fmt.Sprintf("steps[%d]", i), &req.Steps[i]); err != nil {
+			MapErrorToHTTP(c, err)
+			return
+		}
+		if err := validator.ValidateSQLStep(fmt.Sprintf("steps[%d]", i), &req.Steps[i]); err != nil {
+			MapErrorToHTTP(c, err)
+			return
+		}
+	}
+
 	scenario, err := h.service.CreateScenario(&req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		MapErrorToHTTP(c, err)
 		return
 	}
 
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/service"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"go.uber.org/zap"
+)
+
+// SecretHandler handles HTTP requests for secret operations
+type SecretHandler struct {
+	service *service.SecretService
+}
+
+// NewSecretHandler creates a new secret handler
+func NewSecretHandler(service *service.SecretService) *SecretHandler {
+	return &SecretHandler{service: service}
+}
+
+// CreateSecret handles POST /api/v1/secrets
+// The response describes the stored secret but never echoes its PEM contents.
+func (h *SecretHandler) CreateSecret(c *gin.Context) {
+	var req model.CreateSecretRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	secret, err := h.service.CreateSecret(&req)
+	if err != nil {
+		logger.Log.Warn("Failed to create secret", zap.Error(err))
+		MapErrorToHTTP(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, secret)
+}
+
+// GetSecret handles GET /api/v1/secrets/:id
+func (h *SecretHandler) GetSecret(c *gin.Context) {
+	id := c.Param("id")
+
+	secret, err := h.service.GetSecret(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "secret not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, secret)
+}
+
+// GetAllSecrets handles GET /api/v1/secrets
+func (h *SecretHandler) GetAllSecrets(c *gin.Context) {
+	secrets, err := h.service.GetAllSecrets()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secrets": secrets,
+		"count":   len(secrets),
+	})
+}
+
+// DeleteSecret handles DELETE /api/v1/secrets/:id
+func (h *SecretHandler) DeleteSecret(c *gin.Context) {
+	id := c.Param("id")
+
+	if err := h.service.DeleteSecret(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "secret not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "secret deleted successfully"})
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/service"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
@@ -12,13 +15,15 @@ import (
 
 // TestRunHandler handles test run related endpoints
 type TestRunHandler struct {
-	service *service.TestService
+	service   *service.TestService
+	validator *domain.Validator
 }
 
 // NewTestRunHandler creates a new test run handler
 func NewTestRunHandler(service *service.TestService) *TestRunHandler {
 	return &TestRunHandler{
-		service: service,
+		service:   service,
+		validator: domain.NewValidator(),
 	}
 }
 
@@ -31,7 +36,13 @@ func (h *TestRunHandler) StartTest(c *gin.Context) {
 		return
 	}
 
-	run, err := h.service.StartTest(req.PlanID)
+	var run *model.TestRun
+	var err error
+	if req.Distributed {
+		run, err = h.service.StartDistributedTest(req.PlanID, req.Agents)
+	} else {
+		run, err = h.service.StartTest(req.PlanID)
+	}
 	if err != nil {
 		logger.Log.Error("Failed to start test", zap.Error(err))
 		MapErrorToHTTP(c, err)
@@ -54,6 +65,33 @@ func (h *TestRunHandler) StopTest(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "test stopped successfully"})
 }
 
+// ControlTest handles POST /api/test-runs/:id/control
+// Pauses, resumes or retargets the load of a running test
+func (h *TestRunHandler) ControlTest(c *gin.Context) {
+	id := c.Param("id")
+
+	var cmd model.ControlCommand
+	if err := c.ShouldBindJSON(&cmd); err != nil {
+		logger.Log.Warn("Invalid request body", zap.Error(err))
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.validator.ValidateControlCommand(&cmd); err != nil {
+		MapErrorToHTTP(c, err)
+		return
+	}
+
+	event, err := h.service.ControlTest(id, cmd)
+	if err != nil {
+		logger.Log.Warn("Failed to control test", zap.String("id", id), zap.Error(err))
+		MapErrorToHTTP(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
 // GetTestRuns handles GET /api/test-runs
 func (h *TestRunHandler) GetTestRuns(c *gin.Context) {
 	runs, err := h.service.GetAllTestRuns()
@@ -107,3 +145,29 @@ func (h *TestRunHandler) GetLiveMetrics(c *gin.Context) {
 
 	c.JSON(http.StatusOK, metrics)
 }
+
+// GetPercentiles handles GET /api/test-runs/:id/percentiles?p=99.9&p=99.99
+func (h *TestRunHandler) GetPercentiles(c *gin.Context) {
+	id := c.Param("id")
+
+	var percentiles []float64
+	for _, param := range c.QueryArray("p") {
+		for _, raw := range strings.Split(param, ",") {
+			p, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid percentile: " + raw})
+				return
+			}
+			percentiles = append(percentiles, p)
+		}
+	}
+
+	report, err := h.service.GetPercentiles(id, percentiles)
+	if err != nil {
+		logger.Log.Warn("Failed to get percentiles", zap.String("id", id), zap.Error(err))
+		MapErrorToHTTP(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
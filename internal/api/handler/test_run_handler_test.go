@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/distributed"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+func TestControlTestHandler(t *testing.T) {
+	handler := NewTestRunHandler(setupTestService())
+
+	router := gin.New()
+	router.POST("/api/test-runs/:id/control", handler.ControlTest)
+
+	cases := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"invalid json", `not json`, http.StatusBadRequest},
+		{"missing action", `{"value": 10}`, http.StatusBadRequest},
+		{"unknown action", `{"action": "restart"}`, http.StatusBadRequest},
+		{"negative set_rps", `{"action": "set_rps", "value": -5}`, http.StatusBadRequest},
+		{"negative add_vus", `{"action": "add_vus", "value": -2}`, http.StatusBadRequest},
+		{"zero extend_duration", `{"action": "extend_duration"}`, http.StatusBadRequest},
+		{"unknown run", `{"action": "pause"}`, http.StatusNotFound},
+		{"unknown run shorten", `{"action": "extend_duration", "value": -30}`, http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/test-runs/missing/control", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			if w.Code != tc.code {
+				t.Errorf("Expected status %d, got %d. Body: %s", tc.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestStartDistributedTestHandler(t *testing.T) {
+	svc := setupTestService()
+	plan, err := svc.CreateTestPlan(&model.CreateTestPlanRequest{
+		Name:        "Distributed",
+		TargetURL:   "http://localhost:8080/api/test",
+		Method:      "GET",
+		Users:       4,
+		DurationSec: 10,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create plan: %v", err)
+	}
+
+	handler := NewTestRunHandler(svc)
+	router := gin.New()
+	router.POST("/api/test-runs/start", handler.StartTest)
+
+	start := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/test-runs/start", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Distributed runs are unavailable until a controller is set
+	if w := start(`{"plan_id": "` + plan.ID + `", "distributed": true}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a controller, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	svc.SetController(distributed.NewController())
+
+	cases := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"negative agents", `{"plan_id": "` + plan.ID + `", "distributed": true, "agents": -1}`, http.StatusBadRequest},
+		{"unknown plan", `{"plan_id": "missing", "distributed": true}`, http.StatusNotFound},
+		{"no agents registered", `{"plan_id": "` + plan.ID + `", "distributed": true}`, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := start(tc.body); w.Code != tc.code {
+				t.Errorf("Expected status %d, got %d. Body: %s", tc.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
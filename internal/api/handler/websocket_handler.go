@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/config"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/service"
 	"go.uber.org/zap"
 )
@@ -59,7 +62,9 @@ func NewWebSocketHandlerPermissive(testService *service.TestService, logger *zap
 	}
 }
 
-// LiveTestMetrics streams live metrics for a test run via WebSocket
+// LiveTestMetrics streams live metrics for a test run via WebSocket.
+// Clients can send control commands ({"action": "pause"}) over the same
+// connection; each is answered with the recorded event or an error.
 func (h *WebSocketHandler) LiveTestMetrics(c *gin.Context) {
 	runID := c.Param("id")
 
@@ -80,13 +85,23 @@ func (h *WebSocketHandler) LiveTestMetrics(c *gin.Context) {
 	// Channel to handle client disconnection
 	done := make(chan struct{})
 
-	// Read from client (to detect disconnection)
+	// Replies to control commands, written by the loop below since the
+	// connection supports only one concurrent writer
+	replies := make(chan map[string]interface{}, 8)
+
+	// Read control commands from client (and detect disconnection)
 	go func() {
 		for {
-			if _, _, err := conn.ReadMessage(); err != nil {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
 				close(done)
 				return
 			}
+			select {
+			case replies <- h.control(runID, message):
+			default:
+				h.logger.Warn("Dropped control reply, client is not reading", zap.String("run_id", runID))
+			}
 		}
 	}()
 
@@ -96,6 +111,12 @@ func (h *WebSocketHandler) LiveTestMetrics(c *gin.Context) {
 			h.logger.Info("WebSocket connection closed by client", zap.String("run_id", runID))
 			return
 
+		case reply := <-replies:
+			if err := conn.WriteJSON(reply); err != nil {
+				h.logger.Error("Failed to send control reply", zap.Error(err))
+				return
+			}
+
 		case <-ticker.C:
 			// Get live metrics
 			metrics, err := h.testService.GetLiveMetrics(runID)
@@ -180,6 +201,7 @@ func (h *WebSocketHandler) LiveTestStatus(c *gin.Context) {
 				"stop_reason": testRun.StopReason,
 				"start_at":    testRun.StartAt,
 				"end_at":      testRun.EndAt,
+				"events":      testRun.Events,
 			}
 
 			if err := conn.WriteJSON(statusMsg); err != nil {
@@ -192,3 +214,21 @@ func (h *WebSocketHandler) LiveTestStatus(c *gin.Context) {
 		}
 	}
 }
+
+// control applies a control command received over a live metrics connection
+func (h *WebSocketHandler) control(runID string, message []byte) map[string]interface{} {
+	var cmd model.ControlCommand
+	if err := json.Unmarshal(message, &cmd); err != nil {
+		return map[string]interface{}{"error": "invalid control command: " + err.Error()}
+	}
+	if err := domain.NewValidator().ValidateControlCommand(&cmd); err != nil {
+		return map[string]interface{}{"error": err.Error()}
+	}
+
+	event, err := h.testService.ControlTest(runID, cmd)
+	if err != nil {
+		h.logger.Warn("Failed to control test", zap.String("run_id", runID), zap.Error(err))
+		return map[string]interface{}{"error": err.Error()}
+	}
+	return map[string]interface{}{"event": event}
+}
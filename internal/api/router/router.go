@@ -25,10 +25,15 @@ type RouterConfig struct {
 	TestPlanHandler     *handler.TestPlanHandler
 	TestRunHandler      *handler.TestRunHandler
 	ScenarioHandler     *handler.ScenarioHandler
+	DataSetHandler      *handler.DataSetHandler
+	BodyFileHandler     *handler.BodyFileHandler
+	ProtoFileHandler    *handler.ProtoFileHandler
+	SecretHandler       *handler.SecretHandler
 	ReportHandler       *handler.ReportHandler
 	WebSocketHandler    *handler.WebSocketHandler
 	AuthHandler         *handler.AuthHandler
 	AuditHandler        *handler.AuditHandler
+	AgentHandler        *handler.AgentHandler
 	JWTService          *auth.JWTService
 	APIKeyService       *auth.APIKeyService
 	AuditMiddleware     gin.HandlerFunc
@@ -179,6 +184,19 @@ func SetupRouter(routerConfig RouterConfig) *gin.Engine {
 			}
 		}
 
+		// Distributed load agent endpoints (admin only)
+		if routerConfig.AgentHandler != nil {
+			agents := protected.Group("/agents")
+			if routerConfig.AuthEnabled {
+				agents.Use(middleware.RequireRole(auth.RoleAdmin))
+			}
+			{
+				agents.POST("/register", routerConfig.AgentHandler.RegisterAgent)
+				agents.POST("/:id/heartbeat", routerConfig.AgentHandler.Heartbeat)
+				agents.GET("", routerConfig.AgentHandler.GetAgents)
+			}
+		}
+
 		// Test Plan endpoints
 		testPlans := protected.Group("/test-plans")
 		{
@@ -192,10 +210,12 @@ func SetupRouter(routerConfig RouterConfig) *gin.Engine {
 		{
 			testRuns.POST("/start", routerConfig.TestRunHandler.StartTest)
 			testRuns.POST("/:id/stop", routerConfig.TestRunHandler.StopTest)
+			testRuns.POST("/:id/control", routerConfig.TestRunHandler.ControlTest)
 			testRuns.GET("", routerConfig.TestRunHandler.GetTestRuns)
 			testRuns.GET("/:id", routerConfig.TestRunHandler.GetTestRun)
 			testRuns.GET("/:id/metrics", routerConfig.TestRunHandler.GetTestMetrics)
 			testRuns.GET("/:id/live", routerConfig.TestRunHandler.GetLiveMetrics)
+			testRuns.GET("/:id/percentiles", routerConfig.TestRunHandler.GetPercentiles)
 
 			// WebSocket endpoints for live updates
 			if routerConfig.WebSocketHandler != nil {
@@ -218,6 +238,53 @@ func SetupRouter(routerConfig RouterConfig) *gin.Engine {
 			}
 		}
 
+		// Data set endpoints
+		if routerConfig.DataSetHandler != nil {
+			dataSets := protected.Group("/data-sets")
+			{
+				dataSets.POST("", routerConfig.DataSetHandler.CreateDataSet)
+				dataSets.GET("", routerConfig.DataSetHandler.GetAllDataSets)
+				dataSets.GET("/:id", routerConfig.DataSetHandler.GetDataSet)
+				dataSets.DELETE("/:id", routerConfig.DataSetHandler.DeleteDataSet)
+			}
+		}
+
+		// Body file endpoints
+		if routerConfig.BodyFileHandler != nil {
+			bodyFiles := protected.Group("/body-files")
+			{
+				bodyFiles.POST("", routerConfig.BodyFileHandler.CreateBodyFile)
+				bodyFiles.GET("", routerConfig.BodyFileHandler.GetAllBodyFiles)
+				bodyFiles.GET("/:id", routerConfig.BodyFileHandler.GetBodyFile)
+				bodyFiles.DELETE("/:id", routerConfig.BodyFileHandler.DeleteBodyFile)
+			}
+		}
+
+		// Proto file endpoints
+		if routerConfig.ProtoFileHandler != nil {
+			protoFiles := protected.Group("/proto-files")
+			{
+				protoFiles.POST("", routerConfig.ProtoFileHandler.CreateProtoFile)
+				protoFiles.GET("", routerConfig.ProtoFileHandler.GetAllProtoFiles)
+				protoFiles.GET("/:id", routerConfig.ProtoFileHandler.GetProtoFile)
+				protoFiles.DELETE("/:id", routerConfig.ProtoFileHandler.DeleteProtoFile)
+			}
+		}
+
+		// Secret endpoints (admin only)
+		if routerConfig.SecretHandler != nil {
+			secrets := protected.Group("/secrets")
+			if routerConfig.AuthEnabled {
+				secrets.Use(middleware.RequireRole(auth.RoleAdmin))
+			}
+			{
+				secrets.POST("", routerConfig.SecretHandler.CreateSecret)
+				secrets.GET("", routerConfig.SecretHandler.GetAllSecrets)
+				secrets.GET("/:id", routerConfig.SecretHandler.GetSecret)
+				secrets.DELETE("/:id", routerConfig.SecretHandler.DeleteSecret)
+			}
+		}
+
 		// Report endpoints
 		if routerConfig.ReportHandler != nil {
 			reports := protected.Group("/reports")
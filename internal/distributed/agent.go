@@ -0,0 +1,301 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/engine"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"go.uber.org/zap"
+)
+
+// errAgentUnknown is returned when the controller no longer knows the agent,
+// e.g. because it restarted; the agent registers again
+var errAgentUnknown = errors.New("agent is not registered with the controller")
+
+// AgentConfig configures a load generating agent
+type AgentConfig struct {
+	ControllerURL string // Base URL of the controller, e.g. http://localhost:8080
+	Name          string
+	APIKey        string // Sent as X-API-Key when the controller requires authentication
+	Capacity      int    // Relative share of load to take, defaults to 1
+}
+
+// Agent runs shares of distributed tests on a local load generator and
+// reports their metrics to the controller
+type Agent struct {
+	config    AgentConfig
+	client    *http.Client
+	generator *engine.LoadGenerator
+	id        string
+	interval  time.Duration
+	mu        sync.Mutex
+	runs      map[string]bool           // Runs started on the local generator and not yet reported done
+	final     map[string]*model.Metrics // Final metrics of finished runs, until the controller has them
+}
+
+// NewAgent creates an agent that runs its shares on generator
+func NewAgent(cfg AgentConfig, generator *engine.LoadGenerator) *Agent {
+	cfg.ControllerURL = strings.TrimRight(cfg.ControllerURL, "/")
+	a := &Agent{
+		config:    cfg,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		generator: generator,
+		interval:  DefaultHeartbeatInterval,
+		runs:      make(map[string]bool),
+		final:     make(map[string]*model.Metrics),
+	}
+	generator.SetCompletionHandler(a.runFinished)
+	return a
+}
+
+// Run registers with the controller and sends heartbeats until ctx is
+// cancelled. Running shares are then stopped and their final metrics
+// reported before Run returns.
+func (a *Agent) Run(ctx context.Context) error {
+	if err := a.register(ctx); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.shutdown()
+			return nil
+		case <-ticker.C:
+			err := a.heartbeat()
+			if errors.Is(err, errAgentUnknown) {
+				logger.Log.Warn("Controller does not know this agent, registering again")
+				if err := a.register(ctx); err != nil {
+					return err
+				}
+			} else if err != nil {
+				logger.Log.Warn("Heartbeat failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// register registers with the controller, retrying until it succeeds or ctx
+// is cancelled
+func (a *Agent) register(ctx context.Context) error {
+	req := model.RegisterAgentRequest{Name: a.config.Name, Capacity: a.config.Capacity}
+	for {
+		var resp model.RegisterAgentResponse
+		err := a.post("/api/v1/agents/register", req, &resp)
+		if err == nil {
+			a.id = resp.Agent.ID
+			if resp.HeartbeatIntervalMs > 0 {
+				a.interval = time.Duration(resp.HeartbeatIntervalMs) * time.Millisecond
+			}
+			logger.Log.Info("Registered with controller",
+				zap.String("agent_id", a.id),
+				zap.String("controller", a.config.ControllerURL))
+			return nil
+		}
+
+		logger.Log.Warn("Failed to register with controller, retrying",
+			zap.String("controller", a.config.ControllerURL),
+			zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(a.interval):
+		}
+	}
+}
+
+// heartbeat reports every run to the controller and applies the work it
+// hands back
+func (a *Agent) heartbeat() error {
+	hb, reported := a.reports()
+
+	var resp model.AgentHeartbeatResponse
+	if err := a.post("/api/v1/agents/"+a.id+"/heartbeat", hb, &resp); err != nil {
+		return err
+	}
+
+	// The controller has the final metrics of these runs now
+	a.mu.Lock()
+	for _, runID := range reported {
+		delete(a.final, runID)
+		delete(a.runs, runID)
+	}
+	a.mu.Unlock()
+
+	for _, runID := range resp.Stop {
+		if err := a.generator.StopTest(runID); err != nil && !errors.Is(err, engine.ErrTestNotFound) {
+			logger.Log.Warn("Failed to stop run", zap.String("run_id", runID), zap.Error(err))
+		}
+	}
+	for _, ctl := range resp.Controls {
+		if err := a.generator.ControlTest(ctl.RunID, ctl.Command); err != nil {
+			logger.Log.Warn("Failed to apply control command",
+				zap.String("run_id", ctl.RunID),
+				zap.String("action", string(ctl.Command.Action)),
+				zap.Error(err))
+		}
+	}
+	for _, assignment := range resp.Assignments {
+		a.start(assignment)
+	}
+	return nil
+}
+
+// reports builds a heartbeat with the live metrics of every running share
+// and the final metrics of every finished one, and returns the runs reported
+// as done
+func (a *Agent) reports() (*model.AgentHeartbeat, []string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	hb := &model.AgentHeartbeat{}
+	var done []string
+	for runID := range a.runs {
+		if metrics, ok := a.final[runID]; ok {
+			hb.Runs = append(hb.Runs, model.AgentRunReport{
+				RunID:              runID,
+				Metrics:            metrics,
+				LatencyHistogram:   metrics.LatencyHistogram,
+				ResponseHistogram:  metrics.ResponseHistogram,
+				StepHistograms:     metrics.StepHistograms,
+				RequestHistograms:  metrics.RequestHistograms,
+				PhaseHistograms:    metrics.PhaseHistograms,
+				ProtocolHistograms: metrics.ProtocolHistograms,
+				Done:               true,
+			})
+			done = append(done, runID)
+			continue
+		}
+
+		// A run that finished since it was listed reports on the next heartbeat
+		metrics, err := a.generator.GetMetrics(runID)
+		if err != nil {
+			continue
+		}
+		latency, response, err := a.generator.GetHistograms(runID)
+		if err != nil {
+			continue
+		}
+		steps, requests, err := a.generator.GetBreakdownHistograms(runID)
+		if err != nil {
+			continue
+		}
+		phases, err := a.generator.GetPhaseHistograms(runID)
+		if err != nil {
+			continue
+		}
+		protocols, err := a.generator.GetProtocolHistograms(runID)
+		if err != nil {
+			continue
+		}
+		hb.Runs = append(hb.Runs, model.AgentRunReport{
+			RunID:              runID,
+			Metrics:            metrics,
+			LatencyHistogram:   latency,
+			ResponseHistogram:  response,
+			StepHistograms:     steps,
+			RequestHistograms:  requests,
+			PhaseHistograms:    phases,
+			ProtocolHistograms: protocols,
+		})
+	}
+	return hb, done
+}
+
+// start runs an assigned share on the local generator. A share that cannot
+// start is reported done with empty metrics so the run does not wait for it.
+func (a *Agent) start(assignment model.AgentAssignment) {
+	plan := *assignment.Plan
+	plan.Scenario = assignment.Scenario
+	plan.TLSMaterial = assignment.TLS
+	plan.Credentials = assignment.Credentials
+	plan.BodyFiles = assignment.BodyFiles
+	plan.Protos = assignment.Protos
+	if assignment.DataSet != nil {
+		dataSet := *assignment.DataSet
+		dataSet.Rows = assignment.Rows
+		dataSet.RowCount = len(assignment.Rows)
+		plan.DataSet = &dataSet
+	}
+
+	a.mu.Lock()
+	a.runs[assignment.RunID] = true
+	a.mu.Unlock()
+
+	if _, err := a.generator.StartTest(assignment.RunID, &plan); err != nil {
+		logger.Log.Error("Failed to start assigned share",
+			zap.String("run_id", assignment.RunID),
+			zap.Error(err))
+		a.runFinished(model.NewMetrics(assignment.RunID))
+		return
+	}
+
+	logger.Log.Info("Started assigned share",
+		zap.String("run_id", assignment.RunID),
+		zap.Int("users", plan.Users),
+		zap.Int("target_rps", plan.TargetRPS))
+}
+
+// runFinished keeps the final metrics of a run until the next heartbeat
+func (a *Agent) runFinished(metrics *model.Metrics) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.final[metrics.RunID] = metrics
+}
+
+// shutdown stops every running share and reports the final metrics
+func (a *Agent) shutdown() {
+	logger.Log.Info("Agent shutting down",
+		zap.Int("active_tests", a.generator.GetActiveTestCount()))
+
+	if err := a.generator.Shutdown(30 * time.Second); err != nil {
+		logger.Log.Error("Failed to stop every share", zap.Error(err))
+	}
+	if err := a.heartbeat(); err != nil {
+		logger.Log.Warn("Failed to report final metrics", zap.Error(err))
+	}
+}
+
+// post sends body as JSON to the controller and decodes the response into out
+func (a *Agent) post(path string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, a.config.ControllerURL+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.config.APIKey != "" {
+		req.Header.Set("X-API-Key", a.config.APIKey)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && a.id != "" && strings.HasSuffix(path, "/heartbeat") {
+		return errAgentUnknown
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("controller returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,561 @@
+package distributed
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/engine"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHeartbeatInterval is how often agents report to the controller
+	DefaultHeartbeatInterval = time.Second
+
+	// DefaultAgentTimeout is how long an agent may miss heartbeats before it
+	// is considered lost and its share of every run is written off
+	DefaultAgentTimeout = 10 * time.Second
+)
+
+// Controller partitions test runs across registered agents and merges the
+// metrics they report into one view of each run. Agents poll the controller:
+// work for an agent is queued until its next heartbeat.
+type Controller struct {
+	agents     map[string]*agentState
+	runs       map[string]*distributedRun
+	mu         sync.Mutex
+	interval   time.Duration
+	timeout    time.Duration
+	onComplete func(*model.Metrics)
+}
+
+// agentState is a registered agent and the work queued for it
+type agentState struct {
+	agent   model.Agent
+	pending model.AgentHeartbeatResponse
+}
+
+// distributedRun is a test run split across agents
+type distributedRun struct {
+	id      string
+	shares  []*share
+	metrics *model.Metrics // Merged from the latest reports once a second
+	windows *engine.RollingWindows
+}
+
+// share is one agent's part of a distributed run
+type share struct {
+	agentID string
+	name    string
+	weight  int
+	plan    *model.TestPlan
+	report  *model.AgentRunReport // Latest report, nil until the agent first reports
+	done    bool
+	lost    bool
+}
+
+// active reports whether the share's agent is still generating load
+func (sh *share) active() bool {
+	return !sh.done && !sh.lost
+}
+
+// NewController creates a controller with the default heartbeat interval and
+// agent timeout
+func NewController() *Controller {
+	return &Controller{
+		agents:   make(map[string]*agentState),
+		runs:     make(map[string]*distributedRun),
+		interval: DefaultHeartbeatInterval,
+		timeout:  DefaultAgentTimeout,
+	}
+}
+
+// SetAgentTimeout changes how long an agent may stay silent before it is lost
+func (c *Controller) SetAgentTimeout(timeout time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timeout = timeout
+}
+
+// SetCompletionHandler registers a callback that receives the merged final
+// metrics of every distributed run once all of its agents have finished or
+// been lost. Metrics.Agents tells which.
+func (c *Controller) SetCompletionHandler(fn func(*model.Metrics)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onComplete = fn
+}
+
+// Register adds a new agent. An agent that restarts registers again and gets
+// a new ID.
+func (c *Controller) Register(req *model.RegisterAgentRequest) *model.RegisterAgentResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	capacity := req.Capacity
+	if capacity <= 0 {
+		capacity = 1
+	}
+	now := time.Now()
+	state := &agentState{agent: model.Agent{
+		ID:           uuid.New().String(),
+		Name:         req.Name,
+		Capacity:     capacity,
+		Status:       model.AgentStatusIdle,
+		RegisteredAt: now,
+		LastSeen:     now,
+	}}
+	c.agents[state.agent.ID] = state
+
+	logger.Log.Info("Agent registered",
+		zap.String("agent_id", state.agent.ID),
+		zap.String("name", req.Name),
+		zap.Int("capacity", capacity))
+
+	agent := c.agentView(state)
+	return &model.RegisterAgentResponse{
+		Agent:               &agent,
+		HeartbeatIntervalMs: int(c.interval.Milliseconds()),
+	}
+}
+
+// Heartbeat records an agent's reports and hands it the work queued since
+// its last heartbeat. Reports for runs the agent no longer has a share of,
+// because the run ended or the agent was written off as lost, are answered
+// with a stop.
+func (c *Controller) Heartbeat(agentID string, hb *model.AgentHeartbeat) (*model.AgentHeartbeatResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.agents[agentID]
+	if !ok {
+		return nil, domain.NewNotFoundError("agent", agentID)
+	}
+	state.agent.LastSeen = time.Now()
+	if state.agent.Status == model.AgentStatusLost {
+		logger.Log.Info("Lost agent is back",
+			zap.String("agent_id", agentID),
+			zap.String("name", state.agent.Name))
+	}
+
+	resp := state.pending
+	state.pending = model.AgentHeartbeatResponse{}
+
+	for i := range hb.Runs {
+		report := hb.Runs[i]
+		sh := c.shareOf(report.RunID, agentID)
+		if sh == nil || sh.lost {
+			if !report.Done {
+				resp.Stop = append(resp.Stop, report.RunID)
+			}
+			continue
+		}
+		if sh.done {
+			continue
+		}
+		sh.report = &report
+		if report.Done {
+			sh.done = true
+			logger.Log.Info("Agent finished its share",
+				zap.String("agent_id", agentID),
+				zap.String("run_id", report.RunID))
+		}
+	}
+
+	state.agent.Status = c.statusOf(agentID)
+	return &resp, nil
+}
+
+// Agents lists the registered agents
+func (c *Controller) Agents() []*model.Agent {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checkAgents(time.Now())
+	agents := make([]*model.Agent, 0, len(c.agents))
+	for _, state := range c.agents {
+		agent := c.agentView(state)
+		agents = append(agents, &agent)
+	}
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].RegisteredAt.Before(agents[j].RegisteredAt)
+	})
+	return agents
+}
+
+// StartDistributedTest splits a plan across up to agents agents, all available
+// agents if agents is 0, in proportion to their capacity. Idle agents are
+// preferred over busy ones; lost agents are never picked.
+func (c *Controller) StartDistributedTest(runID string, plan *model.TestPlan, agents int) (*model.Metrics, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.runs[runID]; exists {
+		return nil, domain.ErrAlreadyRunning
+	}
+
+	c.checkAgents(time.Now())
+	available := make([]*agentState, 0, len(c.agents))
+	for _, state := range c.agents {
+		if state.agent.Status != model.AgentStatusLost {
+			available = append(available, state)
+		}
+	}
+	if len(available) == 0 {
+		return nil, domain.NewValidationError("agents", "no agents are available")
+	}
+	if agents > len(available) {
+		return nil, domain.NewValidationError("agents",
+			fmt.Sprintf("%d agents requested but only %d are available", agents, len(available)))
+	}
+	if agents == 0 {
+		agents = len(available)
+	}
+	sort.Slice(available, func(i, j int) bool {
+		a, b := available[i].agent, available[j].agent
+		if a.Status != b.Status {
+			return a.Status == model.AgentStatusIdle
+		}
+		if a.Capacity != b.Capacity {
+			return a.Capacity > b.Capacity
+		}
+		return a.RegisteredAt.Before(b.RegisteredAt)
+	})
+	available = available[:shareCount(plan, agents)]
+
+	weights := make([]int, len(available))
+	for i, state := range available {
+		weights[i] = state.agent.Capacity
+	}
+	plans := splitPlan(plan, weights)
+
+	var rows [][]map[string]string
+	if plan.DataSet != nil {
+		feed := plan.Data
+		if feed == nil && plan.Scenario != nil {
+			feed = plan.Scenario.Data
+		}
+		rows = splitRows(plan.DataSet.Rows, feed, weights)
+	}
+
+	run := &distributedRun{
+		id:      runID,
+		shares:  make([]*share, len(available)),
+		metrics: model.NewMetrics(runID),
+		windows: engine.NewRollingWindows(time.Now()),
+	}
+	for i, state := range available {
+		assignment := model.AgentAssignment{
+			RunID:       runID,
+			Plan:        plans[i],
+			Scenario:    plan.Scenario,
+			DataSet:     plan.DataSet,
+			TLS:         plan.TLSMaterial,
+			Credentials: plan.Credentials,
+			BodyFiles:   plan.BodyFiles,
+			Protos:      plan.Protos,
+		}
+		if rows != nil {
+			assignment.Rows = rows[i]
+		}
+		state.pending.Assignments = append(state.pending.Assignments, assignment)
+		state.agent.Status = model.AgentStatusBusy
+
+		run.shares[i] = &share{
+			agentID: state.agent.ID,
+			name:    state.agent.Name,
+			weight:  weights[i],
+			plan:    plans[i],
+		}
+	}
+	run.metrics.Agents = agentMetrics(run.shares)
+	c.runs[runID] = run
+
+	go c.track(run)
+
+	logger.Log.Info("Distributed test started",
+		zap.String("run_id", runID),
+		zap.String("plan_id", plan.ID),
+		zap.Int("agents", len(available)))
+
+	return run.metrics.GetSnapshot(), nil
+}
+
+// StopTest asks every agent still running a share of the run to stop. The
+// run ends once they have reported their final metrics.
+func (c *Controller) StopTest(runID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	run, exists := c.runs[runID]
+	if !exists {
+		return engine.ErrTestNotFound
+	}
+	for _, sh := range run.shares {
+		if sh.active() {
+			state := c.agents[sh.agentID]
+			state.pending.Stop = append(state.pending.Stop, runID)
+		}
+	}
+
+	logger.Log.Info("Distributed test stopped",
+		zap.String("run_id", runID))
+
+	return nil
+}
+
+// ControlTest forwards a live control command to the agents still running a
+// share of the run. Rates and VU counts are split between them like the plan
+// was; pause, resume and duration changes go to every agent as they are.
+func (c *Controller) ControlTest(runID string, cmd model.ControlCommand) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	run, exists := c.runs[runID]
+	if !exists {
+		return engine.ErrTestNotFound
+	}
+	var active []*share
+	for _, sh := range run.shares {
+		if sh.active() {
+			active = append(active, sh)
+		}
+	}
+	if len(active) == 0 {
+		return domain.ErrNotRunning
+	}
+
+	values := make([]int, len(active))
+	switch cmd.Action {
+	case model.ControlPause, model.ControlResume, model.ControlExtendDuration:
+		for i := range values {
+			values[i] = cmd.Value
+		}
+	case model.ControlSetRPS:
+		if cmd.Value == 0 {
+			break // Every agent stops releasing iterations
+		}
+		if cmd.Value < len(active) {
+			return domain.NewValidationError("value",
+				fmt.Sprintf("a rate of %d cannot be split across %d agents", cmd.Value, len(active)))
+		}
+		values = splitAtLeastOne(cmd.Value, shareWeights(active))
+	case model.ControlAddVUs:
+		values = split(cmd.Value, shareWeights(active))
+	case model.ControlRemoveVUs:
+		// Every agent keeps at least one VU, so split what each can give up
+		removable := make([]int, len(active))
+		total := 0
+		for i, sh := range active {
+			removable[i] = sh.vus() - 1
+			total += removable[i]
+		}
+		if cmd.Value > total {
+			return domain.NewValidationError("value",
+				fmt.Sprintf("cannot remove %d VUs from a pool of %d", cmd.Value, total+len(active)))
+		}
+		values = split(cmd.Value, removable)
+	default:
+		return domain.NewValidationError("action", fmt.Sprintf("unknown control action: %s", cmd.Action))
+	}
+
+	for i, sh := range active {
+		if values[i] == 0 && cmd.Action != model.ControlPause && cmd.Action != model.ControlResume && cmd.Action != model.ControlSetRPS {
+			continue
+		}
+		state := c.agents[sh.agentID]
+		state.pending.Controls = append(state.pending.Controls, model.AgentControl{
+			RunID:   runID,
+			Command: model.ControlCommand{Action: cmd.Action, Value: values[i]},
+		})
+	}
+	logger.Log.Info("Control command forwarded to agents",
+		zap.String("run_id", runID),
+		zap.String("action", string(cmd.Action)),
+		zap.Int("value", cmd.Value),
+		zap.Int("agents", len(active)))
+
+	return nil
+}
+
+// GetMetrics returns the merged metrics of a running distributed test
+func (c *Controller) GetMetrics(runID string) (*model.Metrics, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	run, exists := c.runs[runID]
+	if !exists {
+		return nil, engine.ErrTestNotFound
+	}
+	return run.metrics.GetSnapshot(), nil
+}
+
+// GetHistograms merges the service and response time histograms last
+// reported by every agent of a running distributed test
+func (c *Controller) GetHistograms(runID string) (latency, response *model.Histogram, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	run, exists := c.runs[runID]
+	if !exists {
+		return nil, nil, engine.ErrTestNotFound
+	}
+	merged := mergeHistograms(run.shares)
+	return merged.latency, merged.response, nil
+}
+
+// IsRunning checks if a distributed test is currently running
+func (c *Controller) IsRunning(runID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, exists := c.runs[runID]
+	return exists
+}
+
+// track merges the run's reports once a second until every share has
+// finished or been lost, then hands the final metrics to the completion
+// handler and forgets the run
+func (c *Controller) track(run *distributedRun) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		c.mu.Lock()
+		c.checkAgents(now)
+
+		finished := true
+		for _, sh := range run.shares {
+			if sh.active() {
+				finished = false
+			}
+		}
+
+		merged := mergeReports(run.id, run.shares)
+		if finished {
+			setFinalMetrics(merged)
+		} else {
+			merged.metrics.Windows = run.windows.Add(now, merged.latency, merged.metrics.TotalRequests, merged.metrics.FailedRequests)
+		}
+		run.metrics = merged.metrics
+		onComplete := c.onComplete
+		c.mu.Unlock()
+
+		if !finished {
+			continue
+		}
+
+		logger.Log.Info("Distributed test completed",
+			zap.String("run_id", run.id),
+			zap.Int64("total_requests", merged.metrics.TotalRequests))
+
+		if onComplete != nil {
+			onComplete(merged.metrics.GetSnapshot())
+		}
+
+		c.mu.Lock()
+		delete(c.runs, run.id)
+		for _, sh := range run.shares {
+			if state, ok := c.agents[sh.agentID]; ok && state.agent.Status != model.AgentStatusLost {
+				state.agent.Status = c.statusOf(sh.agentID)
+			}
+		}
+		c.mu.Unlock()
+		return
+	}
+}
+
+// checkAgents marks agents that have not sent a heartbeat within the timeout
+// as lost, along with their shares of running tests. The caller must hold c.mu.
+func (c *Controller) checkAgents(now time.Time) {
+	for id, state := range c.agents {
+		if state.agent.Status == model.AgentStatusLost || now.Sub(state.agent.LastSeen) <= c.timeout {
+			continue
+		}
+		state.agent.Status = model.AgentStatusLost
+		state.pending = model.AgentHeartbeatResponse{}
+		for _, run := range c.runs {
+			for _, sh := range run.shares {
+				if sh.agentID == id && sh.active() {
+					sh.lost = true
+				}
+			}
+		}
+		logger.Log.Warn("Agent lost",
+			zap.String("agent_id", id),
+			zap.String("name", state.agent.Name),
+			zap.Time("last_seen", state.agent.LastSeen))
+	}
+}
+
+// shareOf returns an agent's share of a run, or nil. The caller must hold c.mu.
+func (c *Controller) shareOf(runID, agentID string) *share {
+	run, exists := c.runs[runID]
+	if !exists {
+		return nil
+	}
+	for _, sh := range run.shares {
+		if sh.agentID == agentID {
+			return sh
+		}
+	}
+	return nil
+}
+
+// statusOf returns busy if the agent has an active share of any run, idle
+// otherwise. The caller must hold c.mu.
+func (c *Controller) statusOf(agentID string) model.AgentStatus {
+	if len(c.runsOf(agentID)) > 0 {
+		return model.AgentStatusBusy
+	}
+	return model.AgentStatusIdle
+}
+
+// runsOf lists the runs an agent has an active share of. The caller must
+// hold c.mu.
+func (c *Controller) runsOf(agentID string) []string {
+	var runs []string
+	for id, run := range c.runs {
+		for _, sh := range run.shares {
+			if sh.agentID == agentID && sh.active() {
+				runs = append(runs, id)
+			}
+		}
+	}
+	sort.Strings(runs)
+	return runs
+}
+
+// agentView returns a copy of an agent with its active runs. The caller must
+// hold c.mu.
+func (c *Controller) agentView(state *agentState) model.Agent {
+	agent := state.agent
+	if agent.Status != model.AgentStatusLost {
+		agent.Runs = c.runsOf(agent.ID)
+	}
+	return agent
+}
+
+// vus returns how many VUs the share's agent last reported, or the plan's
+// users before its first report
+func (sh *share) vus() int {
+	if sh.report != nil && sh.report.Metrics != nil && sh.report.Metrics.ActiveWorkers > 0 {
+		return sh.report.Metrics.ActiveWorkers
+	}
+	return sh.plan.Users
+}
+
+// shareWeights returns the capacity weights of shares
+func shareWeights(shares []*share) []int {
+	weights := make([]int, len(shares))
+	for i, sh := range shares {
+		weights[i] = sh.weight
+	}
+	return weights
+}
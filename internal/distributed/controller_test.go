@@ -0,0 +1,314 @@
+package distributed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/engine"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/metrics"
+)
+
+var (
+	sharedTestCollector     *metrics.Collector
+	sharedTestCollectorOnce sync.Once
+)
+
+func init() {
+	if err := logger.Init("error"); err != nil {
+		panic(err)
+	}
+}
+
+// newTestGenerator returns a load generator sharing one Prometheus collector
+// across tests, since collectors cannot be registered twice
+func newTestGenerator() *engine.LoadGenerator {
+	sharedTestCollectorOnce.Do(func() {
+		sharedTestCollector = metrics.NewCollector()
+	})
+	return engine.NewLoadGenerator(sharedTestCollector)
+}
+
+// newControllerServer serves the agent endpoints of a controller the way
+// the API does
+func newControllerServer(t *testing.T, c *Controller) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/agents/register", func(w http.ResponseWriter, r *http.Request) {
+		var req model.RegisterAgentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(c.Register(&req))
+	})
+	mux.HandleFunc("POST /api/v1/agents/{id}/heartbeat", func(w http.ResponseWriter, r *http.Request) {
+		var hb model.AgentHeartbeat
+		if err := json.NewDecoder(r.Body).Decode(&hb); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp, err := c.Heartbeat(r.PathValue("id"), &hb)
+		if err != nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// startAgent runs an agent against the controller until the test ends
+func startAgent(t *testing.T, controllerURL, name string, capacity int) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	agent := NewAgent(AgentConfig{ControllerURL: controllerURL, Name: name, Capacity: capacity}, newTestGenerator())
+	go func() {
+		defer close(done)
+		_ = agent.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// waitForAgents waits until n agents have registered
+func waitForAgents(t *testing.T, c *Controller, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(c.Agents()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d agents to register, got %d", n, len(c.Agents()))
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// waitForCompletion waits for the final metrics of a distributed run
+func waitForCompletion(t *testing.T, completed <-chan *model.Metrics) *model.Metrics {
+	t.Helper()
+	select {
+	case m := <-completed:
+		return m
+	case <-time.After(20 * time.Second):
+		t.Fatal("Timed out waiting for the distributed run to complete")
+		return nil
+	}
+}
+
+func TestDistributedRunAcrossAgents(t *testing.T) {
+	var hits int64
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt64(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	controller := NewController()
+	completed := make(chan *model.Metrics, 1)
+	controller.SetCompletionHandler(func(m *model.Metrics) { completed <- m })
+	server := newControllerServer(t, controller)
+
+	startAgent(t, server.URL, "agent-a", 1)
+	startAgent(t, server.URL, "agent-b", 3)
+	waitForAgents(t, controller, 2)
+
+	plan := &model.TestPlan{
+		ID:              "plan-1",
+		Name:            "Distributed",
+		TargetURL:       target.URL,
+		Method:          "GET",
+		Users:           4,
+		DurationSec:     2,
+		TimeoutMs:       1000,
+		TargetRPS:       40,
+		GracefulStopSec: 1,
+	}
+	if _, err := controller.StartDistributedTest("run-1", plan, 0); err != nil {
+		t.Fatalf("Failed to start distributed test: %v", err)
+	}
+	if !controller.IsRunning("run-1") {
+		t.Fatal("Expected the run to be running")
+	}
+	if _, err := controller.StartDistributedTest("run-1", plan, 0); !errors.Is(err, domain.ErrAlreadyRunning) {
+		t.Errorf("Expected starting the same run twice to fail, got %v", err)
+	}
+
+	final := waitForCompletion(t, completed)
+
+	if final.TotalRequests == 0 || final.TotalRequests != atomic.LoadInt64(&hits) {
+		t.Errorf("Expected the merged total to match the %d requests received, got %d", atomic.LoadInt64(&hits), final.TotalRequests)
+	}
+	if final.LatencyHistogram == nil || final.LatencyHistogram.TotalCount() != final.StatusCodes[http.StatusOK] {
+		t.Error("Expected the merged histogram to count every response")
+	}
+	if final.P99LatencyMs <= 0 || final.StatusCodes[http.StatusOK] != final.SuccessRequests {
+		t.Errorf("Expected percentiles and status codes from every agent, got p99=%f codes=%v", final.P99LatencyMs, final.StatusCodes)
+	}
+	if len(final.Phases) != len(model.TimingPhases) || final.NewConnections+final.ReusedConnections != final.StatusCodes[http.StatusOK] {
+		t.Errorf("Expected merged timing phases and a connection per response, got %d phases and %d+%d connections",
+			len(final.Phases), final.NewConnections, final.ReusedConnections)
+	}
+	if len(final.Agents) != 2 {
+		t.Fatalf("Expected a breakdown for 2 agents, got %d", len(final.Agents))
+	}
+
+	var agentTotal int64
+	for _, agent := range final.Agents {
+		if agent.Status != model.AgentStatusIdle {
+			t.Errorf("Expected agent %s to have finished, got %s", agent.Name, agent.Status)
+		}
+		if agent.Name == "agent-b" && (agent.Users != 3 || agent.TargetRPS != 30) {
+			t.Errorf("Expected the agent with 3x capacity to get 3 VUs at 30 RPS, got %d at %d", agent.Users, agent.TargetRPS)
+		}
+		agentTotal += agent.TotalRequests
+	}
+	if agentTotal != final.TotalRequests {
+		t.Errorf("Expected agent totals to add up to %d, got %d", final.TotalRequests, agentTotal)
+	}
+
+	// The run is forgotten once its final metrics have been handed over
+	deadline := time.Now().Add(time.Second)
+	for controller.IsRunning("run-1") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if controller.IsRunning("run-1") {
+		t.Error("Expected the run to end")
+	}
+}
+
+func TestDistributedRunSurvivesAgentLoss(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	controller := NewController()
+	controller.SetAgentTimeout(1500 * time.Millisecond)
+	completed := make(chan *model.Metrics, 1)
+	controller.SetCompletionHandler(func(m *model.Metrics) { completed <- m })
+	server := newControllerServer(t, controller)
+
+	startAgent(t, server.URL, "healthy", 1)
+	waitForAgents(t, controller, 1)
+
+	// An agent that registers and then never reports again
+	silent := controller.Register(&model.RegisterAgentRequest{Name: "silent"})
+
+	plan := &model.TestPlan{
+		ID:          "plan-2",
+		Name:        "Agent loss",
+		TargetURL:   target.URL,
+		Method:      "GET",
+		Users:       2,
+		DurationSec: 3,
+		TimeoutMs:   1000,
+		TargetRPS:   20,
+	}
+	if _, err := controller.StartDistributedTest("run-2", plan, 2); err != nil {
+		t.Fatalf("Failed to start distributed test: %v", err)
+	}
+
+	final := waitForCompletion(t, completed)
+
+	statuses := make(map[string]model.AgentStatus)
+	for _, agent := range final.Agents {
+		statuses[agent.Name] = agent.Status
+	}
+	if statuses["silent"] != model.AgentStatusLost || statuses["healthy"] != model.AgentStatusIdle {
+		t.Errorf("Expected the silent agent to be lost and the healthy one to finish, got %v", statuses)
+	}
+	if final.TotalRequests == 0 {
+		t.Error("Expected the healthy agent's requests to be kept")
+	}
+
+	// A lost agent that comes back is told to stop its share
+	resp, err := controller.Heartbeat(silent.Agent.ID, &model.AgentHeartbeat{
+		Runs: []model.AgentRunReport{{RunID: "run-2", Metrics: model.NewMetrics("run-2")}},
+	})
+	if err != nil {
+		t.Fatalf("Heartbeat failed: %v", err)
+	}
+	if len(resp.Stop) != 1 || resp.Stop[0] != "run-2" {
+		t.Errorf("Expected the returning agent to be told to stop run-2, got %v", resp.Stop)
+	}
+}
+
+func TestControllerRejectsUnknownAgentsAndMissingCapacity(t *testing.T) {
+	controller := NewController()
+
+	var notFound *domain.NotFoundError
+	if _, err := controller.Heartbeat("missing", &model.AgentHeartbeat{}); !errors.As(err, &notFound) {
+		t.Errorf("Expected a not found error for an unknown agent, got %v", err)
+	}
+
+	plan := &model.TestPlan{Users: 1, DurationSec: 1}
+	if _, err := controller.StartDistributedTest("run-1", plan, 0); err == nil || !strings.Contains(err.Error(), "no agents") {
+		t.Errorf("Expected starting without agents to fail, got %v", err)
+	}
+
+	controller.Register(&model.RegisterAgentRequest{Name: "only"})
+	if _, err := controller.StartDistributedTest("run-1", plan, 2); err == nil {
+		t.Error("Expected requesting more agents than registered to fail")
+	}
+}
+
+func TestControlSplitsAcrossAgents(t *testing.T) {
+	controller := NewController()
+	a := controller.Register(&model.RegisterAgentRequest{Name: "a", Capacity: 1})
+	b := controller.Register(&model.RegisterAgentRequest{Name: "b", Capacity: 3})
+
+	plan := &model.TestPlan{Users: 8, DurationSec: 60, TargetRPS: 40}
+	if _, err := controller.StartDistributedTest("run-1", plan, 0); err != nil {
+		t.Fatalf("Failed to start distributed test: %v", err)
+	}
+
+	if err := controller.ControlTest("run-1", model.ControlCommand{Action: model.ControlSetRPS, Value: 100}); err != nil {
+		t.Fatalf("Failed to set RPS: %v", err)
+	}
+	if err := controller.ControlTest("run-1", model.ControlCommand{Action: model.ControlPause}); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	if err := controller.ControlTest("run-1", model.ControlCommand{Action: model.ControlRemoveVUs, Value: 8}); err == nil {
+		t.Error("Expected removing every VU to fail")
+	}
+
+	controls := func(id string) []model.AgentControl {
+		resp, err := controller.Heartbeat(id, &model.AgentHeartbeat{})
+		if err != nil {
+			t.Fatalf("Heartbeat failed: %v", err)
+		}
+		if len(resp.Assignments) != 1 {
+			t.Errorf("Expected one assignment, got %d", len(resp.Assignments))
+		}
+		return resp.Controls
+	}
+
+	ca, cb := controls(a.Agent.ID), controls(b.Agent.ID)
+	if len(ca) != 2 || len(cb) != 2 {
+		t.Fatalf("Expected 2 controls per agent, got %d and %d", len(ca), len(cb))
+	}
+	if ca[0].Command.Value != 25 || cb[0].Command.Value != 75 {
+		t.Errorf("Expected the rate split 25/75, got %d/%d", ca[0].Command.Value, cb[0].Command.Value)
+	}
+	if ca[1].Command.Action != model.ControlPause || cb[1].Command.Action != model.ControlPause {
+		t.Error("Expected pause to reach every agent")
+	}
+
+	_ = controller.StopTest("run-1")
+}
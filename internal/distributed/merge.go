@@ -0,0 +1,398 @@
+package distributed
+
+import (
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/engine"
+)
+
+// merged is the combined view of every share of a run
+type merged struct {
+	metrics   *model.Metrics
+	latency   *model.Histogram
+	response  *model.Histogram
+	steps     []*model.Histogram
+	requests  []*model.Histogram
+	phases    []*model.Histogram
+	protocols model.ProtocolHistograms // By metrics section, for plans that use one
+}
+
+// mergeReports combines the latest report of every share into the metrics
+// of the whole run. Counters add up; percentiles are read from the merged
+// histograms, never averaged. Live values such as the current rate and VU
+// count only come from shares that are still running.
+func mergeReports(runID string, shares []*share) *merged {
+	m := mergeHistograms(shares)
+	metrics := model.NewMetrics(runID)
+	m.metrics = metrics
+
+	for _, sh := range shares {
+		if sh.report == nil || sh.report.Metrics == nil {
+			continue
+		}
+		r := sh.report.Metrics
+
+		metrics.TotalRequests += r.TotalRequests
+		metrics.SuccessRequests += r.SuccessRequests
+		metrics.FailedRequests += r.FailedRequests
+		metrics.DroppedIterations += r.DroppedIterations
+		metrics.Iterations += r.Iterations
+		metrics.FailedIterations += r.FailedIterations
+		metrics.NewConnections += r.NewConnections
+		metrics.ReusedConnections += r.ReusedConnections
+		metrics.CheckFailures += r.CheckFailures
+		if r.TotalDurationMs > metrics.TotalDurationMs {
+			metrics.TotalDurationMs = r.TotalDurationMs
+		}
+		if r.TotalRequests > 0 && (metrics.MinLatencyMs < 0 || r.MinLatencyMs < metrics.MinLatencyMs) {
+			metrics.MinLatencyMs = r.MinLatencyMs
+		}
+		if r.MaxLatencyMs > metrics.MaxLatencyMs {
+			metrics.MaxLatencyMs = r.MaxLatencyMs
+		}
+		for code, n := range r.StatusCodes {
+			metrics.StatusCodes[code] += n
+		}
+		for code, n := range r.GRPCStatusCodes {
+			if metrics.GRPCStatusCodes == nil {
+				metrics.GRPCStatusCodes = make(map[string]int64)
+			}
+			metrics.GRPCStatusCodes[code] += n
+		}
+		for class, n := range r.Errors {
+			metrics.Errors[class] += n
+		}
+		metrics.ErrorSamples = model.MergeErrorSamples(metrics.ErrorSamples, r.ErrorSamples)
+		if sh.active() {
+			metrics.CurrentRPS += r.CurrentRPS
+			metrics.ActiveWorkers += r.ActiveWorkers
+			metrics.Paused = metrics.Paused || r.Paused
+		}
+		metrics.Steps = mergeSteps(metrics.Steps, r.Steps)
+		metrics.RequestBreakdown = mergeRequests(metrics.RequestBreakdown, r.RequestBreakdown)
+		metrics.Checks = mergeChecks(metrics.Checks, r.Checks)
+		metrics.Retry = mergeRetry(metrics.Retry, r.Retry)
+		metrics.WebSocket = mergeWebSocket(metrics.WebSocket, r.WebSocket)
+		metrics.Stream = mergeStream(metrics.Stream, r.Stream)
+		metrics.Socket = mergeSocket(metrics.Socket, r.Socket)
+		metrics.DNS = mergeDNS(metrics.DNS, r.DNS)
+		metrics.SQL = mergeSQL(metrics.SQL, r.SQL)
+		metrics.Redis = mergeRedis(metrics.Redis, r.Redis)
+	}
+
+	if metrics.TotalDurationMs > 0 {
+		metrics.RequestsPerSec = float64(metrics.TotalRequests) / (float64(metrics.TotalDurationMs) / 1000.0)
+	}
+	metrics.P50LatencyMs = m.latency.ValueAtPercentile(50)
+	metrics.P75LatencyMs = m.latency.ValueAtPercentile(75)
+	metrics.P95LatencyMs = m.latency.ValueAtPercentile(95)
+	metrics.P99LatencyMs = m.latency.ValueAtPercentile(99)
+	metrics.AvgLatencyMs = m.latency.MeanMs()
+	metrics.P95ResponseMs = m.response.ValueAtPercentile(95)
+	metrics.P99ResponseMs = m.response.ValueAtPercentile(99)
+	setBreakdownLatencies(m)
+	if len(m.phases) > 0 {
+		metrics.Phases = model.NewPhaseMetrics(m.phases)
+	}
+	engine.SetProtocolStats(metrics, m.protocols)
+	metrics.Agents = agentMetrics(shares)
+
+	return m
+}
+
+// setFinalMetrics fills in the percentiles and histograms kept with the
+// final metrics of a run, and drops the live-only values
+func setFinalMetrics(m *merged) {
+	metrics := m.metrics
+	metrics.P999LatencyMs = m.latency.ValueAtPercentile(99.9)
+	metrics.P9999LatencyMs = m.latency.ValueAtPercentile(99.99)
+	metrics.P50ResponseMs = m.response.ValueAtPercentile(50)
+	metrics.P75ResponseMs = m.response.ValueAtPercentile(75)
+	metrics.P999ResponseMs = m.response.ValueAtPercentile(99.9)
+	metrics.P9999ResponseMs = m.response.ValueAtPercentile(99.99)
+	metrics.AvgResponseMs = m.response.MeanMs()
+	metrics.MaxResponseMs = m.response.MaxMs()
+
+	metrics.LatencyHistogram = m.latency
+	metrics.ResponseHistogram = m.response
+	metrics.StepHistograms = m.steps
+	metrics.RequestHistograms = m.requests
+	metrics.PhaseHistograms = m.phases
+	metrics.ProtocolHistograms = m.protocols
+
+	metrics.CurrentRPS = 0
+	metrics.ActiveWorkers = 0
+	metrics.Paused = false
+	metrics.Windows = nil
+}
+
+// mergeHistograms merges the histograms of the latest report of every share
+func mergeHistograms(shares []*share) *merged {
+	m := &merged{
+		latency:   model.NewHistogram(),
+		response:  model.NewHistogram(),
+		protocols: make(model.ProtocolHistograms),
+	}
+	for _, sh := range shares {
+		if sh.report == nil {
+			continue
+		}
+		m.latency.Merge(sh.report.LatencyHistogram)
+		m.response.Merge(sh.report.ResponseHistogram)
+		m.steps = mergeHistogramList(m.steps, sh.report.StepHistograms)
+		m.requests = mergeHistogramList(m.requests, sh.report.RequestHistograms)
+		m.phases = mergeHistogramList(m.phases, sh.report.PhaseHistograms)
+		for section, hists := range sh.report.ProtocolHistograms {
+			m.protocols[section] = mergeHistogramList(m.protocols[section], hists)
+		}
+	}
+	return m
+}
+
+// mergeHistogramList merges each histogram of from into the one at the same
+// index of into, growing into as needed
+func mergeHistogramList(into, from []*model.Histogram) []*model.Histogram {
+	for i, hist := range from {
+		if i == len(into) {
+			into = append(into, model.NewHistogram())
+		}
+		into[i].Merge(hist)
+	}
+	return into
+}
+
+// mergeSteps adds the per-step counters of from to into, by step index
+func mergeSteps(into, from []model.StepMetrics) []model.StepMetrics {
+	for i, step := range from {
+		if i == len(into) {
+			into = append(into, model.StepMetrics{Name: step.Name})
+		}
+		into[i].Requests += step.Requests
+		into[i].Success += step.Success
+		into[i].Failed += step.Failed
+		into[i].AssertionFailures += step.AssertionFailures
+	}
+	return into
+}
+
+// mergeChecks adds the per-check counters of from to into, by check index
+func mergeChecks(into, from []model.CheckMetrics) []model.CheckMetrics {
+	for i, check := range from {
+		if i == len(into) {
+			into = append(into, model.CheckMetrics{Name: check.Name})
+		}
+		into[i].Passes += check.Passes
+		into[i].Fails += check.Fails
+		into[i].SetPassRate()
+	}
+	return into
+}
+
+// mergeRetry adds the retry counters of from to into
+func mergeRetry(into, from *model.RetryMetrics) *model.RetryMetrics {
+	if from == nil {
+		return into
+	}
+	if into == nil {
+		into = &model.RetryMetrics{}
+	}
+	into.Requests += from.Requests
+	into.Retries += from.Retries
+	into.RetriedRequests += from.RetriedRequests
+	into.RecoveredRequests += from.RecoveredRequests
+	into.ExhaustedRequests += from.ExhaustedRequests
+	return into
+}
+
+// mergeWebSocket adds the WebSocket session counters of from to into
+func mergeWebSocket(into, from *model.WebSocketMetrics) *model.WebSocketMetrics {
+	if from == nil {
+		return into
+	}
+	if into == nil {
+		into = &model.WebSocketMetrics{}
+	}
+	into.Sessions += from.Sessions
+	into.Connections += from.Connections
+	into.MessagesSent += from.MessagesSent
+	into.MessagesReceived += from.MessagesReceived
+	into.ReplyTimeouts += from.ReplyTimeouts
+	for code, n := range from.CloseCodes {
+		if into.CloseCodes == nil {
+			into.CloseCodes = make(map[int]int64)
+		}
+		into.CloseCodes[code] += n
+	}
+	return into
+}
+
+// mergeStream adds the stream event counters of from to into
+func mergeStream(into, from *model.StreamMetrics) *model.StreamMetrics {
+	if from == nil {
+		return into
+	}
+	if into == nil {
+		into = &model.StreamMetrics{}
+	}
+	into.Streams += from.Streams
+	into.Events += from.Events
+	into.EmptyStreams += from.EmptyStreams
+	into.CutStreams += from.CutStreams
+	into.EventCheckFailures += from.EventCheckFailures
+	into.MaxEventsPerStream = max(into.MaxEventsPerStream, from.MaxEventsPerStream)
+	into.SetEventsPerStream()
+	return into
+}
+
+// mergeSocket adds the TCP and UDP exchange counters of from to into
+func mergeSocket(into, from *model.SocketMetrics) *model.SocketMetrics {
+	if from == nil {
+		return into
+	}
+	if into == nil {
+		into = &model.SocketMetrics{}
+	}
+	into.Exchanges += from.Exchanges
+	into.Connections += from.Connections
+	into.BytesSent += from.BytesSent
+	into.BytesReceived += from.BytesReceived
+	into.ResponseTimeouts += from.ResponseTimeouts
+	return into
+}
+
+// mergeDNS adds the DNS query counters of from to into
+func mergeDNS(into, from *model.DNSMetrics) *model.DNSMetrics {
+	if from == nil {
+		return into
+	}
+	if into == nil {
+		into = &model.DNSMetrics{}
+	}
+	into.Queries += from.Queries
+	for code, n := range from.ResponseCodes {
+		if into.ResponseCodes == nil {
+			into.ResponseCodes = make(map[string]int64)
+		}
+		into.ResponseCodes[code] += n
+	}
+	into.Answers += from.Answers
+	into.EmptyAnswers += from.EmptyAnswers
+	into.Truncated += from.Truncated
+	return into
+}
+
+// mergeSQL adds the SQL statement counters of from to into
+func mergeSQL(into, from *model.SQLMetrics) *model.SQLMetrics {
+	if from == nil {
+		return into
+	}
+	if into == nil {
+		into = &model.SQLMetrics{}
+	}
+	into.Queries += from.Queries
+	into.RowsReturned += from.RowsReturned
+	into.RowsAffected += from.RowsAffected
+	for class, n := range from.Errors {
+		if into.Errors == nil {
+			into.Errors = make(map[string]int64)
+		}
+		into.Errors[class] += n
+	}
+	return into
+}
+
+// mergeRedis adds the Redis send counters of from to into
+func mergeRedis(into, from *model.RedisMetrics) *model.RedisMetrics {
+	if from == nil {
+		return into
+	}
+	if into == nil {
+		into = &model.RedisMetrics{}
+	}
+	into.Sends += from.Sends
+	into.Commands += from.Commands
+	into.NilReplies += from.NilReplies
+	for prefix, n := range from.ErrorReplies {
+		if into.ErrorReplies == nil {
+			into.ErrorReplies = make(map[string]int64)
+		}
+		into.ErrorReplies[prefix] += n
+	}
+	into.Connections += from.Connections
+	return into
+}
+
+// mergeRequests adds the per-request counters of from to into, by request index
+func mergeRequests(into, from []model.RequestMetrics) []model.RequestMetrics {
+	for i, r := range from {
+		if i == len(into) {
+			into = append(into, model.RequestMetrics{
+				Name:        r.Name,
+				StatusCodes: make(map[int]int64),
+				Errors:      make(map[string]int64),
+			})
+		}
+		into[i].Requests += r.Requests
+		into[i].Success += r.Success
+		into[i].Failed += r.Failed
+		for code, n := range r.StatusCodes {
+			into[i].StatusCodes[code] += n
+		}
+		for class, n := range r.Errors {
+			into[i].Errors[class] += n
+		}
+	}
+	return into
+}
+
+// setBreakdownLatencies reads the per-step and per-request latency stats
+// from the merged breakdown histograms
+func setBreakdownLatencies(m *merged) {
+	for i := range m.metrics.Steps {
+		if i >= len(m.steps) {
+			break
+		}
+		step, hist := &m.metrics.Steps[i], m.steps[i]
+		step.AvgLatencyMs = hist.MeanMs()
+		step.P50LatencyMs = hist.ValueAtPercentile(50)
+		step.P95LatencyMs = hist.ValueAtPercentile(95)
+		step.P99LatencyMs = hist.ValueAtPercentile(99)
+		step.MaxLatencyMs = hist.MaxMs()
+	}
+	for i := range m.metrics.RequestBreakdown {
+		if i >= len(m.requests) {
+			break
+		}
+		r, hist := &m.metrics.RequestBreakdown[i], m.requests[i]
+		r.AvgLatencyMs = hist.MeanMs()
+		r.P50LatencyMs = hist.ValueAtPercentile(50)
+		r.P95LatencyMs = hist.ValueAtPercentile(95)
+		r.P99LatencyMs = hist.ValueAtPercentile(99)
+		r.MaxLatencyMs = hist.MaxMs()
+	}
+}
+
+// agentMetrics summarises every share of a run
+func agentMetrics(shares []*share) []model.AgentMetrics {
+	agents := make([]model.AgentMetrics, len(shares))
+	for i, sh := range shares {
+		agent := model.AgentMetrics{
+			AgentID:   sh.agentID,
+			Name:      sh.name,
+			Status:    model.AgentStatusBusy,
+			Users:     sh.plan.Users,
+			TargetRPS: sh.plan.TargetRPS,
+		}
+		switch {
+		case sh.lost:
+			agent.Status = model.AgentStatusLost
+		case sh.done:
+			agent.Status = model.AgentStatusIdle
+		}
+		if sh.report != nil && sh.report.Metrics != nil {
+			agent.TotalRequests = sh.report.Metrics.TotalRequests
+			agent.FailedRequests = sh.report.Metrics.FailedRequests
+		}
+		agents[i] = agent
+	}
+	return agents
+}
@@ -0,0 +1,169 @@
+package distributed
+
+import (
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// shareCount returns how many of the available agents a plan can be spread
+// over. Every share needs at least one VU, and a fixed-rate plan at least one
+// request per second, since a share with a target rate of 0 would run unlimited.
+func shareCount(plan *model.TestPlan, agents int) int {
+	n := agents
+	if plan.Users < n {
+		n = plan.Users
+	}
+	if plan.TargetRPS > 0 && plan.TargetRPS < n {
+		n = plan.TargetRPS
+	}
+	return n
+}
+
+// splitPlan divides a plan's load between len(weights) agents in proportion
+// to their weights. VUs and rates are split; durations, timings and targets
+// are shared. The shares add up to the plan's totals.
+func splitPlan(plan *model.TestPlan, weights []int) []*model.TestPlan {
+	n := len(weights)
+	users := splitAtLeastOne(plan.Users, weights)
+	maxVUs := splitAtLeastOne(plan.MaxVUs, weights)
+	targetRPS := splitAtLeastOne(plan.TargetRPS, weights)
+
+	shares := make([]*model.TestPlan, n)
+	for i := range shares {
+		share := *plan
+		share.Users = users[i]
+		share.TargetRPS = targetRPS[i]
+		if plan.MaxVUs > 0 {
+			share.MaxVUs = maxVUs[i]
+			if share.MaxVUs < share.Users {
+				share.MaxVUs = share.Users
+			}
+		}
+		share.RateSteps = nil
+		share.Stages = nil
+		share.Wave = nil
+		shares[i] = &share
+	}
+
+	if len(plan.RateSteps) > 0 {
+		for _, share := range shares {
+			share.RateSteps = make([]model.RateStep, len(plan.RateSteps))
+		}
+		for j, step := range plan.RateSteps {
+			rps := split(step.RPS, weights)
+			for i, share := range shares {
+				share.RateSteps[j] = model.RateStep{RPS: rps[i], DurationSec: step.DurationSec}
+			}
+		}
+	}
+
+	if len(plan.Stages) > 0 {
+		for _, share := range shares {
+			share.Stages = make([]model.Stage, len(plan.Stages))
+		}
+		for j, stage := range plan.Stages {
+			rps := splitTarget(stage.TargetRPS, weights)
+			vus := splitTarget(stage.TargetVUs, weights)
+			for i, share := range shares {
+				share.Stages[j] = model.Stage{DurationSec: stage.DurationSec, TargetRPS: rps[i], TargetVUs: vus[i]}
+			}
+		}
+	}
+
+	if plan.Wave != nil {
+		minRPS := split(plan.Wave.MinRPS, weights)
+		maxRPS := split(plan.Wave.MaxRPS, weights)
+		for i, share := range shares {
+			share.Wave = &model.WaveConfig{MinRPS: minRPS[i], MaxRPS: maxRPS[i], PeriodSec: plan.Wave.PeriodSec}
+		}
+	}
+
+	return shares
+}
+
+// splitRows divides data set rows between agents in proportion to their
+// weights. Only feeds that must not repeat a row across VUs are divided;
+// otherwise every agent gets every row.
+func splitRows(rows []map[string]string, feed *model.DataFeed, weights []int) [][]map[string]string {
+	shares := make([][]map[string]string, len(weights))
+	if feed == nil || (feed.Strategy != model.FeedOnce && feed.Strategy != model.FeedUnique) || len(rows) < len(weights) {
+		for i := range shares {
+			shares[i] = rows
+		}
+		return shares
+	}
+
+	start := 0
+	for i, n := range splitAtLeastOne(len(rows), weights) {
+		shares[i] = rows[start : start+n]
+		start += n
+	}
+	return shares
+}
+
+// splitTarget splits an optional stage target, keeping nil targets nil
+func splitTarget(target *int, weights []int) []*int {
+	targets := make([]*int, len(weights))
+	if target == nil {
+		return targets
+	}
+	for i, v := range split(*target, weights) {
+		v := v
+		targets[i] = &v
+	}
+	return targets
+}
+
+// splitAtLeastOne splits total like split, but gives every share at least 1
+// when total allows it. A total of 0 stays 0 everywhere.
+func splitAtLeastOne(total int, weights []int) []int {
+	if total < len(weights) {
+		return split(total, weights)
+	}
+	parts := split(total-len(weights), weights)
+	for i := range parts {
+		parts[i]++
+	}
+	return parts
+}
+
+// split divides total in proportion to weights using the largest remainder
+// method, so the parts always add up to total
+func split(total int, weights []int) []int {
+	parts := make([]int, len(weights))
+	if total <= 0 || len(weights) == 0 {
+		return parts
+	}
+
+	sum := 0
+	for _, w := range weights {
+		sum += w
+	}
+	if sum <= 0 {
+		weights = make([]int, len(weights))
+		for i := range weights {
+			weights[i] = 1
+		}
+		sum = len(weights)
+	}
+
+	assigned := 0
+	remainders := make([]int, len(weights))
+	for i, w := range weights {
+		parts[i] = total * w / sum
+		remainders[i] = total * w % sum
+		assigned += parts[i]
+	}
+
+	// Hand out what is left to the largest remainders, earliest first on ties
+	for ; assigned < total; assigned++ {
+		best := 0
+		for i := range remainders {
+			if remainders[i] > remainders[best] {
+				best = i
+			}
+		}
+		parts[best]++
+		remainders[best] = -1
+	}
+	return parts
+}
@@ -0,0 +1,126 @@
+package distributed
+
+import (
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		weights []int
+		want    []int
+	}{
+		{"even", 10, []int{1, 1}, []int{5, 5}},
+		{"proportional", 100, []int{1, 3}, []int{25, 75}},
+		{"largest remainder", 10, []int{1, 1, 1}, []int{4, 3, 3}},
+		{"zero total", 0, []int{2, 1}, []int{0, 0}},
+		{"zero weights", 4, []int{0, 0}, []int{2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := split(tt.total, tt.weights)
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("split(%d, %v) = %v, want %v", tt.total, tt.weights, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitPlan(t *testing.T) {
+	five := 5
+	plan := &model.TestPlan{
+		ID:          "plan-1",
+		Users:       10,
+		MaxVUs:      20,
+		TargetRPS:   101,
+		DurationSec: 60,
+		RateSteps:   []model.RateStep{{RPS: 10, DurationSec: 5}, {RPS: 40, DurationSec: 5}},
+		Stages:      []model.Stage{{DurationSec: 10, TargetVUs: &five}, {DurationSec: 10}},
+		Wave:        &model.WaveConfig{MinRPS: 4, MaxRPS: 8, PeriodSec: 30},
+	}
+	weights := []int{1, 3}
+
+	shares := splitPlan(plan, weights)
+	if len(shares) != 2 {
+		t.Fatalf("Expected 2 shares, got %d", len(shares))
+	}
+
+	var users, maxVUs, rps, step2, stageVUs, waveMax int
+	for _, share := range shares {
+		users += share.Users
+		maxVUs += share.MaxVUs
+		rps += share.TargetRPS
+		step2 += share.RateSteps[1].RPS
+		stageVUs += *share.Stages[0].TargetVUs
+		waveMax += share.Wave.MaxRPS
+
+		if share.DurationSec != plan.DurationSec || share.ID != plan.ID {
+			t.Errorf("Expected the share to keep the plan's duration and ID, got %+v", share)
+		}
+		if share.Stages[1].TargetVUs != nil || share.Stages[1].TargetRPS != nil {
+			t.Error("Expected unset stage targets to stay unset")
+		}
+	}
+	if users != 10 || maxVUs != 20 || rps != 101 || step2 != 40 || stageVUs != 5 || waveMax != 8 {
+		t.Errorf("Expected shares to add up to the plan, got users=%d max_vus=%d rps=%d step=%d stage_vus=%d wave_max=%d",
+			users, maxVUs, rps, step2, stageVUs, waveMax)
+	}
+	if shares[1].TargetRPS <= shares[0].TargetRPS {
+		t.Errorf("Expected the heavier agent to get more load, got %d and %d", shares[0].TargetRPS, shares[1].TargetRPS)
+	}
+
+	// The plan itself is left untouched
+	if plan.Users != 10 || plan.RateSteps[1].RPS != 40 || *plan.Stages[0].TargetVUs != 5 {
+		t.Error("Expected splitting not to modify the plan")
+	}
+}
+
+func TestSplitPlanGivesEveryShareLoad(t *testing.T) {
+	plan := &model.TestPlan{Users: 3, TargetRPS: 3, DurationSec: 10}
+
+	shares := splitPlan(plan, []int{1, 1, 100})
+	for i, share := range shares {
+		if share.Users < 1 || share.TargetRPS < 1 {
+			t.Errorf("Share %d: expected at least 1 VU and 1 RPS, got %d and %d", i, share.Users, share.TargetRPS)
+		}
+	}
+}
+
+func TestShareCount(t *testing.T) {
+	if n := shareCount(&model.TestPlan{Users: 2}, 5); n != 2 {
+		t.Errorf("Expected users to cap the share count at 2, got %d", n)
+	}
+	if n := shareCount(&model.TestPlan{Users: 10, TargetRPS: 3}, 5); n != 3 {
+		t.Errorf("Expected the target rate to cap the share count at 3, got %d", n)
+	}
+	if n := shareCount(&model.TestPlan{Users: 10}, 4); n != 4 {
+		t.Errorf("Expected every agent to get a share, got %d", n)
+	}
+}
+
+func TestSplitRows(t *testing.T) {
+	rows := make([]map[string]string, 10)
+	for i := range rows {
+		rows[i] = map[string]string{"n": string(rune('a' + i))}
+	}
+	weights := []int{1, 1}
+
+	unique := splitRows(rows, &model.DataFeed{Strategy: model.FeedUnique}, weights)
+	if len(unique[0])+len(unique[1]) != 10 || len(unique[0]) != 5 {
+		t.Errorf("Expected unique rows to be divided 5/5, got %d/%d", len(unique[0]), len(unique[1]))
+	}
+	if unique[0][4]["n"] == unique[1][0]["n"] {
+		t.Error("Expected no row in both shares")
+	}
+
+	sequential := splitRows(rows, &model.DataFeed{Strategy: model.FeedSequential}, weights)
+	if len(sequential[0]) != 10 || len(sequential[1]) != 10 {
+		t.Errorf("Expected every agent to get every sequential row, got %d/%d", len(sequential[0]), len(sequential[1]))
+	}
+}
@@ -13,6 +13,9 @@ var (
 	// ErrAlreadyRunning indicates a test is already running
 	ErrAlreadyRunning = errors.New("test is already running")
 
+	// ErrNotRunning indicates a test is not running
+	ErrNotRunning = errors.New("test is not running")
+
 	// ErrInvalidInput indicates invalid input parameters
 	ErrInvalidInput = errors.New("invalid input")
 
@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+// AgentStatus is the state of a load generating agent
+type AgentStatus string
+
+const (
+	AgentStatusIdle AgentStatus = "idle" // Registered and waiting for work
+	AgentStatusBusy AgentStatus = "busy" // Running a share of at least one test
+	AgentStatusLost AgentStatus = "lost" // Missed its heartbeats
+)
+
+// Agent is a load generator process registered with the controller
+type Agent struct {
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	Capacity     int         `json:"capacity"` // Relative share of load the agent takes, e.g. its CPU count
+	Status       AgentStatus `json:"status"`
+	Runs         []string    `json:"runs,omitempty"` // Runs the agent has an active share of
+	RegisteredAt time.Time   `json:"registered_at"`
+	LastSeen     time.Time   `json:"last_seen"`
+}
+
+// RegisterAgentRequest represents the request an agent sends when it starts
+type RegisterAgentRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Capacity int    `json:"capacity,omitempty"` // Defaults to 1
+}
+
+// RegisterAgentResponse tells a newly registered agent its ID and how often to report
+type RegisterAgentResponse struct {
+	Agent               *Agent `json:"agent"`
+	HeartbeatIntervalMs int    `json:"heartbeat_interval_ms"`
+}
+
+// AgentAssignment hands an agent its share of a distributed run. The plan's
+// resolved scenario, data set, TLS material, credentials, body files and
+// proto files are
+// not part of its JSON encoding, so they are sent alongside it.
+type AgentAssignment struct {
+	RunID       string              `json:"run_id"`
+	Plan        *TestPlan           `json:"plan"`
+	Scenario    *Scenario           `json:"scenario,omitempty"`
+	DataSet     *DataSet            `json:"data_set,omitempty"`
+	Rows        []map[string]string `json:"rows,omitempty"`        // The data set rows of this share
+	TLS         *TLSMaterial        `json:"tls,omitempty"`         // Certificates of the plan's TLS secrets
+	Credentials *Credentials        `json:"credentials,omitempty"` // Username and password of the plan's credentials secret
+	BodyFiles   BodyFiles           `json:"body_files,omitempty"`  // Body files of the plan's body sources, by ID
+	Protos      ProtoSources        `json:"protos,omitempty"`      // Proto files of the plan's gRPC calls, by import path
+}
+
+// AgentControl forwards a live control command for one run to an agent
+type AgentControl struct {
+	RunID   string         `json:"run_id"`
+	Command ControlCommand `json:"command"`
+}
+
+// AgentRunReport carries an agent's metrics for its share of a run. The
+// histograms are cumulative, so the controller only keeps the latest report.
+type AgentRunReport struct {
+	RunID              string             `json:"run_id"`
+	Metrics            *Metrics           `json:"metrics"`
+	LatencyHistogram   *Histogram         `json:"latency_histogram,omitempty"`
+	ResponseHistogram  *Histogram         `json:"response_histogram,omitempty"`
+	StepHistograms     []*Histogram       `json:"step_histograms,omitempty"`
+	RequestHistograms  []*Histogram       `json:"request_histograms,omitempty"`
+	PhaseHistograms    []*Histogram       `json:"phase_histograms,omitempty"`
+	ProtocolHistograms ProtocolHistograms `json:"protocol_histograms,omitempty"` // By metrics section, for plans that use one
+	Done               bool               `json:"done"`                          // The share has finished; Metrics are final
+}
+
+// AgentHeartbeat is sent by an agent periodically with the state of its runs
+type AgentHeartbeat struct {
+	Runs []AgentRunReport `json:"runs,omitempty"`
+}
+
+// AgentHeartbeatResponse hands an agent the work queued for it since its last heartbeat
+type AgentHeartbeatResponse struct {
+	Assignments []AgentAssignment `json:"assignments,omitempty"`
+	Controls    []AgentControl    `json:"controls,omitempty"`
+	Stop        []string          `json:"stop,omitempty"` // Runs to stop
+}
+
+// AgentMetrics summarises one agent's share of a distributed run
+type AgentMetrics struct {
+	AgentID        string      `json:"agent_id"`
+	Name           string      `json:"name"`
+	Status         AgentStatus `json:"status"` // busy while running, idle once done, lost if it stopped reporting
+	Users          int         `json:"users"`
+	TargetRPS      int         `json:"target_rps,omitempty"`
+	TotalRequests  int64       `json:"total_requests"`
+	FailedRequests int64       `json:"failed_requests"`
+}
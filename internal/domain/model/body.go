@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// BodyFile is an uploaded file sent as a request body or as a multipart file
+// part. Its content is never returned by the API.
+type BodyFile struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	Content     []byte    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BodyFileData is the content of a body file a plan references, resolved
+// when a run starts
+type BodyFileData struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// BodyFiles holds the resolved body files of a run, by ID
+type BodyFiles map[string]*BodyFileData
+
+// BodySourceType defines how a request body is built
+type BodySourceType string
+
+const (
+	BodySourceBase64    BodySourceType = "base64"    // Binary content inline, base64 encoded
+	BodySourceFile      BodySourceType = "file"      // An uploaded body file
+	BodySourceMultipart BodySourceType = "multipart" // multipart/form-data from fields and file parts
+	BodySourceForm      BodySourceType = "form"      // application/x-www-form-urlencoded from fields
+)
+
+// BodySource builds a request body a plain string cannot hold, such as a
+// file upload or a protobuf payload. It replaces the request's body.
+type BodySource struct {
+	Type        BodySourceType `json:"type"`
+	Base64      string         `json:"base64,omitempty"`       // base64 only
+	FileID      string         `json:"file_id,omitempty"`      // file only
+	ContentType string         `json:"content_type,omitempty"` // base64 and file, default: the file's type or application/octet-stream
+	Fields      []FormField    `json:"fields,omitempty"`       // multipart and form
+	Files       []FilePart     `json:"files,omitempty"`        // multipart only
+}
+
+// FormField is a field of a multipart or urlencoded form. Its value supports
+// {{data.column}}, scenario variables and template functions.
+type FormField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// FilePart is a file part of a multipart form, its content an uploaded body file
+type FilePart struct {
+	Field       string `json:"field"`
+	FileID      string `json:"file_id"`
+	Filename    string `json:"filename,omitempty"`     // Default: the file's name; supports templates
+	ContentType string `json:"content_type,omitempty"` // Default: the file's type
+}
+
+// FileIDs returns the body files a body source references
+func (b *BodySource) FileIDs() []string {
+	if b == nil {
+		return nil
+	}
+	var ids []string
+	if b.Type == BodySourceFile {
+		ids = append(ids, b.FileID)
+	}
+	for _, part := range b.Files {
+		ids = append(ids, part.FileID)
+	}
+	return ids
+}
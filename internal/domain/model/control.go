@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// ControlAction is a live adjustment of a running test
+type ControlAction string
+
+const (
+	ControlPause          ControlAction = "pause"           // Stop releasing new iterations
+	ControlResume         ControlAction = "resume"          // Release iterations again after a pause
+	ControlSetRPS         ControlAction = "set_rps"         // Replace the rate pattern with a fixed rate of Value, 0 to stop releasing iterations
+	ControlAddVUs         ControlAction = "add_vus"         // Start Value more VUs
+	ControlRemoveVUs      ControlAction = "remove_vus"      // Stop Value VUs once their current iteration ends
+	ControlExtendDuration ControlAction = "extend_duration" // Move the end of the test by Value seconds; negative shortens it
+)
+
+// ControlCommand asks a running test to change its load
+type ControlCommand struct {
+	Action ControlAction `json:"action" binding:"required"`
+	Value  int           `json:"value,omitempty"`
+}
+
+// ControlEvent records a control command applied to a test run
+type ControlEvent struct {
+	Action ControlAction `json:"action"`
+	Value  int           `json:"value,omitempty"`
+	At     time.Time     `json:"at"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// DataSet is an uploaded table of values used to parameterize requests.
+// Rows are referenced from templates as {{data.column}}.
+type DataSet struct {
+	ID        string              `json:"id"`
+	Name      string              `json:"name"`
+	Format    DataSetFormat       `json:"format"`
+	Columns   []string            `json:"columns"`
+	RowCount  int                 `json:"row_count"`
+	Rows      []map[string]string `json:"-"` // Not returned by the API, data sets can be large
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// DataSetFormat is the file format a data set was uploaded in
+type DataSetFormat string
+
+const (
+	DataSetFormatCSV   DataSetFormat = "csv"
+	DataSetFormatJSONL DataSetFormat = "jsonl"
+)
+
+// FeedStrategy defines how virtual users pick rows from a data set
+type FeedStrategy string
+
+const (
+	FeedSequential FeedStrategy = "sequential" // Rows in order, shared across VUs, wrapping around
+	FeedRandom     FeedStrategy = "random"     // A random row per iteration
+	FeedUnique     FeedStrategy = "unique"     // One fixed row per VU, e.g. per-user credentials
+	FeedOnce       FeedStrategy = "once"       // Each row used once, then OnExhausted applies
+)
+
+// Behaviours of the once strategy after every row has been used
+const (
+	FeedExhaustedStop    = "stop"
+	FeedExhaustedRecycle = "recycle"
+)
+
+// DataFeed attaches a data set to a test plan or scenario
+type DataFeed struct {
+	DataSetID   string       `json:"data_set_id" binding:"required"`
+	Strategy    FeedStrategy `json:"strategy,omitempty"`     // Defaults to sequential
+	OnExhausted string       `json:"on_exhausted,omitempty"` // once strategy only: stop (default) or recycle
+}
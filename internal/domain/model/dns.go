@@ -0,0 +1,23 @@
+package model
+
+// DNSRecordType is the record type a DNS query asks for
+type DNSRecordType string
+
+const (
+	DNSTypeA    DNSRecordType = "A"
+	DNSTypeAAAA DNSRecordType = "AAAA"
+	DNSTypeSRV  DNSRecordType = "SRV"
+	DNSTypeTXT  DNSRecordType = "TXT"
+)
+
+// DNSRequest is a DNS query a plan sends to a resolver instead of an HTTP
+// request. The query counts as one request, whose latency is the time until
+// the answer arrived, and whose response code (NOERROR, NXDOMAIN,
+// SERVFAIL...) takes the place of an HTTP status code.
+type DNSRequest struct {
+	Server      string        `json:"server"`                 // Resolver as host:port, or host for port 53; supports templates
+	Name        string        `json:"name"`                   // Name to resolve, supports templates, e.g. {{uuid}}.example.com for cache misses
+	Type        DNSRecordType `json:"type,omitempty"`         // Default: A
+	Network     SocketNetwork `json:"network,omitempty"`      // udp or tcp, default: udp
+	NoRecursion bool          `json:"no_recursion,omitempty"` // Clear the recursion desired flag, e.g. to query an authoritative server
+}
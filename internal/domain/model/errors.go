@@ -0,0 +1,182 @@
+package model
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// Error classes failed requests are counted under in Metrics.Errors. Raw
+// error messages embed addresses, ports and URLs, so counting by message
+// would produce a key per target and connection.
+const (
+	ErrorClassTimeout           = "timeout"            // Request, dial or handshake deadline exceeded
+	ErrorClassConnectionRefused = "connection_refused" // Nothing listening on the target port
+	ErrorClassConnectionReset   = "connection_reset"   // Connection reset or closed by the peer while writing
+	ErrorClassDNS               = "dns"                // Host name lookup failed
+	ErrorClassTLS               = "tls"                // TLS handshake or certificate verification failed
+	ErrorClassEOF               = "eof"                // Connection closed before a complete response
+	ErrorClassHTTP4xx           = "http_4xx"           // Response with a 4xx status
+	ErrorClassHTTP5xx           = "http_5xx"           // Response with a 5xx status
+	ErrorClassGRPC              = "grpc_status"        // gRPC call ended with a non-OK status
+	ErrorClassDNSRcode          = "dns_rcode"          // DNS query answered with a response code other than NOERROR
+	ErrorClassSQL               = "sql_error"          // SQL statement rejected by the database, e.g. a constraint violation
+	ErrorClassRedis             = "redis_error"        // Redis command answered with an error reply, e.g. WRONGTYPE
+	ErrorClassWebSocketClose    = "websocket_close"    // WebSocket connection closed by the server mid-session
+	ErrorClassCheckFailed       = "check_failed"       // Response failed a plan check or step assertion
+	ErrorClassCanceled          = "canceled"           // Request aborted because the run stopped
+	ErrorClassOther             = "other"              // Anything else, e.g. an invalid request URL
+)
+
+// ErrorClasses lists every error class in report order
+var ErrorClasses = []string{
+	ErrorClassTimeout, ErrorClassConnectionRefused, ErrorClassConnectionReset, ErrorClassDNS,
+	ErrorClassTLS, ErrorClassEOF, ErrorClassHTTP4xx, ErrorClassHTTP5xx, ErrorClassGRPC,
+	ErrorClassDNSRcode, ErrorClassSQL, ErrorClassRedis, ErrorClassWebSocketClose, ErrorClassCheckFailed, ErrorClassCanceled, ErrorClassOther,
+}
+
+// MaxErrorSamples is the number of distinct raw messages kept per error class
+const MaxErrorSamples = 5
+
+// ErrCheckFailed is wrapped by the errors of responses that failed a check
+var ErrCheckFailed = errors.New("response check failed")
+
+// ErrGRPCStatus is wrapped by the errors of gRPC calls that ended with a
+// non-OK status other than a deadline or cancellation
+var ErrGRPCStatus = errors.New("grpc status")
+
+// ErrDNSRcode is wrapped by the errors of DNS queries answered with a
+// response code other than NOERROR
+var ErrDNSRcode = errors.New("dns response code")
+
+// ErrSQL is wrapped by the errors of SQL statements the database rejected
+var ErrSQL = errors.New("sql error")
+
+// ErrRedis is wrapped by the errors of Redis commands answered with an
+// error reply
+var ErrRedis = errors.New("redis error")
+
+// ErrWebSocketClosed is wrapped by the errors of WebSocket sessions the
+// server closed before they were done
+var ErrWebSocketClosed = errors.New("websocket closed")
+
+// ClassifyError returns the error class of a failed request from its error,
+// or from its status code if the request got a response and no error
+func ClassifyError(err error, statusCode int) string {
+	if err == nil {
+		switch {
+		case statusCode >= 500:
+			return ErrorClassHTTP5xx
+		case statusCode >= 400:
+			return ErrorClassHTTP4xx
+		}
+		return ErrorClassOther
+	}
+
+	var (
+		netErr net.Error
+		dnsErr *net.DNSError
+	)
+	switch {
+	case errors.Is(err, ErrCheckFailed):
+		return ErrorClassCheckFailed
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	case errors.Is(err, ErrGRPCStatus):
+		return ErrorClassGRPC
+	case errors.Is(err, ErrDNSRcode):
+		return ErrorClassDNSRcode
+	case errors.Is(err, ErrSQL):
+		return ErrorClassSQL
+	case errors.Is(err, ErrRedis):
+		return ErrorClassRedis
+	case errors.Is(err, ErrWebSocketClosed):
+		return ErrorClassWebSocketClose
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorClassConnectionRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return ErrorClassConnectionReset
+	case isTLSError(err):
+		return ErrorClassTLS
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrorClassEOF
+	}
+	return ErrorClassOther
+}
+
+// errorSample returns the message kept as a sample of a failed request
+func errorSample(err error, statusCode int) string {
+	if err != nil {
+		return err.Error()
+	}
+	if statusCode > 0 {
+		return fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode))
+	}
+	return "request failed"
+}
+
+// addErrorSample keeps msg as a sample of class unless the class already
+// has it or MaxErrorSamples others
+func addErrorSample(samples map[string][]string, class, msg string) {
+	kept := samples[class]
+	if len(kept) >= MaxErrorSamples {
+		return
+	}
+	for _, s := range kept {
+		if s == msg {
+			return
+		}
+	}
+	samples[class] = append(kept, msg)
+}
+
+// MergeErrorSamples adds the samples of src to dst, keeping at most
+// MaxErrorSamples per class, and returns dst
+func MergeErrorSamples(dst, src map[string][]string) map[string][]string {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string][]string, len(src))
+	}
+	for class, msgs := range src {
+		for _, msg := range msgs {
+			addErrorSample(dst, class, msg)
+		}
+	}
+	return dst
+}
+
+// isTLSError reports whether err comes from a failed TLS handshake, either
+// on this side (verification of the server certificate) or reported by the
+// server through an alert, e.g. for a missing client certificate
+func isTLSError(err error) bool {
+	var (
+		verifyErr   *tls.CertificateVerificationError
+		unknownAuth x509.UnknownAuthorityError
+		hostnameErr x509.HostnameError
+		invalidErr  x509.CertificateInvalidError
+		recordErr   tls.RecordHeaderError
+		alertErr    tls.AlertError
+	)
+	if errors.As(err, &verifyErr) || errors.As(err, &unknownAuth) || errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr) || errors.As(err, &recordErr) || errors.As(err, &alertErr) {
+		return true
+	}
+	// Alerts received from the server are wrapped as plain errors
+	msg := err.Error()
+	return strings.Contains(msg, "tls: ") || strings.Contains(msg, "x509: ")
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// GRPCRequest is a gRPC call a plan or scenario step sends instead of an
+// HTTP request. The service is described by uploaded .proto files or, when
+// the plan names none, by the server's reflection service. Unary and
+// server-streaming methods are supported.
+type GRPCRequest struct {
+	Target       string            `json:"target"`                   // Server address, host:port
+	Method       string            `json:"method"`                   // Full method name, package.Service/Method
+	ProtoFileIDs []string          `json:"proto_file_ids,omitempty"` // Proto files describing the service, default: server reflection
+	Message      string            `json:"message,omitempty"`        // Request message as JSON, supports templates, default: {}
+	Metadata     map[string]string `json:"metadata,omitempty"`       // Request metadata, values support templates
+	Plaintext    bool              `json:"plaintext,omitempty"`      // Connect without TLS; TLS connections use the plan's tls settings
+}
+
+// ProtoFile is an uploaded .proto file describing gRPC services. Its name is
+// the path other proto files import it by.
+type ProtoFile struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Services  []string  `json:"services"` // Full names of the services the file defines
+	Methods   []string  `json:"methods"`  // Full names of their methods, package.Service/Method
+	Size      int64     `json:"size"`
+	Content   string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ProtoSources holds the proto files a run's gRPC calls are described by,
+// by import path, with the files they import. Resolved when a run starts.
+type ProtoSources map[string]string
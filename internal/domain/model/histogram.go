@@ -0,0 +1,275 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// Histogram layout. Values are stored in microseconds in log-linear buckets:
+// each power-of-two range is split into 128 linear sub-buckets, which keeps
+// every recorded value within 1% of its true value (2 significant digits)
+// from 1µs up to histogramMaxMicros in a fixed 26KB of counters.
+const (
+	histogramSubBucketHalfMagnitude = 7
+	histogramSubBucketHalfCount     = 1 << histogramSubBucketHalfMagnitude
+	histogramSubBucketMask          = 2*histogramSubBucketHalfCount - 1
+	histogramMaxMicros              = int64(time.Hour / time.Microsecond)
+)
+
+var histogramCountsLen = countsIndex(histogramMaxMicros) + 1
+
+// Histogram is a high-dynamic-range histogram of durations with microsecond
+// resolution. Recording is lock-free and safe for concurrent use, so each
+// worker owns one and they are merged when percentiles are read.
+type Histogram struct {
+	counts     []int64
+	totalCount int64
+	sumMicros  int64
+	minMicros  int64
+	maxMicros  int64
+}
+
+// NewHistogram creates an empty histogram
+func NewHistogram() *Histogram {
+	return &Histogram{
+		counts:    make([]int64, histogramCountsLen),
+		minMicros: math.MaxInt64,
+	}
+}
+
+// countsIndex maps a value in microseconds to its bucket
+func countsIndex(v int64) int {
+	bucket := 64 - bits.LeadingZeros64(uint64(v)|histogramSubBucketMask) - (histogramSubBucketHalfMagnitude + 1)
+	subBucket := int(v >> uint(bucket))
+	return (bucket+1)<<histogramSubBucketHalfMagnitude + subBucket - histogramSubBucketHalfCount
+}
+
+// lowestEquivalentValue returns the smallest value that maps to index
+func lowestEquivalentValue(index int) int64 {
+	bucket := index>>histogramSubBucketHalfMagnitude - 1
+	subBucket := int64(index&(histogramSubBucketHalfCount-1) + histogramSubBucketHalfCount)
+	if bucket < 0 {
+		subBucket -= histogramSubBucketHalfCount
+		bucket = 0
+	}
+	return subBucket << uint(bucket)
+}
+
+// highestEquivalentValue returns the largest value that maps to index
+func highestEquivalentValue(index int) int64 {
+	if index+1 >= histogramCountsLen {
+		return histogramMaxMicros
+	}
+	return lowestEquivalentValue(index+1) - 1
+}
+
+// Record adds a duration to the histogram. Values above one hour are clamped.
+func (h *Histogram) Record(d time.Duration) {
+	h.RecordMicros(d.Microseconds())
+}
+
+// RecordMicros adds a value in microseconds to the histogram
+func (h *Histogram) RecordMicros(v int64) {
+	h.recordN(v, 1)
+}
+
+func (h *Histogram) recordN(v, n int64) {
+	if v < 0 {
+		v = 0
+	}
+	if v > histogramMaxMicros {
+		v = histogramMaxMicros
+	}
+
+	atomic.AddInt64(&h.counts[countsIndex(v)], n)
+	atomic.AddInt64(&h.totalCount, n)
+	atomic.AddInt64(&h.sumMicros, v*n)
+
+	for cur := atomic.LoadInt64(&h.minMicros); v < cur; cur = atomic.LoadInt64(&h.minMicros) {
+		if atomic.CompareAndSwapInt64(&h.minMicros, cur, v) {
+			break
+		}
+	}
+	for cur := atomic.LoadInt64(&h.maxMicros); v > cur; cur = atomic.LoadInt64(&h.maxMicros) {
+		if atomic.CompareAndSwapInt64(&h.maxMicros, cur, v) {
+			break
+		}
+	}
+}
+
+// Merge adds every value recorded in other to h. Other may still be
+// recording concurrently; values recorded during the merge may or may not
+// be included.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil {
+		return
+	}
+	for i := range other.counts {
+		if n := atomic.LoadInt64(&other.counts[i]); n > 0 {
+			atomic.AddInt64(&h.counts[i], n)
+			atomic.AddInt64(&h.totalCount, n)
+		}
+	}
+	atomic.AddInt64(&h.sumMicros, atomic.LoadInt64(&other.sumMicros))
+
+	if v := atomic.LoadInt64(&other.minMicros); v < atomic.LoadInt64(&h.minMicros) {
+		atomic.StoreInt64(&h.minMicros, v)
+	}
+	if v := atomic.LoadInt64(&other.maxMicros); v > atomic.LoadInt64(&h.maxMicros) {
+		atomic.StoreInt64(&h.maxMicros, v)
+	}
+}
+
+// Since returns a histogram of the values recorded in h after base was
+// taken, where base is an earlier merged copy of the same workers. Min and
+// max of the result are accurate to the bucket.
+func (h *Histogram) Since(base *Histogram) *Histogram {
+	out := NewHistogram()
+	lowest, highest := -1, -1
+	for i := range h.counts {
+		n := atomic.LoadInt64(&h.counts[i])
+		if base != nil {
+			n -= atomic.LoadInt64(&base.counts[i])
+		}
+		if n <= 0 {
+			continue
+		}
+		out.counts[i] = n
+		out.totalCount += n
+		if lowest < 0 {
+			lowest = i
+		}
+		highest = i
+	}
+	if out.totalCount == 0 {
+		return out
+	}
+
+	out.sumMicros = atomic.LoadInt64(&h.sumMicros)
+	if base != nil {
+		out.sumMicros -= atomic.LoadInt64(&base.sumMicros)
+	}
+	out.minMicros = lowestEquivalentValue(lowest)
+	out.maxMicros = highestEquivalentValue(highest)
+	if maxMicros := atomic.LoadInt64(&h.maxMicros); out.maxMicros > maxMicros {
+		out.maxMicros = maxMicros
+	}
+	return out
+}
+
+// TotalCount returns the number of recorded values
+func (h *Histogram) TotalCount() int64 {
+	return atomic.LoadInt64(&h.totalCount)
+}
+
+// MinMs returns the smallest recorded value in milliseconds
+func (h *Histogram) MinMs() float64 {
+	if h.TotalCount() == 0 {
+		return 0
+	}
+	return float64(atomic.LoadInt64(&h.minMicros)) / 1000.0
+}
+
+// MaxMs returns the largest recorded value in milliseconds
+func (h *Histogram) MaxMs() float64 {
+	return float64(atomic.LoadInt64(&h.maxMicros)) / 1000.0
+}
+
+// MeanMs returns the exact mean of recorded values in milliseconds
+func (h *Histogram) MeanMs() float64 {
+	count := h.TotalCount()
+	if count == 0 {
+		return 0
+	}
+	return float64(atomic.LoadInt64(&h.sumMicros)) / float64(count) / 1000.0
+}
+
+// ValueAtPercentile returns the value in milliseconds below which the given
+// percentage (0-100) of recorded values fall
+func (h *Histogram) ValueAtPercentile(percentile float64) float64 {
+	count := h.TotalCount()
+	if count == 0 {
+		return 0
+	}
+	if percentile > 100 {
+		percentile = 100
+	}
+
+	target := int64(math.Ceil(percentile / 100 * float64(count)))
+	if target < 1 {
+		target = 1
+	}
+
+	maxMicros := atomic.LoadInt64(&h.maxMicros)
+	var seen int64
+	for i := range h.counts {
+		seen += atomic.LoadInt64(&h.counts[i])
+		if seen >= target {
+			v := highestEquivalentValue(i)
+			if v > maxMicros {
+				v = maxMicros
+			}
+			return float64(v) / 1000.0
+		}
+	}
+	return float64(maxMicros) / 1000.0
+}
+
+// histogramJSON is the persisted form of a Histogram. Only non-empty buckets
+// are kept, as [lowest value in µs, count] pairs.
+type histogramJSON struct {
+	MinMicros int64      `json:"min_us"`
+	MaxMicros int64      `json:"max_us"`
+	SumMicros int64      `json:"sum_us"`
+	Buckets   [][2]int64 `json:"buckets"`
+}
+
+// MarshalJSON encodes the histogram sparsely so it can be stored with the
+// final metrics and re-queried at any percentile later
+func (h *Histogram) MarshalJSON() ([]byte, error) {
+	out := histogramJSON{
+		MinMicros: atomic.LoadInt64(&h.minMicros),
+		MaxMicros: atomic.LoadInt64(&h.maxMicros),
+		SumMicros: atomic.LoadInt64(&h.sumMicros),
+		Buckets:   make([][2]int64, 0),
+	}
+	if h.TotalCount() == 0 {
+		out.MinMicros = 0
+	}
+	for i := range h.counts {
+		if n := atomic.LoadInt64(&h.counts[i]); n > 0 {
+			out.Buckets = append(out.Buckets, [2]int64{lowestEquivalentValue(i), n})
+		}
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON restores a histogram encoded by MarshalJSON
+func (h *Histogram) UnmarshalJSON(data []byte) error {
+	var in histogramJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	*h = *NewHistogram()
+	for _, b := range in.Buckets {
+		v := b[0]
+		if v < 0 {
+			v = 0
+		}
+		if v > histogramMaxMicros {
+			v = histogramMaxMicros
+		}
+		h.counts[countsIndex(v)] += b[1]
+		h.totalCount += b[1]
+	}
+	h.sumMicros = in.SumMicros
+	h.maxMicros = in.MaxMicros
+	if h.totalCount > 0 {
+		h.minMicros = in.MinMicros
+	}
+	return nil
+}
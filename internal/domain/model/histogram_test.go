@@ -0,0 +1,290 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+)
+
+// referencePercentile returns the value at percentile p of sorted values,
+// using the same rank as Histogram.ValueAtPercentile
+func referencePercentile(sorted []int64, p float64) int64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// recordAll records values in microseconds into a new histogram
+func recordAll(values []int64) *Histogram {
+	h := NewHistogram()
+	for _, v := range values {
+		h.RecordMicros(v)
+	}
+	return h
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	tests := []struct {
+		value        int64
+		lowest       int64
+		highest      int64
+		exactBuckets bool // Values below 256µs each have a bucket of their own
+	}{
+		{0, 0, 0, true},
+		{1, 1, 1, true},
+		{255, 255, 255, true},
+		{256, 256, 257, false},
+		{257, 256, 257, false},
+		{1000, 1000, 1003, false},
+		{123456, 123392, 123903, false},
+		{histogramMaxMicros, 3590324224, histogramMaxMicros, false},
+	}
+
+	for _, tt := range tests {
+		index := countsIndex(tt.value)
+		if index < 0 || index >= histogramCountsLen {
+			t.Fatalf("countsIndex(%d) = %d, out of range", tt.value, index)
+		}
+		lowest, highest := lowestEquivalentValue(index), highestEquivalentValue(index)
+		if lowest != tt.lowest || highest != tt.highest {
+			t.Errorf("Bucket of %d = [%d, %d], expected [%d, %d]", tt.value, lowest, highest, tt.lowest, tt.highest)
+		}
+		if tt.exactBuckets && lowest != highest {
+			t.Errorf("Expected %d to have a bucket of its own", tt.value)
+		}
+		if countsIndex(lowest) != index || countsIndex(highest) != index {
+			t.Errorf("Expected the bounds of the bucket of %d to map back to it", tt.value)
+		}
+	}
+
+	// Every bucket is at most 1/128 of its lowest value wide
+	for index := histogramSubBucketHalfCount * 2; index < histogramCountsLen-1; index++ {
+		lowest, highest := lowestEquivalentValue(index), highestEquivalentValue(index)
+		if width := highest - lowest + 1; width*histogramSubBucketHalfCount > lowest {
+			t.Fatalf("Bucket %d = [%d, %d] is wider than 1%% of its values", index, lowest, highest)
+		}
+	}
+}
+
+func TestHistogramPercentiles(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name     string
+		generate func() int64
+	}{
+		{"constant", func() int64 { return 42_000 }},
+		{"uniform", func() int64 { return rng.Int63n(1_000_000) }},
+		{"exponential", func() int64 { return int64(rng.ExpFloat64() * 20_000) }},
+		{"sub-millisecond", func() int64 { return rng.Int63n(300) }},
+		{"long tail", func() int64 {
+			if rng.Intn(1000) == 0 {
+				return 5_000_000 + rng.Int63n(60_000_000)
+			}
+			return 1_000 + rng.Int63n(5_000)
+		}},
+	}
+	percentiles := []float64{0, 1, 25, 50, 75, 90, 95, 99, 99.9, 99.99, 100}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make([]int64, 20_000)
+			for i := range values {
+				values[i] = tt.generate()
+			}
+			h := recordAll(values)
+			slices.Sort(values)
+
+			for _, p := range percentiles {
+				want := referencePercentile(values, p)
+				got := int64(math.Round(h.ValueAtPercentile(p) * 1000))
+				// The bucket of the value may report up to its highest value
+				if got < want || float64(got-want) > float64(want)/histogramSubBucketHalfCount+1 {
+					t.Errorf("p%v = %dµs, expected %dµs within 1%%", p, got, want)
+				}
+			}
+
+			if h.TotalCount() != int64(len(values)) {
+				t.Errorf("Expected %d values, got %d", len(values), h.TotalCount())
+			}
+			if h.MinMs() != float64(values[0])/1000 || h.MaxMs() != float64(values[len(values)-1])/1000 {
+				t.Errorf("Expected min %d and max %d, got %v and %v", values[0], values[len(values)-1], h.MinMs(), h.MaxMs())
+			}
+			var sum int64
+			for _, v := range values {
+				sum += v
+			}
+			if mean := float64(sum) / float64(len(values)) / 1000; math.Abs(h.MeanMs()-mean) > 1e-9 {
+				t.Errorf("Expected an exact mean of %v, got %v", mean, h.MeanMs())
+			}
+		})
+	}
+
+	empty := NewHistogram()
+	if empty.ValueAtPercentile(50) != 0 || empty.MinMs() != 0 || empty.MaxMs() != 0 || empty.MeanMs() != 0 {
+		t.Error("Expected an empty histogram to report zeros")
+	}
+}
+
+func TestHistogramMergeAndSince(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	first, second := make([]int64, 5_000), make([]int64, 5_000)
+	for i := range first {
+		first[i] = 100 + rng.Int63n(10_000)
+		second[i] = 50_000 + rng.Int63n(500_000)
+	}
+
+	tests := []struct {
+		name  string
+		build func() *Histogram
+		want  []int64
+	}{
+		{"merge", func() *Histogram {
+			h := recordAll(first)
+			h.Merge(recordAll(second))
+			h.Merge(nil)
+			return h
+		}, append(slices.Clone(first), second...)},
+		{"merge into empty", func() *Histogram {
+			h := NewHistogram()
+			h.Merge(recordAll(first))
+			return h
+		}, first},
+		{"since a base", func() *Histogram {
+			h := recordAll(first)
+			base := NewHistogram()
+			base.Merge(h)
+			for _, v := range second {
+				h.RecordMicros(v)
+			}
+			return h.Since(base)
+		}, second},
+		{"since nothing", func() *Histogram {
+			return recordAll(first).Since(nil)
+		}, first},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.build()
+			want := recordAll(tt.want)
+			if h.TotalCount() != want.TotalCount() || h.MeanMs() != want.MeanMs() {
+				t.Errorf("Expected %d values with mean %v, got %d with mean %v",
+					want.TotalCount(), want.MeanMs(), h.TotalCount(), h.MeanMs())
+			}
+			for _, p := range []float64{50, 90, 99, 99.9} {
+				if h.ValueAtPercentile(p) != want.ValueAtPercentile(p) {
+					t.Errorf("p%v = %v, expected %v", p, h.ValueAtPercentile(p), want.ValueAtPercentile(p))
+				}
+			}
+			// Since only knows min and max to the bucket
+			minIndex, maxIndex := countsIndex(slices.Min(tt.want)), countsIndex(slices.Max(tt.want))
+			if countsIndex(int64(h.MinMs()*1000)) != minIndex || countsIndex(int64(h.MaxMs()*1000)) != maxIndex {
+				t.Errorf("Expected min %v and max %v to the bucket, got %v and %v",
+					want.MinMs(), want.MaxMs(), h.MinMs(), h.MaxMs())
+			}
+		})
+	}
+
+	h := recordAll(first)
+	base := NewHistogram()
+	base.Merge(h)
+	if since := h.Since(base); since.TotalCount() != 0 || since.MaxMs() != 0 {
+		t.Errorf("Expected nothing recorded since the base, got %d values", since.TotalCount())
+	}
+}
+
+func TestHistogramJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int64
+	}{
+		{"empty", nil},
+		{"single", []int64{1_234}},
+		{"spread", []int64{0, 1, 255, 256, 999, 10_000, 250_000, 3_000_000, histogramMaxMicros}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := recordAll(tt.values)
+			data, err := json.Marshal(h)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+			var decoded histogramJSON
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Failed to decode: %v", err)
+			}
+			if len(decoded.Buckets) != len(tt.values) {
+				t.Errorf("Expected %d sparse buckets, got %d", len(tt.values), len(decoded.Buckets))
+			}
+
+			restored := &Histogram{}
+			if err := json.Unmarshal(data, restored); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if restored.TotalCount() != h.TotalCount() || restored.MinMs() != h.MinMs() ||
+				restored.MaxMs() != h.MaxMs() || restored.MeanMs() != h.MeanMs() {
+				t.Errorf("Expected %d values in [%v, %v] with mean %v, got %d in [%v, %v] with mean %v",
+					h.TotalCount(), h.MinMs(), h.MaxMs(), h.MeanMs(),
+					restored.TotalCount(), restored.MinMs(), restored.MaxMs(), restored.MeanMs())
+			}
+			for _, p := range []float64{0, 50, 99, 100} {
+				if restored.ValueAtPercentile(p) != h.ValueAtPercentile(p) {
+					t.Errorf("p%v = %v after a round trip, expected %v", p, restored.ValueAtPercentile(p), h.ValueAtPercentile(p))
+				}
+			}
+			// The restored histogram keeps recording
+			restored.Record(time.Millisecond)
+			if restored.TotalCount() != h.TotalCount()+1 {
+				t.Errorf("Expected the restored histogram to record, got %d values", restored.TotalCount())
+			}
+		})
+	}
+}
+
+func TestHistogramClamping(t *testing.T) {
+	tests := []struct {
+		name   string
+		record func(h *Histogram)
+		minMs  float64
+		maxMs  float64
+	}{
+		{"negative", func(h *Histogram) { h.RecordMicros(-5) }, 0, 0},
+		{"negative duration", func(h *Histogram) { h.Record(-time.Second) }, 0, 0},
+		{"above an hour", func(h *Histogram) { h.Record(2 * time.Hour) }, 3_600_000, 3_600_000},
+		{"far above an hour", func(h *Histogram) { h.RecordMicros(math.MaxInt64) }, 3_600_000, 3_600_000},
+		{"both ends", func(h *Histogram) {
+			h.RecordMicros(-1)
+			h.Record(time.Hour + time.Minute)
+		}, 0, 3_600_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHistogram()
+			tt.record(h)
+			if h.MinMs() != tt.minMs || h.MaxMs() != tt.maxMs {
+				t.Errorf("Expected min %v and max %v, got %v and %v", tt.minMs, tt.maxMs, h.MinMs(), h.MaxMs())
+			}
+			if h.ValueAtPercentile(100) != tt.maxMs {
+				t.Errorf("Expected p100 = %v, got %v", tt.maxMs, h.ValueAtPercentile(100))
+			}
+		})
+	}
+
+	// Buckets out of range in stored JSON are clamped too
+	var h Histogram
+	data := `{"min_us":0,"max_us":3600000000,"sum_us":3600000000,"buckets":[[-10,1],[9000000000,2]]}`
+	if err := json.Unmarshal([]byte(data), &h); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if h.TotalCount() != 3 || h.ValueAtPercentile(1) != 0 || h.ValueAtPercentile(100) != 3_600_000 {
+		t.Errorf("Expected clamped buckets, got %d values, p1 %v, p100 %v",
+			h.TotalCount(), h.ValueAtPercentile(1), h.ValueAtPercentile(100))
+	}
+}
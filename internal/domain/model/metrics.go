@@ -5,32 +5,78 @@ import (
 	"time"
 )
 
-// Metrics holds the results of a test run
+// Metrics holds the results of a test run.
+// Latency fields measure service time, from the moment a request is actually
+// sent. Response fields measure from the moment it was scheduled to be sent,
+// so they include any queueing delay and are corrected for coordinated omission.
 type Metrics struct {
-	RunID           string           `json:"run_id"`
-	TotalRequests   int64            `json:"total_requests"`
-	SuccessRequests int64            `json:"success_requests"`
-	FailedRequests  int64            `json:"failed_requests"`
-	TotalDurationMs int64            `json:"total_duration_ms"`
-	MinLatencyMs    float64          `json:"min_latency_ms"`
-	MaxLatencyMs    float64          `json:"max_latency_ms"`
-	AvgLatencyMs    float64          `json:"avg_latency_ms"`
-	P50LatencyMs    float64          `json:"p50_latency_ms"`
-	P75LatencyMs    float64          `json:"p75_latency_ms"`
-	P95LatencyMs    float64          `json:"p95_latency_ms"`
-	P99LatencyMs    float64          `json:"p99_latency_ms"`
-	RequestsPerSec  float64          `json:"requests_per_sec"`
-	CurrentRPS      float64          `json:"current_rps"`
-	ActiveWorkers   int              `json:"active_workers"`
-	StatusCodes     map[int]int64    `json:"status_codes"`
-	Errors          map[string]int64 `json:"errors,omitempty"`
-	LastUpdated     time.Time        `json:"last_updated"`
-	StartTime       time.Time        `json:"-"` // For calculating live RPS
-	lastReqCount    int64            // Last request count for RPS calculation
-	lastRPSUpdate   time.Time        // Last time RPS was updated
-	Mu              sync.RWMutex     `json:"-"`
+	RunID              string                 `json:"run_id"`
+	TotalRequests      int64                  `json:"total_requests"`
+	SuccessRequests    int64                  `json:"success_requests"`
+	FailedRequests     int64                  `json:"failed_requests"`
+	TotalDurationMs    int64                  `json:"total_duration_ms"`
+	MinLatencyMs       float64                `json:"min_latency_ms"`
+	MaxLatencyMs       float64                `json:"max_latency_ms"`
+	AvgLatencyMs       float64                `json:"avg_latency_ms"`
+	P50LatencyMs       float64                `json:"p50_latency_ms"`
+	P75LatencyMs       float64                `json:"p75_latency_ms"`
+	P95LatencyMs       float64                `json:"p95_latency_ms"`
+	P99LatencyMs       float64                `json:"p99_latency_ms"`
+	P999LatencyMs      float64                `json:"p999_latency_ms"`
+	P9999LatencyMs     float64                `json:"p9999_latency_ms"`
+	MaxResponseMs      float64                `json:"max_response_ms"`
+	AvgResponseMs      float64                `json:"avg_response_ms"`
+	P50ResponseMs      float64                `json:"p50_response_ms"`
+	P75ResponseMs      float64                `json:"p75_response_ms"`
+	P95ResponseMs      float64                `json:"p95_response_ms"`
+	P99ResponseMs      float64                `json:"p99_response_ms"`
+	P999ResponseMs     float64                `json:"p999_response_ms"`
+	P9999ResponseMs    float64                `json:"p9999_response_ms"`
+	RequestsPerSec     float64                `json:"requests_per_sec"`
+	CurrentRPS         float64                `json:"current_rps"`
+	ActiveWorkers      int                    `json:"active_workers"`
+	Paused             bool                   `json:"paused,omitempty"`            // Load generation is paused by a live control command
+	DroppedIterations  int64                  `json:"dropped_iterations"`          // Arrivals that found no free VU
+	Iterations         int64                  `json:"iterations,omitempty"`        // Scenario iterations that ran every step
+	FailedIterations   int64                  `json:"failed_iterations,omitempty"` // Scenario iterations aborted by a failed step
+	Steps              []StepMetrics          `json:"steps,omitempty"`             // Per-step breakdown for scenario plans, in step order
+	RequestBreakdown   []RequestMetrics       `json:"requests,omitempty"`          // Per-request breakdown for request mix plans, per statement for SQL plans and per command name for Redis plans, in plan order
+	Phases             []PhaseMetrics         `json:"phases,omitempty"`            // Per-phase request timing, in TimingPhases order
+	CheckFailures      int64                  `json:"check_failures,omitempty"`    // Responses that failed one of the plan's checks
+	Checks             []CheckMetrics         `json:"checks,omitempty"`            // Per-check pass rates, in plan order
+	Retry              *RetryMetrics          `json:"retry,omitempty"`             // Retries of plans with a retry policy
+	NewConnections     int64                  `json:"new_connections"`             // Requests that opened a new connection
+	ReusedConnections  int64                  `json:"reused_connections"`          // Requests sent on a kept-alive connection
+	GRPCStatusCodes    map[string]int64       `json:"grpc_status_codes,omitempty"` // gRPC calls per status code name, e.g. OK or UNAVAILABLE
+	WebSocket          *WebSocketMetrics      `json:"websocket,omitempty"`         // Sessions of plans and scenarios with WebSocket requests
+	Stream             *StreamMetrics         `json:"stream,omitempty"`            // Events of plans that read their responses as streams
+	Socket             *SocketMetrics         `json:"socket,omitempty"`            // Exchanges of plans with TCP or UDP requests
+	DNS                *DNSMetrics            `json:"dns,omitempty"`               // Queries of plans with DNS requests
+	SQL                *SQLMetrics            `json:"sql,omitempty"`               // Statements of plans and scenarios with SQL requests
+	Redis              *RedisMetrics          `json:"redis,omitempty"`             // Sends of plans with Redis requests
+	StatusCodes        map[int]int64          `json:"status_codes"`
+	Errors             map[string]int64       `json:"errors,omitempty"`        // Failed requests per error class, see ErrorClasses
+	ErrorSamples       map[string][]string    `json:"error_samples,omitempty"` // Up to MaxErrorSamples raw messages per error class
+	Windows            map[string]WindowStats `json:"windows,omitempty"`       // Rolling 1s/10s/60s stats while the run is live
+	Agents             []AgentMetrics         `json:"agents,omitempty"`        // Per-agent breakdown for distributed runs
+	LastUpdated        time.Time              `json:"last_updated"`
+	LatencyHistogram   *Histogram             `json:"-"` // Merged service times, set with the final metrics
+	ResponseHistogram  *Histogram             `json:"-"` // Merged response times, set with the final metrics
+	StepHistograms     []*Histogram           `json:"-"` // Per-step service times, set with the final metrics
+	RequestHistograms  []*Histogram           `json:"-"` // Per-request service times, set with the final metrics
+	PhaseHistograms    []*Histogram           `json:"-"` // Per-phase request timing, in TimingPhases order, set with the final metrics
+	ProtocolHistograms ProtocolHistograms     `json:"-"` // Histograms of the retry, websocket, stream and socket sections, set with the final metrics
+	StartTime          time.Time              `json:"-"` // For calculating live RPS
+	lastReqCount       int64                  // Last request count for RPS calculation
+	lastRPSUpdate      time.Time              // Last time RPS was updated
+	Mu                 sync.RWMutex           `json:"-"`
 }
 
+// ProtocolHistograms holds the histograms of the metrics sections whose
+// workers share them, such as WebSocket handshake and round trip times, by
+// section name
+type ProtocolHistograms map[string][]*Histogram
+
 // NewMetrics creates a new Metrics instance
 func NewMetrics(runID string) *Metrics {
 	now := time.Now()
@@ -39,6 +85,7 @@ func NewMetrics(runID string) *Metrics {
 		MinLatencyMs:  -1,
 		StatusCodes:   make(map[int]int64),
 		Errors:        make(map[string]int64),
+		ErrorSamples:  make(map[string][]string),
 		LastUpdated:   now,
 		StartTime:     now,
 		lastReqCount:  0,
@@ -57,9 +104,12 @@ func (m *Metrics) RecordRequest(success bool, latencyMs float64, statusCode int,
 		m.SuccessRequests++
 	} else {
 		m.FailedRequests++
-		if err != nil {
-			m.Errors[err.Error()]++
+		class := ClassifyError(err, statusCode)
+		m.Errors[class]++
+		if m.ErrorSamples == nil {
+			m.ErrorSamples = make(map[string][]string)
 		}
+		addErrorSample(m.ErrorSamples, class, errorSample(err, statusCode))
 	}
 
 	if statusCode > 0 {
@@ -77,6 +127,357 @@ func (m *Metrics) RecordRequest(success bool, latencyMs float64, statusCode int,
 	m.LastUpdated = time.Now()
 }
 
+// RecordGRPCStatus counts the status code a gRPC call ended with
+func (m *Metrics) RecordGRPCStatus(code string) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if m.GRPCStatusCodes == nil {
+		m.GRPCStatusCodes = make(map[string]int64)
+	}
+	m.GRPCStatusCodes[code]++
+}
+
+// InitWebSocket prepares the session counters for a plan with WebSocket requests
+func (m *Metrics) InitWebSocket() {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.WebSocket = &WebSocketMetrics{}
+}
+
+// RecordWebSocketSession counts the messages of one WebSocket session, and
+// the close code of a session the server closed abnormally
+func (m *Metrics) RecordWebSocketSession(session *WebSocketSession, connected bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if m.WebSocket == nil {
+		m.WebSocket = &WebSocketMetrics{}
+	}
+	ws := m.WebSocket
+	ws.Sessions++
+	if connected {
+		ws.Connections++
+	}
+	ws.MessagesSent += session.MessagesSent
+	ws.MessagesReceived += session.MessagesReceived
+	ws.ReplyTimeouts += session.ReplyTimeouts
+	if session.CloseCode != 0 {
+		if ws.CloseCodes == nil {
+			ws.CloseCodes = make(map[int]int64)
+		}
+		ws.CloseCodes[session.CloseCode]++
+	}
+}
+
+// InitSocket prepares the exchange counters for a plan with TCP or UDP
+// requests
+func (m *Metrics) InitSocket() {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.Socket = &SocketMetrics{}
+}
+
+// RecordSocketExchange counts one TCP or UDP exchange: whether it opened a
+// new connection, the bytes it sent and received and whether its response
+// timed out
+func (m *Metrics) RecordSocketExchange(connected bool, sent, received int64, timedOut bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if m.Socket == nil {
+		m.Socket = &SocketMetrics{}
+	}
+	s := m.Socket
+	s.Exchanges++
+	if connected {
+		s.Connections++
+	}
+	s.BytesSent += sent
+	s.BytesReceived += received
+	if timedOut {
+		s.ResponseTimeouts++
+	}
+}
+
+// InitDNS prepares the query counters for a plan with DNS requests
+func (m *Metrics) InitDNS() {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.DNS = &DNSMetrics{}
+}
+
+// RecordDNSQuery counts one DNS query: the response code it was answered
+// with, empty if no answer arrived, its answer records and whether the
+// answer was truncated
+func (m *Metrics) RecordDNSQuery(rcode string, answers int, truncated bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if m.DNS == nil {
+		m.DNS = &DNSMetrics{}
+	}
+	d := m.DNS
+	d.Queries++
+	if rcode == "" {
+		return
+	}
+	if d.ResponseCodes == nil {
+		d.ResponseCodes = make(map[string]int64)
+	}
+	d.ResponseCodes[rcode]++
+	d.Answers += int64(answers)
+	if answers == 0 {
+		d.EmptyAnswers++
+	}
+	if truncated {
+		d.Truncated++
+	}
+}
+
+// InitSQL prepares the statement counters for a plan or scenario with SQL
+// requests
+func (m *Metrics) InitSQL() {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.SQL = &SQLMetrics{}
+}
+
+// RecordSQLQuery counts one SQL statement: the rows it returned and
+// affected, and the class of the error it failed with, empty if it ran
+func (m *Metrics) RecordSQLQuery(returned, affected int64, errorClass string) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if m.SQL == nil {
+		m.SQL = &SQLMetrics{}
+	}
+	q := m.SQL
+	q.Queries++
+	q.RowsReturned += returned
+	q.RowsAffected += affected
+	if errorClass != "" {
+		if q.Errors == nil {
+			q.Errors = make(map[string]int64)
+		}
+		q.Errors[errorClass]++
+	}
+}
+
+// InitRedis prepares the command counters for a plan with Redis requests
+func (m *Metrics) InitRedis() {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.Redis = &RedisMetrics{}
+}
+
+// RecordRedisSend counts one Redis send, a single command, a pipeline or a
+// transaction: its commands, how many were answered with a nil reply, the
+// prefix of each error reply, and whether it opened a new connection
+func (m *Metrics) RecordRedisSend(commands, nils int, errorReplies []string, opened bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if m.Redis == nil {
+		m.Redis = &RedisMetrics{}
+	}
+	r := m.Redis
+	r.Sends++
+	r.Commands += int64(commands)
+	r.NilReplies += int64(nils)
+	for _, prefix := range errorReplies {
+		if r.ErrorReplies == nil {
+			r.ErrorReplies = make(map[string]int64)
+		}
+		r.ErrorReplies[prefix]++
+	}
+	if opened {
+		r.Connections++
+	}
+}
+
+// InitStream prepares the event counters for a plan that reads its
+// responses as streams
+func (m *Metrics) InitStream() {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.Stream = &StreamMetrics{}
+}
+
+// RecordStream counts the events of one streamed response, whether reading
+// stopped at one of the plan's limits and whether it failed an event check
+func (m *Metrics) RecordStream(events int64, cut, checkFailed bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if m.Stream == nil {
+		m.Stream = &StreamMetrics{}
+	}
+	st := m.Stream
+	st.Streams++
+	st.Events += events
+	if events == 0 {
+		st.EmptyStreams++
+	}
+	if cut {
+		st.CutStreams++
+	}
+	if checkFailed {
+		st.EventCheckFailures++
+	}
+	st.MaxEventsPerStream = max(st.MaxEventsPerStream, events)
+	st.SetEventsPerStream()
+}
+
+// RecordDroppedIteration records a scheduled arrival that could not be started
+// because every VU was busy and the pool could not grow any further
+func (m *Metrics) RecordDroppedIteration() {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.DroppedIterations++
+}
+
+// InitSteps prepares the per-step breakdown for a scenario with the given step names
+func (m *Metrics) InitSteps(names []string) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.Steps = make([]StepMetrics, len(names))
+	for i, name := range names {
+		m.Steps[i].Name = name
+	}
+}
+
+// RecordStep records the result of one scenario step request
+func (m *Metrics) RecordStep(index int, success, assertionFailed bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if index < 0 || index >= len(m.Steps) {
+		return
+	}
+	step := &m.Steps[index]
+	step.Requests++
+	if success {
+		step.Success++
+	} else {
+		step.Failed++
+	}
+	if assertionFailed {
+		step.AssertionFailures++
+	}
+}
+
+// InitRequests prepares the per-request breakdown for a request mix with the given names
+func (m *Metrics) InitRequests(names []string) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.RequestBreakdown = make([]RequestMetrics, len(names))
+	for i, name := range names {
+		m.RequestBreakdown[i] = RequestMetrics{
+			Name:        name,
+			StatusCodes: make(map[int]int64),
+			Errors:      make(map[string]int64),
+		}
+	}
+}
+
+// RecordNamedRequest records the result of one request from the request mix
+func (m *Metrics) RecordNamedRequest(index int, success bool, statusCode int, err error) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if index < 0 || index >= len(m.RequestBreakdown) {
+		return
+	}
+	r := &m.RequestBreakdown[index]
+	r.Requests++
+	if success {
+		r.Success++
+	} else {
+		r.Failed++
+		r.Errors[ClassifyError(err, statusCode)]++
+	}
+	if statusCode > 0 {
+		r.StatusCodes[statusCode]++
+	}
+}
+
+// InitChecks prepares the per-check breakdown for a plan with the given check names
+func (m *Metrics) InitChecks(names []string) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.Checks = make([]CheckMetrics, len(names))
+	for i, name := range names {
+		m.Checks[i].Name = name
+	}
+}
+
+// RecordChecks records the outcome of every check on one response, indexed
+// like the plan's checks
+func (m *Metrics) RecordChecks(passed []bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	failed := false
+	for i, ok := range passed {
+		failed = failed || !ok
+		if i >= len(m.Checks) {
+			continue
+		}
+		check := &m.Checks[i]
+		if ok {
+			check.Passes++
+		} else {
+			check.Fails++
+		}
+		check.SetPassRate()
+	}
+	if failed {
+		m.CheckFailures++
+	}
+}
+
+// InitRetry prepares the retry counters for a plan with a retry policy
+func (m *Metrics) InitRetry() {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.Retry = &RetryMetrics{}
+}
+
+// RecordAttempts records how many attempts one request took and whether
+// the last of them succeeded
+func (m *Metrics) RecordAttempts(attempts int, success bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if m.Retry == nil {
+		m.Retry = &RetryMetrics{}
+	}
+	m.Retry.Requests++
+	if attempts <= 1 {
+		return
+	}
+	m.Retry.Retries += int64(attempts - 1)
+	m.Retry.RetriedRequests++
+	if success {
+		m.Retry.RecoveredRequests++
+	} else {
+		m.Retry.ExhaustedRequests++
+	}
+}
+
+// RecordIteration records the end of one scenario iteration
+func (m *Metrics) RecordIteration(success bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if success {
+		m.Iterations++
+	} else {
+		m.FailedIterations++
+	}
+}
+
+// RecordConnection records whether a request opened a new connection or
+// reused a kept-alive one
+func (m *Metrics) RecordConnection(reused bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	if reused {
+		m.ReusedConnections++
+	} else {
+		m.NewConnections++
+	}
+}
+
 // SetActiveWorkers updates the number of active workers
 func (m *Metrics) SetActiveWorkers(count int) {
 	m.Mu.Lock()
@@ -84,6 +485,13 @@ func (m *Metrics) SetActiveWorkers(count int) {
 	m.ActiveWorkers = count
 }
 
+// SetPaused records whether load generation is paused
+func (m *Metrics) SetPaused(paused bool) {
+	m.Mu.Lock()
+	defer m.Mu.Unlock()
+	m.Paused = paused
+}
+
 // UpdateLiveMetrics updates current RPS and total duration
 func (m *Metrics) UpdateLiveMetrics() {
 	m.Mu.Lock()
@@ -115,24 +523,47 @@ func (m *Metrics) GetSnapshot() *Metrics {
 	defer m.Mu.RUnlock()
 
 	snapshot := &Metrics{
-		RunID:           m.RunID,
-		TotalRequests:   m.TotalRequests,
-		SuccessRequests: m.SuccessRequests,
-		FailedRequests:  m.FailedRequests,
-		TotalDurationMs: m.TotalDurationMs,
-		MinLatencyMs:    m.MinLatencyMs,
-		MaxLatencyMs:    m.MaxLatencyMs,
-		AvgLatencyMs:    m.AvgLatencyMs,
-		P50LatencyMs:    m.P50LatencyMs,
-		P75LatencyMs:    m.P75LatencyMs,
-		P95LatencyMs:    m.P95LatencyMs,
-		P99LatencyMs:    m.P99LatencyMs,
-		RequestsPerSec:  m.RequestsPerSec,
-		CurrentRPS:      m.CurrentRPS,
-		ActiveWorkers:   m.ActiveWorkers,
-		StatusCodes:     make(map[int]int64),
-		Errors:          make(map[string]int64),
-		LastUpdated:     m.LastUpdated,
+		RunID:              m.RunID,
+		TotalRequests:      m.TotalRequests,
+		SuccessRequests:    m.SuccessRequests,
+		FailedRequests:     m.FailedRequests,
+		TotalDurationMs:    m.TotalDurationMs,
+		MinLatencyMs:       m.MinLatencyMs,
+		MaxLatencyMs:       m.MaxLatencyMs,
+		AvgLatencyMs:       m.AvgLatencyMs,
+		P50LatencyMs:       m.P50LatencyMs,
+		P75LatencyMs:       m.P75LatencyMs,
+		P95LatencyMs:       m.P95LatencyMs,
+		P99LatencyMs:       m.P99LatencyMs,
+		P999LatencyMs:      m.P999LatencyMs,
+		P9999LatencyMs:     m.P9999LatencyMs,
+		MaxResponseMs:      m.MaxResponseMs,
+		AvgResponseMs:      m.AvgResponseMs,
+		P50ResponseMs:      m.P50ResponseMs,
+		P75ResponseMs:      m.P75ResponseMs,
+		P95ResponseMs:      m.P95ResponseMs,
+		P99ResponseMs:      m.P99ResponseMs,
+		P999ResponseMs:     m.P999ResponseMs,
+		P9999ResponseMs:    m.P9999ResponseMs,
+		RequestsPerSec:     m.RequestsPerSec,
+		CurrentRPS:         m.CurrentRPS,
+		ActiveWorkers:      m.ActiveWorkers,
+		Paused:             m.Paused,
+		DroppedIterations:  m.DroppedIterations,
+		Iterations:         m.Iterations,
+		FailedIterations:   m.FailedIterations,
+		NewConnections:     m.NewConnections,
+		ReusedConnections:  m.ReusedConnections,
+		CheckFailures:      m.CheckFailures,
+		StatusCodes:        make(map[int]int64),
+		Errors:             make(map[string]int64),
+		LastUpdated:        m.LastUpdated,
+		LatencyHistogram:   m.LatencyHistogram,
+		ResponseHistogram:  m.ResponseHistogram,
+		StepHistograms:     m.StepHistograms,
+		RequestHistograms:  m.RequestHistograms,
+		PhaseHistograms:    m.PhaseHistograms,
+		ProtocolHistograms: m.ProtocolHistograms,
 	}
 
 	for k, v := range m.StatusCodes {
@@ -141,10 +572,441 @@ func (m *Metrics) GetSnapshot() *Metrics {
 	for k, v := range m.Errors {
 		snapshot.Errors[k] = v
 	}
+	if m.GRPCStatusCodes != nil {
+		snapshot.GRPCStatusCodes = make(map[string]int64, len(m.GRPCStatusCodes))
+		for k, v := range m.GRPCStatusCodes {
+			snapshot.GRPCStatusCodes[k] = v
+		}
+	}
+	snapshot.ErrorSamples = MergeErrorSamples(nil, m.ErrorSamples)
+	if m.Steps != nil {
+		snapshot.Steps = make([]StepMetrics, len(m.Steps))
+		copy(snapshot.Steps, m.Steps)
+	}
+	if m.RequestBreakdown != nil {
+		snapshot.RequestBreakdown = make([]RequestMetrics, len(m.RequestBreakdown))
+		for i, r := range m.RequestBreakdown {
+			r.StatusCodes = make(map[int]int64, len(m.RequestBreakdown[i].StatusCodes))
+			for k, v := range m.RequestBreakdown[i].StatusCodes {
+				r.StatusCodes[k] = v
+			}
+			r.Errors = make(map[string]int64, len(m.RequestBreakdown[i].Errors))
+			for k, v := range m.RequestBreakdown[i].Errors {
+				r.Errors[k] = v
+			}
+			snapshot.RequestBreakdown[i] = r
+		}
+	}
+	if m.Phases != nil {
+		snapshot.Phases = make([]PhaseMetrics, len(m.Phases))
+		copy(snapshot.Phases, m.Phases)
+	}
+	if m.Retry != nil {
+		retry := *m.Retry
+		snapshot.Retry = &retry
+	}
+	if m.WebSocket != nil {
+		ws := *m.WebSocket
+		if m.WebSocket.CloseCodes != nil {
+			ws.CloseCodes = make(map[int]int64, len(m.WebSocket.CloseCodes))
+			for k, v := range m.WebSocket.CloseCodes {
+				ws.CloseCodes[k] = v
+			}
+		}
+		snapshot.WebSocket = &ws
+	}
+	if m.Stream != nil {
+		st := *m.Stream
+		st.Phases = append([]PhaseMetrics(nil), m.Stream.Phases...)
+		snapshot.Stream = &st
+	}
+	if m.Socket != nil {
+		socket := *m.Socket
+		snapshot.Socket = &socket
+	}
+	if m.DNS != nil {
+		dns := *m.DNS
+		if m.DNS.ResponseCodes != nil {
+			dns.ResponseCodes = make(map[string]int64, len(m.DNS.ResponseCodes))
+			for k, v := range m.DNS.ResponseCodes {
+				dns.ResponseCodes[k] = v
+			}
+		}
+		snapshot.DNS = &dns
+	}
+	if m.SQL != nil {
+		q := *m.SQL
+		if m.SQL.Errors != nil {
+			q.Errors = make(map[string]int64, len(m.SQL.Errors))
+			for k, v := range m.SQL.Errors {
+				q.Errors[k] = v
+			}
+		}
+		snapshot.SQL = &q
+	}
+	if m.Redis != nil {
+		r := *m.Redis
+		if m.Redis.ErrorReplies != nil {
+			r.ErrorReplies = make(map[string]int64, len(m.Redis.ErrorReplies))
+			for k, v := range m.Redis.ErrorReplies {
+				r.ErrorReplies[k] = v
+			}
+		}
+		snapshot.Redis = &r
+	}
+	if m.Checks != nil {
+		snapshot.Checks = make([]CheckMetrics, len(m.Checks))
+		copy(snapshot.Checks, m.Checks)
+	}
+	if m.Windows != nil {
+		snapshot.Windows = make(map[string]WindowStats, len(m.Windows))
+		for k, v := range m.Windows {
+			snapshot.Windows[k] = v
+		}
+	}
+	if m.Agents != nil {
+		snapshot.Agents = make([]AgentMetrics, len(m.Agents))
+		copy(snapshot.Agents, m.Agents)
+	}
 
 	return snapshot
 }
 
+// StepMetrics holds the results of one scenario step across every VU iteration
+type StepMetrics struct {
+	Name              string  `json:"name"`
+	Requests          int64   `json:"requests"`
+	Success           int64   `json:"success"`
+	Failed            int64   `json:"failed"`
+	AssertionFailures int64   `json:"assertion_failures"`
+	AvgLatencyMs      float64 `json:"avg_latency_ms"`
+	P50LatencyMs      float64 `json:"p50_latency_ms"`
+	P95LatencyMs      float64 `json:"p95_latency_ms"`
+	P99LatencyMs      float64 `json:"p99_latency_ms"`
+	MaxLatencyMs      float64 `json:"max_latency_ms"`
+}
+
+// RequestMetrics holds the results of one named request from a plan's request mix
+type RequestMetrics struct {
+	Name         string           `json:"name"`
+	Requests     int64            `json:"requests"`
+	Success      int64            `json:"success"`
+	Failed       int64            `json:"failed"`
+	StatusCodes  map[int]int64    `json:"status_codes"`
+	Errors       map[string]int64 `json:"errors,omitempty"`
+	AvgLatencyMs float64          `json:"avg_latency_ms"`
+	P50LatencyMs float64          `json:"p50_latency_ms"`
+	P95LatencyMs float64          `json:"p95_latency_ms"`
+	P99LatencyMs float64          `json:"p99_latency_ms"`
+	MaxLatencyMs float64          `json:"max_latency_ms"`
+}
+
+// RetryMetrics holds the retries of a plan with a retry policy. Latency
+// stats of the run cover every attempt of a request, including backoff
+// delays; the first attempt stats only cover the first one.
+type RetryMetrics struct {
+	Requests          int64   `json:"requests"`           // Requests a retry policy applied to
+	Retries           int64   `json:"retries"`            // Attempts sent after the first
+	RetriedRequests   int64   `json:"retried_requests"`   // Requests retried at least once
+	RecoveredRequests int64   `json:"recovered_requests"` // Retried requests that finally succeeded
+	ExhaustedRequests int64   `json:"exhausted_requests"` // Retried requests that still failed
+	Amplification     float64 `json:"amplification"`      // Attempts sent per request
+	FirstAttemptAvgMs float64 `json:"first_attempt_avg_ms"`
+	FirstAttemptP50Ms float64 `json:"first_attempt_p50_ms"`
+	FirstAttemptP95Ms float64 `json:"first_attempt_p95_ms"`
+	FirstAttemptP99Ms float64 `json:"first_attempt_p99_ms"`
+	FirstAttemptMaxMs float64 `json:"first_attempt_max_ms"`
+}
+
+// SetStats recomputes the amplification and reads the first attempt
+// latency stats from their histogram
+func (r *RetryMetrics) SetStats(firstAttempt *Histogram) {
+	r.Amplification = 0
+	if r.Requests > 0 {
+		r.Amplification = float64(r.Requests+r.Retries) / float64(r.Requests)
+	}
+	if firstAttempt == nil {
+		return
+	}
+	r.FirstAttemptAvgMs = firstAttempt.MeanMs()
+	r.FirstAttemptP50Ms = firstAttempt.ValueAtPercentile(50)
+	r.FirstAttemptP95Ms = firstAttempt.ValueAtPercentile(95)
+	r.FirstAttemptP99Ms = firstAttempt.ValueAtPercentile(99)
+	r.FirstAttemptMaxMs = firstAttempt.MaxMs()
+}
+
+// WebSocketMetrics holds the sessions of a plan or scenario with WebSocket
+// requests. Each session counts as one request, whose latency is its
+// handshake time.
+type WebSocketMetrics struct {
+	Sessions         int64         `json:"sessions"`              // Sessions started
+	Connections      int64         `json:"connections"`           // Sessions that completed the handshake
+	MessagesSent     int64         `json:"messages_sent"`         // Messages sent by every session
+	MessagesReceived int64         `json:"messages_received"`     // Messages received by every session, matching a reply or not
+	ReplyTimeouts    int64         `json:"reply_timeouts"`        // Expected replies that did not arrive in time
+	CloseCodes       map[int]int64 `json:"close_codes,omitempty"` // Sessions the server closed abnormally, per close code, e.g. 1006 or 1011
+	ConnectAvgMs     float64       `json:"connect_avg_ms"`
+	ConnectP50Ms     float64       `json:"connect_p50_ms"`
+	ConnectP95Ms     float64       `json:"connect_p95_ms"`
+	ConnectP99Ms     float64       `json:"connect_p99_ms"`
+	ConnectMaxMs     float64       `json:"connect_max_ms"`
+	RoundTripAvgMs   float64       `json:"round_trip_avg_ms"` // From a send to its matching reply
+	RoundTripP50Ms   float64       `json:"round_trip_p50_ms"`
+	RoundTripP95Ms   float64       `json:"round_trip_p95_ms"`
+	RoundTripP99Ms   float64       `json:"round_trip_p99_ms"`
+	RoundTripMaxMs   float64       `json:"round_trip_max_ms"`
+}
+
+// SetStats reads the connect and round trip latency stats from their
+// histograms
+func (w *WebSocketMetrics) SetStats(connect, roundTrip *Histogram) {
+	if connect != nil {
+		w.ConnectAvgMs = connect.MeanMs()
+		w.ConnectP50Ms = connect.ValueAtPercentile(50)
+		w.ConnectP95Ms = connect.ValueAtPercentile(95)
+		w.ConnectP99Ms = connect.ValueAtPercentile(99)
+		w.ConnectMaxMs = connect.MaxMs()
+	}
+	if roundTrip != nil {
+		w.RoundTripAvgMs = roundTrip.MeanMs()
+		w.RoundTripP50Ms = roundTrip.ValueAtPercentile(50)
+		w.RoundTripP95Ms = roundTrip.ValueAtPercentile(95)
+		w.RoundTripP99Ms = roundTrip.ValueAtPercentile(99)
+		w.RoundTripMaxMs = roundTrip.MaxMs()
+	}
+}
+
+// SocketMetrics holds the exchanges of a plan with TCP or UDP requests.
+// Each exchange counts as one request, whose latency covers the connect,
+// the send and the response.
+type SocketMetrics struct {
+	Exchanges        int64   `json:"exchanges"`         // Exchanges started
+	Connections      int64   `json:"connections"`       // Connections opened, fewer than exchanges with keep_alive
+	BytesSent        int64   `json:"bytes_sent"`        // Payload bytes written
+	BytesReceived    int64   `json:"bytes_received"`    // Response bytes read, including ignored datagrams
+	ResponseTimeouts int64   `json:"response_timeouts"` // Expected responses that did not arrive in time
+	ConnectAvgMs     float64 `json:"connect_avg_ms"`
+	ConnectP50Ms     float64 `json:"connect_p50_ms"`
+	ConnectP95Ms     float64 `json:"connect_p95_ms"`
+	ConnectP99Ms     float64 `json:"connect_p99_ms"`
+	ConnectMaxMs     float64 `json:"connect_max_ms"`
+	RoundTripAvgMs   float64 `json:"round_trip_avg_ms"` // From the payload being sent until the response arrived
+	RoundTripP50Ms   float64 `json:"round_trip_p50_ms"`
+	RoundTripP95Ms   float64 `json:"round_trip_p95_ms"`
+	RoundTripP99Ms   float64 `json:"round_trip_p99_ms"`
+	RoundTripMaxMs   float64 `json:"round_trip_max_ms"`
+}
+
+// SetStats reads the connect and round trip latency stats from their
+// histograms
+func (s *SocketMetrics) SetStats(connect, roundTrip *Histogram) {
+	if connect != nil {
+		s.ConnectAvgMs = connect.MeanMs()
+		s.ConnectP50Ms = connect.ValueAtPercentile(50)
+		s.ConnectP95Ms = connect.ValueAtPercentile(95)
+		s.ConnectP99Ms = connect.ValueAtPercentile(99)
+		s.ConnectMaxMs = connect.MaxMs()
+	}
+	if roundTrip != nil {
+		s.RoundTripAvgMs = roundTrip.MeanMs()
+		s.RoundTripP50Ms = roundTrip.ValueAtPercentile(50)
+		s.RoundTripP95Ms = roundTrip.ValueAtPercentile(95)
+		s.RoundTripP99Ms = roundTrip.ValueAtPercentile(99)
+		s.RoundTripMaxMs = roundTrip.MaxMs()
+	}
+}
+
+// DNSMetrics holds the queries of a plan with DNS requests. Each query
+// counts as one request, whose latency is the time until its answer
+// arrived.
+type DNSMetrics struct {
+	Queries       int64            `json:"queries"`                  // Queries sent
+	ResponseCodes map[string]int64 `json:"response_codes,omitempty"` // Answered queries per response code, e.g. NOERROR or NXDOMAIN
+	Answers       int64            `json:"answers"`                  // Answer records of every answered query
+	EmptyAnswers  int64            `json:"empty_answers"`            // Answered queries without answer records, e.g. NXDOMAIN or no record of the type
+	Truncated     int64            `json:"truncated"`                // UDP answers with the truncated flag set, which are not retried over TCP
+}
+
+// SQLMetrics holds the statements of a plan or scenario with SQL requests.
+// Each statement counts as one request, whose latency is the time until
+// every row it returned was read.
+type SQLMetrics struct {
+	Queries      int64            `json:"queries"`          // Statements run
+	RowsReturned int64            `json:"rows_returned"`    // Rows read from every statement's result
+	RowsAffected int64            `json:"rows_affected"`    // Rows inserted, updated or deleted by statements that return none
+	Errors       map[string]int64 `json:"errors,omitempty"` // Failed statements per SQLSTATE class, e.g. integrity_constraint_violation, or per error class without one
+}
+
+// RedisMetrics holds the sends of a plan with Redis requests. Each send, a
+// single command, a pipeline or a transaction, counts as one request whose
+// latency is the time until every reply was read; the per-request breakdown
+// times each command from its send to its reply.
+type RedisMetrics struct {
+	Sends        int64            `json:"sends"`                   // Single commands, pipelines or transactions sent, one per iteration
+	Commands     int64            `json:"commands"`                // Commands sent, without MULTI/EXEC
+	NilReplies   int64            `json:"nil_replies"`             // Commands answered with a nil reply, e.g. GET cache misses
+	ErrorReplies map[string]int64 `json:"error_replies,omitempty"` // Commands answered with an error reply, per prefix, e.g. WRONGTYPE or MOVED
+	Connections  int64            `json:"connections"`             // Pool connections opened
+}
+
+// StreamMetrics holds the events of a plan that reads its responses as
+// streams. Only successful responses are read as streams.
+type StreamMetrics struct {
+	Streams            int64          `json:"streams"`               // Responses read as streams
+	Events             int64          `json:"events"`                // Events of every stream, not counting end events
+	EmptyStreams       int64          `json:"empty_streams"`         // Streams that ended without an event
+	CutStreams         int64          `json:"cut_streams"`           // Streams stopped at max_events or max_duration_ms rather than ended by the server
+	EventCheckFailures int64          `json:"event_check_failures"`  // Streams that failed one of the event checks
+	AvgEventsPerStream float64        `json:"avg_events_per_stream"` // Events divided by streams
+	MaxEventsPerStream int64          `json:"max_events_per_stream"`
+	Phases             []PhaseMetrics `json:"phases,omitempty"` // Event timing, in StreamPhases order
+}
+
+// SetEventsPerStream recomputes AvgEventsPerStream from the event and
+// stream counts
+func (s *StreamMetrics) SetEventsPerStream() {
+	s.AvgEventsPerStream = 0
+	if s.Streams > 0 {
+		s.AvgEventsPerStream = float64(s.Events) / float64(s.Streams)
+	}
+}
+
+// SetStats reads the event timing stats from their histograms, indexed like
+// StreamPhases
+func (s *StreamMetrics) SetStats(hists []*Histogram) {
+	s.Phases = phaseMetrics(StreamPhases, hists)
+}
+
+// CheckMetrics holds the outcome of one of a plan's response checks
+type CheckMetrics struct {
+	Name     string  `json:"name"`
+	Passes   int64   `json:"passes"`
+	Fails    int64   `json:"fails"`
+	PassRate float64 `json:"pass_rate"` // Percentage of checked responses that passed
+}
+
+// SetPassRate recomputes PassRate from the pass and fail counts
+func (c *CheckMetrics) SetPassRate() {
+	c.PassRate = 0
+	if total := c.Passes + c.Fails; total > 0 {
+		c.PassRate = float64(c.Passes) / float64(total) * 100
+	}
+}
+
+// Request timing phases, in the order they happen during a request
+const (
+	PhaseDNS      = "dns"      // Resolving the target host name
+	PhaseConnect  = "connect"  // Opening the TCP connection
+	PhaseTLS      = "tls"      // TLS handshake
+	PhaseTTFB     = "ttfb"     // Request written until the first response byte, i.e. server think time
+	PhaseTransfer = "transfer" // First response byte until the body has been read
+)
+
+// TimingPhases lists every request timing phase in order. Per-phase
+// histograms and Metrics.Phases are indexed the same way.
+var TimingPhases = []string{PhaseDNS, PhaseConnect, PhaseTLS, PhaseTTFB, PhaseTransfer}
+
+// PhaseMetrics holds the distribution of one request timing phase. DNS,
+// connect and TLS only happen on requests that open a new connection, so
+// their counts are usually lower than the number of requests.
+type PhaseMetrics struct {
+	Phase string  `json:"phase"`
+	Count int64   `json:"count"`
+	AvgMs float64 `json:"avg_ms"`
+	P50Ms float64 `json:"p50_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	P99Ms float64 `json:"p99_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+// Stream event timing phases, measured from the request being sent
+const (
+	StreamFirstEvent = "first_event" // Until the first event arrived
+	StreamEventGap   = "event_gap"   // Between two consecutive events of a stream
+	StreamDuration   = "duration"    // Until the stream ended or reading stopped
+)
+
+// StreamPhases lists every stream event timing phase. Stream histograms and
+// StreamMetrics.Phases are indexed the same way.
+var StreamPhases = []string{StreamFirstEvent, StreamEventGap, StreamDuration}
+
+// NewPhaseMetrics summarizes per-phase histograms, indexed like TimingPhases
+func NewPhaseMetrics(hists []*Histogram) []PhaseMetrics {
+	return phaseMetrics(TimingPhases, hists)
+}
+
+// phaseMetrics summarizes the histograms of the phases names lists, indexed
+// the same way
+func phaseMetrics(names []string, hists []*Histogram) []PhaseMetrics {
+	phases := make([]PhaseMetrics, 0, len(hists))
+	for i, hist := range hists {
+		if i >= len(names) {
+			break
+		}
+		phases = append(phases, PhaseMetrics{
+			Phase: names[i],
+			Count: hist.TotalCount(),
+			AvgMs: hist.MeanMs(),
+			P50Ms: hist.ValueAtPercentile(50),
+			P95Ms: hist.ValueAtPercentile(95),
+			P99Ms: hist.ValueAtPercentile(99),
+			MaxMs: hist.MaxMs(),
+		})
+	}
+	return phases
+}
+
+// RequestTiming is the phase breakdown of a single HTTP request. Connection
+// phases are zero when the request reused a kept-alive connection or the
+// phase did not happen, such as DNS for an IP address or TLS for plain HTTP.
+type RequestTiming struct {
+	DNSMs            float64 `json:"dns_ms,omitempty"`
+	ConnectMs        float64 `json:"connect_ms,omitempty"`
+	TLSMs            float64 `json:"tls_ms,omitempty"`
+	TTFBMs           float64 `json:"ttfb_ms"`
+	TransferMs       float64 `json:"transfer_ms"`
+	ConnectionReused bool    `json:"connection_reused"`
+}
+
+// Rolling window names used as keys of Metrics.Windows
+const (
+	Window1s  = "1s"
+	Window10s = "10s"
+	Window60s = "60s"
+)
+
+// WindowStats holds latency percentiles, throughput and error rate over the
+// most recent part of a run. Windows are shorter than their nominal length
+// until the run has been going for that long.
+type WindowStats struct {
+	DurationSec  float64 `json:"duration_sec"` // Time actually covered by the window
+	Requests     int64   `json:"requests"`
+	Failed       int64   `json:"failed"`
+	RPS          float64 `json:"rps"`
+	ErrorRate    float64 `json:"error_rate"` // Percentage (0-100)
+	P50LatencyMs float64 `json:"p50_latency_ms"`
+	P95LatencyMs float64 `json:"p95_latency_ms"`
+	P99LatencyMs float64 `json:"p99_latency_ms"`
+}
+
+// DefaultPercentiles are reported when a percentile query names none
+var DefaultPercentiles = []float64{50, 75, 90, 95, 99, 99.9, 99.99}
+
+// PercentileValue is a single percentile read from the latency histograms
+type PercentileValue struct {
+	Percentile float64 `json:"percentile"`
+	LatencyMs  float64 `json:"latency_ms"`
+	ResponseMs float64 `json:"response_ms"`
+}
+
+// PercentileReport answers an arbitrary percentile query for a test run
+type PercentileReport struct {
+	RunID       string            `json:"run_id"`
+	Count       int64             `json:"count"`
+	Live        bool              `json:"live"` // True while the run is still in progress
+	Percentiles []PercentileValue `json:"percentiles"`
+}
+
 // LatencyRecord holds individual request latency for percentile calculation
 type LatencyRecord struct {
 	Timestamp time.Time
@@ -0,0 +1,40 @@
+package model
+
+// RedisMode defines what a Redis plan sends per iteration
+type RedisMode string
+
+const (
+	RedisModeSingle      RedisMode = "single"      // One command, picked with probability proportional to its weight
+	RedisModePipeline    RedisMode = "pipeline"    // Every command as one pipeline, in order
+	RedisModeTransaction RedisMode = "transaction" // Every command as one pipeline wrapped in MULTI/EXEC
+)
+
+// RedisRequest sends Redis commands over RESP instead of HTTP requests.
+// Each run opens one connection pool, shared by its VUs. Every iteration
+// sends one command, a pipeline or a transaction, depending on the mode,
+// which counts as one request whose latency is the time until every reply
+// was read.
+type RedisRequest struct {
+	Address             string         `json:"address"`                         // host:port of the server
+	CredentialsSecretID string         `json:"credentials_secret_id,omitempty"` // credentials secret sent with AUTH on every new connection; its username is the ACL user, default: the default user
+	DB                  int            `json:"db,omitempty"`                    // Database selected on every new connection
+	TLS                 bool           `json:"tls,omitempty"`                   // Connect over TLS, with the plan's tls settings if any
+	PoolSize            int            `json:"pool_size,omitempty"`             // Connections shared by the VUs, default: the run's largest VU count
+	Mode                RedisMode      `json:"mode,omitempty"`                  // What an iteration sends, default: single
+	Commands            []RedisCommand `json:"commands"`                        // Mix of commands in single mode, or the commands of the pipeline in order
+}
+
+// RedisCommand is one command of a plan's mix or pipeline
+type RedisCommand struct {
+	Args   []string `json:"args"`             // Command name and arguments, e.g. ["HGET", "user:{{data.user_id}}", "name"]; arguments support templates
+	Weight int      `json:"weight,omitempty"` // Relative frequency within the plan's mix in single mode, default: 1
+}
+
+// RedisDisallowedCommands are the commands a plan may not send: those that
+// change the state of a pooled connection, and MULTI/EXEC, which the
+// transaction mode sends
+var RedisDisallowedCommands = []string{
+	"AUTH", "SELECT", "HELLO", "RESET", "QUIT", "CLIENT",
+	"MULTI", "EXEC", "DISCARD", "WATCH", "UNWATCH",
+	"SUBSCRIBE", "PSUBSCRIBE", "SSUBSCRIBE", "MONITOR",
+}
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Scenario represents a multi-step test workflow
 type Scenario struct {
@@ -9,21 +13,27 @@ type Scenario struct {
 	Description string    `json:"description,omitempty"`
 	Steps       []Step    `json:"steps" binding:"required,min=1"`
 	Variables   Variables `json:"variables,omitempty"` // Global variables
+	Data        *DataFeed `json:"data,omitempty"`      // Feeds {{data.column}} values when run as a load test
 	CreatedAt   time.Time `json:"created_at"`
 }
 
 // Step represents a single step in a scenario
 type Step struct {
 	Name        string               `json:"name" binding:"required"`
-	Method      string               `json:"method" binding:"required"`
-	URL         string               `json:"url" binding:"required"`
+	Method      string               `json:"method" binding:"required_without_all=GRPC WebSocket SQL"`
+	URL         string               `json:"url" binding:"required_without_all=GRPC WebSocket SQL"`
 	Headers     map[string]string    `json:"headers,omitempty"`
 	Body        string               `json:"body,omitempty"`
+	BodySource  *BodySource          `json:"body_source,omitempty"` // Replaces Body
+	GRPC        *GRPCRequest         `json:"grpc,omitempty"`        // gRPC call sent instead of an HTTP request, replaces Method/URL/Headers/Body
+	WebSocket   *WebSocketRequest    `json:"websocket,omitempty"`   // WebSocket session opened instead of an HTTP request, replaces Method/URL/Headers/Body
+	SQL         *SQLStatement        `json:"sql,omitempty"`         // Statement run on the plan's database instead of an HTTP request, replaces Method/URL/Headers/Body
 	TimeoutMs   int                  `json:"timeout_ms,omitempty"`
 	Extractions []VariableExtraction `json:"extractions,omitempty"` // Extract variables from response
 	Assertions  []Assertion          `json:"assertions,omitempty"`  // Validate response
 	SkipIf      *Condition           `json:"skip_if,omitempty"`     // Conditional execution
 	ThinkTimeMs int                  `json:"think_time_ms,omitempty"`
+	Retry       *RetryPolicy         `json:"retry,omitempty"` // Overrides the plan's retry policy for this step
 }
 
 // VariableExtraction defines how to extract a value from response
@@ -46,10 +56,29 @@ const (
 
 // Assertion validates a response
 type Assertion struct {
+	Name     string        `json:"name,omitempty"` // Label in metrics and reports, default: derived from the assertion
 	Type     AssertionType `json:"type" binding:"required"`
 	Target   string        `json:"target,omitempty"`   // JSONPath or header name
-	Operator string        `json:"operator,omitempty"` // "eq", "contains", "gt", "lt"
-	Value    interface{}   `json:"value,omitempty"`
+	Operator string        `json:"operator,omitempty"` // "eq", "ne", "contains", "not_contains", "gt", "lt", "exists", "not_exists"
+	Value    interface{}   `json:"value,omitempty"`    // status_code also takes a list of accepted codes
+}
+
+// Label returns the assertion's name, or a description built from its fields
+func (a *Assertion) Label() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	parts := []string{string(a.Type)}
+	if a.Target != "" {
+		parts = append(parts, a.Target)
+	}
+	if a.Operator != "" {
+		parts = append(parts, a.Operator)
+	}
+	if a.Value != nil {
+		parts = append(parts, fmt.Sprintf("%v", a.Value))
+	}
+	return strings.Join(parts, " ")
 }
 
 // AssertionType defines what to assert
@@ -61,6 +90,7 @@ const (
 	AssertionJSONPath     AssertionType = "jsonpath"
 	AssertionHeader       AssertionType = "header"
 	AssertionBodyContains AssertionType = "body_contains"
+	AssertionBodySize     AssertionType = "body_size" // Response body length in bytes
 )
 
 // Condition defines a conditional execution rule
@@ -90,7 +120,14 @@ type StepResult struct {
 	StepName         string                 `json:"step_name"`
 	Status           string                 `json:"status"` // "success", "failed", "skipped"
 	StatusCode       int                    `json:"status_code,omitempty"`
-	ResponseTimeMs   float64                `json:"response_time_ms"`
+	GRPCStatus       string                 `json:"grpc_status,omitempty"`      // Status code name of a gRPC step
+	WebSocket        *WebSocketSession      `json:"websocket,omitempty"`        // Session of a WebSocket step
+	RowsReturned     int64                  `json:"rows_returned,omitempty"`    // Rows read by an SQL step
+	RowsAffected     int64                  `json:"rows_affected,omitempty"`    // Rows changed by an SQL step
+	ResponseTimeMs   float64                `json:"response_time_ms"`           // Across every attempt, including backoff delays
+	Attempts         int                    `json:"attempts,omitempty"`         // Requests sent, more than 1 when the step was retried
+	FirstAttemptMs   float64                `json:"first_attempt_ms,omitempty"` // Response time of the first attempt
+	Timing           *RequestTiming         `json:"timing,omitempty"`
 	Extractions      map[string]interface{} `json:"extractions,omitempty"`
 	AssertionsFailed []string               `json:"assertions_failed,omitempty"`
 	Error            string                 `json:"error,omitempty"`
@@ -104,6 +141,7 @@ type CreateScenarioRequest struct {
 	Description string    `json:"description,omitempty"`
 	Steps       []Step    `json:"steps" binding:"required,min=1"`
 	Variables   Variables `json:"variables,omitempty"`
+	Data        *DataFeed `json:"data,omitempty"`
 }
 
 // ExecuteScenarioRequest represents a request to execute a scenario
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// SecretType identifies what a secret holds
+type SecretType string
+
+const (
+	SecretTLSClient   SecretType = "tls_client"  // Client certificate and private key for mTLS
+	SecretCABundle    SecretType = "ca_bundle"   // CA certificates trusted to verify target servers
+	SecretCredentials SecretType = "credentials" // Username and password of a database or cache
+)
+
+// Secret is key material stored server-side and referenced from test plans
+// by ID. Its PEM contents and passwords are never returned by the API.
+type Secret struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Type        SecretType `json:"type"`
+	Subject     string     `json:"subject,omitempty"`   // Subject of the (first) certificate
+	NotAfter    *time.Time `json:"not_after,omitempty"` // Expiry of the (first) certificate
+	Username    string     `json:"username,omitempty"`  // User name, credentials only
+	Certificate string     `json:"-"`                   // PEM certificate chain or CA bundle
+	PrivateKey  string     `json:"-"`                   // PEM private key, tls_client only
+	Password    string     `json:"-"`                   // Password, credentials only
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// CreateSecretRequest represents the request to store a secret
+type CreateSecretRequest struct {
+	Name        string     `json:"name" binding:"required"`
+	Type        SecretType `json:"type" binding:"required"`
+	Certificate string     `json:"certificate,omitempty"` // PEM encoded, required for tls_client and ca_bundle
+	PrivateKey  string     `json:"private_key,omitempty"` // PEM encoded, required for tls_client
+	Username    string     `json:"username,omitempty"`    // credentials only, optional
+	Password    string     `json:"password,omitempty"`    // Required for credentials
+}
+
+// Credentials holds the username and password of a credentials secret a
+// plan references, resolved when a run starts
+type Credentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+}
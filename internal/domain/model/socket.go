@@ -0,0 +1,45 @@
+package model
+
+// SocketNetwork is the transport of a raw socket request
+type SocketNetwork string
+
+const (
+	SocketTCP SocketNetwork = "tcp"
+	SocketUDP SocketNetwork = "udp"
+)
+
+// PayloadEncoding defines how the string form of a socket payload or
+// delimiter is turned into bytes
+type PayloadEncoding string
+
+const (
+	EncodingText   PayloadEncoding = "text"   // The string's UTF-8 bytes
+	EncodingHex    PayloadEncoding = "hex"    // Hex digits, e.g. 0d0a
+	EncodingBase64 PayloadEncoding = "base64" // Standard base64
+)
+
+// SocketRequest is a raw TCP or UDP exchange a plan sends instead of an HTTP
+// request, for services that do not speak HTTP. Each iteration connects,
+// sends the payload and, when a response is expected, reads until it
+// arrives. An exchange counts as one request, whose latency covers the
+// connect, the send and the response.
+type SocketRequest struct {
+	Network   SocketNetwork   `json:"network"`              // tcp or udp
+	Address   string          `json:"address"`              // host:port, supports templates
+	Payload   string          `json:"payload,omitempty"`    // Supports templates, applied before decoding
+	Encoding  PayloadEncoding `json:"encoding,omitempty"`   // Of the payload and the response delimiter, default: text
+	KeepAlive bool            `json:"keep_alive,omitempty"` // Reuse the VU's connection across iterations instead of connecting every time
+	Response  *SocketResponse `json:"response,omitempty"`   // Response to wait for, default: none, the exchange ends once the payload is sent
+}
+
+// SocketResponse is the response an exchange waits for. A TCP response is
+// read until the delimiter arrives or the bytes read so far match the
+// pattern; without either, the first bytes received are the response, and
+// the connection cannot be kept alive. Each UDP datagram is a response of
+// its own, and datagrams that do not match the pattern are ignored.
+type SocketResponse struct {
+	Delimiter string `json:"delimiter,omitempty"`  // TCP only, encoded like the payload, e.g. "0d0a" in hex; bytes after it start the next response on a kept-alive connection
+	Pattern   string `json:"pattern,omitempty"`    // Regex the response must match, e.g. ^OK
+	MaxBytes  int    `json:"max_bytes,omitempty"`  // Fails a longer response, default: 65536
+	TimeoutMs int    `json:"timeout_ms,omitempty"` // Default: the plan's timeout
+}
@@ -0,0 +1,22 @@
+package model
+
+// SQLRequest runs SQL statements against a database through database/sql
+// instead of sending HTTP requests. Each run opens one connection pool,
+// shared by its VUs. Every iteration runs one statement, picked with
+// probability proportional to its weight, which counts as one request whose
+// latency is the time until every row was read.
+type SQLRequest struct {
+	Driver              string         `json:"driver,omitempty"`                // database/sql driver name, default: postgres
+	DSN                 string         `json:"dsn"`                             // Data source name without a password, e.g. postgres://localhost:5432/app?sslmode=disable
+	CredentialsSecretID string         `json:"credentials_secret_id,omitempty"` // credentials secret whose username and password are added to the DSN when a run starts
+	MaxOpenConns        int            `json:"max_open_conns,omitempty"`        // Pool size, default: the run's largest VU count
+	Statements          []SQLStatement `json:"statements,omitempty"`            // Mix of statements the plan runs; scenario plans run their steps' statements instead
+}
+
+// SQLStatement is one statement of a plan's mix or a scenario step
+type SQLStatement struct {
+	Name   string   `json:"name,omitempty"`   // Breakdown name of a plan's statement, required there
+	Query  string   `json:"query"`            // SQL with the driver's placeholders, e.g. $1 for postgres; supports templates
+	Args   []string `json:"args,omitempty"`   // Bound to the placeholders in order; support templates, e.g. {{data.user_id}} or an extracted {{order_id}}
+	Weight int      `json:"weight,omitempty"` // Relative frequency within the plan's mix, default: 1
+}
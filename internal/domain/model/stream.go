@@ -0,0 +1,52 @@
+package model
+
+// StreamFormat defines how a streamed response body is split into events
+type StreamFormat string
+
+const (
+	StreamSSE    StreamFormat = "sse"    // Server-Sent Events, one event per data block ending in a blank line
+	StreamLines  StreamFormat = "lines"  // One event per non-empty line, e.g. NDJSON token streams
+	StreamChunks StreamFormat = "chunks" // One event per read of the body, roughly one per chunk the server flushes
+)
+
+// StreamConfig reads the successful responses of an HTTP plan as event
+// streams, such as Server-Sent Events, chunked token streams or long polls,
+// instead of draining them. A request's latency stays the time until its
+// response headers arrived; the stream metrics time the events that follow.
+// Reading stops when the server ends the stream, at the end event, after
+// MaxEvents or after MaxDurationMs, whichever comes first.
+type StreamConfig struct {
+	Format        StreamFormat `json:"format,omitempty"`          // Default: sse
+	MaxEvents     int          `json:"max_events,omitempty"`      // Default: no limit
+	MaxDurationMs int          `json:"max_duration_ms,omitempty"` // From the request being sent, default: no limit but the plan's timeout
+	EndData       string       `json:"end_data,omitempty"`        // Event data that ends the stream, e.g. [DONE]; not counted as an event
+	EventChecks   []EventCheck `json:"event_checks,omitempty"`    // Checks on the data of the events
+}
+
+// EventMatch defines how many of a stream's events an event check needs
+type EventMatch string
+
+const (
+	EventMatchEvery EventMatch = "every" // Every event passes; a stream without events fails
+	EventMatchAny   EventMatch = "any"   // At least one event passes
+)
+
+// EventCheck is a body_contains, jsonpath or body_size check applied to the
+// data of every event of a stream. Its results count towards the plan's
+// per-check pass rates, after the plan's assertions.
+type EventCheck struct {
+	Assertion
+	Match EventMatch `json:"match,omitempty"` // Default: every
+}
+
+// Label returns the check's name, or a description built from its fields
+func (c *EventCheck) Label() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	match := c.Match
+	if match == "" {
+		match = EventMatchEvery
+	}
+	return string(match) + " event " + c.Assertion.Label()
+}
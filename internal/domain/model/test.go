@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"crypto/tls"
+	"time"
+)
 
 // RatePattern defines the rate control pattern
 type RatePattern string
@@ -10,6 +13,18 @@ const (
 	RatePatternStep  RatePattern = "step"  // Step up RPS in stages
 	RatePatternRamp  RatePattern = "ramp"  // Linear ramp up
 	RatePatternSpike RatePattern = "spike" // Sudden spike then back to base
+
+	RatePatternStages  RatePattern = "stages"  // Linear interpolation between stage targets for RPS and VUs
+	RatePatternSine    RatePattern = "sine"    // Sine wave between a min and max RPS
+	RatePatternDiurnal RatePattern = "diurnal" // Day-shaped traffic curve between a min and max RPS
+)
+
+// ExecutorType defines how iterations are scheduled onto virtual users
+type ExecutorType string
+
+const (
+	ExecutorClosed      ExecutorType = "closed"       // Fixed VU pool pulls from a bounded queue (default)
+	ExecutorArrivalRate ExecutorType = "arrival_rate" // Open model: arrivals are scheduled independently of VU availability
 )
 
 // RateStep defines a step in step/spike rate patterns
@@ -18,6 +33,183 @@ type RateStep struct {
 	DurationSec int `json:"duration_sec" binding:"min=1"`
 }
 
+// Stage is one segment of a stages load profile. Target RPS and VUs change
+// linearly from the previous stage's values to this stage's over
+// DurationSec; the first stage starts from the plan's target_rps and users.
+// A nil target holds the previous value.
+type Stage struct {
+	DurationSec int  `json:"duration_sec" binding:"min=1"`
+	TargetRPS   *int `json:"target_rps,omitempty"`
+	TargetVUs   *int `json:"target_vus,omitempty"`
+}
+
+// StagesSetRPS reports whether any stage sets a target rate
+func StagesSetRPS(stages []Stage) bool {
+	for _, stage := range stages {
+		if stage.TargetRPS != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// StagesSetVUs reports whether any stage sets a target VU count
+func StagesSetVUs(stages []Stage) bool {
+	for _, stage := range stages {
+		if stage.TargetVUs != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// WaveConfig shapes the arrival rate of the sine and diurnal patterns. The
+// sine wave starts at MinRPS and peaks at MaxRPS half way through a period.
+// The diurnal curve compresses a typical 24h traffic day into one period.
+type WaveConfig struct {
+	MinRPS    int `json:"min_rps" binding:"min=0"`
+	MaxRPS    int `json:"max_rps" binding:"min=1"`
+	PeriodSec int `json:"period_sec,omitempty" binding:"min=0"` // Default: the test duration
+}
+
+// WeightedRequest is one named request in a plan's request mix. Each
+// iteration picks a request with probability proportional to its weight.
+type WeightedRequest struct {
+	Name       string            `json:"name" binding:"required"`
+	Method     string            `json:"method" binding:"required"`
+	URL        string            `json:"url" binding:"required"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	BodySource *BodySource       `json:"body_source,omitempty"` // Replaces Body
+	Weight     int               `json:"weight" binding:"min=1"`
+}
+
+// HTTPVersion selects the HTTP protocol a plan's requests are sent with
+type HTTPVersion string
+
+const (
+	HTTPVersionAuto  HTTPVersion = "auto"  // HTTP/2 when a TLS server offers it, HTTP/1.1 otherwise (default)
+	HTTPVersionHTTP1 HTTPVersion = "http1" // HTTP/1.1 only
+	HTTPVersionHTTP2 HTTPVersion = "http2" // HTTP/2 over TLS only
+	HTTPVersionH2C   HTTPVersion = "h2c"   // HTTP/2 over cleartext with prior knowledge
+)
+
+// RedirectPolicy defines how redirect responses are handled
+type RedirectPolicy string
+
+const (
+	RedirectFollow   RedirectPolicy = "follow"    // Follow up to max_redirects redirects (default)
+	RedirectSameHost RedirectPolicy = "same_host" // Follow redirects that stay on the original host
+	RedirectNone     RedirectPolicy = "none"      // Return the redirect response itself
+)
+
+// TransportConfig controls how a plan's requests are sent. Plans without one
+// share a pooled transport with every other run. Zero values keep Go's
+// defaults, except that connections per host are unlimited.
+type TransportConfig struct {
+	DisableKeepAlives       bool           `json:"disable_keep_alives,omitempty"`        // Open a new connection for every request
+	MaxConnsPerHost         int            `json:"max_conns_per_host,omitempty"`         // 0 means unlimited
+	MaxIdleConnsPerHost     int            `json:"max_idle_conns_per_host,omitempty"`    // Default: max_conns_per_host, or the VU count
+	HTTPVersion             HTTPVersion    `json:"http_version,omitempty"`               // Default: auto
+	PoolPerVU               bool           `json:"pool_per_vu,omitempty"`                // Give every VU its own connection pool, like distinct clients
+	Redirects               RedirectPolicy `json:"redirects,omitempty"`                  // Default: follow
+	MaxRedirects            int            `json:"max_redirects,omitempty"`              // Default: 10
+	ConnectTimeoutMs        int            `json:"connect_timeout_ms,omitempty"`         // TCP connect, default 30s
+	TLSHandshakeTimeoutMs   int            `json:"tls_handshake_timeout_ms,omitempty"`   // Default 10s
+	ResponseHeaderTimeoutMs int            `json:"response_header_timeout_ms,omitempty"` // Request written until response headers, default none
+}
+
+// TLSConfig controls TLS connections to the target. Certificates are
+// referenced as secrets rather than sent inline. Versions are "1.0" to "1.3".
+type TLSConfig struct {
+	ClientCertSecretID string   `json:"client_cert_secret_id,omitempty"` // tls_client secret presented for mTLS
+	CASecretID         string   `json:"ca_secret_id,omitempty"`          // ca_bundle secret trusted instead of the system roots
+	InsecureSkipVerify bool     `json:"insecure_skip_verify,omitempty"`  // Accept any server certificate, e.g. self-signed staging certs
+	ServerName         string   `json:"server_name,omitempty"`           // SNI and verification host, default: the URL host
+	MinVersion         string   `json:"min_version,omitempty"`           // Default: 1.2
+	MaxVersion         string   `json:"max_version,omitempty"`           // Default: 1.3
+	CipherSuites       []string `json:"cipher_suites,omitempty"`         // Go cipher suite names, TLS 1.0-1.2 only
+}
+
+// TLSVersions maps the TLS version names accepted in a plan to their IDs
+var TLSVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSCipherSuite returns the ID of a cipher suite by its Go name, such as
+// TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, including insecure suites
+func TLSCipherSuite(name string) (uint16, bool) {
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		if suite.Name == name {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+// TLSMaterial holds the PEM contents of the secrets a plan's TLS settings
+// reference, resolved when a run starts
+type TLSMaterial struct {
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+	CACerts    string `json:"ca_certs,omitempty"`
+}
+
+// CookieConfig controls the cookie jar each VU keeps. Without one, every VU
+// gets its own jar that persists across its iterations.
+type CookieConfig struct {
+	Disabled          bool         `json:"disabled,omitempty"`            // Send and keep no cookies
+	ResetPerIteration bool         `json:"reset_per_iteration,omitempty"` // Empty and re-seed the jar before every iteration
+	Seed              []SeedCookie `json:"seed,omitempty"`                // Cookies put in the jar before the VU's first iteration
+}
+
+// SeedCookie is a cookie placed in a VU's jar before it sends any request.
+// Name, value and URL support {{data.column}}, scenario variables such as
+// {{vu_id}} and template functions.
+type SeedCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"` // Default: the plan's target URL, first request or first scenario step
+}
+
+// RetryPolicy makes a request that fails be sent again, like a client with
+// retries would. MaxAttempts counts the first attempt too.
+type RetryPolicy struct {
+	MaxAttempts      int         `json:"max_attempts"`                 // Total attempts, at least 2
+	RetryOn          []string    `json:"retry_on,omitempty"`           // Error classes to retry, see ErrorClasses
+	StatusCodes      []int       `json:"status_codes,omitempty"`       // Response statuses to retry
+	Backoff          BackoffType `json:"backoff,omitempty"`            // Default: exponential
+	InitialDelayMs   int         `json:"initial_delay_ms,omitempty"`   // Delay before the first retry, default: 100
+	MaxDelayMs       int         `json:"max_delay_ms,omitempty"`       // Cap on any delay, including Retry-After, default: 10000
+	IgnoreRetryAfter bool        `json:"ignore_retry_after,omitempty"` // Use the backoff even when the response sends Retry-After
+}
+
+// BackoffType defines how the delay between attempts grows
+type BackoffType string
+
+const (
+	BackoffConstant    BackoffType = "constant"    // initial_delay_ms before every retry
+	BackoffExponential BackoffType = "exponential" // Doubles after every retry
+	BackoffJittered    BackoffType = "jittered"    // Exponential with full jitter, a random delay up to the exponential one
+)
+
+// Retry policy defaults
+const (
+	DefaultRetryInitialDelayMs = 100
+	DefaultRetryMaxDelayMs     = 10000
+)
+
+// DefaultRetryOn lists the error classes retried when a policy names
+// neither error classes nor status codes
+var DefaultRetryOn = []string{ErrorClassTimeout, ErrorClassConnectionRefused, ErrorClassConnectionReset, ErrorClassEOF}
+
+// DefaultRetryStatusCodes lists the statuses retried when a policy names
+// neither error classes nor status codes
+var DefaultRetryStatusCodes = []int{429, 502, 503, 504}
+
 // SLAConfig defines SLA thresholds for test validation
 type SLAConfig struct {
 	MaxP95Latency float64 `json:"max_p95_latency,omitempty"` // Max P95 latency in ms
@@ -28,21 +220,49 @@ type SLAConfig struct {
 
 // TestPlan defines the configuration for a stress test
 type TestPlan struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name" binding:"required"`
-	TargetURL   string            `json:"target_url" binding:"required,url"`
-	Method      string            `json:"method" binding:"required"`
-	Headers     map[string]string `json:"headers,omitempty"`
-	Body        string            `json:"body,omitempty"`
-	Users       int               `json:"users" binding:"required,min=1"`
-	RampUpSec   int               `json:"ramp_up_sec" binding:"min=0"`
-	DurationSec int               `json:"duration_sec" binding:"required,min=1"`
-	TimeoutMs   int               `json:"timeout_ms" binding:"min=0"`
-	TargetRPS   int               `json:"target_rps" binding:"min=0"` // 0 means unlimited
-	RatePattern RatePattern       `json:"rate_pattern,omitempty"`     // Default: fixed
-	RateSteps   []RateStep        `json:"rate_steps,omitempty"`       // For step/spike patterns
-	SLA         *SLAConfig        `json:"sla,omitempty"`              // SLA thresholds
-	CreatedAt   time.Time         `json:"created_at"`
+	ID              string            `json:"id"`
+	Name            string            `json:"name" binding:"required"`
+	TargetURL       string            `json:"target_url" binding:"omitempty,url"` // Unused when the plan runs a scenario
+	Method          string            `json:"method"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	BodySource      *BodySource       `json:"body_source,omitempty"`          // Builds a binary, file or form body, replaces Body
+	BodyFiles       BodyFiles         `json:"-"`                              // Resolved from the body sources' file IDs when a run starts
+	GRPC            *GRPCRequest      `json:"grpc,omitempty"`                 // gRPC call sent instead of an HTTP request, replaces TargetURL/Method/Headers/Body
+	Protos          ProtoSources      `json:"-"`                              // Resolved from the gRPC proto file IDs when a run starts
+	WebSocket       *WebSocketRequest `json:"websocket,omitempty"`            // WebSocket session opened instead of an HTTP request, replaces TargetURL/Method/Headers/Body
+	Socket          *SocketRequest    `json:"socket,omitempty"`               // Raw TCP or UDP exchange sent instead of an HTTP request, replaces TargetURL/Method/Headers/Body
+	DNS             *DNSRequest       `json:"dns,omitempty"`                  // DNS query sent instead of an HTTP request, replaces TargetURL/Method/Headers/Body
+	SQL             *SQLRequest       `json:"sql,omitempty"`                  // SQL statements run instead of HTTP requests, replace TargetURL/Method/Headers/Body; the database of a scenario's SQL steps
+	Redis           *RedisRequest     `json:"redis,omitempty"`                // Redis commands sent instead of HTTP requests, replace TargetURL/Method/Headers/Body
+	Requests        []WeightedRequest `json:"requests,omitempty"`             // Weighted request mix, replaces TargetURL/Method/Headers/Body
+	Assertions      []Assertion       `json:"assertions,omitempty"`           // Response checks for the plan's requests, replace the 2xx/3xx default when they check the status
+	Stream          *StreamConfig     `json:"stream,omitempty"`               // Reads successful HTTP responses as event streams instead of draining them
+	ScenarioID      string            `json:"scenario_id,omitempty"`          // Each VU iteration runs this scenario's full step chain
+	Scenario        *Scenario         `json:"-"`                              // Resolved from ScenarioID when a run starts
+	Data            *DataFeed         `json:"data,omitempty"`                 // Feeds {{data.column}} values, overrides the scenario's feed
+	DataSet         *DataSet          `json:"-"`                              // Resolved from the plan's or scenario's feed when a run starts
+	Users           int               `json:"users" binding:"required,min=1"` // Pre-allocated VUs for arrival_rate executor
+	RampUpSec       int               `json:"ramp_up_sec" binding:"min=0"`
+	DurationSec     int               `json:"duration_sec" binding:"required,min=1"`
+	TimeoutMs       int               `json:"timeout_ms" binding:"min=0"`  // Overall request timeout, including redirects and the body
+	Transport       *TransportConfig  `json:"transport,omitempty"`         // Client and connection settings, default: shared pool
+	TLS             *TLSConfig        `json:"tls,omitempty"`               // TLS and mTLS settings for target connections
+	TLSMaterial     *TLSMaterial      `json:"-"`                           // Resolved from the TLS secrets when a run starts
+	Credentials     *Credentials      `json:"-"`                           // Resolved from the sql or redis credentials secret when a run starts
+	Cookies         *CookieConfig     `json:"cookies,omitempty"`           // Per-VU cookie jar settings, default: a jar per VU
+	Retry           *RetryPolicy      `json:"retry,omitempty"`             // Retries failed requests, and scenario steps without their own policy
+	TargetRPS       int               `json:"target_rps" binding:"min=0"`  // 0 means unlimited
+	RatePattern     RatePattern       `json:"rate_pattern,omitempty"`      // Default: fixed
+	RateSteps       []RateStep        `json:"rate_steps,omitempty"`        // For step/spike patterns
+	Stages          []Stage           `json:"stages,omitempty"`            // For the stages pattern
+	Wave            *WaveConfig       `json:"wave,omitempty"`              // For sine/diurnal patterns
+	RampDownSec     int               `json:"ramp_down_sec,omitempty"`     // Scale rate and VUs down to zero over the end of the test
+	GracefulStopSec int               `json:"graceful_stop_sec,omitempty"` // Time in-flight iterations get to finish after the test ends
+	Executor        ExecutorType      `json:"executor,omitempty"`          // Default: closed
+	MaxVUs          int               `json:"max_vus,omitempty"`           // VU pool cap for arrival_rate executor (default: users)
+	SLA             *SLAConfig        `json:"sla,omitempty"`               // SLA thresholds
+	CreatedAt       time.Time         `json:"created_at"`
 }
 
 // TestRunStatus represents the status of a test run
@@ -66,33 +286,58 @@ const (
 
 // TestRun represents an execution instance of a TestPlan
 type TestRun struct {
-	ID         string        `json:"id"`
-	PlanID     string        `json:"plan_id"`
-	Status     TestRunStatus `json:"status"`
-	StopReason *StopReason   `json:"stop_reason,omitempty"` // How the run ended
-	StartAt    time.Time     `json:"start_at"`
-	EndAt      *time.Time    `json:"end_at,omitempty"`
-	CreatedAt  time.Time     `json:"created_at"`
+	ID         string         `json:"id"`
+	PlanID     string         `json:"plan_id"`
+	Status     TestRunStatus  `json:"status"`
+	StopReason *StopReason    `json:"stop_reason,omitempty"` // How the run ended
+	StartAt    time.Time      `json:"start_at"`
+	EndAt      *time.Time     `json:"end_at,omitempty"`
+	Events     []ControlEvent `json:"events,omitempty"` // Live control commands applied while running
+	CreatedAt  time.Time      `json:"created_at"`
 }
 
 // CreateTestPlanRequest represents the request to create a test plan
 type CreateTestPlanRequest struct {
-	Name        string            `json:"name" binding:"required"`
-	TargetURL   string            `json:"target_url" binding:"required,url"`
-	Method      string            `json:"method" binding:"required"`
-	Headers     map[string]string `json:"headers,omitempty"`
-	Body        string            `json:"body,omitempty"`
-	Users       int               `json:"users" binding:"required,min=1"`
-	RampUpSec   int               `json:"ramp_up_sec" binding:"min=0"`
-	DurationSec int               `json:"duration_sec" binding:"required,min=1"`
-	TimeoutMs   int               `json:"timeout_ms" binding:"min=0"`
-	TargetRPS   int               `json:"target_rps" binding:"min=0"`
-	RatePattern RatePattern       `json:"rate_pattern,omitempty"`
-	RateSteps   []RateStep        `json:"rate_steps,omitempty"`
-	SLA         *SLAConfig        `json:"sla,omitempty"`
+	Name            string            `json:"name" binding:"required"`
+	TargetURL       string            `json:"target_url,omitempty" binding:"omitempty,url"` // Required unless requests or scenario_id is set
+	Method          string            `json:"method,omitempty"`                             // Required unless requests or scenario_id is set
+	Headers         map[string]string `json:"headers,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	BodySource      *BodySource       `json:"body_source,omitempty"`
+	GRPC            *GRPCRequest      `json:"grpc,omitempty"`      // Replaces target_url and method
+	WebSocket       *WebSocketRequest `json:"websocket,omitempty"` // Replaces target_url and method
+	Socket          *SocketRequest    `json:"socket,omitempty"`    // Replaces target_url and method
+	DNS             *DNSRequest       `json:"dns,omitempty"`       // Replaces target_url and method
+	SQL             *SQLRequest       `json:"sql,omitempty"`       // Replaces target_url and method
+	Redis           *RedisRequest     `json:"redis,omitempty"`     // Replaces target_url and method
+	Requests        []WeightedRequest `json:"requests,omitempty" binding:"omitempty,dive"`
+	Assertions      []Assertion       `json:"assertions,omitempty" binding:"omitempty,dive"`
+	Stream          *StreamConfig     `json:"stream,omitempty"`
+	ScenarioID      string            `json:"scenario_id,omitempty"`
+	Data            *DataFeed         `json:"data,omitempty"`
+	Users           int               `json:"users" binding:"required,min=1"`
+	RampUpSec       int               `json:"ramp_up_sec" binding:"min=0"`
+	DurationSec     int               `json:"duration_sec" binding:"required,min=1"`
+	TimeoutMs       int               `json:"timeout_ms" binding:"min=0"`
+	Transport       *TransportConfig  `json:"transport,omitempty"`
+	TLS             *TLSConfig        `json:"tls,omitempty"`
+	Cookies         *CookieConfig     `json:"cookies,omitempty"`
+	Retry           *RetryPolicy      `json:"retry,omitempty"`
+	TargetRPS       int               `json:"target_rps" binding:"min=0"`
+	RatePattern     RatePattern       `json:"rate_pattern,omitempty"`
+	RateSteps       []RateStep        `json:"rate_steps,omitempty"`
+	Stages          []Stage           `json:"stages,omitempty" binding:"omitempty,dive"`
+	Wave            *WaveConfig       `json:"wave,omitempty"`
+	RampDownSec     int               `json:"ramp_down_sec,omitempty" binding:"min=0"`
+	GracefulStopSec int               `json:"graceful_stop_sec,omitempty" binding:"min=0"`
+	Executor        ExecutorType      `json:"executor,omitempty"`
+	MaxVUs          int               `json:"max_vus,omitempty" binding:"min=0"`
+	SLA             *SLAConfig        `json:"sla,omitempty"`
 }
 
 // StartTestRequest represents the request to start a test
 type StartTestRequest struct {
-	PlanID string `json:"plan_id" binding:"required"`
+	PlanID      string `json:"plan_id" binding:"required"`
+	Distributed bool   `json:"distributed,omitempty"`            // Split the run across registered agents
+	Agents      int    `json:"agents,omitempty" binding:"min=0"` // Agents to use for a distributed run, 0 for all available
 }
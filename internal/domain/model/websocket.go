@@ -0,0 +1,43 @@
+package model
+
+// WebSocketRequest is a WebSocket session a plan or scenario step opens
+// instead of sending an HTTP request. Each iteration connects, sends the
+// messages in order, waits for their expected replies, then holds the
+// connection open before closing it. A session counts as one request,
+// whose latency is the time the handshake took.
+type WebSocketRequest struct {
+	URL          string             `json:"url"`                    // ws:// or wss:// URL, supports templates
+	Headers      map[string]string  `json:"headers,omitempty"`      // Handshake request headers, values support templates
+	Subprotocols []string           `json:"subprotocols,omitempty"` // Offered in the handshake, in order of preference
+	Messages     []WebSocketMessage `json:"messages,omitempty"`     // Sent in order once connected
+	HoldMs       int                `json:"hold_ms,omitempty"`      // How long the connection stays open after the last message
+}
+
+// WebSocketMessage is a text message a session sends
+type WebSocketMessage struct {
+	Data       string          `json:"data"`                  // Message text, supports templates
+	DelayMs    int             `json:"delay_ms,omitempty"`    // Wait before the first send, after the previous message
+	Repeat     int             `json:"repeat,omitempty"`      // Times the message is sent, default: 1
+	IntervalMs int             `json:"interval_ms,omitempty"` // Wait between repeats
+	Reply      *WebSocketReply `json:"reply,omitempty"`       // Reply every send waits for, default: none
+}
+
+// WebSocketReply matches the reply a message waits for, by a JSONPath value
+// or a regex. Messages that do not match are counted as received and
+// otherwise ignored. A reply that does not arrive in time fails the session.
+type WebSocketReply struct {
+	JSONPath  string `json:"json_path,omitempty"`  // Field of a JSON reply, e.g. $.type
+	Value     string `json:"value,omitempty"`      // Value the field must have; any value when empty
+	Regex     string `json:"regex,omitempty"`      // Pattern the reply text must match
+	TimeoutMs int    `json:"timeout_ms,omitempty"` // Default: the plan's or step's timeout
+}
+
+// WebSocketSession summarises one WebSocket session
+type WebSocketSession struct {
+	ConnectMs        float64   `json:"connect_ms"` // Handshake time
+	MessagesSent     int64     `json:"messages_sent"`
+	MessagesReceived int64     `json:"messages_received"`
+	ReplyTimeouts    int64     `json:"reply_timeouts,omitempty"`
+	RoundTripsMs     []float64 `json:"round_trips_ms,omitempty"` // From each send to its matching reply
+	CloseCode        int       `json:"close_code,omitempty"`     // Close code of a connection the server closed abnormally
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+	"go.uber.org/zap"
+)
+
+// BodyFileService handles business logic for body file operations
+type BodyFileService struct {
+	bodyFileRepo repository.BodyFileRepository
+}
+
+// NewBodyFileService creates a new body file service
+func NewBodyFileService(bodyFileRepo repository.BodyFileRepository) *BodyFileService {
+	return &BodyFileService{bodyFileRepo: bodyFileRepo}
+}
+
+// CreateBodyFile stores an uploaded file to be sent as a request body or
+// multipart file part. Without a content type, one is detected from the
+// file's first bytes.
+func (s *BodyFileService) CreateBodyFile(name, contentType string, r io.Reader) (*model.BodyFile, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, domain.NewValidationError("name", "name is required")
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, domain.NewValidationError("file", "failed to read file: "+err.Error())
+	}
+	if len(content) == 0 {
+		return nil, domain.NewValidationError("file", "file is empty")
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	bodyFile := &model.BodyFile{
+		ID:          uuid.New().String(),
+		Name:        name,
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		Content:     content,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := s.bodyFileRepo.Create(bodyFile); err != nil {
+		logger.Log.Error("Failed to create body file", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Log.Info("Body file created",
+		zap.String("body_file_id", bodyFile.ID),
+		zap.String("name", bodyFile.Name),
+		zap.Int64("size", bodyFile.Size))
+
+	return bodyFile, nil
+}
+
+// GetBodyFile retrieves a body file by ID
+func (s *BodyFileService) GetBodyFile(id string) (*model.BodyFile, error) {
+	return s.bodyFileRepo.GetByID(id)
+}
+
+// GetAllBodyFiles retrieves all body files
+func (s *BodyFileService) GetAllBodyFiles() ([]*model.BodyFile, error) {
+	return s.bodyFileRepo.GetAll()
+}
+
+// DeleteBodyFile deletes a body file
+func (s *BodyFileService) DeleteBodyFile(id string) error {
+	return s.bodyFileRepo.Delete(id)
+}
+
+// bodySources lists the body sources of a plan's request, its request mix
+// and its scenario's steps
+func bodySources(source *model.BodySource, requests []model.WeightedRequest, steps []model.Step) []*model.BodySource {
+	var sources []*model.BodySource
+	if source != nil {
+		sources = append(sources, source)
+	}
+	for i := range requests {
+		if requests[i].BodySource != nil {
+			sources = append(sources, requests[i].BodySource)
+		}
+	}
+	for i := range steps {
+		if steps[i].BodySource != nil {
+			sources = append(sources, steps[i].BodySource)
+		}
+	}
+	return sources
+}
+
+// resolveBodyFiles loads the body files referenced by body sources. It
+// returns nil if they reference none.
+func resolveBodyFiles(repo repository.BodyFileRepository, sources []*model.BodySource) (model.BodyFiles, error) {
+	var files model.BodyFiles
+	for _, source := range sources {
+		for _, id := range source.FileIDs() {
+			if _, ok := files[id]; ok {
+				continue
+			}
+			if repo == nil {
+				return nil, domain.NewValidationError("body_source", "body files are not available")
+			}
+			file, err := repo.GetByID(id)
+			if err != nil || file == nil {
+				return nil, domain.NewNotFoundError("body file", id)
+			}
+			if files == nil {
+				files = make(model.BodyFiles)
+			}
+			files[id] = &model.BodyFileData{Name: file.Name, ContentType: file.ContentType, Data: file.Content}
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/config"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+)
+
+func TestCreateBodyFile(t *testing.T) {
+	service := NewBodyFileService(repository.NewMemoryBodyFileRepository())
+
+	bodyFile, err := service.CreateBodyFile("avatar.png", "", strings.NewReader("\x89PNG\r\n\x1a\n0000"))
+	if err != nil {
+		t.Fatalf("Failed to create body file: %v", err)
+	}
+	if bodyFile.ContentType != "image/png" || bodyFile.Size != 12 {
+		t.Errorf("Expected a detected image/png of 12 bytes, got %s of %d", bodyFile.ContentType, bodyFile.Size)
+	}
+
+	stored, err := service.GetBodyFile(bodyFile.ID)
+	if err != nil || string(stored.Content[:4]) != "\x89PNG" {
+		t.Errorf("Expected body file to be stored with its content, got %v (%v)", stored, err)
+	}
+
+	if _, err := service.CreateBodyFile("empty.bin", "", strings.NewReader("")); err == nil {
+		t.Error("Expected an empty file to be rejected")
+	}
+	if _, err := service.CreateBodyFile(" ", "text/plain", strings.NewReader("x")); err == nil {
+		t.Error("Expected a file without a name to be rejected")
+	}
+}
+
+func TestResolveBodyFiles(t *testing.T) {
+	repo := repository.NewMemoryBodyFileRepository()
+	_ = repo.Create(&model.BodyFile{ID: "doc", Name: "doc.pdf", ContentType: "application/pdf", Content: []byte("%PDF")})
+
+	sources := bodySources(
+		&model.BodySource{Type: model.BodySourceFile, FileID: "doc"},
+		[]model.WeightedRequest{{Name: "form", BodySource: &model.BodySource{Type: model.BodySourceForm}}},
+		[]model.Step{{Name: "upload", BodySource: &model.BodySource{
+			Type:  model.BodySourceMultipart,
+			Files: []model.FilePart{{Field: "doc", FileID: "doc"}},
+		}}},
+	)
+	if len(sources) != 3 {
+		t.Fatalf("Expected 3 body sources, got %d", len(sources))
+	}
+
+	files, err := resolveBodyFiles(repo, sources)
+	if err != nil {
+		t.Fatalf("Failed to resolve body files: %v", err)
+	}
+	if len(files) != 1 || files["doc"].Name != "doc.pdf" || string(files["doc"].Data) != "%PDF" {
+		t.Errorf("Expected the referenced file once, got %+v", files)
+	}
+
+	missing := []*model.BodySource{{Type: model.BodySourceFile, FileID: "missing"}}
+	var notFound *domain.NotFoundError
+	if _, err := resolveBodyFiles(repo, missing); !errors.As(err, &notFound) {
+		t.Errorf("Expected a missing file to be not found, got %v", err)
+	}
+	if _, err := resolveBodyFiles(nil, missing); err == nil {
+		t.Error("Expected an error without a body file repository")
+	}
+	if files, err := resolveBodyFiles(nil, sources[1:2]); err != nil || files != nil {
+		t.Errorf("Expected a source without files to need no repository, got %v (%v)", files, err)
+	}
+}
+
+func TestCreateTestPlanWithBodyFile(t *testing.T) {
+	repo := repository.NewMemoryBodyFileRepository()
+	_ = repo.Create(&model.BodyFile{ID: "payload", Name: "payload.bin", Content: []byte{0x08, 0x01}})
+
+	service := NewTestService(
+		repository.NewMemoryTestPlanRepository(),
+		repository.NewMemoryTestRunRepository(),
+		repository.NewMemoryMetricsRepository(),
+		nil,
+		nil,
+		nil,
+		repo,
+		nil,
+		nil,
+		&config.Config{MaxWorkers: 100, DefaultTimeout: 30000},
+	)
+
+	req := &model.CreateTestPlanRequest{
+		Name:        "Upload Plan",
+		TargetURL:   "http://localhost:8080/upload",
+		Method:      "POST",
+		Users:       1,
+		DurationSec: 10,
+		BodySource:  &model.BodySource{Type: model.BodySourceFile, FileID: "payload"},
+	}
+	plan, err := service.CreateTestPlan(req)
+	if err != nil {
+		t.Fatalf("Failed to create plan: %v", err)
+	}
+	if plan.BodySource == nil || plan.BodySource.FileID != "payload" || plan.BodyFiles != nil {
+		t.Errorf("Expected the plan to reference the file by ID only, got %+v", plan)
+	}
+
+	req.BodySource = &model.BodySource{Type: model.BodySourceFile, FileID: "missing"}
+	if _, err := service.CreateTestPlan(req); err == nil {
+		t.Error("Expected a plan referencing a missing file to be rejected")
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+)
+
+func TestCreateDataSetCSV(t *testing.T) {
+	service := NewDataSetService(repository.NewMemoryDataSetRepository())
+
+	csv := "user_id, sku\n42,ABC-1\n43,\"DEF,2\"\n"
+	dataSet, err := service.CreateDataSet("users", model.DataSetFormatCSV, strings.NewReader(csv))
+	if err != nil {
+		t.Fatalf("Failed to create data set: %v", err)
+	}
+
+	if dataSet.RowCount != 2 || len(dataSet.Columns) != 2 || dataSet.Columns[1] != "sku" {
+		t.Errorf("Expected 2 rows with columns [user_id sku], got %d rows with %v", dataSet.RowCount, dataSet.Columns)
+	}
+	if dataSet.Rows[1]["user_id"] != "43" || dataSet.Rows[1]["sku"] != "DEF,2" {
+		t.Errorf("Unexpected second row: %v", dataSet.Rows[1])
+	}
+
+	stored, err := service.GetDataSet(dataSet.ID)
+	if err != nil || stored.Name != "users" {
+		t.Errorf("Expected data set to be stored, got %v (%v)", stored, err)
+	}
+}
+
+func TestCreateDataSetJSONL(t *testing.T) {
+	service := NewDataSetService(repository.NewMemoryDataSetRepository())
+
+	jsonl := `{"user": "alice", "id": 1}
+
+{"user": "bob", "id": 2, "tags": ["a"]}
+`
+	dataSet, err := service.CreateDataSet("accounts", model.DataSetFormatJSONL, strings.NewReader(jsonl))
+	if err != nil {
+		t.Fatalf("Failed to create data set: %v", err)
+	}
+
+	if dataSet.RowCount != 2 {
+		t.Fatalf("Expected 2 rows, got %d", dataSet.RowCount)
+	}
+	if got := strings.Join(dataSet.Columns, ","); got != "id,user,tags" {
+		t.Errorf("Expected columns id,user,tags, got %s", got)
+	}
+	if dataSet.Rows[0]["user"] != "alice" || dataSet.Rows[0]["id"] != "1" || dataSet.Rows[1]["tags"] != `["a"]` {
+		t.Errorf("Unexpected rows: %v", dataSet.Rows)
+	}
+}
+
+func TestCreateDataSetInvalid(t *testing.T) {
+	service := NewDataSetService(repository.NewMemoryDataSetRepository())
+
+	tests := []struct {
+		name   string
+		format model.DataSetFormat
+		input  string
+	}{
+		{"unknown format", "xml", "<rows/>"},
+		{"empty csv", model.DataSetFormatCSV, ""},
+		{"header only", model.DataSetFormatCSV, "user_id\n"},
+		{"ragged csv", model.DataSetFormatCSV, "a,b\n1\n"},
+		{"jsonl array", model.DataSetFormatJSONL, "[1, 2]\n"},
+	}
+
+	for _, tc := range tests {
+		if _, err := service.CreateDataSet("invalid", tc.format, strings.NewReader(tc.input)); err == nil {
+			t.Errorf("%s: expected error", tc.name)
+		}
+	}
+}
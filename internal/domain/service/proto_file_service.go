@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/engine"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ProtoFileService handles business logic for proto file operations
+type ProtoFileService struct {
+	protoFileRepo repository.ProtoFileRepository
+}
+
+// NewProtoFileService creates a new proto file service
+func NewProtoFileService(protoFileRepo repository.ProtoFileRepository) *ProtoFileService {
+	return &ProtoFileService{protoFileRepo: protoFileRepo}
+}
+
+// CreateProtoFile stores an uploaded .proto file describing gRPC services.
+// The name is the path other proto files import it by, so it must be
+// unique, and the files it imports must have been uploaded before it.
+func (s *ProtoFileService) CreateProtoFile(name string, r io.Reader) (*model.ProtoFile, error) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "/")
+	if name == "" {
+		return nil, domain.NewValidationError("name", "name is required")
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, domain.NewValidationError("file", "failed to read file: "+err.Error())
+	}
+	if len(content) == 0 {
+		return nil, domain.NewValidationError("file", "file is empty")
+	}
+
+	sources, err := storedProtos(s.protoFileRepo)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := sources[name]; exists {
+		return nil, domain.NewValidationError("name", "a proto file named "+name+" already exists")
+	}
+	sources[name] = string(content)
+	files, err := engine.CompileProtos(context.Background(), sources, name)
+	if err != nil {
+		return nil, domain.NewValidationError("file", "invalid proto file: "+err.Error())
+	}
+
+	protoFile := &model.ProtoFile{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Services:  make([]string, 0),
+		Methods:   make([]string, 0),
+		Size:      int64(len(content)),
+		Content:   string(content),
+		CreatedAt: time.Now(),
+	}
+	services := files[0].Services()
+	for i := 0; i < services.Len(); i++ {
+		sd := services.Get(i)
+		protoFile.Services = append(protoFile.Services, string(sd.FullName()))
+		for j := 0; j < sd.Methods().Len(); j++ {
+			protoFile.Methods = append(protoFile.Methods, string(sd.FullName())+"/"+string(sd.Methods().Get(j).Name()))
+		}
+	}
+
+	if err := s.protoFileRepo.Create(protoFile); err != nil {
+		logger.Log.Error("Failed to create proto file", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Log.Info("Proto file created",
+		zap.String("proto_file_id", protoFile.ID),
+		zap.String("name", protoFile.Name),
+		zap.Strings("services", protoFile.Services))
+
+	return protoFile, nil
+}
+
+// GetProtoFile retrieves a proto file by ID
+func (s *ProtoFileService) GetProtoFile(id string) (*model.ProtoFile, error) {
+	return s.protoFileRepo.GetByID(id)
+}
+
+// GetAllProtoFiles retrieves all proto files
+func (s *ProtoFileService) GetAllProtoFiles() ([]*model.ProtoFile, error) {
+	return s.protoFileRepo.GetAll()
+}
+
+// DeleteProtoFile deletes a proto file
+func (s *ProtoFileService) DeleteProtoFile(id string) error {
+	return s.protoFileRepo.Delete(id)
+}
+
+// storedProtos returns the content of every proto file in repo by name
+func storedProtos(repo repository.ProtoFileRepository) (model.ProtoSources, error) {
+	protoFiles, err := repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	sources := make(model.ProtoSources, len(protoFiles))
+	for _, protoFile := range protoFiles {
+		sources[protoFile.Name] = protoFile.Content
+	}
+	return sources, nil
+}
+
+// grpcRequests lists the gRPC calls of a plan and its scenario's steps
+func grpcRequests(request *model.GRPCRequest, steps []model.Step) []*model.GRPCRequest {
+	var requests []*model.GRPCRequest
+	if request != nil {
+		requests = append(requests, request)
+	}
+	for i := range steps {
+		if steps[i].GRPC != nil {
+			requests = append(requests, steps[i].GRPC)
+		}
+	}
+	return requests
+}
+
+// resolveProtos loads the proto files gRPC calls reference, with every file
+// they import, and checks that each call's method is defined in them. It
+// returns nil if the calls reference none and use server reflection.
+func resolveProtos(repo repository.ProtoFileRepository, requests []*model.GRPCRequest) (model.ProtoSources, error) {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, req := range requests {
+		for _, id := range req.ProtoFileIDs {
+			if repo == nil {
+				return nil, domain.NewValidationError("grpc", "proto files are not available")
+			}
+			protoFile, err := repo.GetByID(id)
+			if err != nil || protoFile == nil {
+				return nil, domain.NewNotFoundError("proto file", id)
+			}
+			if !seen[protoFile.Name] {
+				seen[protoFile.Name] = true
+				paths = append(paths, protoFile.Name)
+			}
+		}
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	stored, err := storedProtos(repo)
+	if err != nil {
+		return nil, err
+	}
+	files, err := engine.CompileProtos(context.Background(), stored, paths...)
+	if err != nil {
+		return nil, domain.NewValidationError("grpc", "invalid proto files: "+err.Error())
+	}
+	for _, req := range requests {
+		if len(req.ProtoFileIDs) == 0 {
+			continue
+		}
+		if _, err := engine.FindGRPCMethod(files, req.Method); err != nil {
+			return nil, domain.NewValidationError("grpc.method", err.Error())
+		}
+	}
+
+	// Keep the referenced files and the uploaded files they import; the
+	// well-known types are built into the engine
+	protos := make(model.ProtoSources)
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		content, ok := stored[fd.Path()]
+		if _, done := protos[fd.Path()]; done || !ok {
+			return
+		}
+		protos[fd.Path()] = content
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+	}
+	for _, file := range files {
+		add(file)
+	}
+	return protos, nil
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+)
+
+const commonProto = `syntax = "proto3";
+package shop.common;
+message Money { string currency = 1; int64 units = 2; }`
+
+const orderProto = `syntax = "proto3";
+package shop.v1;
+import "shop/common.proto";
+message Order { string id = 1; shop.common.Money total = 2; }
+message GetOrderRequest { string id = 1; }
+service Orders {
+  rpc GetOrder(GetOrderRequest) returns (Order);
+  rpc WatchOrders(GetOrderRequest) returns (stream Order);
+}`
+
+func TestCreateProtoFile(t *testing.T) {
+	service := NewProtoFileService(repository.NewMemoryProtoFileRepository())
+
+	if _, err := service.CreateProtoFile("shop/orders.proto", strings.NewReader(orderProto)); err == nil {
+		t.Error("Expected a file whose import has not been uploaded to be rejected")
+	}
+	if _, err := service.CreateProtoFile("shop/common.proto", strings.NewReader(commonProto)); err != nil {
+		t.Fatalf("Failed to create proto file: %v", err)
+	}
+
+	protoFile, err := service.CreateProtoFile("shop/orders.proto", strings.NewReader(orderProto))
+	if err != nil {
+		t.Fatalf("Failed to create proto file: %v", err)
+	}
+	if fmt.Sprint(protoFile.Services) != "[shop.v1.Orders]" ||
+		fmt.Sprint(protoFile.Methods) != "[shop.v1.Orders/GetOrder shop.v1.Orders/WatchOrders]" {
+		t.Errorf("Expected the file's service and methods, got %v and %v", protoFile.Services, protoFile.Methods)
+	}
+
+	if _, err := service.CreateProtoFile("shop/common.proto", strings.NewReader(commonProto)); err == nil {
+		t.Error("Expected a duplicate name to be rejected")
+	}
+	if _, err := service.CreateProtoFile("broken.proto", strings.NewReader("service {")); err == nil {
+		t.Error("Expected an invalid file to be rejected")
+	}
+}
+
+func TestResolveProtos(t *testing.T) {
+	repo := repository.NewMemoryProtoFileRepository()
+	_ = repo.Create(&model.ProtoFile{ID: "common", Name: "shop/common.proto", Content: commonProto})
+	_ = repo.Create(&model.ProtoFile{ID: "orders", Name: "shop/orders.proto", Content: orderProto})
+	_ = repo.Create(&model.ProtoFile{ID: "unused", Name: "unused.proto", Content: `syntax = "proto3";`})
+
+	requests := grpcRequests(
+		&model.GRPCRequest{Method: "shop.v1.Orders/GetOrder", ProtoFileIDs: []string{"orders"}},
+		[]model.Step{{Name: "health", GRPC: &model.GRPCRequest{Method: "grpc.health.v1.Health/Check"}}},
+	)
+	protos, err := resolveProtos(repo, requests)
+	if err != nil {
+		t.Fatalf("Failed to resolve proto files: %v", err)
+	}
+	if len(protos) != 2 || protos["shop/orders.proto"] != orderProto || protos["shop/common.proto"] != commonProto {
+		t.Errorf("Expected the referenced file and its import, got %v", protos)
+	}
+
+	if protos, err := resolveProtos(nil, requests[1:]); err != nil || protos != nil {
+		t.Errorf("Expected reflection calls to need no proto files, got %v (%v)", protos, err)
+	}
+	if _, err := resolveProtos(nil, requests); err == nil {
+		t.Error("Expected an error without a proto file repository")
+	}
+
+	var notFound *domain.NotFoundError
+	missing := []*model.GRPCRequest{{Method: "shop.v1.Orders/GetOrder", ProtoFileIDs: []string{"missing"}}}
+	if _, err := resolveProtos(repo, missing); !errors.As(err, &notFound) {
+		t.Errorf("Expected a missing file to be not found, got %v", err)
+	}
+	unknown := []*model.GRPCRequest{{Method: "shop.v1.Orders/CancelOrder", ProtoFileIDs: []string{"orders"}}}
+	if _, err := resolveProtos(repo, unknown); err == nil {
+		t.Error("Expected a method the files do not define to be rejected")
+	}
+}
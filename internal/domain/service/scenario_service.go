@@ -16,29 +16,43 @@ type ScenarioService struct {
 	scenarioRepo          repository.ScenarioRepository
 	scenarioExecutionRepo repository.ScenarioExecutionRepository
 	executor              *engine.ScenarioExecutor
+	bodyFileRepo          repository.BodyFileRepository
+	protoRepo             repository.ProtoFileRepository
 }
 
 // NewScenarioService creates a new scenario service
 func NewScenarioService(
 	scenarioRepo repository.ScenarioRepository,
 	scenarioExecutionRepo repository.ScenarioExecutionRepository,
+	bodyFileRepo repository.BodyFileRepository,
+	protoRepo repository.ProtoFileRepository,
 	executor *engine.ScenarioExecutor,
 ) *ScenarioService {
 	return &ScenarioService{
 		scenarioRepo:          scenarioRepo,
 		scenarioExecutionRepo: scenarioExecutionRepo,
+		bodyFileRepo:          bodyFileRepo,
+		protoRepo:             protoRepo,
 		executor:              executor,
 	}
 }
 
 // CreateScenario creates a new scenario
 func (s *ScenarioService) CreateScenario(req *model.CreateScenarioRequest) (*model.Scenario, error) {
+	if _, err := resolveBodyFiles(s.bodyFileRepo, bodySources(nil, nil, req.Steps)); err != nil {
+		return nil, err
+	}
+	if _, err := resolveProtos(s.protoRepo, grpcRequests(nil, req.Steps)); err != nil {
+		return nil, err
+	}
+
 	scenario := &model.Scenario{
 		ID:          uuid.New().String(),
 		Name:        req.Name,
 		Description: req.Description,
 		Steps:       req.Steps,
 		Variables:   req.Variables,
+		Data:        req.Data,
 		CreatedAt:   time.Now(),
 	}
 
@@ -78,8 +92,16 @@ func (s *ScenarioService) ExecuteScenario(req *model.ExecuteScenarioRequest) (*m
 		return nil, err
 	}
 
-	// Execute scenario
-	execution, err := s.executor.Execute(scenario, req.Variables)
+	// Execute scenario, with the files its steps send and describe their calls
+	bodyFiles, err := resolveBodyFiles(s.bodyFileRepo, bodySources(nil, nil, scenario.Steps))
+	if err != nil {
+		return nil, err
+	}
+	protos, err := resolveProtos(s.protoRepo, grpcRequests(nil, scenario.Steps))
+	if err != nil {
+		return nil, err
+	}
+	execution, err := s.executor.WithBodyFiles(bodyFiles).WithProtos(protos).Execute(scenario, req.Variables)
 	if err != nil {
 		// Even if execution failed, we want to store the result
 		logger.Log.Warn("Scenario execution failed",
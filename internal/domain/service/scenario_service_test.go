@@ -21,7 +21,7 @@ func TestNewScenarioService(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	if service == nil {
 		t.Fatal("Expected ScenarioService to be created")
@@ -32,7 +32,7 @@ func TestCreateScenario(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	req := &model.CreateScenarioRequest{
 		Name:        "Login Scenario",
@@ -77,7 +77,7 @@ func TestGetScenario(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	req := &model.CreateScenarioRequest{
 		Name:        "Test Scenario",
@@ -113,7 +113,7 @@ func TestGetScenarioNotFound(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	_, err := service.GetScenario("non-existent-id")
 	if err == nil {
@@ -125,7 +125,7 @@ func TestGetAllScenarios(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	// Create multiple scenarios
 	for i := 0; i < 3; i++ {
@@ -160,7 +160,7 @@ func TestDeleteScenario(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	req := &model.CreateScenarioRequest{
 		Name:        "Delete Test",
@@ -196,7 +196,7 @@ func TestCreateScenarioTimestamp(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	beforeCreate := time.Now()
 
@@ -228,7 +228,7 @@ func TestCreateScenarioWithVariables(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	req := &model.CreateScenarioRequest{
 		Name:        "Variables Test",
@@ -264,7 +264,7 @@ func TestCreateScenarioMultipleSteps(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	req := &model.CreateScenarioRequest{
 		Name:        "Multi-Step Scenario",
@@ -321,7 +321,7 @@ func TestDeleteNonExistentScenario(t *testing.T) {
 	scenarioRepo := repository.NewMemoryScenarioRepository()
 	executionRepo := repository.NewMemoryScenarioExecutionRepository()
 
-	service := NewScenarioService(scenarioRepo, executionRepo, nil)
+	service := NewScenarioService(scenarioRepo, executionRepo, nil, nil, nil)
 
 	err := service.DeleteScenario("non-existent-id")
 	if err == nil {
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+	"go.uber.org/zap"
+)
+
+// SecretService handles business logic for secret operations
+type SecretService struct {
+	secretRepo repository.SecretRepository
+}
+
+// NewSecretService creates a new secret service
+func NewSecretService(secretRepo repository.SecretRepository) *SecretService {
+	return &SecretService{secretRepo: secretRepo}
+}
+
+// CreateSecret validates and stores key material. A tls_client secret must
+// hold a certificate chain and its matching private key, a ca_bundle secret
+// one or more CA certificates, and a credentials secret a password.
+func (s *SecretService) CreateSecret(req *model.CreateSecretRequest) (*model.Secret, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, domain.NewValidationError("name", "name is required")
+	}
+
+	if (req.Type == model.SecretTLSClient || req.Type == model.SecretCABundle) && (req.Username != "" || req.Password != "") {
+		return nil, domain.NewValidationError("password", fmt.Sprintf("a %s secret must not contain a username or password", req.Type))
+	}
+
+	var leaf *x509.Certificate
+	switch req.Type {
+	case model.SecretTLSClient:
+		pair, err := tls.X509KeyPair([]byte(req.Certificate), []byte(req.PrivateKey))
+		if err != nil {
+			return nil, domain.NewValidationError("certificate", fmt.Sprintf("invalid certificate or private key: %v", err))
+		}
+		leaf, err = x509.ParseCertificate(pair.Certificate[0])
+		if err != nil {
+			return nil, domain.NewValidationError("certificate", fmt.Sprintf("invalid certificate: %v", err))
+		}
+	case model.SecretCABundle:
+		if req.PrivateKey != "" {
+			return nil, domain.NewValidationError("private_key", "a ca_bundle secret must not contain a private key")
+		}
+		var err error
+		leaf, err = parseCABundle(req.Certificate)
+		if err != nil {
+			return nil, err
+		}
+	case model.SecretCredentials:
+		if req.Certificate != "" || req.PrivateKey != "" {
+			return nil, domain.NewValidationError("certificate", "a credentials secret must not contain a certificate or private key")
+		}
+		if req.Password == "" {
+			return nil, domain.NewValidationError("password", "password is required")
+		}
+	default:
+		return nil, domain.NewValidationError("type", fmt.Sprintf("invalid type: %s (must be: tls_client, ca_bundle or credentials)", req.Type))
+	}
+
+	secret := &model.Secret{
+		ID:          uuid.New().String(),
+		Name:        req.Name,
+		Type:        req.Type,
+		Username:    req.Username,
+		Certificate: req.Certificate,
+		PrivateKey:  req.PrivateKey,
+		Password:    req.Password,
+		CreatedAt:   time.Now(),
+	}
+	if leaf != nil {
+		notAfter := leaf.NotAfter
+		secret.Subject = leaf.Subject.String()
+		secret.NotAfter = &notAfter
+	}
+
+	if err := s.secretRepo.Create(secret); err != nil {
+		logger.Log.Error("Failed to create secret", zap.Error(err))
+		return nil, err
+	}
+
+	logger.Log.Info("Secret created",
+		zap.String("secret_id", secret.ID),
+		zap.String("name", secret.Name),
+		zap.String("type", string(secret.Type)))
+
+	return secret, nil
+}
+
+// GetSecret retrieves a secret by ID
+func (s *SecretService) GetSecret(id string) (*model.Secret, error) {
+	return s.secretRepo.GetByID(id)
+}
+
+// GetAllSecrets retrieves all secrets
+func (s *SecretService) GetAllSecrets() ([]*model.Secret, error) {
+	return s.secretRepo.GetAll()
+}
+
+// DeleteSecret deletes a secret
+func (s *SecretService) DeleteSecret(id string) error {
+	return s.secretRepo.Delete(id)
+}
+
+// parseCABundle checks that a PEM bundle holds only certificates and returns
+// the first of them
+func parseCABundle(bundle string) (*x509.Certificate, error) {
+	var first *x509.Certificate
+	rest := []byte(bundle)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, domain.NewValidationError("certificate", fmt.Sprintf("unexpected PEM block %q in CA bundle", block.Type))
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, domain.NewValidationError("certificate", fmt.Sprintf("invalid certificate: %v", err))
+		}
+		if first == nil {
+			first = cert
+		}
+	}
+	if first == nil {
+		return nil, domain.NewValidationError("certificate", "no PEM certificates found")
+	}
+	return first, nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+)
+
+// selfSignedPEM returns a PEM certificate and private key for name
+func selfSignedPEM(t *testing.T, name string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestCreateSecret(t *testing.T) {
+	service := NewSecretService(repository.NewMemorySecretRepository())
+	cert, key := selfSignedPEM(t, "load-tester")
+
+	secret, err := service.CreateSecret(&model.CreateSecretRequest{
+		Name:        "client",
+		Type:        model.SecretTLSClient,
+		Certificate: cert,
+		PrivateKey:  key,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create secret: %v", err)
+	}
+	if secret.Subject != "CN=load-tester" || secret.NotAfter == nil || secret.PrivateKey != key {
+		t.Errorf("Unexpected secret: %+v", secret)
+	}
+
+	stored, err := service.GetSecret(secret.ID)
+	if err != nil || stored.Name != "client" {
+		t.Errorf("Expected secret to be stored, got %v (%v)", stored, err)
+	}
+
+	bundle, err := service.CreateSecret(&model.CreateSecretRequest{Name: "ca", Type: model.SecretCABundle, Certificate: cert + cert})
+	if err != nil || bundle.Subject != "CN=load-tester" {
+		t.Errorf("Expected a CA bundle to be stored, got %v (%v)", bundle, err)
+	}
+
+	creds, err := service.CreateSecret(&model.CreateSecretRequest{Name: "db", Type: model.SecretCredentials, Username: "load", Password: "secret"})
+	if err != nil || creds.Username != "load" || creds.Password != "secret" || creds.NotAfter != nil {
+		t.Errorf("Expected credentials to be stored, got %v (%v)", creds, err)
+	}
+}
+
+func TestCreateSecretValidation(t *testing.T) {
+	service := NewSecretService(repository.NewMemorySecretRepository())
+	cert, key := selfSignedPEM(t, "load-tester")
+	_, otherKey := selfSignedPEM(t, "other")
+
+	tests := []struct {
+		name  string
+		req   model.CreateSecretRequest
+		field string
+	}{
+		{"unknown type", model.CreateSecretRequest{Name: "s", Type: "password", Certificate: cert}, "type"},
+		{"missing key", model.CreateSecretRequest{Name: "s", Type: model.SecretTLSClient, Certificate: cert}, "certificate"},
+		{"mismatched key", model.CreateSecretRequest{Name: "s", Type: model.SecretTLSClient, Certificate: cert, PrivateKey: otherKey}, "certificate"},
+		{"key in CA bundle", model.CreateSecretRequest{Name: "s", Type: model.SecretCABundle, Certificate: cert, PrivateKey: key}, "private_key"},
+		{"key block in CA bundle", model.CreateSecretRequest{Name: "s", Type: model.SecretCABundle, Certificate: cert + key}, "certificate"},
+		{"not PEM", model.CreateSecretRequest{Name: "s", Type: model.SecretCABundle, Certificate: "hello"}, "certificate"},
+		{"blank name", model.CreateSecretRequest{Name: " ", Type: model.SecretCABundle, Certificate: cert}, "name"},
+		{"missing password", model.CreateSecretRequest{Name: "s", Type: model.SecretCredentials, Username: "load"}, "password"},
+		{"certificate in credentials", model.CreateSecretRequest{Name: "s", Type: model.SecretCredentials, Password: "p", Certificate: cert}, "certificate"},
+		{"password in CA bundle", model.CreateSecretRequest{Name: "s", Type: model.SecretCABundle, Certificate: cert, Password: "p"}, "password"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateSecret(&tt.req)
+			validationErr, ok := err.(*domain.ValidationError)
+			if !ok {
+				t.Fatalf("Expected a validation error, got %v", err)
+			}
+			if validationErr.Field != tt.field {
+				t.Errorf("Expected an error for %s, got %s: %s", tt.field, validationErr.Field, validationErr.Message)
+			}
+			if strings.Contains(validationErr.Message, "PRIVATE KEY-----") {
+				t.Error("Expected the error not to echo key material")
+			}
+		})
+	}
+}
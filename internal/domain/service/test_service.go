@@ -2,10 +2,12 @@ package service
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/config"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/distributed"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/engine"
@@ -16,11 +18,32 @@ import (
 
 // TestService handles business logic for test operations
 type TestService struct {
-	planRepo    repository.TestPlanRepository
-	runRepo     repository.TestRunRepository
-	metricsRepo repository.MetricsRepository
-	generator   *engine.LoadGenerator
-	config      *config.Config
+	planRepo     repository.TestPlanRepository
+	runRepo      repository.TestRunRepository
+	metricsRepo  repository.MetricsRepository
+	scenarioRepo repository.ScenarioRepository
+	dataSetRepo  repository.DataSetRepository
+	secretRepo   repository.SecretRepository
+	bodyFileRepo repository.BodyFileRepository
+	protoRepo    repository.ProtoFileRepository
+	generator    *engine.LoadGenerator
+	controller   *distributed.Controller
+	config       *config.Config
+
+	// live holds the runs whose live metrics may still be saved; a run
+	// leaves it when its final metrics are saved
+	liveMu sync.Mutex
+	live   map[string]bool
+}
+
+// testRunner runs tests, either on the local load generator or split across
+// agents by the distributed controller
+type testRunner interface {
+	StopTest(runID string) error
+	ControlTest(runID string, cmd model.ControlCommand) error
+	GetMetrics(runID string) (*model.Metrics, error)
+	GetHistograms(runID string) (latency, response *model.Histogram, err error)
+	IsRunning(runID string) bool
 }
 
 // NewTestService creates a new test service
@@ -28,16 +51,59 @@ func NewTestService(
 	planRepo repository.TestPlanRepository,
 	runRepo repository.TestRunRepository,
 	metricsRepo repository.MetricsRepository,
+	scenarioRepo repository.ScenarioRepository,
+	dataSetRepo repository.DataSetRepository,
+	secretRepo repository.SecretRepository,
+	bodyFileRepo repository.BodyFileRepository,
+	protoRepo repository.ProtoFileRepository,
 	generator *engine.LoadGenerator,
 	cfg *config.Config,
 ) *TestService {
-	return &TestService{
-		planRepo:    planRepo,
-		runRepo:     runRepo,
-		metricsRepo: metricsRepo,
-		generator:   generator,
-		config:      cfg,
+	s := &TestService{
+		planRepo:     planRepo,
+		runRepo:      runRepo,
+		metricsRepo:  metricsRepo,
+		scenarioRepo: scenarioRepo,
+		dataSetRepo:  dataSetRepo,
+		secretRepo:   secretRepo,
+		bodyFileRepo: bodyFileRepo,
+		protoRepo:    protoRepo,
+		generator:    generator,
+		config:       cfg,
+		live:         make(map[string]bool),
+	}
+
+	// Persist the final metrics, including the latency histograms, as soon
+	// as a run finishes so it can be re-queried at any percentile later
+	if generator != nil {
+		generator.SetCompletionHandler(s.saveFinalMetrics)
+	}
+
+	return s
+}
+
+// SetController enables distributed runs on the agents registered with c
+func (s *TestService) SetController(c *distributed.Controller) {
+	s.controller = c
+	c.SetCompletionHandler(s.finishDistributedRun)
+}
+
+// runner returns whichever of the distributed controller and the local load
+// generator is running a test, the generator if neither is
+func (s *TestService) runner(runID string) testRunner {
+	if s.controller != nil && s.controller.IsRunning(runID) {
+		return s.controller
+	}
+	if s.generator == nil {
+		return nil
 	}
+	return s.generator
+}
+
+// isRunning checks if a test is running locally or on agents
+func (s *TestService) isRunning(runID string) bool {
+	r := s.runner(runID)
+	return r != nil && r.IsRunning(runID)
 }
 
 // CreateTestPlan creates a new test plan
@@ -46,32 +112,90 @@ func (s *TestService) CreateTestPlan(req *model.CreateTestPlanRequest) (*model.T
 	if req.Users > s.config.MaxWorkers {
 		return nil, fmt.Errorf("users (%d) exceeds maximum allowed workers (%d)", req.Users, s.config.MaxWorkers)
 	}
+	if req.MaxVUs > s.config.MaxWorkers {
+		return nil, fmt.Errorf("max_vus (%d) exceeds maximum allowed workers (%d)", req.MaxVUs, s.config.MaxWorkers)
+	}
+	if req.ScenarioID != "" {
+		if _, err := s.getScenario(req.ScenarioID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Data != nil {
+		if _, err := s.getDataSet(req.Data.DataSetID); err != nil {
+			return nil, err
+		}
+	}
+	if req.TLS != nil {
+		if _, err := s.resolveTLS(req.TLS); err != nil {
+			return nil, err
+		}
+	}
+	if field, id := credentialsSecretID(req.SQL, req.Redis); id != "" {
+		if _, err := s.resolveCredentials(field, id); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := resolveBodyFiles(s.bodyFileRepo, bodySources(req.BodySource, req.Requests, nil)); err != nil {
+		return nil, err
+	}
+	if _, err := resolveProtos(s.protoRepo, grpcRequests(req.GRPC, nil)); err != nil {
+		return nil, err
+	}
 
 	plan := &model.TestPlan{
-		ID:          uuid.New().String(),
-		Name:        req.Name,
-		TargetURL:   req.TargetURL,
-		Method:      req.Method,
-		Headers:     req.Headers,
-		Body:        req.Body,
-		Users:       req.Users,
-		RampUpSec:   req.RampUpSec,
-		DurationSec: req.DurationSec,
-		TimeoutMs:   req.TimeoutMs,
-		TargetRPS:   req.TargetRPS,
-		RatePattern: req.RatePattern,
-		RateSteps:   req.RateSteps,
-		SLA:         req.SLA,
-		CreatedAt:   time.Now(),
+		ID:              uuid.New().String(),
+		Name:            req.Name,
+		TargetURL:       req.TargetURL,
+		Method:          req.Method,
+		Headers:         req.Headers,
+		Body:            req.Body,
+		BodySource:      req.BodySource,
+		Requests:        req.Requests,
+		GRPC:            req.GRPC,
+		WebSocket:       req.WebSocket,
+		Assertions:      req.Assertions,
+		Stream:          req.Stream,
+		Socket:          req.Socket,
+		DNS:             req.DNS,
+		SQL:             req.SQL,
+		Redis:           req.Redis,
+		ScenarioID:      req.ScenarioID,
+		Data:            req.Data,
+		Users:           req.Users,
+		RampUpSec:       req.RampUpSec,
+		DurationSec:     req.DurationSec,
+		TimeoutMs:       req.TimeoutMs,
+		Transport:       req.Transport,
+		TLS:             req.TLS,
+		Cookies:         req.Cookies,
+		Retry:           req.Retry,
+		TargetRPS:       req.TargetRPS,
+		RatePattern:     req.RatePattern,
+		RateSteps:       req.RateSteps,
+		Stages:          req.Stages,
+		Wave:            req.Wave,
+		RampDownSec:     req.RampDownSec,
+		Executor:        req.Executor,
+		MaxVUs:          req.MaxVUs,
+		GracefulStopSec: req.GracefulStopSec,
+		SLA:             req.SLA,
+		CreatedAt:       time.Now(),
 	}
 
 	// Set defaults
 	if plan.TimeoutMs == 0 {
 		plan.TimeoutMs = s.config.DefaultTimeout
+		if plan.Stream != nil && plan.Stream.MaxDurationMs > plan.TimeoutMs {
+			return nil, domain.NewValidationError("stream.max_duration_ms",
+				fmt.Sprintf("max_duration_ms (%d) cannot exceed the default timeout (%d ms), which ends the stream first", plan.Stream.MaxDurationMs, plan.TimeoutMs))
+		}
 	}
 	if plan.RatePattern == "" {
 		plan.RatePattern = model.RatePatternFixed
 	}
+	if plan.Executor == "" {
+		plan.Executor = model.ExecutorClosed
+	}
 
 	if err := s.planRepo.Create(plan); err != nil {
 		return nil, err
@@ -84,6 +208,87 @@ func (s *TestService) CreateTestPlan(req *model.CreateTestPlanRequest) (*model.T
 	return plan, nil
 }
 
+// getScenario looks up a scenario referenced by a test plan
+func (s *TestService) getScenario(id string) (*model.Scenario, error) {
+	if s.scenarioRepo == nil {
+		return nil, domain.NewValidationError("scenario_id", "scenarios are not available")
+	}
+	scenario, err := s.scenarioRepo.GetByID(id)
+	if err != nil || scenario == nil {
+		return nil, domain.NewNotFoundError("scenario", id)
+	}
+	return scenario, nil
+}
+
+// getDataSet looks up a data set referenced by a data feed
+func (s *TestService) getDataSet(id string) (*model.DataSet, error) {
+	if s.dataSetRepo == nil {
+		return nil, domain.NewValidationError("data.data_set_id", "data sets are not available")
+	}
+	dataSet, err := s.dataSetRepo.GetByID(id)
+	if err != nil || dataSet == nil {
+		return nil, domain.NewNotFoundError("data set", id)
+	}
+	return dataSet, nil
+}
+
+// getSecret looks up a secret of the given type referenced by a plan
+func (s *TestService) getSecret(field, id string, secretType model.SecretType) (*model.Secret, error) {
+	if s.secretRepo == nil {
+		return nil, domain.NewValidationError(field, "secrets are not available")
+	}
+	secret, err := s.secretRepo.GetByID(id)
+	if err != nil || secret == nil {
+		return nil, domain.NewNotFoundError("secret", id)
+	}
+	if secret.Type != secretType {
+		return nil, domain.NewValidationError(field, fmt.Sprintf("secret %s is a %s secret, expected %s", id, secret.Type, secretType))
+	}
+	return secret, nil
+}
+
+// resolveTLS loads the certificates referenced by a plan's TLS settings
+func (s *TestService) resolveTLS(cfg *model.TLSConfig) (*model.TLSMaterial, error) {
+	material := &model.TLSMaterial{}
+	if cfg.ClientCertSecretID != "" {
+		secret, err := s.getSecret("tls.client_cert_secret_id", cfg.ClientCertSecretID, model.SecretTLSClient)
+		if err != nil {
+			return nil, err
+		}
+		material.ClientCert = secret.Certificate
+		material.ClientKey = secret.PrivateKey
+	}
+	if cfg.CASecretID != "" {
+		secret, err := s.getSecret("tls.ca_secret_id", cfg.CASecretID, model.SecretCABundle)
+		if err != nil {
+			return nil, err
+		}
+		material.CACerts = secret.Certificate
+	}
+	return material, nil
+}
+
+// credentialsSecretID returns the credentials secret a plan's SQL or Redis
+// settings reference, and the field naming it
+func credentialsSecretID(sql *model.SQLRequest, redis *model.RedisRequest) (field, id string) {
+	switch {
+	case sql != nil:
+		return "sql.credentials_secret_id", sql.CredentialsSecretID
+	case redis != nil:
+		return "redis.credentials_secret_id", redis.CredentialsSecretID
+	}
+	return "", ""
+}
+
+// resolveCredentials loads the username and password of a credentials secret
+func (s *TestService) resolveCredentials(field, id string) (*model.Credentials, error) {
+	secret, err := s.getSecret(field, id, model.SecretCredentials)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Credentials{Username: secret.Username, Password: secret.Password}, nil
+}
+
 // GetTestPlan retrieves a test plan by ID
 func (s *TestService) GetTestPlan(id string) (*model.TestPlan, error) {
 	return s.planRepo.GetByID(id)
@@ -96,12 +301,83 @@ func (s *TestService) GetAllTestPlans() ([]*model.TestPlan, error) {
 
 // StartTest starts a new test run
 func (s *TestService) StartTest(planID string) (*model.TestRun, error) {
+	return s.startTest(planID, func(runID string, plan *model.TestPlan) (*model.Metrics, error) {
+		return s.generator.StartTest(runID, plan)
+	})
+}
+
+// StartDistributedTest starts a new test run split across up to agents
+// registered agents, or all of them if agents is 0
+func (s *TestService) StartDistributedTest(planID string, agents int) (*model.TestRun, error) {
+	if s.controller == nil {
+		return nil, domain.NewValidationError("distributed", "distributed runs are not enabled")
+	}
+	return s.startTest(planID, func(runID string, plan *model.TestPlan) (*model.Metrics, error) {
+		return s.controller.StartDistributedTest(runID, plan, agents)
+	})
+}
+
+// startTest creates a run of a plan and starts it with start
+func (s *TestService) startTest(planID string, start func(runID string, plan *model.TestPlan) (*model.Metrics, error)) (*model.TestRun, error) {
 	// Get test plan
 	plan, err := s.planRepo.GetByID(planID)
 	if err != nil {
 		return nil, domain.NewNotFoundError("test plan", planID)
 	}
 
+	// Resolve the scenario each VU iteration will run, the data set its
+	// feed reads from, the certificates of its TLS secrets and the files its
+	// bodies send. The plan is copied so the stored plan keeps referencing
+	// them only by ID.
+	runPlan := *plan
+	if plan.ScenarioID != "" {
+		scenario, err := s.getScenario(plan.ScenarioID)
+		if err != nil {
+			return nil, err
+		}
+		runPlan.Scenario = scenario
+	}
+	feed := runPlan.Data
+	if feed == nil && runPlan.Scenario != nil {
+		feed = runPlan.Scenario.Data
+	}
+	if feed != nil {
+		dataSet, err := s.getDataSet(feed.DataSetID)
+		if err != nil {
+			return nil, err
+		}
+		runPlan.DataSet = dataSet
+	}
+	if runPlan.TLS != nil {
+		material, err := s.resolveTLS(runPlan.TLS)
+		if err != nil {
+			return nil, err
+		}
+		runPlan.TLSMaterial = material
+	}
+	if field, id := credentialsSecretID(runPlan.SQL, runPlan.Redis); id != "" {
+		creds, err := s.resolveCredentials(field, id)
+		if err != nil {
+			return nil, err
+		}
+		runPlan.Credentials = creds
+	}
+	var steps []model.Step
+	if runPlan.Scenario != nil {
+		steps = runPlan.Scenario.Steps
+	}
+	bodyFiles, err := resolveBodyFiles(s.bodyFileRepo, bodySources(runPlan.BodySource, runPlan.Requests, steps))
+	if err != nil {
+		return nil, err
+	}
+	runPlan.BodyFiles = bodyFiles
+	protos, err := resolveProtos(s.protoRepo, grpcRequests(runPlan.GRPC, steps))
+	if err != nil {
+		return nil, err
+	}
+	runPlan.Protos = protos
+	plan = &runPlan
+
 	// Create test run
 	run := &model.TestRun{
 		ID:        uuid.New().String(),
@@ -115,9 +391,11 @@ func (s *TestService) StartTest(planID string) (*model.TestRun, error) {
 		return nil, err
 	}
 
-	// Start the load generator
-	metrics, err := s.generator.StartTest(run.ID, plan)
+	// Start the load generator or the agents
+	s.setLive(run.ID, true)
+	metrics, err := start(run.ID, plan)
 	if err != nil {
+		s.setLive(run.ID, false)
 		// Update run status to failed
 		run.Status = model.StatusFailed
 		now := time.Now()
@@ -127,7 +405,7 @@ func (s *TestService) StartTest(planID string) (*model.TestRun, error) {
 	}
 
 	// Save initial metrics
-	if err := s.metricsRepo.Save(metrics); err != nil {
+	if err := s.saveLiveMetrics(metrics); err != nil {
 		logger.Log.Error("Failed to save metrics",
 			zap.String("run_id", run.ID),
 			zap.Error(err))
@@ -147,6 +425,7 @@ func (s *TestService) StartTest(planID string) (*model.TestRun, error) {
 func (s *TestService) monitorTestRun(run *model.TestRun) {
 	ticker := time.NewTicker(2 * time.Second)
 	defer ticker.Stop()
+	defer s.setLive(run.ID, false)
 
 	for {
 		<-ticker.C
@@ -169,7 +448,8 @@ func (s *TestService) monitorTestRun(run *model.TestRun) {
 		}
 
 		// Check if test is still running
-		if !s.generator.IsRunning(run.ID) {
+		runner := s.runner(run.ID)
+		if runner == nil || !runner.IsRunning(run.ID) {
 			// Test completed naturally
 			reason := model.ReasonCompleted
 			currentRun.Status = model.StatusCompleted
@@ -183,11 +463,6 @@ func (s *TestService) monitorTestRun(run *model.TestRun) {
 					zap.Error(err))
 			}
 
-			// Get final metrics and save
-			if metrics, err := s.generator.GetMetrics(run.ID); err == nil {
-				_ = s.metricsRepo.Save(metrics)
-			}
-
 			logger.Log.Info("Test run completed",
 				zap.String("run_id", run.ID),
 				zap.String("status", string(currentRun.Status)))
@@ -196,12 +471,12 @@ func (s *TestService) monitorTestRun(run *model.TestRun) {
 		}
 
 		// Update metrics and check SLA
-		if metrics, err := s.generator.GetMetrics(run.ID); err == nil {
-			_ = s.metricsRepo.Save(metrics)
+		if metrics, err := runner.GetMetrics(run.ID); err == nil {
+			_ = s.saveLiveMetrics(metrics)
 
 			// Check SLA violations
 			plan, _ := s.planRepo.GetByID(currentRun.PlanID)
-			if plan != nil && plan.SLA != nil {
+			if plan != nil && plan.SLA != nil && !slaSettling(currentRun, metrics, time.Now()) {
 				if s.checkSLAViolation(metrics, plan.SLA) {
 					// Mark as failed due to SLA violation
 					reason := model.ReasonFailed
@@ -211,7 +486,7 @@ func (s *TestService) monitorTestRun(run *model.TestRun) {
 					currentRun.EndAt = &now
 
 					// Stop the test
-					_ = s.generator.StopTest(run.ID)
+					_ = runner.StopTest(run.ID)
 
 					if err := s.runRepo.Update(currentRun); err != nil {
 						logger.Log.Error("Failed to update test run after SLA violation",
@@ -229,43 +504,77 @@ func (s *TestService) monitorTestRun(run *model.TestRun) {
 	}
 }
 
-// checkSLAViolation checks if current metrics violate SLA thresholds
+// slaWindow is the rolling window SLA thresholds are checked against
+const slaWindow = model.Window10s
+
+// slaSettleTime is how long the SLA goes unchecked after a pause, resume or
+// rate change: one full window, until it no longer mixes the load before
+// the change with the load after it
+const slaSettleTime = 10 * time.Second
+
+// slaSettling reports whether the SLA window of a run does not reflect its
+// current load yet, so checking it could stop a run that is only paused or
+// slowed down on purpose
+func slaSettling(run *model.TestRun, metrics *model.Metrics, now time.Time) bool {
+	if metrics.Paused {
+		return true
+	}
+	for _, event := range run.Events {
+		switch event.Action {
+		case model.ControlPause, model.ControlResume, model.ControlSetRPS:
+			if now.Sub(event.At) < slaSettleTime {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkSLAViolation checks if the most recent window of a run violates SLA
+// thresholds. Cumulative values would let a long healthy start mask a
+// degradation late in the run.
 func (s *TestService) checkSLAViolation(metrics *model.Metrics, sla *model.SLAConfig) bool {
 	if sla == nil {
 		return false
 	}
 
+	window, ok := metrics.Windows[slaWindow]
+	if !ok || window.Requests == 0 {
+		return false
+	}
+
 	// Check P95 latency
-	if sla.MaxP95Latency > 0 && metrics.P95LatencyMs > sla.MaxP95Latency {
+	if sla.MaxP95Latency > 0 && window.P95LatencyMs > sla.MaxP95Latency {
 		logger.Log.Warn("SLA violation: P95 latency exceeded",
-			zap.Float64("current", metrics.P95LatencyMs),
+			zap.String("window", slaWindow),
+			zap.Float64("current", window.P95LatencyMs),
 			zap.Float64("max", sla.MaxP95Latency))
 		return true
 	}
 
 	// Check P99 latency
-	if sla.MaxP99Latency > 0 && metrics.P99LatencyMs > sla.MaxP99Latency {
+	if sla.MaxP99Latency > 0 && window.P99LatencyMs > sla.MaxP99Latency {
 		logger.Log.Warn("SLA violation: P99 latency exceeded",
-			zap.Float64("current", metrics.P99LatencyMs),
+			zap.String("window", slaWindow),
+			zap.Float64("current", window.P99LatencyMs),
 			zap.Float64("max", sla.MaxP99Latency))
 		return true
 	}
 
 	// Check error rate
-	if sla.MaxErrorRate > 0 && metrics.TotalRequests > 0 {
-		errorRate := float64(metrics.FailedRequests) / float64(metrics.TotalRequests) * 100
-		if errorRate > sla.MaxErrorRate {
-			logger.Log.Warn("SLA violation: Error rate exceeded",
-				zap.Float64("current", errorRate),
-				zap.Float64("max", sla.MaxErrorRate))
-			return true
-		}
+	if sla.MaxErrorRate > 0 && window.ErrorRate > sla.MaxErrorRate {
+		logger.Log.Warn("SLA violation: Error rate exceeded",
+			zap.String("window", slaWindow),
+			zap.Float64("current", window.ErrorRate),
+			zap.Float64("max", sla.MaxErrorRate))
+		return true
 	}
 
 	// Check minimum RPS
-	if sla.MinRPS > 0 && metrics.CurrentRPS > 0 && metrics.CurrentRPS < sla.MinRPS {
+	if sla.MinRPS > 0 && window.RPS < sla.MinRPS {
 		logger.Log.Warn("SLA violation: RPS below minimum",
-			zap.Float64("current", metrics.CurrentRPS),
+			zap.String("window", slaWindow),
+			zap.Float64("current", window.RPS),
 			zap.Float64("min", sla.MinRPS))
 		return true
 	}
@@ -281,8 +590,12 @@ func (s *TestService) StopTest(runID string) error {
 		return err
 	}
 
-	// Stop the generator
-	if err := s.generator.StopTest(runID); err != nil {
+	// Stop the generator or the agents
+	runner := s.runner(runID)
+	if runner == nil {
+		return engine.ErrTestNotFound
+	}
+	if err := runner.StopTest(runID); err != nil {
 		return err
 	}
 
@@ -293,21 +606,61 @@ func (s *TestService) StopTest(runID string) error {
 	now := time.Now()
 	run.EndAt = &now
 
+	// The final metrics, with their histograms, are saved by the completion
+	// handler once the stopped run has wound down
 	if err := s.runRepo.Update(run); err != nil {
 		return err
 	}
 
-	// Save final metrics
-	if metrics, err := s.generator.GetMetrics(runID); err == nil {
-		_ = s.metricsRepo.Save(metrics)
-	}
-
 	logger.Log.Info("Test run stopped",
 		zap.String("run_id", runID))
 
 	return nil
 }
 
+// ControlTest applies a live control command to a running test and records
+// it as a timestamped event on the run
+func (s *TestService) ControlTest(runID string, cmd model.ControlCommand) (*model.ControlEvent, error) {
+	run, err := s.runRepo.GetByID(runID)
+	if err != nil {
+		return nil, domain.NewNotFoundError("test run", runID)
+	}
+	runner := s.runner(runID)
+	if run.Status != model.StatusRunning || runner == nil || !runner.IsRunning(runID) {
+		return nil, domain.ErrNotRunning
+	}
+
+	// Added VUs count against the same limit as the plan's users
+	if cmd.Action == model.ControlAddVUs {
+		if metrics, err := runner.GetMetrics(runID); err == nil && metrics.ActiveWorkers+cmd.Value > s.config.MaxWorkers {
+			return nil, domain.NewValidationError("value",
+				fmt.Sprintf("adding %d VUs to %d would exceed maximum allowed workers (%d)", cmd.Value, metrics.ActiveWorkers, s.config.MaxWorkers))
+		}
+	}
+
+	if err := runner.ControlTest(runID, cmd); err != nil {
+		return nil, err
+	}
+
+	event := model.ControlEvent{
+		Action: cmd.Action,
+		Value:  cmd.Value,
+		At:     time.Now(),
+	}
+	if err := s.runRepo.AddEvent(runID, event); err != nil {
+		logger.Log.Error("Failed to record control event",
+			zap.String("run_id", runID),
+			zap.Error(err))
+	}
+
+	logger.Log.Info("Test run controlled",
+		zap.String("run_id", runID),
+		zap.String("action", string(cmd.Action)),
+		zap.Int("value", cmd.Value))
+
+	return &event, nil
+}
+
 // GetTestRun retrieves a test run by ID
 func (s *TestService) GetTestRun(id string) (*model.TestRun, error) {
 	return s.runRepo.GetByID(id)
@@ -321,20 +674,131 @@ func (s *TestService) GetAllTestRuns() ([]*model.TestRun, error) {
 // GetTestMetrics retrieves metrics for a test run
 func (s *TestService) GetTestMetrics(runID string) (*model.Metrics, error) {
 	// Try to get from active tests first
-	if s.generator.IsRunning(runID) {
-		return s.generator.GetMetrics(runID)
+	if s.isRunning(runID) {
+		return s.runner(runID).GetMetrics(runID)
 	}
 
 	// Get from repository
 	return s.metricsRepo.GetByRunID(runID)
 }
 
+// setLive marks whether the live metrics of a run may be saved
+func (s *TestService) setLive(runID string, live bool) {
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+	if live {
+		s.live[runID] = true
+	} else {
+		delete(s.live, runID)
+	}
+}
+
+// saveLiveMetrics stores the metrics of a run in progress. A snapshot taken
+// before the run finished is dropped once the final metrics are saved, so it
+// cannot overwrite their percentiles.
+func (s *TestService) saveLiveMetrics(metrics *model.Metrics) error {
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+	if !s.live[metrics.RunID] {
+		return nil
+	}
+	return s.metricsRepo.Save(metrics)
+}
+
+// saveFinalMetrics stores the final metrics of a finished run
+func (s *TestService) saveFinalMetrics(metrics *model.Metrics) {
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+	delete(s.live, metrics.RunID)
+	if err := s.metricsRepo.Save(metrics); err != nil {
+		logger.Log.Error("Failed to save final metrics",
+			zap.String("run_id", metrics.RunID),
+			zap.Error(err))
+	}
+}
+
+// finishDistributedRun stores the final metrics of a distributed run. A run
+// whose every agent was lost produced no complete result and is marked failed.
+func (s *TestService) finishDistributedRun(metrics *model.Metrics) {
+	s.saveFinalMetrics(metrics)
+
+	for _, agent := range metrics.Agents {
+		if agent.Status != model.AgentStatusLost {
+			return
+		}
+	}
+
+	run, err := s.runRepo.GetByID(metrics.RunID)
+	if err != nil || run.Status != model.StatusRunning {
+		return
+	}
+	reason := model.ReasonFailed
+	run.Status = model.StatusFailed
+	run.StopReason = &reason
+	now := time.Now()
+	run.EndAt = &now
+	if err := s.runRepo.Update(run); err != nil {
+		logger.Log.Error("Failed to update test run",
+			zap.String("run_id", run.ID),
+			zap.Error(err))
+	}
+
+	logger.Log.Warn("Distributed test run failed, every agent was lost",
+		zap.String("run_id", run.ID))
+}
+
+// GetPercentiles reads arbitrary percentiles of a test run's service and
+// response times. Running tests are answered from the live worker histograms,
+// finished ones from the histograms stored with their final metrics.
+func (s *TestService) GetPercentiles(runID string, percentiles []float64) (*model.PercentileReport, error) {
+	if len(percentiles) == 0 {
+		percentiles = model.DefaultPercentiles
+	}
+	for _, p := range percentiles {
+		if p <= 0 || p > 100 {
+			return nil, domain.NewValidationError("p", "percentiles must be greater than 0 and at most 100")
+		}
+	}
+
+	report := &model.PercentileReport{RunID: runID}
+
+	var latency, response *model.Histogram
+	if s.isRunning(runID) {
+		var err error
+		if latency, response, err = s.runner(runID).GetHistograms(runID); err != nil {
+			return nil, err
+		}
+		report.Live = true
+	} else {
+		metrics, err := s.metricsRepo.GetByRunID(runID)
+		if err != nil || metrics == nil {
+			return nil, domain.NewNotFoundError("metrics", runID)
+		}
+		if metrics.LatencyHistogram == nil || metrics.ResponseHistogram == nil {
+			return nil, domain.NewNotFoundError("histogram", runID)
+		}
+		latency, response = metrics.LatencyHistogram, metrics.ResponseHistogram
+	}
+
+	report.Count = latency.TotalCount()
+	report.Percentiles = make([]model.PercentileValue, 0, len(percentiles))
+	for _, p := range percentiles {
+		report.Percentiles = append(report.Percentiles, model.PercentileValue{
+			Percentile: p,
+			LatencyMs:  latency.ValueAtPercentile(p),
+			ResponseMs: response.ValueAtPercentile(p),
+		})
+	}
+
+	return report, nil
+}
+
 // GetLiveMetrics retrieves real-time metrics for a running test
 func (s *TestService) GetLiveMetrics(runID string) (*model.Metrics, error) {
-	if !s.generator.IsRunning(runID) {
+	if !s.isRunning(runID) {
 		// Test not running, get stored metrics
 		return s.metricsRepo.GetByRunID(runID)
 	}
 
-	return s.generator.GetMetrics(runID)
+	return s.runner(runID).GetMetrics(runID)
 }
@@ -1,15 +1,26 @@
 package service
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/config"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/engine"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/metrics"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
 )
 
+var (
+	sharedTestCollector     *metrics.Collector
+	sharedTestCollectorOnce sync.Once
+)
+
 func init() {
 	// Initialize logger for tests
 	if err := logger.Init("error"); err != nil {
@@ -17,6 +28,15 @@ func init() {
 	}
 }
 
+// newTestGenerator returns a load generator sharing one Prometheus collector
+// across tests, since collectors cannot be registered twice
+func newTestGenerator() *engine.LoadGenerator {
+	sharedTestCollectorOnce.Do(func() {
+		sharedTestCollector = metrics.NewCollector()
+	})
+	return engine.NewLoadGenerator(sharedTestCollector)
+}
+
 func TestNewTestService(t *testing.T) {
 	planRepo := repository.NewMemoryTestPlanRepository()
 	runRepo := repository.NewMemoryTestRunRepository()
@@ -26,7 +46,7 @@ func TestNewTestService(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	if service == nil {
 		t.Fatal("Expected TestService to be created")
@@ -42,7 +62,7 @@ func TestCreateTestPlan(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	req := &model.CreateTestPlanRequest{
 		Name:        "Test Plan 1",
@@ -84,7 +104,7 @@ func TestCreateTestPlanExceedsMaxWorkers(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	req := &model.CreateTestPlanRequest{
 		Name:        "Excessive Plan",
@@ -109,7 +129,7 @@ func TestCreateTestPlanWithCustomTimeout(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	req := &model.CreateTestPlanRequest{
 		Name:        "Custom Timeout Plan",
@@ -139,7 +159,7 @@ func TestGetTestPlan(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	req := &model.CreateTestPlanRequest{
 		Name:        "Get Test Plan",
@@ -176,7 +196,7 @@ func TestGetTestPlanNotFound(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	_, err := service.GetTestPlan("non-existent-id")
 	if err == nil {
@@ -193,7 +213,7 @@ func TestGetAllTestPlans(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	// Create multiple plans
 	for i := 0; i < 5; i++ {
@@ -229,7 +249,7 @@ func TestCreateTestPlanWithHeaders(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	req := &model.CreateTestPlanRequest{
 		Name:      "Plan with Headers",
@@ -269,7 +289,7 @@ func TestCreateTestPlanTimestamp(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	beforeCreate := time.Now()
 
@@ -302,7 +322,7 @@ func TestCreateTestPlanDefaultRatePattern(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	req := &model.CreateTestPlanRequest{
 		Name:        "Default Rate Pattern",
@@ -331,7 +351,7 @@ func TestCreateTestPlanWithSLA(t *testing.T) {
 		DefaultTimeout: 30000,
 	}
 
-	service := NewTestService(planRepo, runRepo, metricsRepo, nil, cfg)
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
 
 	req := &model.CreateTestPlanRequest{
 		Name:        "SLA Test",
@@ -361,3 +381,456 @@ func TestCreateTestPlanWithSLA(t *testing.T) {
 		t.Errorf("MaxErrorRate mismatch: expected 0.01, got %f", plan.SLA.MaxErrorRate)
 	}
 }
+
+func TestGetPercentilesFromStoredHistogram(t *testing.T) {
+	planRepo := repository.NewMemoryTestPlanRepository()
+	runRepo := repository.NewMemoryTestRunRepository()
+	metricsRepo := repository.NewMemoryMetricsRepository()
+	cfg := &config.Config{
+		MaxWorkers:     100,
+		DefaultTimeout: 30000,
+	}
+
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
+
+	// 1..10000µs, so the p-th percentile is p/100 * 10ms
+	latency := model.NewHistogram()
+	for v := int64(1); v <= 10000; v++ {
+		latency.RecordMicros(v)
+	}
+
+	// Round-trip through the persisted form, as a finished run would be stored
+	data, err := json.Marshal(latency)
+	if err != nil {
+		t.Fatalf("Failed to encode histogram: %v", err)
+	}
+	restored := model.NewHistogram()
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("Failed to decode histogram: %v", err)
+	}
+
+	metrics := model.NewMetrics("run-percentiles")
+	metrics.LatencyHistogram = restored
+	metrics.ResponseHistogram = restored
+	_ = metricsRepo.Save(metrics)
+
+	report, err := service.GetPercentiles("run-percentiles", []float64{50, 99.9, 99.99})
+	if err != nil {
+		t.Fatalf("Failed to get percentiles: %v", err)
+	}
+
+	if report.Count != 10000 {
+		t.Errorf("Expected 10000 samples, got %d", report.Count)
+	}
+	if report.Live {
+		t.Error("Expected stored run not to be reported as live")
+	}
+
+	expected := []float64{5.0, 9.99, 9.999}
+	for i, pv := range report.Percentiles {
+		if diff := pv.LatencyMs - expected[i]; diff < -expected[i]*0.01 || diff > expected[i]*0.01 {
+			t.Errorf("P%v: expected %.3fms within 1%%, got %.3fms", pv.Percentile, expected[i], pv.LatencyMs)
+		}
+	}
+}
+
+func TestGetPercentilesValidation(t *testing.T) {
+	planRepo := repository.NewMemoryTestPlanRepository()
+	runRepo := repository.NewMemoryTestRunRepository()
+	metricsRepo := repository.NewMemoryMetricsRepository()
+	cfg := &config.Config{
+		MaxWorkers:     100,
+		DefaultTimeout: 30000,
+	}
+
+	service := NewTestService(planRepo, runRepo, metricsRepo, nil, nil, nil, nil, nil, nil, cfg)
+
+	if _, err := service.GetPercentiles("run-1", []float64{101}); err == nil {
+		t.Error("Expected error for percentile above 100")
+	}
+
+	// Runs without a stored histogram cannot be queried
+	_ = metricsRepo.Save(model.NewMetrics("run-no-histogram"))
+	if _, err := service.GetPercentiles("run-no-histogram", nil); err == nil {
+		t.Error("Expected error for run without histogram")
+	}
+}
+
+func TestCheckSLAViolationUsesWindow(t *testing.T) {
+	service := NewTestService(
+		repository.NewMemoryTestPlanRepository(),
+		repository.NewMemoryTestRunRepository(),
+		repository.NewMemoryMetricsRepository(),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		&config.Config{MaxWorkers: 100, DefaultTimeout: 30000},
+	)
+	sla := &model.SLAConfig{MaxP95Latency: 100, MaxErrorRate: 5}
+
+	// Healthy cumulative values must not mask a degraded recent window
+	metrics := model.NewMetrics("run-sla")
+	metrics.TotalRequests = 100000
+	metrics.P95LatencyMs = 20
+	metrics.Windows = map[string]model.WindowStats{
+		model.Window10s: {Requests: 1000, P95LatencyMs: 250},
+	}
+	if !service.checkSLAViolation(metrics, sla) {
+		t.Error("Expected windowed P95 above threshold to violate SLA")
+	}
+
+	metrics.Windows[model.Window10s] = model.WindowStats{Requests: 1000, Failed: 100, ErrorRate: 10, P95LatencyMs: 50}
+	if !service.checkSLAViolation(metrics, sla) {
+		t.Error("Expected windowed error rate above threshold to violate SLA")
+	}
+
+	metrics.Windows[model.Window10s] = model.WindowStats{Requests: 1000, P95LatencyMs: 50}
+	if service.checkSLAViolation(metrics, sla) {
+		t.Error("Expected healthy window not to violate SLA")
+	}
+
+	// No window yet (first second of the run) means nothing to judge
+	metrics.Windows = nil
+	metrics.P95LatencyMs = 500
+	if service.checkSLAViolation(metrics, sla) {
+		t.Error("Expected no violation before the first window is available")
+	}
+}
+
+func TestCreateTestPlanWithScenario(t *testing.T) {
+	scenarioRepo := repository.NewMemoryScenarioRepository()
+	_ = scenarioRepo.Create(&model.Scenario{
+		ID:    "scenario-1",
+		Name:  "Checkout",
+		Steps: []model.Step{{Name: "login", Method: "POST", URL: "http://localhost:8080/login"}},
+	})
+
+	service := NewTestService(
+		repository.NewMemoryTestPlanRepository(),
+		repository.NewMemoryTestRunRepository(),
+		repository.NewMemoryMetricsRepository(),
+		scenarioRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		&config.Config{MaxWorkers: 100, DefaultTimeout: 30000},
+	)
+
+	plan, err := service.CreateTestPlan(&model.CreateTestPlanRequest{
+		Name:        "Scenario Plan",
+		ScenarioID:  "scenario-1",
+		Users:       10,
+		DurationSec: 60,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create scenario plan: %v", err)
+	}
+	if plan.ScenarioID != "scenario-1" {
+		t.Errorf("Expected scenario_id 'scenario-1', got '%s'", plan.ScenarioID)
+	}
+
+	_, err = service.CreateTestPlan(&model.CreateTestPlanRequest{
+		Name:        "Missing Scenario Plan",
+		ScenarioID:  "does-not-exist",
+		Users:       10,
+		DurationSec: 60,
+	})
+	if err == nil {
+		t.Error("Expected error for unknown scenario")
+	}
+}
+
+func TestCreateTestPlanWithRequestMix(t *testing.T) {
+	service := NewTestService(
+		repository.NewMemoryTestPlanRepository(),
+		repository.NewMemoryTestRunRepository(),
+		repository.NewMemoryMetricsRepository(),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		&config.Config{MaxWorkers: 100, DefaultTimeout: 30000},
+	)
+
+	requests := []model.WeightedRequest{
+		{Name: "browse", Method: "GET", URL: "http://localhost:8080/product/{{random:100}}", Weight: 80},
+		{Name: "checkout", Method: "POST", URL: "http://localhost:8080/checkout", Weight: 20},
+	}
+	plan, err := service.CreateTestPlan(&model.CreateTestPlanRequest{
+		Name:        "Mix Plan",
+		Requests:    requests,
+		Users:       10,
+		DurationSec: 60,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create request mix plan: %v", err)
+	}
+	if len(plan.Requests) != 2 || plan.Requests[1].Name != "checkout" {
+		t.Errorf("Expected the request mix to be stored, got %+v", plan.Requests)
+	}
+}
+
+func TestCreateTestPlanWithTLSSecrets(t *testing.T) {
+	secretRepo := repository.NewMemorySecretRepository()
+	_ = secretRepo.Create(&model.Secret{ID: "client", Type: model.SecretTLSClient, Certificate: "cert", PrivateKey: "key"})
+	_ = secretRepo.Create(&model.Secret{ID: "ca", Type: model.SecretCABundle, Certificate: "ca"})
+
+	service := NewTestService(
+		repository.NewMemoryTestPlanRepository(),
+		repository.NewMemoryTestRunRepository(),
+		repository.NewMemoryMetricsRepository(),
+		nil,
+		nil,
+		secretRepo,
+		nil,
+		nil,
+		nil,
+		&config.Config{MaxWorkers: 100, DefaultTimeout: 30000},
+	)
+
+	plan, err := service.CreateTestPlan(&model.CreateTestPlanRequest{
+		Name:        "mTLS Plan",
+		TargetURL:   "https://localhost:8443",
+		Method:      "GET",
+		Users:       1,
+		DurationSec: 10,
+		TLS:         &model.TLSConfig{ClientCertSecretID: "client", CASecretID: "ca"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create mTLS plan: %v", err)
+	}
+	if plan.TLS == nil || plan.TLS.ClientCertSecretID != "client" || plan.TLSMaterial != nil {
+		t.Errorf("Expected the plan to reference the secrets by ID only, got %+v", plan)
+	}
+
+	material, err := service.resolveTLS(plan.TLS)
+	if err != nil || material.ClientKey != "key" || material.CACerts != "ca" {
+		t.Errorf("Expected the secrets to resolve, got %+v (%v)", material, err)
+	}
+
+	tests := []struct {
+		name string
+		tls  *model.TLSConfig
+	}{
+		{"unknown secret", &model.TLSConfig{ClientCertSecretID: "missing"}},
+		{"CA bundle as client certificate", &model.TLSConfig{ClientCertSecretID: "ca"}},
+		{"client certificate as CA bundle", &model.TLSConfig{CASecretID: "client"}},
+	}
+	for _, tt := range tests {
+		_, err := service.CreateTestPlan(&model.CreateTestPlanRequest{
+			Name:        tt.name,
+			TargetURL:   "https://localhost:8443",
+			Method:      "GET",
+			Users:       1,
+			DurationSec: 10,
+			TLS:         tt.tls,
+		})
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestCreateTestPlanWithCredentialsSecret(t *testing.T) {
+	secretRepo := repository.NewMemorySecretRepository()
+	_ = secretRepo.Create(&model.Secret{ID: "db", Type: model.SecretCredentials, Username: "load", Password: "secret"})
+	_ = secretRepo.Create(&model.Secret{ID: "ca", Type: model.SecretCABundle, Certificate: "ca"})
+
+	service := NewTestService(
+		repository.NewMemoryTestPlanRepository(),
+		repository.NewMemoryTestRunRepository(),
+		repository.NewMemoryMetricsRepository(),
+		nil,
+		nil,
+		secretRepo,
+		nil,
+		nil,
+		nil,
+		&config.Config{MaxWorkers: 100, DefaultTimeout: 30000},
+	)
+
+	newPlan := func(secretID string) (*model.TestPlan, error) {
+		return service.CreateTestPlan(&model.CreateTestPlanRequest{
+			Name:        "SQL Plan",
+			Users:       1,
+			DurationSec: 10,
+			SQL: &model.SQLRequest{
+				DSN:                 "postgres://localhost:5432/app",
+				CredentialsSecretID: secretID,
+				Statements:          []model.SQLStatement{{Name: "ping", Query: "SELECT 1"}},
+			},
+		})
+	}
+
+	plan, err := newPlan("db")
+	if err != nil {
+		t.Fatalf("Failed to create SQL plan: %v", err)
+	}
+	if plan.Credentials != nil {
+		t.Errorf("Expected the plan to reference the secret by ID only, got %+v", plan.Credentials)
+	}
+	field, id := credentialsSecretID(plan.SQL, plan.Redis)
+	creds, err := service.resolveCredentials(field, id)
+	if err != nil || creds.Username != "load" || creds.Password != "secret" {
+		t.Errorf("Expected the secret to resolve, got %+v (%v)", creds, err)
+	}
+
+	if _, err := newPlan("missing"); err == nil {
+		t.Error("Expected an error for an unknown secret")
+	}
+	if _, err := newPlan("ca"); err == nil {
+		t.Error("Expected an error for a CA bundle as credentials")
+	}
+
+	_, err = service.CreateTestPlan(&model.CreateTestPlanRequest{
+		Name:        "Redis Plan",
+		Users:       1,
+		DurationSec: 10,
+		Redis: &model.RedisRequest{
+			Address:             "localhost:6379",
+			CredentialsSecretID: "missing",
+			Commands:            []model.RedisCommand{{Args: []string{"PING"}}},
+		},
+	})
+	if err == nil {
+		t.Error("Expected an error for an unknown Redis credentials secret")
+	}
+}
+
+func TestLiveMetricsDoNotOverwriteFinalMetrics(t *testing.T) {
+	metricsRepo := repository.NewMemoryMetricsRepository()
+	service := NewTestService(
+		repository.NewMemoryTestPlanRepository(),
+		repository.NewMemoryTestRunRepository(),
+		metricsRepo,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		&config.Config{MaxWorkers: 100, DefaultTimeout: 30000},
+	)
+
+	service.setLive("run-final", true)
+	live := model.NewMetrics("run-final")
+	live.TotalRequests = 50
+	if err := service.saveLiveMetrics(live); err != nil {
+		t.Fatalf("Failed to save live metrics: %v", err)
+	}
+
+	final := model.NewMetrics("run-final")
+	final.TotalRequests = 100
+	final.P999LatencyMs = 42
+	service.saveFinalMetrics(final)
+
+	// A snapshot the monitor took before the run finished
+	if err := service.saveLiveMetrics(live); err != nil {
+		t.Fatalf("Failed to save live metrics: %v", err)
+	}
+
+	stored, err := metricsRepo.GetByRunID("run-final")
+	if err != nil {
+		t.Fatalf("Failed to get metrics: %v", err)
+	}
+	if stored.TotalRequests != 100 || stored.P999LatencyMs != 42 {
+		t.Errorf("Expected the final metrics to be kept, got %d requests and p999 %v", stored.TotalRequests, stored.P999LatencyMs)
+	}
+}
+
+func TestSLASettling(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		paused   bool
+		events   []model.ControlEvent
+		settling bool
+	}{
+		{"no events", false, nil, false},
+		{"paused", true, []model.ControlEvent{{Action: model.ControlPause, At: now.Add(-time.Minute)}}, true},
+		{"just resumed", false, []model.ControlEvent{{Action: model.ControlResume, At: now.Add(-3 * time.Second)}}, true},
+		{"rate just changed", false, []model.ControlEvent{{Action: model.ControlSetRPS, Value: 10, At: now.Add(-9 * time.Second)}}, true},
+		{"window passed", false, []model.ControlEvent{
+			{Action: model.ControlPause, At: now.Add(-30 * time.Second)},
+			{Action: model.ControlResume, At: now.Add(-20 * time.Second)},
+		}, false},
+		{"VUs added", false, []model.ControlEvent{{Action: model.ControlAddVUs, Value: 2, At: now}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := model.NewMetrics("run-settling")
+			metrics.Paused = tt.paused
+			run := &model.TestRun{ID: "run-settling", Events: tt.events}
+			if got := slaSettling(run, metrics, now); got != tt.settling {
+				t.Errorf("Expected settling %v, got %v", tt.settling, got)
+			}
+		})
+	}
+}
+
+func TestPausedRunDoesNotViolateMinRPS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping live run in short mode")
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	runRepo := repository.NewMemoryTestRunRepository()
+	service := NewTestService(
+		repository.NewMemoryTestPlanRepository(),
+		runRepo,
+		repository.NewMemoryMetricsRepository(),
+		nil,
+		nil,
+		nil,
+		nil,
+		nil,
+		newTestGenerator(),
+		&config.Config{MaxWorkers: 100, DefaultTimeout: 30000},
+	)
+
+	plan, err := service.CreateTestPlan(&model.CreateTestPlanRequest{
+		Name:        "Paused SLA",
+		TargetURL:   server.URL,
+		Method:      "GET",
+		Users:       2,
+		DurationSec: 30,
+		TargetRPS:   50,
+		SLA:         &model.SLAConfig{MinRPS: 30},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test plan: %v", err)
+	}
+	run, err := service.StartTest(plan.ID)
+	if err != nil {
+		t.Fatalf("Failed to start test: %v", err)
+	}
+	defer func() { _ = service.StopTest(run.ID) }()
+
+	// Let the monitor check a healthy window, then pause. The window keeps
+	// the requests from before the pause, far below min_rps once averaged
+	// over the time since.
+	time.Sleep(2500 * time.Millisecond)
+	if _, err := service.ControlTest(run.ID, model.ControlCommand{Action: model.ControlPause}); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	time.Sleep(4500 * time.Millisecond)
+
+	current, err := runRepo.GetByID(run.ID)
+	if err != nil {
+		t.Fatalf("Failed to get run: %v", err)
+	}
+	if current.Status != model.StatusRunning {
+		t.Errorf("Expected the paused run to keep running, got %s", current.Status)
+	}
+}
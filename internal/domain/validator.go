@@ -1,13 +1,34 @@
 package domain
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"net"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
 )
 
+// validMethods are the HTTP methods a test plan may use
+var validMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true,
+	"DELETE": true, "HEAD": true, "OPTIONS": true,
+}
+
+// validAssertionOperators are the operators a response check may use, the
+// empty operator meaning the assertion type's default
+var validAssertionOperators = map[string]bool{
+	"": true, "eq": true, "ne": true, "contains": true, "not_contains": true,
+	"gt": true, "lt": true, "exists": true, "not_exists": true,
+}
+
+// dsnPasswordPattern matches the password of a key=value DSN
+var dsnPasswordPattern = regexp.MustCompile(`(?i)(^|\s)password\s*=`)
+
 // Validator provides validation logic for domain models
 type Validator struct{}
 
@@ -22,21 +43,112 @@ func (v *Validator) ValidateTestPlan(req *model.CreateTestPlanRequest) error {
 		return NewValidationError("name", "name is required")
 	}
 
-	// Validate URL format
-	if strings.TrimSpace(req.TargetURL) == "" {
-		return NewValidationError("target_url", "target URL is required")
+	kind, err := requestKind(req)
+	if err != nil {
+		return err
 	}
-	if _, err := url.ParseRequestURI(req.TargetURL); err != nil {
-		return NewValidationError("target_url", "invalid URL format")
+	if kind != "" && kind != "scenario_id" && kind != "requests" {
+		// Other protocols send their own messages instead of an HTTP request
+		if req.Body != "" || req.BodySource != nil {
+			return NewValidationError(kind, kind+" cannot be combined with body or body_source")
+		}
+		if req.Retry != nil {
+			return NewValidationError("retry", "retry cannot be combined with "+kind)
+		}
+		if req.Stream != nil {
+			return NewValidationError("stream", "stream cannot be combined with "+kind)
+		}
 	}
 
-	// Validate HTTP method
-	validMethods := map[string]bool{
-		"GET": true, "POST": true, "PUT": true, "PATCH": true,
-		"DELETE": true, "HEAD": true, "OPTIONS": true,
-	}
-	if !validMethods[strings.ToUpper(req.Method)] {
-		return NewValidationError("method", "invalid HTTP method")
+	switch kind {
+	case "scenario_id":
+		// Scenario steps carry their own URLs, methods and assertions
+		if len(req.Assertions) > 0 {
+			return NewValidationError("assertions", "assertions cannot be combined with scenario_id")
+		}
+		// Scenario steps carry their own bodies
+		if req.BodySource != nil {
+			return NewValidationError("body_source", "body_source cannot be combined with scenario_id")
+		}
+		if req.Stream != nil {
+			return NewValidationError("stream", "stream cannot be combined with scenario_id")
+		}
+		// Scenario SQL steps run their own statements on the plan's database
+		if req.SQL != nil {
+			if len(req.SQL.Statements) > 0 {
+				return NewValidationError("sql.statements", "statements cannot be combined with scenario_id")
+			}
+			if err := v.ValidateSQL("sql", req.SQL); err != nil {
+				return err
+			}
+		}
+	case "requests":
+		if req.BodySource != nil {
+			return NewValidationError("body_source", "body_source cannot be combined with requests")
+		}
+		if err := v.ValidateRequestMix(req.Requests); err != nil {
+			return err
+		}
+	case "grpc":
+		if err := v.ValidateGRPC("grpc", req.GRPC); err != nil {
+			return err
+		}
+	case "websocket":
+		if err := v.ValidateWebSocket("websocket", req.WebSocket); err != nil {
+			return err
+		}
+	case "socket":
+		if err := v.ValidateSocket("socket", req.Socket); err != nil {
+			return err
+		}
+		// Socket responses have neither status codes nor headers
+		if err := validateAssertionTypes(req.Assertions, kind, model.AssertionStatusCode, model.AssertionHeader); err != nil {
+			return err
+		}
+	case "dns":
+		if err := v.ValidateDNS("dns", req.DNS); err != nil {
+			return err
+		}
+		// DNS answers have response codes but no headers
+		if err := validateAssertionTypes(req.Assertions, kind, model.AssertionHeader); err != nil {
+			return err
+		}
+	case "sql":
+		if len(req.SQL.Statements) == 0 {
+			return NewValidationError("sql.statements", "at least one statement is required")
+		}
+		if err := v.ValidateSQL("sql", req.SQL); err != nil {
+			return err
+		}
+		// Statement results have neither status codes nor headers
+		if err := validateAssertionTypes(req.Assertions, kind, model.AssertionStatusCode, model.AssertionHeader); err != nil {
+			return err
+		}
+	case "redis":
+		if err := v.ValidateRedis("redis", req.Redis); err != nil {
+			return err
+		}
+		// Pipeline replies have neither status codes nor headers
+		if err := validateAssertionTypes(req.Assertions, kind, model.AssertionStatusCode, model.AssertionHeader); err != nil {
+			return err
+		}
+	default:
+		// Validate URL format
+		if strings.TrimSpace(req.TargetURL) == "" {
+			return NewValidationError("target_url", "target URL is required")
+		}
+		if _, err := url.ParseRequestURI(req.TargetURL); err != nil {
+			return NewValidationError("target_url", "invalid URL format")
+		}
+
+		// Validate HTTP method
+		if !validMethods[strings.ToUpper(req.Method)] {
+			return NewValidationError("method", "invalid HTTP method")
+		}
+
+		if err := v.ValidateBodySource("body_source", req.BodySource, req.Body); err != nil {
+			return err
+		}
 	}
 
 	// Validate positive numbers
@@ -69,6 +181,897 @@ func (v *Validator) ValidateTestPlan(req *model.CreateTestPlanRequest) error {
 		return NewValidationError("target_rps", "target_rps cannot be negative")
 	}
 
+	if err := v.ValidateDataFeed(req.Data); err != nil {
+		return err
+	}
+
+	if err := v.ValidateLoadProfile(req); err != nil {
+		return err
+	}
+
+	if err := v.ValidateTransport(req.Transport); err != nil {
+		return err
+	}
+
+	if err := v.ValidateTLS(req.TLS); err != nil {
+		return err
+	}
+
+	if err := v.ValidateCookies(req.Cookies); err != nil {
+		return err
+	}
+
+	// status_code checks of gRPC and DNS plans take their status and
+	// response codes
+	lo, hi := 100.0, 599.0
+	switch {
+	case req.GRPC != nil:
+		lo, hi = 0, 16
+	case req.DNS != nil:
+		lo, hi = 0, 15
+	}
+	if err := v.validateAssertions(req.Assertions, lo, hi); err != nil {
+		return err
+	}
+
+	if err := v.ValidateStream(req.Stream, req.TimeoutMs); err != nil {
+		return err
+	}
+
+	if err := v.ValidateRetry("retry", req.Retry); err != nil {
+		return err
+	}
+
+	return v.ValidateExecutor(req)
+}
+
+// requestKind returns the one kind of request a plan sets, or "" for the
+// single HTTP request of target_url when it sets none. Scenario plans may
+// also set sql, as the database their SQL steps run on.
+func requestKind(req *model.CreateTestPlanRequest) (string, error) {
+	fields := []struct {
+		name string
+		set  bool
+	}{
+		{"scenario_id", req.ScenarioID != ""},
+		{"requests", len(req.Requests) > 0},
+		{"grpc", req.GRPC != nil},
+		{"websocket", req.WebSocket != nil},
+		{"socket", req.Socket != nil},
+		{"dns", req.DNS != nil},
+		{"sql", req.SQL != nil && req.ScenarioID == ""},
+		{"redis", req.Redis != nil},
+	}
+	var kinds []string
+	for _, f := range fields {
+		if f.set {
+			kinds = append(kinds, f.name)
+		}
+	}
+	switch len(kinds) {
+	case 0:
+		return "", nil
+	case 1:
+		return kinds[0], nil
+	}
+	return "", NewValidationError(kinds[1], fmt.Sprintf("%s cannot be combined with %s", kinds[1], kinds[0]))
+}
+
+// validateAssertionTypes rejects the assertions of a plan whose kind of
+// request has nothing for the given assertion types to check
+func validateAssertionTypes(assertions []model.Assertion, kind string, types ...model.AssertionType) error {
+	for i, a := range assertions {
+		if slices.Contains(types, a.Type) {
+			return NewValidationError(fmt.Sprintf("assertions[%d].type", i), fmt.Sprintf("%s assertions do not apply to %s requests", a.Type, kind))
+		}
+	}
+	return nil
+}
+
+// ValidateTransport validates a plan's client and connection settings
+func (v *Validator) ValidateTransport(cfg *model.TransportConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	switch cfg.HTTPVersion {
+	case "", model.HTTPVersionAuto, model.HTTPVersionHTTP1, model.HTTPVersionHTTP2, model.HTTPVersionH2C:
+	default:
+		return NewValidationError("transport.http_version", fmt.Sprintf("invalid http_version: %s (must be: auto, http1, http2 or h2c)", cfg.HTTPVersion))
+	}
+
+	switch cfg.Redirects {
+	case "", model.RedirectFollow, model.RedirectSameHost, model.RedirectNone:
+	default:
+		return NewValidationError("transport.redirects", fmt.Sprintf("invalid redirects: %s (must be: follow, same_host or none)", cfg.Redirects))
+	}
+	if cfg.MaxRedirects < 0 {
+		return NewValidationError("transport.max_redirects", "max_redirects cannot be negative")
+	}
+	if cfg.MaxRedirects > 0 && cfg.Redirects == model.RedirectNone {
+		return NewValidationError("transport.max_redirects", "max_redirects cannot be combined with redirects: none")
+	}
+
+	if cfg.MaxConnsPerHost < 0 {
+		return NewValidationError("transport.max_conns_per_host", "max_conns_per_host cannot be negative")
+	}
+	if cfg.MaxIdleConnsPerHost < 0 {
+		return NewValidationError("transport.max_idle_conns_per_host", "max_idle_conns_per_host cannot be negative")
+	}
+	if cfg.DisableKeepAlives && cfg.MaxIdleConnsPerHost > 0 {
+		return NewValidationError("transport.max_idle_conns_per_host", "max_idle_conns_per_host cannot be combined with disable_keep_alives")
+	}
+
+	timeouts := []struct {
+		field string
+		ms    int
+	}{
+		{"connect_timeout_ms", cfg.ConnectTimeoutMs},
+		{"tls_handshake_timeout_ms", cfg.TLSHandshakeTimeoutMs},
+		{"response_header_timeout_ms", cfg.ResponseHeaderTimeoutMs},
+	}
+	for _, t := range timeouts {
+		if t.ms < 0 {
+			return NewValidationError("transport."+t.field, t.field+" cannot be negative")
+		}
+		if t.ms > 300000 {
+			return NewValidationError("transport."+t.field, t.field+" cannot exceed 300000 (5 minutes)")
+		}
+	}
+
+	return nil
+}
+
+// ValidateTLS validates a plan's TLS settings. Whether the referenced
+// secrets exist is checked when the plan is created.
+func (v *Validator) ValidateTLS(cfg *model.TLSConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if cfg.InsecureSkipVerify && cfg.CASecretID != "" {
+		return NewValidationError("tls.ca_secret_id", "ca_secret_id cannot be combined with insecure_skip_verify")
+	}
+
+	versions := []struct {
+		field   string
+		version string
+	}{
+		{"min_version", cfg.MinVersion},
+		{"max_version", cfg.MaxVersion},
+	}
+	for _, ver := range versions {
+		if _, ok := model.TLSVersions[ver.version]; ver.version != "" && !ok {
+			return NewValidationError("tls."+ver.field, fmt.Sprintf("invalid %s: %s (must be: 1.0, 1.1, 1.2 or 1.3)", ver.field, ver.version))
+		}
+	}
+	if cfg.MinVersion != "" && cfg.MaxVersion != "" && model.TLSVersions[cfg.MinVersion] > model.TLSVersions[cfg.MaxVersion] {
+		return NewValidationError("tls.min_version", "min_version cannot be greater than max_version")
+	}
+
+	for i, name := range cfg.CipherSuites {
+		if _, ok := model.TLSCipherSuite(name); !ok {
+			return NewValidationError(fmt.Sprintf("tls.cipher_suites[%d]", i), fmt.Sprintf("unknown cipher suite: %s", name))
+		}
+	}
+	if len(cfg.CipherSuites) > 0 && cfg.MinVersion == "1.3" {
+		return NewValidationError("tls.cipher_suites", "cipher_suites cannot be chosen for TLS 1.3 only connections")
+	}
+
+	return nil
+}
+
+// ValidateCookies validates a plan's cookie jar settings
+func (v *Validator) ValidateCookies(cfg *model.CookieConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	if cfg.Disabled && (cfg.ResetPerIteration || len(cfg.Seed) > 0) {
+		return NewValidationError("cookies.disabled", "disabled cannot be combined with reset_per_iteration or seed")
+	}
+
+	for i, seed := range cfg.Seed {
+		field := fmt.Sprintf("cookies.seed[%d]", i)
+		if seed.Name == "" {
+			return NewValidationError(field+".name", "name is required")
+		}
+		if strings.ContainsAny(seed.Name, " \t;,=") {
+			return NewValidationError(field+".name", fmt.Sprintf("invalid cookie name: %q", seed.Name))
+		}
+		if seed.URL != "" && !strings.Contains(seed.URL, "{{") {
+			u, err := url.Parse(seed.URL)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return NewValidationError(field+".url", "url must be an absolute http or https URL")
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateAssertions validates a plan's response checks
+func (v *Validator) ValidateAssertions(assertions []model.Assertion) error {
+	return v.validateAssertions(assertions, 100, 599)
+}
+
+// validateAssertions validates response checks whose status_code checks
+// take status codes between lo and hi
+func (v *Validator) validateAssertions(assertions []model.Assertion, lo, hi float64) error {
+	for i, a := range assertions {
+		if err := v.validateAssertion(fmt.Sprintf("assertions[%d]", i), &a, lo, hi); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateAssertion validates one check, field being its path in the
+// request and lo and hi the range of the status codes it may check
+func (v *Validator) validateAssertion(field string, a *model.Assertion, lo, hi float64) error {
+	if !validAssertionOperators[a.Operator] {
+		return NewValidationError(field+".operator", fmt.Sprintf("invalid operator: %s", a.Operator))
+	}
+
+	switch a.Type {
+	case model.AssertionStatusCode:
+		if !isStatusCodes(a.Value, lo, hi) {
+			return NewValidationError(field+".value", "value must be a status code or a list of status codes")
+		}
+		if a.Operator != "" && a.Operator != "eq" && a.Operator != "ne" {
+			return NewValidationError(field+".operator", "status_code supports the eq and ne operators")
+		}
+	case model.AssertionResponseTime:
+		if ms, ok := a.Value.(float64); !ok || ms <= 0 {
+			return NewValidationError(field+".value", "value must be a number of milliseconds greater than 0")
+		}
+	case model.AssertionBodyContains:
+		if _, ok := a.Value.(string); !ok {
+			return NewValidationError(field+".value", "value must be a string")
+		}
+		if a.Operator != "" && a.Operator != "contains" && a.Operator != "not_contains" {
+			return NewValidationError(field+".operator", "body_contains supports the contains and not_contains operators")
+		}
+	case model.AssertionBodySize:
+		if size, ok := a.Value.(float64); !ok || size < 0 {
+			return NewValidationError(field+".value", "value must be a number of bytes")
+		}
+		if a.Operator == "contains" || a.Operator == "not_contains" || a.Operator == "exists" || a.Operator == "not_exists" {
+			return NewValidationError(field+".operator", "body_size supports the eq, ne, gt and lt operators")
+		}
+	case model.AssertionJSONPath, model.AssertionHeader:
+		if strings.TrimSpace(a.Target) == "" {
+			return NewValidationError(field+".target", fmt.Sprintf("target is required for %s assertions", a.Type))
+		}
+	default:
+		return NewValidationError(field+".type", fmt.Sprintf("invalid assertion type: %s", a.Type))
+	}
+
+	return nil
+}
+
+// ValidateRetry validates a retry policy of a plan or scenario step, field
+// being the policy's path in the request
+func (v *Validator) ValidateRetry(field string, policy *model.RetryPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	if policy.MaxAttempts < 2 || policy.MaxAttempts > 10 {
+		return NewValidationError(field+".max_attempts", "max_attempts must be between 2 and 10")
+	}
+
+	for i, class := range policy.RetryOn {
+		if !slices.Contains(model.ErrorClasses, class) {
+			return NewValidationError(fmt.Sprintf("%s.retry_on[%d]", field, i), fmt.Sprintf("invalid error class: %s", class))
+		}
+	}
+	for i, code := range policy.StatusCodes {
+		if code < 100 || code > 599 {
+			return NewValidationError(fmt.Sprintf("%s.status_codes[%d]", field, i), fmt.Sprintf("invalid status code: %d", code))
+		}
+	}
+
+	switch policy.Backoff {
+	case "", model.BackoffConstant, model.BackoffExponential, model.BackoffJittered:
+	default:
+		return NewValidationError(field+".backoff", fmt.Sprintf("invalid backoff: %s (must be: constant, exponential, or jittered)", policy.Backoff))
+	}
+
+	if policy.InitialDelayMs < 0 {
+		return NewValidationError(field+".initial_delay_ms", "initial_delay_ms cannot be negative")
+	}
+	if policy.MaxDelayMs < 0 {
+		return NewValidationError(field+".max_delay_ms", "max_delay_ms cannot be negative")
+	}
+	if policy.MaxDelayMs > 0 && policy.MaxDelayMs < policy.InitialDelayMs {
+		return NewValidationError(field+".max_delay_ms", "max_delay_ms cannot be less than initial_delay_ms")
+	}
+
+	return nil
+}
+
+// ValidateBodySource validates the body source of a plan, weighted request
+// or scenario step, field being the source's path in the request. A body
+// source replaces the plain body, so the two cannot be combined.
+func (v *Validator) ValidateBodySource(field string, source *model.BodySource, body string) error {
+	if source == nil {
+		return nil
+	}
+	if body != "" {
+		return NewValidationError(field, "body_source cannot be combined with body")
+	}
+
+	switch source.Type {
+	case model.BodySourceBase64:
+		if source.Base64 == "" {
+			return NewValidationError(field+".base64", "base64 is required")
+		}
+		if _, err := base64.StdEncoding.DecodeString(source.Base64); err != nil {
+			return NewValidationError(field+".base64", "base64 is not valid standard base64")
+		}
+	case model.BodySourceFile:
+		if source.FileID == "" {
+			return NewValidationError(field+".file_id", "file_id is required")
+		}
+	case model.BodySourceForm:
+		if len(source.Fields) == 0 {
+			return NewValidationError(field+".fields", "form bodies need at least one field")
+		}
+		if len(source.Files) > 0 {
+			return NewValidationError(field+".files", "form bodies cannot contain files, use multipart")
+		}
+	case model.BodySourceMultipart:
+		if len(source.Fields) == 0 && len(source.Files) == 0 {
+			return NewValidationError(field, "multipart bodies need at least one field or file")
+		}
+		for i, part := range source.Files {
+			partField := fmt.Sprintf("%s.files[%d]", field, i)
+			if strings.TrimSpace(part.Field) == "" {
+				return NewValidationError(partField+".field", "field is required")
+			}
+			if part.FileID == "" {
+				return NewValidationError(partField+".file_id", "file_id is required")
+			}
+		}
+	default:
+		return NewValidationError(field+".type", fmt.Sprintf("invalid body source type: %s (must be: base64, file, multipart, or form)", source.Type))
+	}
+
+	for i, f := range source.Fields {
+		if strings.TrimSpace(f.Name) == "" {
+			return NewValidationError(fmt.Sprintf("%s.fields[%d].name", field, i), "name is required")
+		}
+	}
+
+	return nil
+}
+
+// ValidateGRPC validates the gRPC call of a plan or scenario step, field
+// being the call's path in the request
+func (v *Validator) ValidateGRPC(field string, req *model.GRPCRequest) error {
+	if req == nil {
+		return nil
+	}
+	if strings.TrimSpace(req.Target) == "" {
+		return NewValidationError(field+".target", "target is required")
+	}
+	service, method, ok := strings.Cut(strings.TrimPrefix(req.Method, "/"), "/")
+	if !ok || service == "" || method == "" || strings.Contains(method, "/") {
+		return NewValidationError(field+".method", "method must be a full method name: package.Service/Method")
+	}
+	for i, id := range req.ProtoFileIDs {
+		if strings.TrimSpace(id) == "" {
+			return NewValidationError(fmt.Sprintf("%s.proto_file_ids[%d]", field, i), "proto file ID is required")
+		}
+	}
+	return nil
+}
+
+// ValidateGRPCStep validates the gRPC call of a scenario step, field being
+// the step's path in the request. Retry policies only apply to HTTP steps.
+func (v *Validator) ValidateGRPCStep(field string, step *model.Step) error {
+	if step.GRPC == nil {
+		return nil
+	}
+	if step.Body != "" || step.BodySource != nil {
+		return NewValidationError(field+".grpc", "grpc cannot be combined with body or body_source")
+	}
+	if step.Retry != nil {
+		return NewValidationError(field+".retry", "retry cannot be combined with grpc")
+	}
+	return v.ValidateGRPC(field+".grpc", step.GRPC)
+}
+
+// ValidateWebSocket validates the WebSocket session of a plan or scenario
+// step, field being the session's path in the request
+func (v *Validator) ValidateWebSocket(field string, req *model.WebSocketRequest) error {
+	if req == nil {
+		return nil
+	}
+	scheme, _, ok := strings.Cut(strings.TrimSpace(req.URL), "://")
+	if !ok || (!strings.EqualFold(scheme, "ws") && !strings.EqualFold(scheme, "wss")) {
+		return NewValidationError(field+".url", "url must be a ws:// or wss:// URL")
+	}
+	if req.HoldMs < 0 {
+		return NewValidationError(field+".hold_ms", "hold_ms cannot be negative")
+	}
+
+	for i, msg := range req.Messages {
+		msgField := fmt.Sprintf("%s.messages[%d]", field, i)
+		if msg.DelayMs < 0 || msg.IntervalMs < 0 {
+			return NewValidationError(msgField, "delay_ms and interval_ms cannot be negative")
+		}
+		if msg.Repeat < 0 {
+			return NewValidationError(msgField+".repeat", "repeat cannot be negative")
+		}
+		reply := msg.Reply
+		if reply == nil {
+			continue
+		}
+		switch {
+		case (reply.JSONPath == "") == (reply.Regex == ""):
+			return NewValidationError(msgField+".reply", "reply must set exactly one of json_path or regex")
+		case reply.Regex != "" && reply.Value != "":
+			return NewValidationError(msgField+".reply.value", "value only applies to json_path replies")
+		case reply.TimeoutMs < 0:
+			return NewValidationError(msgField+".reply.timeout_ms", "timeout_ms cannot be negative")
+		}
+		if reply.Regex != "" {
+			if _, err := regexp.Compile(reply.Regex); err != nil {
+				return NewValidationError(msgField+".reply.regex", "invalid regex: "+err.Error())
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateWebSocketStep validates the WebSocket session of a scenario step,
+// field being the step's path in the request. Retry policies only apply to
+// HTTP steps.
+func (v *Validator) ValidateWebSocketStep(field string, step *model.Step) error {
+	if step.WebSocket == nil {
+		return nil
+	}
+	if step.GRPC != nil {
+		return NewValidationError(field+".websocket", "websocket cannot be combined with grpc")
+	}
+	if step.Body != "" || step.BodySource != nil {
+		return NewValidationError(field+".websocket", "websocket cannot be combined with body or body_source")
+	}
+	if step.Retry != nil {
+		return NewValidationError(field+".retry", "retry cannot be combined with websocket")
+	}
+	return v.ValidateWebSocket(field+".websocket", step.WebSocket)
+}
+
+// ValidateSocket validates the TCP or UDP exchange of a plan, field being
+// the exchange's path in the request. Payloads with templates are only
+// decoded once their templates are filled in, when the test runs.
+func (v *Validator) ValidateSocket(field string, req *model.SocketRequest) error {
+	if req == nil {
+		return nil
+	}
+	if req.Network != model.SocketTCP && req.Network != model.SocketUDP {
+		return NewValidationError(field+".network", "network must be tcp or udp")
+	}
+	if !strings.Contains(req.Address, "{{") {
+		if _, _, err := net.SplitHostPort(req.Address); err != nil {
+			return NewValidationError(field+".address", "address must be host:port")
+		}
+	}
+	switch req.Encoding {
+	case "", model.EncodingText, model.EncodingHex, model.EncodingBase64:
+	default:
+		return NewValidationError(field+".encoding", fmt.Sprintf("invalid encoding: %s", req.Encoding))
+	}
+	if !strings.Contains(req.Payload, "{{") && !isEncoded(req.Payload, req.Encoding) {
+		return NewValidationError(field+".payload", fmt.Sprintf("payload is not valid %s", req.Encoding))
+	}
+
+	resp := req.Response
+	if resp == nil {
+		return nil
+	}
+	if resp.Delimiter != "" {
+		if req.Network == model.SocketUDP {
+			return NewValidationError(field+".response.delimiter", "delimiter only applies to tcp, every udp datagram is a response")
+		}
+		if !isEncoded(resp.Delimiter, req.Encoding) {
+			return NewValidationError(field+".response.delimiter", fmt.Sprintf("delimiter is not valid %s", req.Encoding))
+		}
+	}
+	if resp.Pattern != "" {
+		if _, err := regexp.Compile(resp.Pattern); err != nil {
+			return NewValidationError(field+".response.pattern", "invalid regex: "+err.Error())
+		}
+	}
+	// On a kept-alive connection, the rest of a response cut at its first
+	// bytes would be read as the next one
+	if req.KeepAlive && req.Network == model.SocketTCP && resp.Delimiter == "" && resp.Pattern == "" {
+		return NewValidationError(field+".keep_alive", "keep_alive needs a response delimiter or pattern to tell where a tcp response ends")
+	}
+	if resp.MaxBytes < 0 {
+		return NewValidationError(field+".response.max_bytes", "max_bytes cannot be negative")
+	}
+	if resp.TimeoutMs < 0 {
+		return NewValidationError(field+".response.timeout_ms", "timeout_ms cannot be negative")
+	}
+	return nil
+}
+
+// ValidateDNS validates the DNS query of a plan, field being the query's
+// path in the request
+func (v *Validator) ValidateDNS(field string, req *model.DNSRequest) error {
+	if req == nil {
+		return nil
+	}
+	if strings.TrimSpace(req.Server) == "" {
+		return NewValidationError(field+".server", "server is required")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return NewValidationError(field+".name", "name is required")
+	}
+	switch req.Type {
+	case "", model.DNSTypeA, model.DNSTypeAAAA, model.DNSTypeSRV, model.DNSTypeTXT:
+	default:
+		return NewValidationError(field+".type", fmt.Sprintf("invalid record type: %s", req.Type))
+	}
+	if req.Network != "" && req.Network != model.SocketUDP && req.Network != model.SocketTCP {
+		return NewValidationError(field+".network", "network must be udp or tcp")
+	}
+	return nil
+}
+
+// ValidateSQL validates the database and statements of a plan, field being
+// the request's path. Queries and arguments may contain templates, so only
+// their presence is checked here.
+func (v *Validator) ValidateSQL(field string, req *model.SQLRequest) error {
+	if req == nil {
+		return nil
+	}
+	if strings.TrimSpace(req.DSN) == "" {
+		return NewValidationError(field+".dsn", "dsn is required")
+	}
+	if dsnHasPassword(req.DSN) {
+		return NewValidationError(field+".dsn", "dsn may not contain a password; reference a credentials secret with credentials_secret_id")
+	}
+	if req.MaxOpenConns < 0 {
+		return NewValidationError(field+".max_open_conns", "max_open_conns cannot be negative")
+	}
+	if len(req.Statements) > 100 {
+		return NewValidationError(field+".statements", "statements cannot contain more than 100 entries")
+	}
+
+	names := make(map[string]bool, len(req.Statements))
+	for i := range req.Statements {
+		stmt := &req.Statements[i]
+		stmtField := fmt.Sprintf("%s.statements[%d]", field, i)
+		if strings.TrimSpace(stmt.Name) == "" {
+			return NewValidationError(stmtField+".name", "name is required")
+		}
+		if names[stmt.Name] {
+			return NewValidationError(stmtField+".name", fmt.Sprintf("duplicate statement name %q", stmt.Name))
+		}
+		names[stmt.Name] = true
+		if strings.TrimSpace(stmt.Query) == "" {
+			return NewValidationError(stmtField+".query", "query is required")
+		}
+		if stmt.Weight < 0 {
+			return NewValidationError(stmtField+".weight", "weight cannot be negative")
+		}
+	}
+	return nil
+}
+
+// dsnHasPassword reports whether a URL or key=value DSN holds a password
+func dsnHasPassword(dsn string) bool {
+	if !strings.Contains(dsn, "://") {
+		return dsnPasswordPattern.MatchString(dsn)
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return false
+	}
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			return true
+		}
+	}
+	return u.Query().Has("password")
+}
+
+// ValidateSQLStep validates the SQL statement of a scenario step, field
+// being the step's path in the request. Retry policies only apply to HTTP
+// steps.
+func (v *Validator) ValidateSQLStep(field string, step *model.Step) error {
+	if step.SQL == nil {
+		return nil
+	}
+	if step.GRPC != nil || step.WebSocket != nil {
+		return NewValidationError(field+".sql", "sql cannot be combined with grpc or websocket")
+	}
+	if step.Body != "" || step.BodySource != nil {
+		return NewValidationError(field+".sql", "sql cannot be combined with body or body_source")
+	}
+	if step.Retry != nil {
+		return NewValidationError(field+".retry", "retry cannot be combined with sql")
+	}
+	if strings.TrimSpace(step.SQL.Query) == "" {
+		return NewValidationError(field+".sql.query", "query is required")
+	}
+	return nil
+}
+
+// ValidateRedis validates the server and commands of a plan, field being the
+// request's path. Arguments may contain templates, but command names may
+// not, since the per-command breakdown is named after them.
+func (v *Validator) ValidateRedis(field string, req *model.RedisRequest) error {
+	if req == nil {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(req.Address); err != nil {
+		return NewValidationError(field+".address", "address must be host:port")
+	}
+	if req.DB < 0 {
+		return NewValidationError(field+".db", "db cannot be negative")
+	}
+	if req.PoolSize < 0 {
+		return NewValidationError(field+".pool_size", "pool_size cannot be negative")
+	}
+	switch req.Mode {
+	case "", model.RedisModeSingle, model.RedisModePipeline, model.RedisModeTransaction:
+	default:
+		return NewValidationError(field+".mode", fmt.Sprintf("invalid mode: %s", req.Mode))
+	}
+	if len(req.Commands) == 0 {
+		return NewValidationError(field+".commands", "at least one command is required")
+	}
+	if len(req.Commands) > 100 {
+		return NewValidationError(field+".commands", "commands cannot contain more than 100 entries")
+	}
+
+	for i, cmd := range req.Commands {
+		cmdField := fmt.Sprintf("%s.commands[%d]", field, i)
+		if len(cmd.Args) == 0 || strings.TrimSpace(cmd.Args[0]) == "" {
+			return NewValidationError(cmdField+".args", "command name is required")
+		}
+		name := strings.ToUpper(cmd.Args[0])
+		if strings.Contains(name, "{{") {
+			return NewValidationError(cmdField+".args", "command name cannot contain templates")
+		}
+		if slices.Contains(model.RedisDisallowedCommands, name) {
+			return NewValidationError(cmdField+".args", fmt.Sprintf("%s cannot be sent by a plan", name))
+		}
+		if cmd.Weight < 0 {
+			return NewValidationError(cmdField+".weight", "weight cannot be negative")
+		}
+	}
+	return nil
+}
+
+// isEncoded reports whether s decodes as a socket payload in encoding
+func isEncoded(s string, encoding model.PayloadEncoding) bool {
+	var err error
+	switch encoding {
+	case model.EncodingHex:
+		_, err = hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	case model.EncodingBase64:
+		_, err = base64.StdEncoding.DecodeString(s)
+	}
+	return err == nil
+}
+
+// ValidateStream validates how a plan reads its responses as streams. A
+// stream is read within the plan's timeout, so max_duration_ms cannot
+// exceed timeoutMs; 0 leaves the check to the server's default timeout.
+func (v *Validator) ValidateStream(cfg *model.StreamConfig, timeoutMs int) error {
+	if cfg == nil {
+		return nil
+	}
+	switch cfg.Format {
+	case "", model.StreamSSE, model.StreamLines, model.StreamChunks:
+	default:
+		return NewValidationError("stream.format", fmt.Sprintf("invalid stream format: %s", cfg.Format))
+	}
+	if cfg.MaxEvents < 0 {
+		return NewValidationError("stream.max_events", "max_events cannot be negative")
+	}
+	if cfg.MaxDurationMs < 0 {
+		return NewValidationError("stream.max_duration_ms", "max_duration_ms cannot be negative")
+	}
+	if timeoutMs > 0 && cfg.MaxDurationMs > timeoutMs {
+		return NewValidationError("stream.max_duration_ms",
+			fmt.Sprintf("max_duration_ms (%d) cannot exceed timeout_ms (%d), which ends the stream first", cfg.MaxDurationMs, timeoutMs))
+	}
+
+	for i := range cfg.EventChecks {
+		check := &cfg.EventChecks[i]
+		field := fmt.Sprintf("stream.event_checks[%d]", i)
+		switch check.Type {
+		case model.AssertionBodyContains, model.AssertionJSONPath, model.AssertionBodySize:
+		default:
+			return NewValidationError(field+".type", "event checks support the body_contains, jsonpath and body_size types")
+		}
+		if check.Match != "" && check.Match != model.EventMatchEvery && check.Match != model.EventMatchAny {
+			return NewValidationError(field+".match", "match must be every or any")
+		}
+		if err := v.validateAssertion(field, &check.Assertion, 0, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isStatusCodes reports whether value is a status code between lo and hi or
+// a non-empty list of them, as decoded from JSON
+func isStatusCodes(value interface{}, lo, hi float64) bool {
+	switch v := value.(type) {
+	case float64:
+		return v >= lo && v <= hi && v == float64(int(v))
+	case []interface{}:
+		if len(v) == 0 {
+			return false
+		}
+		for _, code := range v {
+			if !isStatusCodes(code, lo, hi) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// ValidateLoadProfile validates stages, waves, ramp-down and graceful stop
+func (v *Validator) ValidateLoadProfile(req *model.CreateTestPlanRequest) error {
+	switch req.RatePattern {
+	case model.RatePatternStages:
+		if len(req.Stages) == 0 {
+			return NewValidationError("stages", "stages are required for the stages pattern")
+		}
+		for i, stage := range req.Stages {
+			field := fmt.Sprintf("stages[%d]", i)
+			if stage.DurationSec <= 0 {
+				return NewValidationError(field+".duration_sec", "duration_sec must be greater than 0")
+			}
+			if stage.TargetRPS != nil && *stage.TargetRPS < 0 {
+				return NewValidationError(field+".target_rps", "target_rps cannot be negative")
+			}
+			if stage.TargetVUs != nil {
+				if *stage.TargetVUs < 0 {
+					return NewValidationError(field+".target_vus", "target_vus cannot be negative")
+				}
+				if *stage.TargetVUs > 10000 {
+					return NewValidationError(field+".target_vus", "target_vus cannot exceed 10000")
+				}
+				if req.Executor == model.ExecutorArrivalRate {
+					return NewValidationError(field+".target_vus", "target_vus is not supported by the arrival_rate executor, which sizes its pool from the arrival rate")
+				}
+			}
+		}
+	case model.RatePatternSine, model.RatePatternDiurnal:
+		if req.Wave == nil {
+			return NewValidationError("wave", fmt.Sprintf("wave is required for the %s pattern", req.RatePattern))
+		}
+		if req.Wave.MinRPS < 0 {
+			return NewValidationError("wave.min_rps", "min_rps cannot be negative")
+		}
+		if req.Wave.MaxRPS <= 0 || req.Wave.MaxRPS < req.Wave.MinRPS {
+			return NewValidationError("wave.max_rps", "max_rps must be greater than 0 and at least min_rps")
+		}
+		if req.Wave.PeriodSec < 0 {
+			return NewValidationError("wave.period_sec", "period_sec cannot be negative")
+		}
+	}
+
+	if req.RampDownSec < 0 {
+		return NewValidationError("ramp_down_sec", "ramp_down_sec cannot be negative")
+	}
+	if req.RampDownSec > req.DurationSec {
+		return NewValidationError("ramp_down_sec", "ramp_down_sec cannot exceed duration_sec")
+	}
+
+	if req.GracefulStopSec < 0 {
+		return NewValidationError("graceful_stop_sec", "graceful_stop_sec cannot be negative")
+	}
+	if req.GracefulStopSec > 3600 {
+		return NewValidationError("graceful_stop_sec", "graceful_stop_sec cannot exceed 3600 (1 hour)")
+	}
+
+	return nil
+}
+
+// ValidateDataFeed validates a data feed attached to a plan or scenario
+func (v *Validator) ValidateDataFeed(feed *model.DataFeed) error {
+	if feed == nil {
+		return nil
+	}
+
+	if strings.TrimSpace(feed.DataSetID) == "" {
+		return NewValidationError("data.data_set_id", "data_set_id is required")
+	}
+
+	switch feed.Strategy {
+	case "", model.FeedSequential, model.FeedRandom, model.FeedUnique:
+		if feed.OnExhausted != "" {
+			return NewValidationError("data.on_exhausted", "on_exhausted is only supported by the once strategy")
+		}
+	case model.FeedOnce:
+		if feed.OnExhausted != "" && feed.OnExhausted != model.FeedExhaustedStop && feed.OnExhausted != model.FeedExhaustedRecycle {
+			return NewValidationError("data.on_exhausted", fmt.Sprintf("invalid on_exhausted: %s (must be: stop or recycle)", feed.OnExhausted))
+		}
+	default:
+		return NewValidationError("data.strategy", fmt.Sprintf("invalid strategy: %s (must be: sequential, random, unique or once)", feed.Strategy))
+	}
+
+	return nil
+}
+
+// ValidateRequestMix validates a weighted request mix
+func (v *Validator) ValidateRequestMix(requests []model.WeightedRequest) error {
+	if len(requests) > 100 {
+		return NewValidationError("requests", "requests cannot contain more than 100 entries")
+	}
+
+	names := make(map[string]bool, len(requests))
+	for i, r := range requests {
+		field := fmt.Sprintf("requests[%d]", i)
+		if strings.TrimSpace(r.Name) == "" {
+			return NewValidationError(field+".name", "name is required")
+		}
+		if names[r.Name] {
+			return NewValidationError(field+".name", fmt.Sprintf("duplicate request name %q", r.Name))
+		}
+		names[r.Name] = true
+
+		if !validMethods[strings.ToUpper(r.Method)] {
+			return NewValidationError(field+".method", "invalid HTTP method")
+		}
+		// URLs may contain {{...}} templates, so only the scheme is checked here
+		if !strings.HasPrefix(r.URL, "http://") && !strings.HasPrefix(r.URL, "https://") {
+			return NewValidationError(field+".url", "url must start with http:// or https://")
+		}
+		if r.Weight < 1 {
+			return NewValidationError(field+".weight", "weight must be at least 1")
+		}
+		if err := v.ValidateBodySource(field+".body_source", r.BodySource, r.Body); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ValidateExecutor validates executor and VU pool configuration
+func (v *Validator) ValidateExecutor(req *model.CreateTestPlanRequest) error {
+	switch req.Executor {
+	case "", model.ExecutorClosed:
+		if req.MaxVUs != 0 {
+			return NewValidationError("max_vus", "max_vus is only supported by the arrival_rate executor")
+		}
+	case model.ExecutorArrivalRate:
+		if req.MaxVUs < 0 {
+			return NewValidationError("max_vus", "max_vus cannot be negative")
+		}
+		if req.MaxVUs > 10000 {
+			return NewValidationError("max_vus", "max_vus cannot exceed 10000")
+		}
+		if req.MaxVUs > 0 && req.MaxVUs < req.Users {
+			return NewValidationError("max_vus", "max_vus cannot be lower than users (pre-allocated VUs)")
+		}
+		pattern := req.RatePattern
+		if (pattern == "" || pattern == model.RatePatternFixed) && req.TargetRPS == 0 {
+			return NewValidationError("target_rps", "target_rps is required for the arrival_rate executor")
+		}
+		if pattern == model.RatePatternStages && req.TargetRPS == 0 && !model.StagesSetRPS(req.Stages) {
+			return NewValidationError("stages", "a stage target_rps or target_rps is required for the arrival_rate executor")
+		}
+	default:
+		return NewValidationError("executor", fmt.Sprintf("invalid executor: %s (must be: closed or arrival_rate)", req.Executor))
+	}
+
 	return nil
 }
 
@@ -80,6 +1083,29 @@ func (v *Validator) ValidateStartTestRequest(req *model.StartTestRequest) error
 	return nil
 }
 
+// ValidateControlCommand validates a live control command
+func (v *Validator) ValidateControlCommand(cmd *model.ControlCommand) error {
+	switch cmd.Action {
+	case model.ControlPause, model.ControlResume:
+		return nil
+	case model.ControlSetRPS:
+		if cmd.Value < 0 {
+			return NewValidationError("value", "set_rps value cannot be negative")
+		}
+	case model.ControlAddVUs, model.ControlRemoveVUs:
+		if cmd.Value < 1 {
+			return NewValidationError("value", fmt.Sprintf("%s value must be at least 1", cmd.Action))
+		}
+	case model.ControlExtendDuration:
+		if cmd.Value == 0 {
+			return NewValidationError("value", "extend_duration value must be a non-zero number of seconds")
+		}
+	default:
+		return NewValidationError("action", fmt.Sprintf("invalid action: %s (must be: pause, resume, set_rps, add_vus, remove_vus or extend_duration)", cmd.Action))
+	}
+	return nil
+}
+
 // ValidateSLAConfig validates SLA configuration
 func (v *Validator) ValidateSLAConfig(sla *model.SLAConfig) error {
 	if sla == nil {
@@ -109,10 +1135,11 @@ func (v *Validator) ValidateSLAConfig(sla *model.SLAConfig) error {
 func (v *Validator) ValidateRatePattern(pattern string, steps []model.RateStep) error {
 	validPatterns := map[string]bool{
 		"fixed": true, "step": true, "ramp": true, "spike": true,
+		"stages": true, "sine": true, "diurnal": true,
 	}
 
 	if !validPatterns[pattern] {
-		return NewValidationError("rate_pattern", fmt.Sprintf("invalid rate pattern: %s (must be: fixed, step, ramp, or spike)", pattern))
+		return NewValidationError("rate_pattern", fmt.Sprintf("invalid rate pattern: %s (must be: fixed, step, ramp, spike, stages, sine, or diurnal)", pattern))
 	}
 
 	if pattern == "step" && len(steps) == 0 {
@@ -77,10 +77,10 @@ func BenchmarkWorkerRequestExecution(b *testing.B) {
 	worker := NewWorker(1, plan, m, http.DefaultClient, collector)
 
 	ctx, cancel := context.WithCancel(context.Background())
-	requestChan := make(chan struct{}, b.N)
+	requestChan := make(chan Arrival, b.N)
 
 	for i := 0; i < b.N; i++ {
-		requestChan <- struct{}{}
+		requestChan <- Arrival{}
 	}
 
 	b.ResetTimer()
@@ -179,10 +179,10 @@ func BenchmarkWorkerPOSTRequest(b *testing.B) {
 	worker := NewWorker(1, plan, m, http.DefaultClient, collector)
 
 	ctx, cancel := context.WithCancel(context.Background())
-	requestChan := make(chan struct{}, b.N)
+	requestChan := make(chan Arrival, b.N)
 
 	for i := 0; i < b.N; i++ {
-		requestChan <- struct{}{}
+		requestChan <- Arrival{}
 	}
 
 	b.ResetTimer()
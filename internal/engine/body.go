@@ -0,0 +1,127 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// Content types of the bodies built from a body source
+const (
+	contentTypeOctetStream = "application/octet-stream"
+	contentTypeForm        = "application/x-www-form-urlencoded"
+)
+
+// quoteEscaper escapes the field and file names of a multipart part header
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// buildBody returns the body a request sends and the content type it sets,
+// if any. A body source replaces the plain body; process applies data and
+// template substitution to its form field values and file names.
+func buildBody(body string, source *model.BodySource, files model.BodyFiles, process func(string) string) ([]byte, string, error) {
+	if source == nil {
+		return []byte(body), "", nil
+	}
+
+	switch source.Type {
+	case model.BodySourceBase64:
+		data, err := base64.StdEncoding.DecodeString(source.Base64)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid base64 body: %w", err)
+		}
+		return data, defaultString(source.ContentType, contentTypeOctetStream), nil
+
+	case model.BodySourceFile:
+		file, ok := files[source.FileID]
+		if !ok {
+			return nil, "", fmt.Errorf("body file %s is not loaded", source.FileID)
+		}
+		return file.Data, defaultString(source.ContentType, file.ContentType, contentTypeOctetStream), nil
+
+	case model.BodySourceForm:
+		var form strings.Builder
+		for i, field := range source.Fields {
+			if i > 0 {
+				form.WriteByte('&')
+			}
+			form.WriteString(url.QueryEscape(field.Name))
+			form.WriteByte('=')
+			form.WriteString(url.QueryEscape(process(field.Value)))
+		}
+		return []byte(form.String()), contentTypeForm, nil
+
+	case model.BodySourceMultipart:
+		return buildMultipart(source, files, process)
+	}
+	return nil, "", fmt.Errorf("invalid body source type: %s", source.Type)
+}
+
+// buildMultipart builds a multipart/form-data body, fields first, then file
+// parts, each in the order the source lists them
+func buildMultipart(source *model.BodySource, files model.BodyFiles, process func(string) string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	for _, field := range source.Fields {
+		if err := writer.WriteField(field.Name, process(field.Value)); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for _, part := range source.Files {
+		file, ok := files[part.FileID]
+		if !ok {
+			return nil, "", fmt.Errorf("body file %s is not loaded", part.FileID)
+		}
+		filename := file.Name
+		if part.Filename != "" {
+			filename = process(part.Filename)
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			quoteEscaper.Replace(part.Field), quoteEscaper.Replace(filename)))
+		header.Set("Content-Type", defaultString(part.ContentType, file.ContentType, contentTypeOctetStream))
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := w.Write(file.Data); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), writer.FormDataContentType(), nil
+}
+
+// setContentType sets the content type of a built body. Multipart bodies
+// always get theirs, which names the boundary; other bodies keep a
+// Content-Type header the request sets itself.
+func setContentType(header http.Header, contentType string) {
+	if contentType == "" {
+		return
+	}
+	if header.Get("Content-Type") == "" || strings.HasPrefix(contentType, "multipart/") {
+		header.Set("Content-Type", contentType)
+	}
+}
+
+// defaultString returns the first non-empty value
+func defaultString(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
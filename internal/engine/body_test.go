@@ -0,0 +1,153 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// bodyServer records the content type and a summary of the body of every
+// request it receives
+type bodyServer struct {
+	mu       sync.Mutex
+	received []string
+}
+
+func (s *bodyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var summary string
+	contentType := r.Header.Get("Content-Type")
+	switch {
+	case contentType == contentTypeForm:
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		summary = fmt.Sprintf("form user=%s note=%s", r.PostForm.Get("user"), r.PostForm.Get("note"))
+	case r.ParseMultipartForm(1<<20) == nil:
+		file, header, err := r.FormFile("avatar")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		summary = fmt.Sprintf("multipart user=%s %s %s %q", r.FormValue("user"),
+			header.Filename, header.Header.Get("Content-Type"), data)
+	default:
+		data, _ := io.ReadAll(r.Body)
+		summary = fmt.Sprintf("%s %q", contentType, data)
+	}
+	s.mu.Lock()
+	s.received = append(s.received, summary)
+	s.mu.Unlock()
+}
+
+func TestWorkerBodySources(t *testing.T) {
+	files := model.BodyFiles{
+		"avatar": {Name: "avatar.png", ContentType: "image/png", Data: []byte("\x89PNG")},
+		"doc":    {Name: "doc.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.7")},
+	}
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		source   *model.BodySource
+		received string
+	}{
+		{"base64", nil, &model.BodySource{Type: model.BodySourceBase64, Base64: "CAESBWFsaWNl", ContentType: "application/x-protobuf"},
+			`application/x-protobuf "\b\x01\x12\x05alice"`},
+		{"base64 default type", nil, &model.BodySource{Type: model.BodySourceBase64, Base64: "AAE="},
+			`application/octet-stream "\x00\x01"`},
+		{"file", nil, &model.BodySource{Type: model.BodySourceFile, FileID: "doc"},
+			`application/pdf "%PDF-1.7"`},
+		{"header keeps its type", map[string]string{"Content-Type": "application/octet-stream"}, &model.BodySource{Type: model.BodySourceFile, FileID: "doc"},
+			`application/octet-stream "%PDF-1.7"`},
+		{"form", nil, &model.BodySource{Type: model.BodySourceForm, Fields: []model.FormField{{Name: "user", Value: "{{data.user}}"}, {Name: "note", Value: "a&b=c"}}},
+			"form user=alice note=a&b=c"},
+		{"multipart", map[string]string{"Content-Type": "application/json"}, &model.BodySource{
+			Type:   model.BodySourceMultipart,
+			Fields: []model.FormField{{Name: "user", Value: "{{data.user}}"}},
+			Files:  []model.FilePart{{Field: "avatar", FileID: "avatar", Filename: "{{data.user}}.png"}},
+		}, `multipart user=alice alice.png image/png "\x89PNG"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &bodyServer{}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			plan := &model.TestPlan{ID: "test-body", TargetURL: ts.URL, Method: "POST", TimeoutMs: 5000,
+				Headers: tt.headers, BodySource: tt.source, BodyFiles: files}
+			m := model.NewMetrics("run-body")
+			worker := NewWorker(1, plan, m, ts.Client(), getSharedTestCollector())
+			worker.row = map[string]string{"user": "alice"}
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if m.SuccessRequests != 1 || len(server.received) != 1 {
+				t.Fatalf("Expected one successful request, got %d (errors %v)", m.SuccessRequests, m.ErrorSamples)
+			}
+			if server.received[0] != tt.received {
+				t.Errorf("Expected the server to receive %s, got %s", tt.received, server.received[0])
+			}
+		})
+	}
+}
+
+func TestWorkerBodySourceMissingFile(t *testing.T) {
+	server := &bodyServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	plan := &model.TestPlan{ID: "test-body-missing", TargetURL: ts.URL, Method: "POST", TimeoutMs: 5000,
+		BodySource: &model.BodySource{Type: model.BodySourceFile, FileID: "missing"}}
+	m := model.NewMetrics("run-body-missing")
+	worker := NewWorker(1, plan, m, ts.Client(), getSharedTestCollector())
+
+	worker.executeRequest(context.Background(), Arrival{})
+
+	if m.FailedRequests != 1 || m.Errors[model.ErrorClassOther] != 1 || len(server.received) != 0 {
+		t.Errorf("Expected the request to fail without being sent, got %d failed with errors %v", m.FailedRequests, m.Errors)
+	}
+}
+
+func TestScenarioExecutorBodySources(t *testing.T) {
+	server := &bodyServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	scenario := &model.Scenario{
+		ID: "scenario-body",
+		Steps: []model.Step{
+			{Name: "upload", Method: "POST", URL: ts.URL, BodySource: &model.BodySource{
+				Type:   model.BodySourceMultipart,
+				Fields: []model.FormField{{Name: "user", Value: "{{user}}"}},
+				Files:  []model.FilePart{{Field: "avatar", FileID: "avatar"}},
+			}},
+			{Name: "form", Method: "POST", URL: ts.URL, BodySource: &model.BodySource{
+				Type:   model.BodySourceForm,
+				Fields: []model.FormField{{Name: "user", Value: "{{user}}"}},
+			}},
+		},
+	}
+	files := model.BodyFiles{"avatar": {Name: "avatar.png", ContentType: "image/png", Data: []byte("\x89PNG")}}
+
+	execution, err := NewScenarioExecutor().WithBodyFiles(files).Execute(scenario, model.Variables{"user": "bob"})
+	if err != nil {
+		t.Fatalf("Failed to execute scenario: %v", err)
+	}
+	if execution.Status != model.StatusCompleted {
+		t.Errorf("Expected the execution to complete, got %s", execution.Status)
+	}
+
+	want := fmt.Sprint([]string{`multipart user=bob avatar.png image/png "\x89PNG"`, "form user=bob note="})
+	if got := fmt.Sprint(server.received); got != want {
+		t.Errorf("Expected the server to receive %s, got %s", want, got)
+	}
+}
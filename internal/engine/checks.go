@@ -0,0 +1,203 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// runChecks evaluates a plan's checks against a response. It returns the
+// result of every check, whether all of them passed and whether any of them
+// checks the status code, which then replaces the default 2xx/3xx success.
+func runChecks(assertions []model.Assertion, resp *http.Response, body []byte, responseTimeMs int64) ([]bool, bool, bool) {
+	results := make([]bool, len(assertions))
+	passed, statusChecked := true, false
+	for i := range assertions {
+		results[i] = evaluateAssertion(&assertions[i], resp, body, responseTimeMs)
+		passed = passed && results[i]
+		statusChecked = statusChecked || assertions[i].Type == model.AssertionStatusCode
+	}
+	return results, passed, statusChecked
+}
+
+// planChecks returns the checks whose results a plan's responses record:
+// its assertions, followed by the event checks of a streaming plan under
+// their labels
+func planChecks(plan *model.TestPlan) []model.Assertion {
+	if plan.Stream == nil || len(plan.Stream.EventChecks) == 0 {
+		return plan.Assertions
+	}
+	checks := make([]model.Assertion, 0, len(plan.Assertions)+len(plan.Stream.EventChecks))
+	checks = append(checks, plan.Assertions...)
+	for i := range plan.Stream.EventChecks {
+		check := plan.Stream.EventChecks[i].Assertion
+		check.Name = plan.Stream.EventChecks[i].Label()
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// failedChecksError returns the error a response that failed checks is
+// recorded with, naming the checks it failed
+func failedChecksError(assertions []model.Assertion, results []bool) error {
+	var failed []string
+	for i, passed := range results {
+		if !passed {
+			failed = append(failed, assertions[i].Label())
+		}
+	}
+	return fmt.Errorf("%w: %s", model.ErrCheckFailed, strings.Join(failed, ", "))
+}
+
+// evaluateAssertion checks if an assertion passes. It is shared by scenario
+// steps and the response checks of simple and request mix plans.
+func evaluateAssertion(assertion *model.Assertion, resp *http.Response, body []byte, responseTimeMs int64) bool {
+	switch assertion.Type {
+	case model.AssertionStatusCode:
+		matched := statusMatches(resp.StatusCode, assertion.Value)
+		if assertion.Operator == "ne" {
+			return !matched
+		}
+		return matched
+
+	case model.AssertionResponseTime:
+		maxTime, ok := assertion.Value.(float64)
+		if !ok {
+			return false
+		}
+		return responseTimeMs <= int64(maxTime)
+
+	case model.AssertionBodyContains:
+		expected, ok := assertion.Value.(string)
+		if !ok {
+			return false
+		}
+		contains := strings.Contains(string(body), expected)
+		if assertion.Operator == "not_contains" {
+			return !contains
+		}
+		return contains
+
+	case model.AssertionBodySize:
+		return compareValues(len(body), assertion.Operator, assertion.Value)
+
+	case model.AssertionHeader:
+		values, exists := resp.Header[http.CanonicalHeaderKey(assertion.Target)]
+		switch assertion.Operator {
+		case "exists":
+			return exists
+		case "not_exists":
+			return !exists
+		}
+		if !exists {
+			values = []string{""}
+		}
+		expected := fmt.Sprintf("%v", assertion.Value)
+		return compareValues(values[0], assertion.Operator, expected)
+
+	case model.AssertionJSONPath:
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			// A body that is not JSON has no value at any path
+			return assertion.Operator == "not_exists"
+		}
+		value := extractJSONPath(data, assertion.Target)
+		switch assertion.Operator {
+		case "exists":
+			return value != nil
+		case "not_exists":
+			return value == nil
+		}
+		return compareValues(value, assertion.Operator, assertion.Value)
+
+	default:
+		return false
+	}
+}
+
+// statusMatches reports whether status equals an expected code, or is one
+// of a list of codes. JSON numbers decode as float64.
+func statusMatches(status int, expected interface{}) bool {
+	switch v := expected.(type) {
+	case float64:
+		return status == int(v)
+	case int:
+		return status == v
+	case []interface{}:
+		for _, code := range v {
+			if statusMatches(status, code) {
+				return true
+			}
+		}
+	case []int:
+		for _, code := range v {
+			if status == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractJSONPath extracts value using simple dot notation (e.g., "data.id"
+// or "$.data.id")
+func extractJSONPath(data interface{}, path string) interface{} {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return data
+	}
+
+	parts := strings.Split(path, ".")
+	current := data
+
+	for _, part := range parts {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			current = v[part]
+		default:
+			return nil
+		}
+		if current == nil {
+			return nil
+		}
+	}
+
+	return current
+}
+
+// compareValues compares two values using an operator. gt and lt compare
+// numerically when both values are numbers, as strings otherwise.
+func compareValues(left interface{}, operator string, right interface{}) bool {
+	leftStr := fmt.Sprintf("%v", left)
+	rightStr := fmt.Sprintf("%v", right)
+
+	switch operator {
+	case "eq", "":
+		return leftStr == rightStr
+	case "ne":
+		return leftStr != rightStr
+	case "contains":
+		return strings.Contains(leftStr, rightStr)
+	case "not_contains":
+		return !strings.Contains(leftStr, rightStr)
+	case "gt", "lt":
+		l, lErr := strconv.ParseFloat(leftStr, 64)
+		r, rErr := strconv.ParseFloat(rightStr, 64)
+		if lErr == nil && rErr == nil {
+			if operator == "gt" {
+				return l > r
+			}
+			return l < r
+		}
+		if operator == "gt" {
+			return leftStr > rightStr
+		}
+		return leftStr < rightStr
+	default:
+		return false
+	}
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+func TestWorkerResponseChecks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/maintenance":
+			_, _ = w.Write([]byte(`{"error":"maintenance"}`))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"id":0}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":42,"status":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	noError := model.Assertion{Name: "no error", Type: model.AssertionJSONPath, Target: "$.error", Operator: "not_exists"}
+	accepted := model.Assertion{Type: model.AssertionStatusCode, Value: []interface{}{float64(200), float64(404)}}
+
+	tests := []struct {
+		name       string
+		path       string
+		assertions []model.Assertion
+		success    bool
+		checks     []model.CheckMetrics
+	}{
+		{"passing checks", "/ok", []model.Assertion{noError, {Type: model.AssertionJSONPath, Target: "status", Value: "ok"}}, true,
+			[]model.CheckMetrics{{Name: "no error", Passes: 1, PassRate: 100}, {Name: "jsonpath status ok", Passes: 1, PassRate: 100}}},
+		{"error in a 200 response", "/maintenance", []model.Assertion{noError}, false,
+			[]model.CheckMetrics{{Name: "no error", Fails: 1}}},
+		{"default status still applies", "/missing", []model.Assertion{noError}, false,
+			[]model.CheckMetrics{{Name: "no error", Passes: 1, PassRate: 100}}},
+		{"status set replaces the default", "/missing", []model.Assertion{accepted}, true,
+			[]model.CheckMetrics{{Name: "status_code [200 404]", Passes: 1, PassRate: 100}}},
+		{"body size", "/ok", []model.Assertion{{Name: "small", Type: model.AssertionBodySize, Operator: "lt", Value: float64(10)}}, false,
+			[]model.CheckMetrics{{Name: "small", Fails: 1}}},
+		{"header", "/ok", []model.Assertion{{Name: "json", Type: model.AssertionHeader, Target: "content-type", Operator: "contains", Value: "json"}}, true,
+			[]model.CheckMetrics{{Name: "json", Passes: 1, PassRate: 100}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &model.TestPlan{ID: "test-checks", TargetURL: server.URL + tt.path, Method: "GET", TimeoutMs: 5000, Assertions: tt.assertions}
+			m := model.NewMetrics("run-checks")
+			names := make([]string, len(tt.assertions))
+			for i := range tt.assertions {
+				names[i] = tt.assertions[i].Label()
+			}
+			m.InitChecks(names)
+			worker := NewWorker(1, plan, m, server.Client(), getSharedTestCollector())
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if (m.SuccessRequests == 1) != tt.success {
+				t.Errorf("Expected success %v, got %d successful and %d failed requests", tt.success, m.SuccessRequests, m.FailedRequests)
+			}
+			if !tt.success && len(m.Errors) != 1 {
+				t.Errorf("Expected the failure to be recorded under one error class, got %v", m.Errors)
+			}
+			for i, want := range tt.checks {
+				if m.Checks[i] != want {
+					t.Errorf("Expected check %d to be %+v, got %+v", i, want, m.Checks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWorkerResponseCheckFailuresAreCounted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"error":"maintenance"}`))
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:         "test-check-failures",
+		TargetURL:  server.URL,
+		Method:     "GET",
+		TimeoutMs:  5000,
+		Assertions: []model.Assertion{{Type: model.AssertionBodyContains, Operator: "not_contains", Value: "maintenance"}},
+	}
+	m := model.NewMetrics("run-check-failures")
+	worker := NewWorker(1, plan, m, server.Client(), getSharedTestCollector())
+
+	for i := 0; i < 3; i++ {
+		worker.executeRequest(context.Background(), Arrival{})
+	}
+
+	if m.FailedRequests != 3 || m.CheckFailures != 3 || m.Errors[model.ErrorClassCheckFailed] != 3 {
+		t.Errorf("Expected 3 failed requests and check failures, got %d and %d with errors %v", m.FailedRequests, m.CheckFailures, m.Errors)
+	}
+	want := []string{"response check failed: body_contains not_contains maintenance"}
+	if fmt.Sprint(m.ErrorSamples[model.ErrorClassCheckFailed]) != fmt.Sprint(want) {
+		t.Errorf("Expected the failed check to be sampled once, got %q", m.ErrorSamples)
+	}
+}
@@ -0,0 +1,199 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"go.uber.org/zap"
+)
+
+// Control applies a live control command to the running test. Commands are
+// rejected once the test duration is over.
+func (s *Scheduler) Control(cmd model.ControlCommand) error {
+	s.workersMu.Lock()
+	stopped := s.stopped
+	s.workersMu.Unlock()
+	if stopped || s.loadCtx.Err() != nil {
+		return domain.ErrNotRunning
+	}
+
+	var err error
+	switch cmd.Action {
+	case model.ControlPause:
+		s.pause()
+	case model.ControlResume:
+		s.resume()
+	case model.ControlSetRPS:
+		s.setRPS(cmd.Value)
+	case model.ControlAddVUs:
+		s.addVUs(cmd.Value)
+	case model.ControlRemoveVUs:
+		err = s.removeVUs(cmd.Value)
+	case model.ControlExtendDuration:
+		s.extendDuration(time.Duration(cmd.Value) * time.Second)
+	default:
+		err = domain.NewValidationError("action", fmt.Sprintf("unknown control action: %s", cmd.Action))
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Log.Info("Control command applied",
+		zap.String("plan_id", s.plan.ID),
+		zap.String("action", string(cmd.Action)),
+		zap.Int("value", cmd.Value))
+	return nil
+}
+
+// pause stops the release of new iterations. Iterations queued for the VUs
+// are discarded; those already in flight finish. The load profile's clock
+// keeps running while paused.
+func (s *Scheduler) pause() {
+	s.controlMu.Lock()
+	if s.paused {
+		s.controlMu.Unlock()
+		return
+	}
+	s.paused = true
+	s.resumed = make(chan struct{})
+	s.controlMu.Unlock()
+
+	s.drainArrivals()
+	s.metrics.SetPaused(true)
+}
+
+// resume releases iterations again after a pause
+func (s *Scheduler) resume() {
+	s.controlMu.Lock()
+	if !s.paused {
+		s.controlMu.Unlock()
+		return
+	}
+	s.paused = false
+	close(s.resumed)
+	s.controlMu.Unlock()
+
+	s.metrics.SetPaused(false)
+}
+
+// setRPS replaces the plan's rate pattern with a fixed rate for the rest of
+// the test. The ramp-down still applies. A rate of 0 stops releasing
+// iterations until another rate is set.
+func (s *Scheduler) setRPS(rps int) {
+	rate := float64(rps)
+	s.controlMu.Lock()
+	s.rpsOverride = &rate
+	s.controlMu.Unlock()
+
+	// Unlimited plans keep the queue full; empty it so the new rate applies at once
+	s.drainArrivals()
+}
+
+// addVUs raises the VU pool by n. The closed model starts the VUs at once,
+// the arrival-rate executor raises the pool's limit and grows it on demand.
+// A ramp-up or VU profile still in progress stops at the current pool size.
+func (s *Scheduler) addVUs(n int) {
+	s.workersMu.Lock()
+	s.manualVUs = true
+	if s.executor() == model.ExecutorArrivalRate {
+		s.vuLimit += n
+		s.workersMu.Unlock()
+		return
+	}
+	s.vuLimit = len(s.vus) + n
+	s.workersMu.Unlock()
+
+	s.startWorkers(n)
+}
+
+// removeVUs lowers the VU pool by n, stopping VUs once their current
+// iteration ends. At least one VU is always kept.
+func (s *Scheduler) removeVUs(n int) error {
+	s.workersMu.Lock()
+	current := len(s.vus)
+	if s.executor() == model.ExecutorArrivalRate {
+		current = s.vuLimit
+	}
+	if n >= current {
+		s.workersMu.Unlock()
+		return domain.NewValidationError("value", fmt.Sprintf("cannot remove %d VUs from a pool of %d", n, current))
+	}
+	s.manualVUs = true
+	s.vuLimit = current - n
+	excess := len(s.vus) - s.vuLimit
+	s.workersMu.Unlock()
+
+	if excess > 0 {
+		s.stopWorkers(excess)
+	}
+	return nil
+}
+
+// extendDuration moves the end of the test by d, which may be negative. A
+// test shortened past the current time ends at once.
+func (s *Scheduler) extendDuration(d time.Duration) {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+
+	s.end = s.end.Add(d)
+	remaining := time.Until(s.end)
+	if remaining < 0 {
+		remaining = 0
+	}
+	s.endTimer.Reset(remaining)
+}
+
+// duration returns the current length of the test, including control commands
+func (s *Scheduler) duration() time.Duration {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+	return s.end.Sub(s.started)
+}
+
+// controlState returns whether the test is paused, a channel closed when the
+// pause ends, and the rate set by a control command (nil if none)
+func (s *Scheduler) controlState() (paused bool, resumed <-chan struct{}, rps *float64) {
+	s.controlMu.Lock()
+	defer s.controlMu.Unlock()
+	return s.paused, s.resumed, s.rpsOverride
+}
+
+// rateAt returns the arrival rate to release at elapsed: nothing while
+// paused, otherwise the control command's rate if set or the pattern's rate,
+// scaled by the ramp-down
+func (s *Scheduler) rateAt(pattern func(elapsed time.Duration) float64, elapsed time.Duration) float64 {
+	paused, _, override := s.controlState()
+	if paused {
+		return 0
+	}
+	rps := pattern(elapsed)
+	if override != nil {
+		rps = *override
+	}
+	return rps * s.rampDown(elapsed)
+}
+
+// vusSetManually reports whether a control command has taken over sizing the
+// VU pool from the ramp-up or load profile
+func (s *Scheduler) vusSetManually() bool {
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
+	return s.manualVUs
+}
+
+// drainArrivals discards arrivals queued for the VUs but not yet picked up
+func (s *Scheduler) drainArrivals() {
+	for {
+		select {
+		case _, ok := <-s.requestChan:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
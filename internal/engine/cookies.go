@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
+	"go.uber.org/zap"
+)
+
+// newCookieJar creates an empty cookie jar. Without a public suffix list
+// the jar still keeps cookies per host, which is all a single VU needs.
+func newCookieJar() http.CookieJar {
+	jar, _ := cookiejar.New(nil) // Only fails for a broken public suffix list
+	return jar
+}
+
+// withCookieJar returns a copy of client with an empty cookie jar of its own.
+// The copy shares the client's transport and so its connection pool.
+func withCookieJar(client *http.Client) *http.Client {
+	return &http.Client{
+		Transport:     client.Transport,
+		CheckRedirect: client.CheckRedirect,
+		Timeout:       client.Timeout,
+		Jar:           newCookieJar(),
+	}
+}
+
+// cookiesEnabled reports whether the plan's VUs keep cookie jars
+func cookiesEnabled(plan *model.TestPlan) bool {
+	return plan.Cookies == nil || !plan.Cookies.Disabled
+}
+
+// prepareCookieJar seeds the VU's jar before its first iteration, and
+// empties and re-seeds it before every iteration when the plan resets it.
+// substitute resolves the variables of the current iteration.
+func (w *Worker) prepareCookieJar(substitute func(string) string) {
+	if w.client.Jar == nil {
+		return
+	}
+	cfg := w.plan.Cookies
+	if w.jarReady {
+		if cfg == nil || !cfg.ResetPerIteration {
+			return
+		}
+		// The step runner shares the client, so it sees the new jar too
+		w.client.Jar = newCookieJar()
+	}
+	w.jarReady = true
+	if cfg == nil {
+		return
+	}
+
+	for _, seed := range cfg.Seed {
+		rawURL := seed.URL
+		if rawURL == "" {
+			rawURL = w.defaultCookieURL()
+		}
+		u, err := url.Parse(substitute(rawURL))
+		if err != nil || u.Host == "" {
+			logger.Log.Debug("Cannot seed cookie without a valid URL",
+				zap.Int("worker_id", w.ID),
+				zap.String("cookie", seed.Name))
+			continue
+		}
+		w.client.Jar.SetCookies(u, []*http.Cookie{{
+			Name:  substitute(seed.Name),
+			Value: substitute(seed.Value),
+			Path:  "/",
+		}})
+	}
+}
+
+// defaultCookieURL returns the URL seed cookies are set for when they name
+// none: the plan's target, its first request or its scenario's first step
+func (w *Worker) defaultCookieURL() string {
+	switch {
+	case w.plan.TargetURL != "":
+		return w.plan.TargetURL
+	case len(w.plan.Requests) > 0:
+		return w.plan.Requests[0].URL
+	case w.plan.Scenario != nil && len(w.plan.Scenario.Steps) > 0:
+		return w.plan.Scenario.Steps[0].URL
+	}
+	return ""
+}
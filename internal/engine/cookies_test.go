@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// sessionServer hands out a new session cookie to every request without one
+// and records the session each request carried
+type sessionServer struct {
+	mu       sync.Mutex
+	sessions int
+	seen     []string
+}
+
+func (s *sessionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cookie, err := r.Cookie("session")
+	if err != nil {
+		s.sessions++
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: fmt.Sprintf("s%d", s.sessions), Path: "/"})
+		s.seen = append(s.seen, "")
+		return
+	}
+	s.seen = append(s.seen, cookie.Value)
+}
+
+func TestWorkerCookieJars(t *testing.T) {
+	tests := []struct {
+		name     string
+		cookies  *model.CookieConfig
+		sessions int      // Sessions the server handed out
+		seen     []string // Sessions the requests carried, first VU then second
+	}{
+		{"kept per VU", nil, 2, []string{"", "s1", "s1", "", "s2", "s2"}},
+		{"reset per iteration", &model.CookieConfig{ResetPerIteration: true}, 6, []string{"", "", "", "", "", ""}},
+		{"disabled", &model.CookieConfig{Disabled: true}, 6, []string{"", "", "", "", "", ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &sessionServer{}
+			ts := httptest.NewServer(server)
+			defer ts.Close()
+
+			plan := &model.TestPlan{ID: "test-cookies", TargetURL: ts.URL, Method: "GET", TimeoutMs: 5000, Cookies: tt.cookies}
+			for id := 1; id <= 2; id++ {
+				worker := NewWorker(id, plan, model.NewMetrics("run-cookies"), ts.Client(), getSharedTestCollector())
+				for i := 0; i < 3; i++ {
+					worker.executeRequest(context.Background(), Arrival{})
+				}
+			}
+
+			if server.sessions != tt.sessions {
+				t.Errorf("Expected %d sessions, got %d", tt.sessions, server.sessions)
+			}
+			if fmt.Sprint(server.seen) != fmt.Sprint(tt.seen) {
+				t.Errorf("Expected requests to carry sessions %q, got %q", tt.seen, server.seen)
+			}
+		})
+	}
+}
+
+func TestWorkerSeedsCookies(t *testing.T) {
+	server := &sessionServer{}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	plan := &model.TestPlan{
+		ID:        "test-seed",
+		TargetURL: ts.URL,
+		Method:    "GET",
+		TimeoutMs: 5000,
+		Cookies: &model.CookieConfig{
+			ResetPerIteration: true,
+			Seed:              []model.SeedCookie{{Name: "session", Value: "{{data.user}}"}},
+		},
+	}
+	worker := NewWorker(1, plan, model.NewMetrics("run-seed"), ts.Client(), getSharedTestCollector())
+
+	for _, user := range []string{"alice", "bob"} {
+		worker.row = map[string]string{"user": user}
+		worker.executeRequest(context.Background(), Arrival{})
+	}
+
+	if server.sessions != 0 || fmt.Sprint(server.seen) != "[alice bob]" {
+		t.Errorf("Expected the seeded cookies to be sent, got %d new sessions and %q", server.sessions, server.seen)
+	}
+}
+
+func TestScenarioExecutorKeepsCookiesBetweenSteps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+			return
+		}
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	scenario := &model.Scenario{
+		ID: "scenario-cookies",
+		Steps: []model.Step{
+			{Name: "login", Method: "POST", URL: server.URL + "/login"},
+			{Name: "profile", Method: "GET", URL: server.URL + "/profile",
+				Assertions: []model.Assertion{{Type: model.AssertionStatusCode, Value: float64(200)}}},
+		},
+	}
+
+	executor := NewScenarioExecutor()
+	execution, err := executor.Execute(scenario, nil)
+	if err != nil {
+		t.Fatalf("Expected the session cookie to be sent by the second step, got %v", err)
+	}
+	if execution.Status != model.StatusCompleted {
+		t.Errorf("Expected the execution to complete, got %s", execution.Status)
+	}
+
+	// Executions do not share cookies
+	scenario.Steps = scenario.Steps[1:]
+	if _, err := executor.Execute(scenario, nil); err == nil {
+		t.Error("Expected a new execution to start without cookies")
+	}
+}
@@ -0,0 +1,271 @@
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	dnsMethodLabel    = "DNS"            // Method DNS queries are counted under in Prometheus
+	dnsDefaultPort    = "53"             // Port of resolvers given without one
+	dnsDefaultTimeout = 30 * time.Second // Query timeout of plans without one
+	dnsMaxMessageSize = 65535
+)
+
+// dnsTypeCodes are the wire codes of the record types a query may ask for
+var dnsTypeCodes = map[model.DNSRecordType]dnsmessage.Type{
+	model.DNSTypeA:    dnsmessage.TypeA,
+	model.DNSTypeAAAA: dnsmessage.TypeAAAA,
+	model.DNSTypeSRV:  dnsmessage.TypeSRV,
+	model.DNSTypeTXT:  dnsmessage.TypeTXT,
+}
+
+// dnsRcodeNames are the names of the response codes a DNS header can carry
+var dnsRcodeNames = []string{
+	"NOERROR", "FORMERR", "SERVFAIL", "NXDOMAIN", "NOTIMP", "REFUSED",
+	"YXDOMAIN", "YXRRSET", "NXRRSET", "NOTAUTH", "NOTZONE",
+}
+
+// errDNSMalformed fails a query whose answer could not be parsed
+var errDNSMalformed = errors.New("malformed dns message")
+
+// dnsRcodeName returns the name of a response code, e.g. NXDOMAIN
+func dnsRcodeName(rcode int) string {
+	if rcode >= 0 && rcode < len(dnsRcodeNames) {
+		return dnsRcodeNames[rcode]
+	}
+	return fmt.Sprintf("RCODE%d", rcode)
+}
+
+// dnsRcodeError returns the error a query answered with a response code
+// other than NOERROR is recorded with
+func dnsRcodeError(code, name string) error {
+	return fmt.Errorf("%w %s: %s", model.ErrDNSRcode, code, name)
+}
+
+// dnsServerAddress returns the address of a resolver, adding port 53 to a
+// bare host
+func dnsServerAddress(server string) string {
+	if _, _, err := net.SplitHostPort(server); err == nil {
+		return server
+	}
+	return net.JoinHostPort(strings.Trim(server, "[]"), dnsDefaultPort)
+}
+
+// dnsAnswer is a resolver's answer to one query
+type dnsAnswer struct {
+	rcode     int
+	truncated bool     // The answer did not fit in a UDP datagram
+	records   []string // Answer records in zone file form, e.g. "www.example.com. 300 A 10.0.0.1"
+}
+
+// response returns the answer as an HTTP response, so the checks of HTTP
+// requests apply to it. The status code is the response code.
+func (a *dnsAnswer) response() *http.Response {
+	return &http.Response{StatusCode: a.rcode, Header: http.Header{}}
+}
+
+// body returns the answer records one per line, which body checks see
+func (a *dnsAnswer) body() []byte {
+	return []byte(strings.Join(a.records, "\n"))
+}
+
+// queryDNS asks server for the records of name over req's network and
+// waits for the answer until timeout
+func queryDNS(ctx context.Context, req *model.DNSRequest, server, name string, timeout time.Duration) (*dnsAnswer, error) {
+	qtype := dnsTypeCodes[req.Type]
+	if req.Type == "" {
+		qtype = dnsmessage.TypeA
+	}
+	id := uint16(rand.UintN(1 << 16))
+	query, err := buildDNSQuery(id, name, qtype, !req.NoRecursion)
+	if err != nil {
+		return nil, err
+	}
+	if timeout <= 0 {
+		timeout = dnsDefaultTimeout
+	}
+
+	network := string(model.SocketUDP)
+	if req.Network == model.SocketTCP {
+		network = string(model.SocketTCP)
+	}
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, network, dnsServerAddress(server))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// A run that stops interrupts the query through the deadline
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	var answer *dnsAnswer
+	if network == string(model.SocketTCP) {
+		answer, err = exchangeDNSStream(conn, query, id)
+	} else {
+		answer, err = exchangeDNSDatagram(conn, query, id)
+	}
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return answer, err
+}
+
+// exchangeDNSDatagram sends a query over UDP and reads datagrams until the
+// answer to it arrives, ignoring answers to other queries
+func exchangeDNSDatagram(conn net.Conn, query []byte, id uint16) (*dnsAnswer, error) {
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, dnsMaxMessageSize)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		var p dnsmessage.Parser
+		h, err := p.Start(buf[:n])
+		if err != nil || h.ID != id || !h.Response {
+			continue
+		}
+		return parseDNSAnswer(&p, h)
+	}
+}
+
+// exchangeDNSStream sends a query over TCP, where messages are prefixed
+// with their length, and reads the answer
+func exchangeDNSStream(conn net.Conn, query []byte, id uint16) (*dnsAnswer, error) {
+	msg := binary.BigEndian.AppendUint16(make([]byte, 0, 2+len(query)), uint16(len(query)))
+	if _, err := conn.Write(append(msg, query...)); err != nil {
+		return nil, err
+	}
+	var size [2]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(size[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+	var p dnsmessage.Parser
+	h, err := p.Start(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errDNSMalformed, err)
+	}
+	if h.ID != id {
+		return nil, fmt.Errorf("%w: answer does not match the query", errDNSMalformed)
+	}
+	return parseDNSAnswer(&p, h)
+}
+
+// buildDNSQuery encodes a query for the records of type qtype of name
+func buildDNSQuery(id uint16, name string, qtype dnsmessage.Type, recursion bool) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+	if len(name) > 253 {
+		return nil, fmt.Errorf("dns name %q is longer than 253 characters", name)
+	}
+	qname, err := dnsmessage.NewName(name + ".")
+	if err != nil {
+		return nil, fmt.Errorf("invalid dns name %q: %w", name, err)
+	}
+
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), dnsmessage.Header{ID: id, RecursionDesired: recursion})
+	if err := b.StartQuestions(); err != nil {
+		return nil, err
+	}
+	if err := b.Question(dnsmessage.Question{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, fmt.Errorf("invalid dns name %q: %w", name, err)
+	}
+	return b.Finish()
+}
+
+// parseDNSAnswer reads the answer records of a DNS message whose header p
+// has parsed
+func parseDNSAnswer(p *dnsmessage.Parser, h dnsmessage.Header) (*dnsAnswer, error) {
+	answer := &dnsAnswer{rcode: int(h.RCode), truncated: h.Truncated}
+
+	// A truncated answer keeps the records that fit
+	fail := func(err error) (*dnsAnswer, error) {
+		if answer.truncated {
+			return answer, nil
+		}
+		return nil, fmt.Errorf("%w: %w", errDNSMalformed, err)
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return fail(err)
+	}
+	for {
+		rh, err := p.AnswerHeader()
+		if errors.Is(err, dnsmessage.ErrSectionDone) {
+			return answer, nil
+		}
+		if err != nil {
+			return fail(err)
+		}
+		kind, data, err := formatDNSRecord(p, rh.Type)
+		if err != nil {
+			return fail(err)
+		}
+		answer.records = append(answer.records, fmt.Sprintf("%s %d %s %s", rh.Name, rh.TTL, kind, data))
+	}
+}
+
+// formatDNSRecord reads the data of the answer record of type rtype p is
+// at, returning the record's type and data in zone file form
+func formatDNSRecord(p *dnsmessage.Parser, rtype dnsmessage.Type) (string, string, error) {
+	switch rtype {
+	case dnsmessage.TypeA:
+		r, err := p.AResource()
+		if err != nil {
+			return "", "", err
+		}
+		return string(model.DNSTypeA), net.IP(r.A[:]).String(), nil
+	case dnsmessage.TypeAAAA:
+		r, err := p.AAAAResource()
+		if err != nil {
+			return "", "", err
+		}
+		return string(model.DNSTypeAAAA), net.IP(r.AAAA[:]).String(), nil
+	case dnsmessage.TypeSRV:
+		r, err := p.SRVResource()
+		if err != nil {
+			return "", "", err
+		}
+		return string(model.DNSTypeSRV), fmt.Sprintf("%d %d %d %s", r.Priority, r.Weight, r.Port, r.Target), nil
+	case dnsmessage.TypeTXT:
+		r, err := p.TXTResource()
+		if err != nil {
+			return "", "", err
+		}
+		parts := make([]string, len(r.TXT))
+		for i, txt := range r.TXT {
+			parts[i] = fmt.Sprintf("%q", txt)
+		}
+		return string(model.DNSTypeTXT), strings.Join(parts, " "), nil
+	case dnsmessage.TypeCNAME:
+		r, err := p.CNAMEResource()
+		if err != nil {
+			return "", "", err
+		}
+		return "CNAME", r.CNAME.String(), nil
+	}
+	r, err := p.UnknownResource()
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("TYPE%d", rtype), fmt.Sprintf("\\# %d %x", len(r.Data), r.Data), nil
+}
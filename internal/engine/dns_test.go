@@ -0,0 +1,241 @@
+package engine
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// dnsTestReply answers a query the way startDNSServer's zone does:
+// missing.example.test is NXDOMAIN, broken.example.test is SERVFAIL,
+// big.example.test is truncated, and any other name has an A, AAAA, SRV
+// and TXT record
+func dnsTestReply(query []byte) []byte {
+	var p dnsmessage.Parser
+	h, err := p.Start(query)
+	if err != nil {
+		return nil
+	}
+	q, err := p.Question()
+	if err != nil {
+		return nil
+	}
+
+	reply := dnsmessage.Header{ID: h.ID, Response: true, RecursionDesired: h.RecursionDesired, RecursionAvailable: true}
+	switch q.Name.String() {
+	case "missing.example.test.":
+		reply.RCode = dnsmessage.RCodeNameError
+	case "broken.example.test.":
+		reply.RCode = dnsmessage.RCodeServerFailure
+	case "big.example.test.":
+		reply.Truncated = true
+	}
+
+	b := dnsmessage.NewBuilder(make([]byte, 0, 512), reply)
+	b.EnableCompression()
+	if b.StartQuestions() != nil || b.Question(q) != nil || b.StartAnswers() != nil {
+		return nil
+	}
+	if reply.RCode == dnsmessage.RCodeSuccess && !reply.Truncated {
+		rh := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 300}
+		switch q.Type {
+		case dnsmessage.TypeA:
+			err = b.AResource(rh, dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}})
+		case dnsmessage.TypeAAAA:
+			var aaaa dnsmessage.AAAAResource
+			copy(aaaa.AAAA[:], net.ParseIP("fd00::1").To16())
+			err = b.AAAAResource(rh, aaaa)
+		case dnsmessage.TypeSRV:
+			// The question's name is the target
+			err = b.SRVResource(rh, dnsmessage.SRVResource{Priority: 10, Weight: 5, Port: 8080, Target: q.Name})
+		case dnsmessage.TypeTXT:
+			err = b.TXTResource(rh, dnsmessage.TXTResource{TXT: []string{"v=1", "hello"}})
+		}
+		if err != nil {
+			return nil
+		}
+	}
+	msg, err := b.Finish()
+	if err != nil {
+		return nil
+	}
+	return msg
+}
+
+// startDNSServer serves dnsTestReply's zone over UDP and TCP on the same
+// port. Over UDP, every answer is preceded by an answer to another query.
+func startDNSServer(t *testing.T) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+	ln, err := net.Listen("tcp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		buf := make([]byte, dnsMaxMessageSize)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			reply := dnsTestReply(buf[:n])
+			stale := append([]byte(nil), reply...)
+			stale[0] ^= 0xff
+			_, _ = pc.WriteTo(stale, addr)
+			_, _ = pc.WriteTo(reply, addr)
+		}
+	}()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var size [2]byte
+				if _, err := io.ReadFull(conn, size[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(size[:]))
+				if _, err := io.ReadFull(conn, query); err != nil {
+					return
+				}
+				reply := dnsTestReply(query)
+				_, _ = conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(reply))), reply...))
+			}()
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func TestWorkerDNS(t *testing.T) {
+	server := startDNSServer(t)
+
+	tests := []struct {
+		name       string
+		dns        model.DNSRequest
+		assertions []model.Assertion
+		success    bool
+		errorClass string
+		rcode      string
+		answers    int64
+		truncated  int64
+	}{
+		{"a over udp", model.DNSRequest{Name: "www.example.test"}, []model.Assertion{{Type: model.AssertionBodyContains, Value: "www.example.test. 300 A 10.0.0.1"}}, true, "", "NOERROR", 1, 0},
+		{"aaaa over tcp", model.DNSRequest{Name: "www.example.test", Type: model.DNSTypeAAAA, Network: model.SocketTCP}, []model.Assertion{{Type: model.AssertionBodyContains, Value: "AAAA fd00::1"}}, true, "", "NOERROR", 1, 0},
+		{"srv", model.DNSRequest{Name: "_http._tcp.example.test.", Type: model.DNSTypeSRV}, []model.Assertion{{Type: model.AssertionBodyContains, Value: "SRV 10 5 8080 _http._tcp.example.test."}}, true, "", "NOERROR", 1, 0},
+		{"txt", model.DNSRequest{Name: "www.example.test", Type: model.DNSTypeTXT}, []model.Assertion{{Type: model.AssertionBodyContains, Value: `TXT "v=1" "hello"`}}, true, "", "NOERROR", 1, 0},
+		{"templated name", model.DNSRequest{Name: "{{random_string:12}}.example.test"}, nil, true, "", "NOERROR", 1, 0},
+		{"nxdomain", model.DNSRequest{Name: "missing.example.test"}, nil, false, model.ErrorClassDNSRcode, "NXDOMAIN", 0, 0},
+		{"servfail over tcp", model.DNSRequest{Name: "broken.example.test", Network: model.SocketTCP}, nil, false, model.ErrorClassDNSRcode, "SERVFAIL", 0, 0},
+		{"status check accepts nxdomain", model.DNSRequest{Name: "missing.example.test"}, []model.Assertion{{Type: model.AssertionStatusCode, Value: float64(3)}}, true, "", "NXDOMAIN", 0, 0},
+		{"failed check", model.DNSRequest{Name: "www.example.test"}, []model.Assertion{{Type: model.AssertionBodyContains, Value: "10.0.0.2"}}, false, model.ErrorClassCheckFailed, "NOERROR", 1, 0},
+		{"truncated", model.DNSRequest{Name: "big.example.test"}, nil, true, "", "NOERROR", 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.dns.Server = server
+			plan := &model.TestPlan{ID: "test-dns", TimeoutMs: 2000, DNS: &tt.dns, Assertions: tt.assertions}
+			m := model.NewMetrics("run-dns")
+			m.InitDNS()
+			if len(tt.assertions) > 0 {
+				m.InitChecks([]string{tt.assertions[0].Label()})
+			}
+			worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if (m.SuccessRequests == 1) != tt.success {
+				t.Errorf("Expected success %v, got %d successful and %d failed requests (errors %v)", tt.success, m.SuccessRequests, m.FailedRequests, m.ErrorSamples)
+			}
+			if tt.errorClass != "" && m.Errors[tt.errorClass] != 1 {
+				t.Errorf("Expected the failure to be counted as %s, got %v", tt.errorClass, m.Errors)
+			}
+			d := m.DNS
+			if d.Queries != 1 || d.ResponseCodes[tt.rcode] != 1 || d.Answers != tt.answers || d.Truncated != tt.truncated {
+				t.Errorf("Expected one %s answer with %d records and %d truncated, got %+v", tt.rcode, tt.answers, tt.truncated, d)
+			}
+		})
+	}
+}
+
+func TestWorkerDNSUnreachable(t *testing.T) {
+	// Nothing answers on a closed TCP port
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	plan := &model.TestPlan{ID: "test-dns-unreachable", TimeoutMs: 2000, DNS: &model.DNSRequest{
+		Server: addr, Name: "www.example.test", Network: model.SocketTCP,
+	}}
+	m := model.NewMetrics("run-dns-unreachable")
+	m.InitDNS()
+	worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+
+	worker.executeRequest(context.Background(), Arrival{})
+
+	if m.FailedRequests != 1 || m.Errors[model.ErrorClassConnectionRefused] != 1 {
+		t.Errorf("Expected a refused connection, got %d failed requests and errors %v", m.FailedRequests, m.Errors)
+	}
+	if m.DNS.Queries != 1 || len(m.DNS.ResponseCodes) != 0 {
+		t.Errorf("Expected an unanswered query, got %+v", m.DNS)
+	}
+}
+
+func TestBuildDNSQuery(t *testing.T) {
+	query, err := buildDNSQuery(0xabcd, "www.example.test.", dnsmessage.TypeSRV, true)
+	if err != nil {
+		t.Fatalf("Failed to build query: %v", err)
+	}
+	var p dnsmessage.Parser
+	h, err := p.Start(query)
+	if err != nil {
+		t.Fatalf("Failed to parse query: %v", err)
+	}
+	q, err := p.Question()
+	if err != nil || q.Name.String() != "www.example.test." {
+		t.Fatalf("Expected the query to ask for www.example.test., got %q (%v)", q.Name, err)
+	}
+	if h.ID != 0xabcd || !h.RecursionDesired || q.Type != dnsmessage.TypeSRV {
+		t.Errorf("Expected ID abcd, recursion desired and type SRV, got %+v %+v", h, q)
+	}
+
+	for _, name := range []string{"a..example.test", strings.Repeat("a", 64) + ".example.test"} {
+		if _, err := buildDNSQuery(1, name, dnsmessage.TypeA, true); err == nil {
+			t.Errorf("Expected %q to be rejected", name)
+		}
+	}
+}
+
+func TestDNSServerAddress(t *testing.T) {
+	tests := map[string]string{
+		"10.0.0.53":       "10.0.0.53:53",
+		"10.0.0.53:5353":  "10.0.0.53:5353",
+		"resolver.local":  "resolver.local:53",
+		"[fd00::53]:5353": "[fd00::53]:5353",
+		"fd00::53":        "[fd00::53]:53",
+	}
+	for server, want := range tests {
+		if got := dnsServerAddress(server); got != want {
+			t.Errorf("%s: expected %s, got %s", server, want, got)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package engine
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+func TestWorkerErrorClasses(t *testing.T) {
+	// hijack takes over the connection before any response is written
+	hijack := func(close func(net.Conn)) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			close(conn)
+		}
+	}
+
+	server := httptest.NewServer(http.NewServeMux())
+	mux := server.Config.Handler.(*http.ServeMux)
+	mux.HandleFunc("/unavailable", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	})
+	mux.HandleFunc("/eof", hijack(func(conn net.Conn) { _ = conn.Close() }))
+	mux.HandleFunc("/reset", hijack(func(conn net.Conn) {
+		_ = conn.(*net.TCPConn).SetLinger(0) // Close with a RST
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	// A port nothing listens on
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	closedURL := "http://" + listener.Addr().String()
+	_ = listener.Close()
+
+	tests := []struct {
+		name   string
+		url    string
+		class  string
+		sample string // Expected sample, if it is stable
+	}{
+		{"status class", server.URL + "/unavailable", model.ErrorClassHTTP5xx, "503 Service Unavailable"},
+		{"timeout", server.URL + "/slow", model.ErrorClassTimeout, ""},
+		{"connection closed", server.URL + "/eof", model.ErrorClassEOF, ""},
+		{"connection reset", server.URL + "/reset", model.ErrorClassConnectionReset, ""},
+		{"connection refused", closedURL, model.ErrorClassConnectionRefused, ""},
+		{"dns", "http://volcanion-test.invalid", model.ErrorClassDNS, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &model.TestPlan{ID: "test-errors", TargetURL: tt.url, Method: "GET", TimeoutMs: 100}
+			m := model.NewMetrics("run-errors")
+			client := &http.Client{Timeout: 100 * time.Millisecond}
+			worker := NewWorker(1, plan, m, client, getSharedTestCollector())
+
+			for i := 0; i < 2; i++ {
+				worker.executeRequest(context.Background(), Arrival{})
+			}
+
+			if m.Errors[tt.class] != 2 || len(m.Errors) != 1 {
+				t.Fatalf("Expected 2 errors of class %s, got %v (samples %q)", tt.class, m.Errors, m.ErrorSamples)
+			}
+			samples := m.ErrorSamples[tt.class]
+			if len(samples) == 0 || tt.sample != "" && samples[0] != tt.sample {
+				t.Errorf("Expected sample %q, got %q", tt.sample, samples)
+			}
+		})
+	}
+}
+
+func TestMetricsKeepsBoundedErrorSamples(t *testing.T) {
+	m := model.NewMetrics("run-samples")
+	for port := 0; port < 100; port++ {
+		err := &net.OpError{Op: "dial", Net: "tcp", Addr: &net.TCPAddr{Port: 10000 + port}, Err: context.DeadlineExceeded}
+		m.RecordRequest(false, 1, 0, err)
+	}
+
+	if len(m.Errors) != 1 || m.Errors[model.ErrorClassTimeout] != 100 {
+		t.Errorf("Expected every error under the timeout class, got %v", m.Errors)
+	}
+	if n := len(m.ErrorSamples[model.ErrorClassTimeout]); n != model.MaxErrorSamples {
+		t.Errorf("Expected %d samples, got %d", model.MaxErrorSamples, n)
+	}
+}
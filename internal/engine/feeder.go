@@ -0,0 +1,70 @@
+package engine
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// Feeder hands out data set rows to virtual users according to a feed strategy
+type Feeder struct {
+	rows     []map[string]string
+	strategy model.FeedStrategy
+	recycle  bool  // once strategy: start over instead of stopping when rows run out
+	next     int64 // Next row index for the shared sequential and once strategies
+
+	random *rand.Rand
+	mu     sync.Mutex // Protects random
+}
+
+// NewFeeder creates a feeder over the rows of a data set
+func NewFeeder(set *model.DataSet, feed *model.DataFeed) *Feeder {
+	f := &Feeder{
+		rows:     set.Rows,
+		strategy: model.FeedSequential,
+		// Not security-sensitive randomness; math/rand is acceptable here.
+		random: rand.New(rand.NewSource(time.Now().UnixNano())), //nolint:gosec
+	}
+	if feed != nil {
+		if feed.Strategy != "" {
+			f.strategy = feed.Strategy
+		}
+		f.recycle = feed.OnExhausted == model.FeedExhaustedRecycle
+	}
+	return f
+}
+
+// Next returns the row a VU should use for its next iteration. It returns
+// false once a once-strategy feeder without recycling has used every row,
+// or if the data set is empty.
+func (f *Feeder) Next(vuID int) (map[string]string, bool) {
+	n := int64(len(f.rows))
+	if n == 0 {
+		return nil, false
+	}
+
+	switch f.strategy {
+	case model.FeedRandom:
+		f.mu.Lock()
+		i := f.random.Int63n(n)
+		f.mu.Unlock()
+		return f.rows[i], true
+
+	case model.FeedUnique:
+		return f.rows[int64(vuID)%n], true
+
+	case model.FeedOnce:
+		i := atomic.AddInt64(&f.next, 1) - 1
+		if i >= n && !f.recycle {
+			return nil, false
+		}
+		return f.rows[i%n], true
+
+	default:
+		i := atomic.AddInt64(&f.next, 1) - 1
+		return f.rows[i%n], true
+	}
+}
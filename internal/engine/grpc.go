@@ -0,0 +1,435 @@
+package engine
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/linker"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"golang.org/x/sync/singleflight"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// CompileProtos compiles the proto files at paths, resolving their imports
+// from sources and the well-known types
+func CompileProtos(ctx context.Context, sources model.ProtoSources, paths ...string) (linker.Files, error) {
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	return compiler.Compile(ctx, paths...)
+}
+
+// FindGRPCMethod looks up a method, named package.Service/Method, in
+// compiled proto files
+func FindGRPCMethod(files linker.Files, name string) (protoreflect.MethodDescriptor, error) {
+	service, method, err := splitGRPCMethod(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if sd, ok := file.FindDescriptorByName(protoreflect.FullName(service)).(protoreflect.ServiceDescriptor); ok {
+			return findMethod(sd, method)
+		}
+	}
+	return nil, fmt.Errorf("service %s not found", service)
+}
+
+// splitGRPCMethod splits a full method name into its service and method
+func splitGRPCMethod(name string) (service, method string, err error) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(name, "/"), "/")
+	if !ok || service == "" || method == "" || strings.Contains(method, "/") {
+		return "", "", fmt.Errorf("invalid method %q (must be package.Service/Method)", name)
+	}
+	return service, method, nil
+}
+
+// findMethod returns a method of a service that the engine can call
+func findMethod(sd protoreflect.ServiceDescriptor, name string) (protoreflect.MethodDescriptor, error) {
+	md := sd.Methods().ByName(protoreflect.Name(name))
+	if md == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", name, sd.FullName())
+	}
+	if md.IsStreamingClient() {
+		return nil, fmt.Errorf("method %s is client streaming, only unary and server streaming methods are supported", name)
+	}
+	return md, nil
+}
+
+// grpcMethodLabel is the method gRPC calls are counted under in Prometheus,
+// where their status label is the status code name
+const grpcMethodLabel = "GRPC"
+
+// errNoGRPCClient fails the calls of a worker the scheduler gave no client
+var errNoGRPCClient = errors.New("gRPC client not set")
+
+// withCallTimeout bounds a call by timeoutMs, or by fallback when it is not
+// set; a zero fallback leaves the call unbounded
+func withCallTimeout(ctx context.Context, timeoutMs int, fallback time.Duration) (context.Context, context.CancelFunc) {
+	timeout := fallback
+	if timeoutMs > 0 {
+		timeout = time.Duration(timeoutMs) * time.Millisecond
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// grpcClient sends the gRPC calls of a run. Connections are opened once per
+// target and shared by every VU, since one HTTP/2 connection multiplexes
+// their calls. Methods are looked up in the run's proto files, or through
+// the server's reflection service when a call names no proto files.
+type grpcClient struct {
+	protos    model.ProtoSources
+	tlsConfig *tls.Config // Plan TLS settings for connections without plaintext
+
+	mu      sync.Mutex
+	files   linker.Files // Compiled from protos on first use
+	conns   map[string]*grpc.ClientConn
+	methods map[string]protoreflect.MethodDescriptor
+	lookups singleflight.Group // Compiles protos and resolves methods once for the VUs waiting on them, outside mu
+}
+
+// grpcProtosKey is the lookup compiling the proto files; method keys always
+// contain a "/"
+const grpcProtosKey = "protos"
+
+// newGRPCClient creates a client for calls described by protos
+func newGRPCClient(protos model.ProtoSources, tlsConfig *tls.Config) *grpcClient {
+	return &grpcClient{
+		protos:    protos,
+		tlsConfig: tlsConfig,
+		conns:     make(map[string]*grpc.ClientConn),
+		methods:   make(map[string]protoreflect.MethodDescriptor),
+	}
+}
+
+// grpcResponse is the outcome of one gRPC call
+type grpcResponse struct {
+	code     codes.Code
+	message  string      // Status message of a failed call
+	body     []byte      // Response message as JSON, a JSON array of them for server streaming calls
+	header   metadata.MD // Response header metadata
+	messages int         // Response messages received
+}
+
+// response returns the call as an HTTP response, so the checks and
+// extractions of HTTP requests apply to it. The status code is the gRPC
+// code and the headers are the response metadata.
+func (r *grpcResponse) response() *http.Response {
+	header := make(http.Header, len(r.header))
+	for k, v := range r.header {
+		header[http.CanonicalHeaderKey(k)] = v
+	}
+	return &http.Response{StatusCode: int(r.code), Header: header}
+}
+
+// err returns the error of a call that timed out or was canceled. Like an
+// HTTP request that timed out, such a call has no response.
+func (r *grpcResponse) err() error {
+	switch r.code {
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("%w: %s", context.DeadlineExceeded, r.message)
+	case codes.Canceled:
+		return fmt.Errorf("%w: %s", context.Canceled, r.message)
+	}
+	return nil
+}
+
+// grpcStatusError returns the error a call answered with a non-OK status is
+// recorded with
+func grpcStatusError(code, message string) error {
+	return fmt.Errorf("%w %s: %s", model.ErrGRPCStatus, code, message)
+}
+
+// grpcCodeName returns the canonical name of a status code, e.g. NOT_FOUND
+func grpcCodeName(code codes.Code) string {
+	var name strings.Builder
+	prev := rune(0)
+	for _, r := range code.String() {
+		if unicode.IsUpper(r) && unicode.IsLower(prev) {
+			name.WriteByte('_')
+		}
+		name.WriteRune(unicode.ToUpper(r))
+		prev = r
+	}
+	return name.String()
+}
+
+// call sends a request message, given as JSON, and reads the response. An
+// error means the call could not be made at all, e.g. the method is
+// unknown or the message does not match its input type; a call the server
+// answers with a non-OK status is a response with that code.
+func (c *grpcClient) call(ctx context.Context, req *model.GRPCRequest, message string, md map[string]string) (*grpcResponse, error) {
+	conn, err := c.conn(req.Target, req.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	method, err := c.method(ctx, conn, req)
+	if err != nil {
+		return nil, err
+	}
+
+	in := dynamicpb.NewMessage(method.Input())
+	if strings.TrimSpace(message) != "" {
+		if err := protojson.Unmarshal([]byte(message), in); err != nil {
+			return nil, fmt.Errorf("invalid request message for %s: %w", method.Input().FullName(), err)
+		}
+	}
+	if len(md) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(md))
+	}
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+
+	resp := &grpcResponse{}
+	if !method.IsStreamingServer() {
+		out := dynamicpb.NewMessage(method.Output())
+		err = conn.Invoke(ctx, fullMethod, in, out, grpc.Header(&resp.header))
+		if err == nil {
+			resp.messages = 1
+			resp.body, err = protojson.Marshal(out)
+		}
+	} else {
+		err = c.stream(ctx, conn, fullMethod, method, in, resp)
+	}
+
+	st := status.Convert(err)
+	resp.code, resp.message = st.Code(), st.Message()
+	return resp, nil
+}
+
+// stream sends a server streaming call and reads every response message
+func (c *grpcClient) stream(ctx context.Context, conn *grpc.ClientConn, fullMethod string, method protoreflect.MethodDescriptor, in proto.Message, resp *grpcResponse) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fullMethod)
+	if err != nil {
+		return err
+	}
+	if err := stream.SendMsg(in); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	messages := []json.RawMessage{}
+	for {
+		out := dynamicpb.NewMessage(method.Output())
+		if err := stream.RecvMsg(out); err != nil {
+			resp.header, _ = stream.Header()
+			resp.body, _ = json.Marshal(messages)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		data, err := protojson.Marshal(out)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, data)
+		resp.messages++
+	}
+}
+
+// conn returns the connection to a target, opening it on first use
+func (c *grpcClient) conn(target string, plaintext bool) (*grpc.ClientConn, error) {
+	key := fmt.Sprintf("%s|%t", target, plaintext)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if conn, ok := c.conns[key]; ok {
+		return conn, nil
+	}
+
+	creds := insecure.NewCredentials()
+	if !plaintext {
+		tlsConfig := c.tlsConfig
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+	c.conns[key] = conn
+	return conn, nil
+}
+
+// method returns the descriptor of the method a request calls. The first
+// call resolves it, through server reflection or the compiled proto files;
+// concurrent calls for the same method wait for that lookup.
+func (c *grpcClient) method(ctx context.Context, conn *grpc.ClientConn, req *model.GRPCRequest) (protoreflect.MethodDescriptor, error) {
+	key := req.Method
+	if len(req.ProtoFileIDs) == 0 {
+		key = req.Target + "|" + req.Method
+	}
+	c.mu.Lock()
+	md, ok := c.methods[key]
+	c.mu.Unlock()
+	if ok {
+		return md, nil
+	}
+
+	v, err, _ := c.lookups.Do(key, func() (interface{}, error) {
+		var (
+			md  protoreflect.MethodDescriptor
+			err error
+		)
+		if len(req.ProtoFileIDs) > 0 {
+			var files linker.Files
+			if files, err = c.compiledProtos(ctx); err != nil {
+				return nil, err
+			}
+			md, err = FindGRPCMethod(files, req.Method)
+		} else {
+			md, err = reflectMethod(ctx, conn, req.Method)
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.methods[key] = md
+		c.mu.Unlock()
+		return md, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(protoreflect.MethodDescriptor), nil
+}
+
+// compiledProtos returns the client's proto files, compiling them on first use
+func (c *grpcClient) compiledProtos(ctx context.Context) (linker.Files, error) {
+	c.mu.Lock()
+	files := c.files
+	c.mu.Unlock()
+	if files != nil {
+		return files, nil
+	}
+
+	v, err, _ := c.lookups.Do(grpcProtosKey, func() (interface{}, error) {
+		paths := make([]string, 0, len(c.protos))
+		for path := range c.protos {
+			paths = append(paths, path)
+		}
+		files, err := CompileProtos(ctx, c.protos, paths...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proto files: %w", err)
+		}
+		c.mu.Lock()
+		c.files = files
+		c.mu.Unlock()
+		return files, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(linker.Files), nil
+}
+
+// close closes every connection the client opened
+func (c *grpcClient) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, conn := range c.conns {
+		_ = conn.Close()
+		delete(c.conns, key)
+	}
+}
+
+// reflectMethod looks up a method through the server's reflection service,
+// fetching the file that defines its service and every file it imports
+func reflectMethod(ctx context.Context, conn *grpc.ClientConn, name string) (protoreflect.MethodDescriptor, error) {
+	service, method, err := splitGRPCMethod(name)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("server reflection: %w", err)
+	}
+	defer func() { _ = stream.CloseSend() }()
+
+	fetched := make(map[string]*descriptorpb.FileDescriptorProto)
+	request := &reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+	}
+	for request != nil {
+		if err := stream.Send(request); err != nil {
+			return nil, fmt.Errorf("server reflection: %w", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("server reflection: %w", err)
+		}
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			return nil, fmt.Errorf("server reflection: %s", errResp.GetErrorMessage())
+		}
+		for _, data := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fd := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(data, fd); err != nil {
+				return nil, fmt.Errorf("server reflection: %w", err)
+			}
+			fetched[fd.GetName()] = fd
+		}
+
+		// Ask for the first import the server has not sent yet
+		request = nil
+		for _, fd := range fetched {
+			for _, dep := range fd.GetDependency() {
+				if _, ok := fetched[dep]; !ok && request == nil {
+					request = &reflectionpb.ServerReflectionRequest{
+						MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+					}
+				}
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, fd := range fetched {
+		set.File = append(set.File, fd)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("server reflection: %w", err)
+	}
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found through server reflection", service)
+	}
+	sd, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", service)
+	}
+	return findMethod(sd, method)
+}
@@ -0,0 +1,261 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// echoProto describes echoService, which has no generated code and is
+// therefore only known through its proto file
+const echoProto = `syntax = "proto3";
+package echo.v1;
+import "google/protobuf/wrappers.proto";
+service Echo {
+  rpc Say(google.protobuf.StringValue) returns (google.protobuf.StringValue);
+  rpc Repeat(google.protobuf.StringValue) returns (stream google.protobuf.StringValue);
+}`
+
+// echoService greets the name it is sent, echoing it in the x-echo header,
+// and rejects an empty name. Repeat streams the name three times.
+var echoService = grpc.ServiceDesc{
+	ServiceName: "echo.v1.Echo",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Say",
+		Handler: func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+			in := &wrapperspb.StringValue{}
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			if in.Value == "" {
+				return nil, status.Error(codes.InvalidArgument, "name is required")
+			}
+			_ = grpc.SetHeader(ctx, metadata.Pairs("x-echo", in.Value))
+			return wrapperspb.String("hello " + in.Value), nil
+		},
+	}},
+	Streams: []grpc.StreamDesc{{
+		StreamName:    "Repeat",
+		ServerStreams: true,
+		Handler: func(_ any, stream grpc.ServerStream) error {
+			in := &wrapperspb.StringValue{}
+			if err := stream.RecvMsg(in); err != nil {
+				return err
+			}
+			for i := 0; i < 3; i++ {
+				if err := stream.SendMsg(wrapperspb.String(fmt.Sprintf("%s %d", in.Value, i))); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}},
+}
+
+// startGRPCServer serves the health service, with "api" serving, the echo
+// service and server reflection on a local port
+func startGRPCServer(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("api", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	server.RegisterService(&echoService, struct{}{})
+	reflection.Register(server)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	return lis.Addr().String()
+}
+
+func TestWorkerGRPC(t *testing.T) {
+	target := startGRPCServer(t)
+
+	notFound := model.Assertion{Type: model.AssertionStatusCode, Value: float64(codes.NotFound)}
+	tests := []struct {
+		name       string
+		service    string
+		assertions []model.Assertion
+		success    bool
+		code       string
+		errorClass string
+	}{
+		{"serving", "api", nil, true, "OK", ""},
+		{"unknown service", "billing", nil, false, "NOT_FOUND", model.ErrorClassGRPC},
+		{"status set replaces the default", "billing", []model.Assertion{notFound}, true, "NOT_FOUND", ""},
+		{"failed check", "api", []model.Assertion{{Type: model.AssertionBodyContains, Value: "NOT_SERVING"}}, false, "OK", model.ErrorClassCheckFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &model.TestPlan{ID: "test-grpc", TimeoutMs: 5000, Assertions: tt.assertions, GRPC: &model.GRPCRequest{
+				Target:    target,
+				Method:    "grpc.health.v1.Health/Check",
+				Message:   `{"service": "{{data.service}}"}`,
+				Plaintext: true,
+			}}
+			m := model.NewMetrics("run-grpc")
+			client := newGRPCClient(nil, nil)
+			defer client.close()
+			worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+			worker.grpc = client
+			worker.row = map[string]string{"service": tt.service}
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if (m.SuccessRequests == 1) != tt.success {
+				t.Errorf("Expected success %v, got %d successful and %d failed requests (errors %v)", tt.success, m.SuccessRequests, m.FailedRequests, m.ErrorSamples)
+			}
+			if m.GRPCStatusCodes[tt.code] != 1 {
+				t.Errorf("Expected one %s status, got %v", tt.code, m.GRPCStatusCodes)
+			}
+			if tt.errorClass != "" && m.Errors[tt.errorClass] != 1 {
+				t.Errorf("Expected the failure to be counted as %s, got %v", tt.errorClass, m.Errors)
+			}
+		})
+	}
+}
+
+func TestWorkerGRPCUnknownMethod(t *testing.T) {
+	target := startGRPCServer(t)
+
+	plan := &model.TestPlan{ID: "test-grpc-unknown", TimeoutMs: 5000, GRPC: &model.GRPCRequest{
+		Target:    target,
+		Method:    "grpc.health.v1.Health/Probe",
+		Plaintext: true,
+	}}
+	m := model.NewMetrics("run-grpc-unknown")
+	client := newGRPCClient(nil, nil)
+	defer client.close()
+	worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+	worker.grpc = client
+
+	worker.executeRequest(context.Background(), Arrival{})
+
+	if m.FailedRequests != 1 || len(m.GRPCStatusCodes) != 0 {
+		t.Errorf("Expected the call to fail without a status, got %d failed with statuses %v", m.FailedRequests, m.GRPCStatusCodes)
+	}
+}
+
+func TestGRPCClientResolvesMethodConcurrently(t *testing.T) {
+	target := startGRPCServer(t)
+	client := newGRPCClient(nil, nil)
+	defer client.close()
+	req := &model.GRPCRequest{Target: target, Method: "grpc.health.v1.Health/Check", Plaintext: true}
+	conn, err := client.conn(req.Target, req.Plaintext)
+	if err != nil {
+		t.Fatalf("Failed to open connection: %v", err)
+	}
+
+	// Every VU waiting on the first lookup gets its descriptor
+	var wg sync.WaitGroup
+	found := make([]protoreflect.MethodDescriptor, 8)
+	for i := range found {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			found[i], _ = client.method(context.Background(), conn, req)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, md := range found {
+		if md == nil || md.FullName() != "grpc.health.v1.Health.Check" {
+			t.Fatalf("Expected call %d to resolve the method, got %v", i, md)
+		}
+	}
+	if len(client.methods) != 1 {
+		t.Errorf("Expected the method to be cached once, got %d entries", len(client.methods))
+	}
+}
+
+func TestScenarioExecutorGRPC(t *testing.T) {
+	target := startGRPCServer(t)
+	call := func(method, message string) *model.GRPCRequest {
+		return &model.GRPCRequest{Target: target, Method: method, ProtoFileIDs: []string{"echo"}, Message: message, Plaintext: true}
+	}
+
+	scenario := &model.Scenario{
+		ID: "scenario-grpc",
+		Steps: []model.Step{
+			{
+				Name:        "say",
+				GRPC:        call("echo.v1.Echo/Say", `"{{user}}"`),
+				Extractions: []model.VariableExtraction{{Name: "echoed", Source: "header", Type: model.ExtractionHeader, Path: "x-echo"}},
+				Assertions:  []model.Assertion{{Type: model.AssertionBodyContains, Value: "hello bob"}},
+			},
+			{
+				Name:       "repeat",
+				GRPC:       call("echo.v1.Echo/Repeat", `"{{echoed}}"`),
+				Assertions: []model.Assertion{{Type: model.AssertionBodyContains, Value: `["bob 0","bob 1","bob 2"]`}},
+			},
+			{
+				Name: "empty",
+				GRPC: call("echo.v1.Echo/Say", `""`),
+			},
+		},
+	}
+	protos := model.ProtoSources{"echo.proto": echoProto}
+
+	execution, err := NewScenarioExecutor().WithProtos(protos).Execute(scenario, model.Variables{"user": "bob"})
+	if err != nil {
+		t.Fatalf("Failed to execute scenario: %v (%+v)", err, execution.StepResults)
+	}
+
+	want := []string{"success OK", "success OK", "failed INVALID_ARGUMENT"}
+	for i, result := range execution.StepResults {
+		if got := result.Status + " " + result.GRPCStatus; got != want[i] {
+			t.Errorf("Expected step %s to be %s, got %s (%v)", result.StepName, want[i], got, result.AssertionsFailed)
+		}
+	}
+	if echoed := execution.Variables["echoed"]; echoed != "bob" {
+		t.Errorf("Expected the header to be extracted, got %v", echoed)
+	}
+}
+
+func TestCompileProtos(t *testing.T) {
+	files, err := CompileProtos(context.Background(), model.ProtoSources{"echo.proto": echoProto}, "echo.proto")
+	if err != nil {
+		t.Fatalf("Failed to compile: %v", err)
+	}
+	method, err := FindGRPCMethod(files, "echo.v1.Echo/Repeat")
+	if err != nil || !method.IsStreamingServer() {
+		t.Errorf("Expected the server streaming method, got %v (%v)", method, err)
+	}
+	for _, name := range []string{"echo.v1.Echo/Shout", "echo.v1.Other/Say", "Say"} {
+		if _, err := FindGRPCMethod(files, name); err == nil {
+			t.Errorf("Expected %s not to be found", name)
+		}
+	}
+}
+
+func TestGRPCCodeName(t *testing.T) {
+	for code, want := range map[codes.Code]string{
+		codes.OK:                "OK",
+		codes.NotFound:          "NOT_FOUND",
+		codes.DeadlineExceeded:  "DEADLINE_EXCEEDED",
+		codes.ResourceExhausted: "RESOURCE_EXHAUSTED",
+	} {
+		if got := grpcCodeName(code); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+}
@@ -26,6 +26,7 @@ type LoadGenerator struct {
 	shutdownCtx  context.Context
 	shutdownFunc context.CancelFunc
 	collector    *metrics.Collector
+	onComplete   func(*model.Metrics)
 }
 
 // TestExecution holds the runtime state of a test
@@ -81,8 +82,16 @@ func (lg *LoadGenerator) StartTest(runID string, plan *model.TestPlan) (*model.M
 	// Create metrics
 	metrics := model.NewMetrics(runID)
 
-	// Create scheduler with shared client
+	// Create scheduler with shared client. It is started before the test is
+	// registered so that control commands never reach a scheduler that is
+	// still starting.
 	scheduler := NewScheduler(plan, metrics, lg.sharedClient, lg.collector)
+	if err := scheduler.Start(); err != nil {
+		logger.Log.Error("Failed to start scheduler",
+			zap.String("run_id", runID),
+			zap.Error(err))
+		return nil, err
+	}
 
 	// Create test execution context
 	ctx, cancel := context.WithCancel(context.Background())
@@ -102,7 +111,7 @@ func (lg *LoadGenerator) StartTest(runID string, plan *model.TestPlan) (*model.M
 	// Update active tests gauge
 	lg.collector.SetActiveTests(len(lg.activeTests))
 
-	// Start the test in background
+	// Run the test in background
 	go lg.runTest(execution)
 
 	logger.Log.Info("Test started",
@@ -112,17 +121,16 @@ func (lg *LoadGenerator) StartTest(runID string, plan *model.TestPlan) (*model.M
 	return metrics, nil
 }
 
+// SetCompletionHandler registers a callback that receives the final metrics
+// of every test once it has finished, whether it completed or was stopped
+func (lg *LoadGenerator) SetCompletionHandler(fn func(*model.Metrics)) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.onComplete = fn
+}
+
 // runTest executes the test and handles cleanup
 func (lg *LoadGenerator) runTest(execution *TestExecution) {
-	// Start scheduler
-	if err := execution.Scheduler.Start(); err != nil {
-		logger.Log.Error("Failed to start scheduler",
-			zap.String("run_id", execution.RunID),
-			zap.Error(err))
-		lg.cleanupTest(execution.RunID)
-		return
-	}
-
 	// Wait for test to complete
 	execution.Scheduler.Wait()
 
@@ -137,6 +145,13 @@ func (lg *LoadGenerator) runTest(execution *TestExecution) {
 		zap.Duration("duration", duration),
 		zap.Int64("total_requests", execution.Metrics.TotalRequests))
 
+	lg.mu.RLock()
+	onComplete := lg.onComplete
+	lg.mu.RUnlock()
+	if onComplete != nil {
+		onComplete(execution.Metrics.GetSnapshot())
+	}
+
 	// Cleanup after test
 	lg.cleanupTest(execution.RunID)
 }
@@ -161,6 +176,18 @@ func (lg *LoadGenerator) StopTest(runID string) error {
 	return nil
 }
 
+// ControlTest applies a live control command to a running test
+func (lg *LoadGenerator) ControlTest(runID string, cmd model.ControlCommand) error {
+	lg.mu.RLock()
+	execution, exists := lg.activeTests[runID]
+	lg.mu.RUnlock()
+	if !exists {
+		return ErrTestNotFound
+	}
+
+	return execution.Scheduler.Control(cmd)
+}
+
 // GetMetrics retrieves current metrics for a running test
 func (lg *LoadGenerator) GetMetrics(runID string) (*model.Metrics, error) {
 	lg.mu.RLock()
@@ -174,6 +201,64 @@ func (lg *LoadGenerator) GetMetrics(runID string) (*model.Metrics, error) {
 	return execution.Metrics.GetSnapshot(), nil
 }
 
+// GetHistograms merges the live service and response time histograms of a
+// running test
+func (lg *LoadGenerator) GetHistograms(runID string) (latency, response *model.Histogram, err error) {
+	lg.mu.RLock()
+	defer lg.mu.RUnlock()
+
+	execution, exists := lg.activeTests[runID]
+	if !exists {
+		return nil, nil, ErrTestNotFound
+	}
+
+	latency, response = execution.Scheduler.Histograms()
+	return latency, response, nil
+}
+
+// GetBreakdownHistograms returns the live per-step and per-request service
+// time histograms of a running test
+func (lg *LoadGenerator) GetBreakdownHistograms(runID string) (steps, requests []*model.Histogram, err error) {
+	lg.mu.RLock()
+	defer lg.mu.RUnlock()
+
+	execution, exists := lg.activeTests[runID]
+	if !exists {
+		return nil, nil, ErrTestNotFound
+	}
+
+	steps, requests = execution.Scheduler.BreakdownHistograms()
+	return steps, requests, nil
+}
+
+// GetPhaseHistograms returns the live per-phase request timing histograms of
+// a running test, indexed like model.TimingPhases
+func (lg *LoadGenerator) GetPhaseHistograms(runID string) ([]*model.Histogram, error) {
+	lg.mu.RLock()
+	defer lg.mu.RUnlock()
+
+	execution, exists := lg.activeTests[runID]
+	if !exists {
+		return nil, ErrTestNotFound
+	}
+
+	return execution.Scheduler.PhaseHistograms(), nil
+}
+
+// GetProtocolHistograms returns the live histograms of the metrics sections
+// of a running test, such as its WebSocket handshake times, by section name
+func (lg *LoadGenerator) GetProtocolHistograms(runID string) (model.ProtocolHistograms, error) {
+	lg.mu.RLock()
+	defer lg.mu.RUnlock()
+
+	execution, exists := lg.activeTests[runID]
+	if !exists {
+		return nil, ErrTestNotFound
+	}
+
+	return execution.Scheduler.ProtocolHistograms(), nil
+}
+
 // IsRunning checks if a test is currently running
 func (lg *LoadGenerator) IsRunning(runID string) bool {
 	lg.mu.RLock()
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"math"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// diurnalShape is the relative traffic of each hour of a typical day, from
+// midnight, from 0 at the night-time trough to 1 at the evening peak
+var diurnalShape = [24]float64{
+	0.17, 0.09, 0.03, 0.00, 0.00, 0.03, 0.17, 0.39, 0.61, 0.78, 0.87, 0.92,
+	0.94, 0.92, 0.89, 0.87, 0.87, 0.89, 0.94, 1.00, 0.94, 0.78, 0.56, 0.33,
+}
+
+// stageValues returns a function that interpolates one dimension of a stages
+// profile. get picks the stage's target for that dimension; a nil target
+// holds the previous value. The last value is held once the stages end.
+func stageValues(stages []model.Stage, start float64, get func(model.Stage) *int) func(elapsed time.Duration) float64 {
+	return func(elapsed time.Duration) float64 {
+		from := start
+		var boundary time.Duration
+		for _, stage := range stages {
+			to := from
+			if target := get(stage); target != nil {
+				to = float64(*target)
+			}
+			length := time.Duration(stage.DurationSec) * time.Second
+			if elapsed < boundary+length {
+				progress := float64(elapsed-boundary) / float64(length)
+				return from + (to-from)*progress
+			}
+			boundary += length
+			from = to
+		}
+		return from
+	}
+}
+
+// stagesRate returns the arrival rate of a stages profile, starting from startRPS
+func stagesRate(stages []model.Stage, startRPS int) func(elapsed time.Duration) float64 {
+	return stageValues(stages, float64(startRPS), func(s model.Stage) *int { return s.TargetRPS })
+}
+
+// stagesVUs returns the VU count of a stages profile, starting from startVUs
+func stagesVUs(stages []model.Stage, startVUs int) func(elapsed time.Duration) float64 {
+	return stageValues(stages, float64(startVUs), func(s model.Stage) *int { return s.TargetVUs })
+}
+
+// sineRate oscillates between the wave's min and max rate, starting at the
+// minimum and peaking half way through each period
+func sineRate(wave model.WaveConfig, period time.Duration) func(elapsed time.Duration) float64 {
+	amplitude := float64(wave.MaxRPS - wave.MinRPS)
+	return func(elapsed time.Duration) float64 {
+		phase := 2 * math.Pi * float64(elapsed) / float64(period)
+		return float64(wave.MinRPS) + amplitude*(1-math.Cos(phase))/2
+	}
+}
+
+// diurnalRate replays diurnalShape once per period, interpolating linearly
+// between hours and scaling it between the wave's min and max rate
+func diurnalRate(wave model.WaveConfig, period time.Duration) func(elapsed time.Duration) float64 {
+	amplitude := float64(wave.MaxRPS - wave.MinRPS)
+	return func(elapsed time.Duration) float64 {
+		hours := math.Mod(float64(elapsed)/float64(period), 1) * 24
+		hour := int(hours)
+		next := (hour + 1) % 24
+		shape := diurnalShape[hour] + (diurnalShape[next]-diurnalShape[hour])*(hours-float64(hour))
+		return float64(wave.MinRPS) + amplitude*shape
+	}
+}
+
+// rampDownFactor returns 1 until the final rampDown of the test, then falls
+// linearly to 0 at the end of the test
+func rampDownFactor(duration, rampDown time.Duration) func(elapsed time.Duration) float64 {
+	return func(elapsed time.Duration) float64 {
+		if rampDown <= 0 || elapsed < duration-rampDown {
+			return 1
+		}
+		if elapsed >= duration {
+			return 0
+		}
+		return float64(duration-elapsed) / float64(rampDown)
+	}
+}
@@ -0,0 +1,283 @@
+package engine
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// protocol is a protocol or request feature whose workers share a client,
+// a metrics section or histograms, such as SQL statements, WebSocket
+// sessions or retries. Each is registered once in protocols; the scheduler,
+// the agents and the distributed merge only go through this interface.
+type protocol interface {
+	// name keys the protocol's histograms in model.ProtocolHistograms
+	name() string
+	// open creates the shared client and the metrics section of a plan that
+	// uses the protocol and returns the histograms its workers share, or
+	// false if the plan does not use it
+	open(s *Scheduler, tlsConfig *tls.Config) ([]*model.Histogram, bool, error)
+	// attach hands a worker, and its scenario step runner, the shared client
+	// and histograms
+	attach(s *Scheduler, w *Worker, hists []*model.Histogram)
+	// close releases the shared client once every worker has finished
+	close(s *Scheduler)
+	// finalize reads the section's stats from its histograms, if the
+	// metrics have the section. The caller holds m.Mu if m is shared.
+	finalize(m *model.Metrics, hists []*model.Histogram)
+}
+
+// protocols are the registered protocols and request features
+var protocols = []protocol{
+	retryProtocol{},
+	webSocketProtocol{},
+	streamProtocol{},
+	socketProtocol{},
+	dnsProtocol{},
+	grpcProtocol{},
+	sqlProtocol{},
+	redisProtocol{},
+}
+
+// SetProtocolStats reads the stats of every metrics section of m from its
+// histograms, such as the merged histograms of a distributed run. The
+// caller holds m.Mu if m is shared.
+func SetProtocolStats(m *model.Metrics, hists model.ProtocolHistograms) {
+	for _, p := range protocols {
+		p.finalize(m, hists[p.name()])
+	}
+}
+
+// newHistograms creates n empty histograms
+func newHistograms(n int) []*model.Histogram {
+	hists := make([]*model.Histogram, n)
+	for i := range hists {
+		hists[i] = model.NewHistogram()
+	}
+	return hists
+}
+
+// histogramAt returns the histogram at index i of hists, nil if there is none
+func histogramAt(hists []*model.Histogram, i int) *model.Histogram {
+	if i >= len(hists) {
+		return nil
+	}
+	return hists[i]
+}
+
+// noClient is embedded by protocols without a shared client to release
+type noClient struct{}
+
+func (noClient) close(*Scheduler) {}
+
+// noStats is embedded by protocols whose metrics section has no stats read
+// from histograms
+type noStats struct{}
+
+func (noStats) finalize(*model.Metrics, []*model.Histogram) {}
+
+// retryProtocol times the first attempts of plans with a retry policy
+type retryProtocol struct{ noClient }
+
+func (retryProtocol) name() string { return "retry" }
+
+func (retryProtocol) open(s *Scheduler, _ *tls.Config) ([]*model.Histogram, bool, error) {
+	if !usesRetries(s.plan) {
+		return nil, false, nil
+	}
+	s.metrics.InitRetry()
+	return newHistograms(1), true, nil
+}
+
+func (retryProtocol) attach(_ *Scheduler, w *Worker, hists []*model.Histogram) {
+	w.firstAttemptHist = hists[0]
+}
+
+func (retryProtocol) finalize(m *model.Metrics, hists []*model.Histogram) {
+	if m.Retry != nil {
+		m.Retry.SetStats(histogramAt(hists, 0))
+	}
+}
+
+// webSocketProtocol shares one dialer between the WebSocket sessions of
+// plans and scenarios, counts their messages and times their handshakes
+// and replies
+type webSocketProtocol struct{ noClient }
+
+func (webSocketProtocol) name() string { return "websocket" }
+
+func (webSocketProtocol) open(s *Scheduler, tlsConfig *tls.Config) ([]*model.Histogram, bool, error) {
+	reqs := webSocketRequests(s.plan)
+	if len(reqs) == 0 {
+		return nil, false, nil
+	}
+	client, err := newWSClient(tlsConfig, reqs...)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid WebSocket settings: %w", err)
+	}
+	s.websocket = client
+	s.metrics.InitWebSocket()
+	return newHistograms(2), true, nil
+}
+
+func (webSocketProtocol) attach(s *Scheduler, w *Worker, hists []*model.Histogram) {
+	w.websocket = s.websocket
+	if w.stepRunner != nil {
+		w.stepRunner.websocket = s.websocket
+	}
+	w.wsConnectHist, w.wsRoundTripHist = hists[0], hists[1]
+}
+
+func (webSocketProtocol) finalize(m *model.Metrics, hists []*model.Histogram) {
+	if m.WebSocket != nil {
+		m.WebSocket.SetStats(histogramAt(hists, 0), histogramAt(hists, 1))
+	}
+}
+
+// streamProtocol counts the events of streaming plans and times them,
+// indexed like model.StreamPhases
+type streamProtocol struct{ noClient }
+
+func (streamProtocol) name() string { return "stream" }
+
+func (streamProtocol) open(s *Scheduler, _ *tls.Config) ([]*model.Histogram, bool, error) {
+	if s.plan.Stream == nil {
+		return nil, false, nil
+	}
+	s.metrics.InitStream()
+	return newHistograms(len(model.StreamPhases)), true, nil
+}
+
+func (streamProtocol) attach(_ *Scheduler, w *Worker, hists []*model.Histogram) {
+	w.streamHists = hists
+}
+
+func (streamProtocol) finalize(m *model.Metrics, hists []*model.Histogram) {
+	if m.Stream != nil {
+		m.Stream.SetStats(hists)
+	}
+}
+
+// socketProtocol counts the bytes of plans with TCP or UDP requests and
+// times their connects and responses. Every VU opens its own connections.
+type socketProtocol struct{ noClient }
+
+func (socketProtocol) name() string { return "socket" }
+
+func (socketProtocol) open(s *Scheduler, _ *tls.Config) ([]*model.Histogram, bool, error) {
+	if s.plan.Socket == nil {
+		return nil, false, nil
+	}
+	s.metrics.InitSocket()
+	return newHistograms(2), true, nil
+}
+
+func (socketProtocol) attach(_ *Scheduler, w *Worker, hists []*model.Histogram) {
+	w.socketConnHist, w.socketRTTHist = hists[0], hists[1]
+}
+
+func (socketProtocol) finalize(m *model.Metrics, hists []*model.Histogram) {
+	if m.Socket != nil {
+		m.Socket.SetStats(histogramAt(hists, 0), histogramAt(hists, 1))
+	}
+}
+
+// dnsProtocol counts the answers of plans with DNS queries
+type dnsProtocol struct {
+	noClient
+	noStats
+}
+
+func (dnsProtocol) name() string { return "dns" }
+
+func (dnsProtocol) open(s *Scheduler, _ *tls.Config) ([]*model.Histogram, bool, error) {
+	if s.plan.DNS == nil {
+		return nil, false, nil
+	}
+	s.metrics.InitDNS()
+	return nil, true, nil
+}
+
+func (dnsProtocol) attach(*Scheduler, *Worker, []*model.Histogram) {}
+
+// grpcProtocol shares one client, with its connections and method
+// descriptors, between the gRPC calls of plans and scenarios
+type grpcProtocol struct{ noStats }
+
+func (grpcProtocol) name() string { return "grpc" }
+
+func (grpcProtocol) open(s *Scheduler, tlsConfig *tls.Config) ([]*model.Histogram, bool, error) {
+	if s.plan.GRPC == nil && (s.plan.Scenario == nil || !hasGRPCSteps(s.plan.Scenario)) {
+		return nil, false, nil
+	}
+	s.grpc = newGRPCClient(s.plan.Protos, tlsConfig)
+	return nil, true, nil
+}
+
+func (grpcProtocol) attach(s *Scheduler, w *Worker, _ []*model.Histogram) {
+	w.grpc = s.grpc
+	if w.stepRunner != nil {
+		w.stepRunner.grpc = s.grpc
+	}
+}
+
+func (grpcProtocol) close(s *Scheduler) {
+	s.grpc.close()
+}
+
+// sqlProtocol shares one connection pool between the SQL statements of a
+// plan and counts their rows. Scenario SQL steps run on the plan's database.
+type sqlProtocol struct{ noStats }
+
+func (sqlProtocol) name() string { return "sql" }
+
+func (sqlProtocol) open(s *Scheduler, _ *tls.Config) ([]*model.Histogram, bool, error) {
+	if s.plan.SQL == nil {
+		if s.plan.Scenario != nil && hasSQLSteps(s.plan.Scenario) {
+			return nil, false, fmt.Errorf("scenario has SQL steps: %w", errNoSQLDatabase)
+		}
+		return nil, false, nil
+	}
+	db, err := openSQLDatabase(s.plan.SQL, s.plan.Credentials, s.vuLimit)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid SQL settings: %w", err)
+	}
+	s.db = db
+	s.metrics.InitSQL()
+	return nil, true, nil
+}
+
+func (sqlProtocol) attach(s *Scheduler, w *Worker, _ []*model.Histogram) {
+	w.db = s.db
+	if w.stepRunner != nil {
+		w.stepRunner.db = s.db
+	}
+}
+
+func (sqlProtocol) close(s *Scheduler) {
+	_ = s.db.Close()
+}
+
+// redisProtocol shares one connection pool between the Redis commands of a
+// plan and counts their replies
+type redisProtocol struct{ noStats }
+
+func (redisProtocol) name() string { return "redis" }
+
+func (redisProtocol) open(s *Scheduler, tlsConfig *tls.Config) ([]*model.Histogram, bool, error) {
+	if s.plan.Redis == nil {
+		return nil, false, nil
+	}
+	s.redis = newRedisPool(s.plan.Redis, s.plan.Credentials, tlsConfig, s.vuLimit)
+	s.metrics.InitRedis()
+	return nil, true, nil
+}
+
+func (redisProtocol) attach(s *Scheduler, w *Worker, _ []*model.Histogram) {
+	w.redis = s.redis
+}
+
+func (redisProtocol) close(s *Scheduler) {
+	s.redis.close()
+}
@@ -0,0 +1,69 @@
+package engine
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+func TestProtocols(t *testing.T) {
+	plan := &model.TestPlan{
+		ID:          "plan-stats",
+		Users:       1,
+		DurationSec: 1,
+		Retry:       &model.RetryPolicy{MaxAttempts: 2},
+		Socket:      &model.SocketRequest{Network: model.SocketTCP, Address: "localhost:1"},
+		DNS:         &model.DNSRequest{Name: "example.com"},
+	}
+	s := NewScheduler(plan, model.NewMetrics("run-stats"), nil, getSharedTestCollector())
+	s.stats = make(model.ProtocolHistograms)
+	for _, p := range protocols {
+		hists, ok, err := p.open(s, nil)
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", p.name(), err)
+		}
+		if ok {
+			s.used = append(s.used, p)
+			if len(hists) > 0 {
+				s.stats[p.name()] = hists
+			}
+		}
+	}
+	if len(s.used) != 3 {
+		t.Fatalf("Expected the retry, socket and DNS protocols, got %d", len(s.used))
+	}
+	if len(s.stats) != 2 || len(s.stats["retry"]) != 1 || len(s.stats["socket"]) != 2 {
+		t.Fatalf("Expected retry and socket histograms, got %v", s.stats)
+	}
+	if s.metrics.Retry == nil || s.metrics.Socket == nil || s.metrics.DNS == nil || s.metrics.WebSocket != nil || s.metrics.Stream != nil {
+		t.Fatalf("Expected only the retry, socket and DNS sections, got %+v", s.metrics)
+	}
+	if s.grpc != nil || s.db != nil || s.redis != nil || s.websocket != nil {
+		t.Fatal("Expected no shared clients for a plan without gRPC, SQL, Redis or WebSocket requests")
+	}
+
+	worker := NewWorker(0, plan, s.metrics, http.DefaultClient, getSharedTestCollector())
+	for _, p := range s.used {
+		p.attach(s, worker, s.stats[p.name()])
+	}
+	s.closeProtocols()
+	worker.socketConnHist.Record(5 * time.Millisecond)
+	worker.socketRTTHist.Record(20 * time.Millisecond)
+
+	SetProtocolStats(s.metrics, s.stats)
+	if s.metrics.Socket.ConnectMaxMs < 5 || s.metrics.Socket.RoundTripMaxMs < 20 {
+		t.Errorf("Expected the socket stats to be read from the shared histograms, got %+v", s.metrics.Socket)
+	}
+
+	// Sections without histograms, such as those of a merged report from
+	// agents that sent none, still get their derived stats
+	merged := model.NewMetrics("run-merged")
+	merged.InitRetry()
+	merged.Retry.Requests, merged.Retry.Retries = 4, 2
+	SetProtocolStats(merged, nil)
+	if merged.Retry.Amplification != 1.5 {
+		t.Errorf("Expected an amplification of 1.5, got %v", merged.Retry.Amplification)
+	}
+}
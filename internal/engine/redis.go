@@ -0,0 +1,426 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+const (
+	redisMethodLabel    = "REDIS"                           // Method Redis sends are counted under in Prometheus
+	redisDefaultTimeout = 30 * time.Second                  // Send timeout of plans without one
+	redisMaxBulkSize    = 512 << 20                         // Largest bulk string a server may send
+	redisMaxArrayHint   = 1024                              // Elements allocated up front for an array reply
+	redisExecDiscarded  = "EXECABORT Transaction discarded" // Reply recorded for a transaction whose EXEC replied nil
+)
+
+// errNoRedisPool fails the sends of a worker the scheduler gave no
+// connection pool
+var errNoRedisPool = errors.New("redis pool not set")
+
+// errRESPMalformed fails a send whose replies could not be parsed
+var errRESPMalformed = errors.New("malformed resp reply")
+
+// redisErrorReply is a command's error reply, e.g. "WRONGTYPE Operation
+// against a key holding the wrong kind of value"
+type redisErrorReply string
+
+// prefix returns the error's code, the first word of the reply, e.g.
+// WRONGTYPE or MOVED
+func (e redisErrorReply) prefix() string {
+	code, _, _ := strings.Cut(string(e), " ")
+	return code
+}
+
+// redisReplyError returns the error a command answered with an error reply
+// is recorded with
+func redisReplyError(command string, reply redisErrorReply) error {
+	return fmt.Errorf("%w %s: %s", model.ErrRedis, command, string(reply))
+}
+
+// redisCommandNames returns the breakdown name of each of a plan's
+// commands: its position and upper-cased name, e.g. "1 GET", so that two
+// GETs are timed apart, as the request mix's requests are. Transactions
+// also time their EXEC, named after the position past the last command,
+// e.g. "2 EXEC".
+func redisCommandNames(req *model.RedisRequest) []string {
+	names := make([]string, len(req.Commands), len(req.Commands)+1)
+	for i, cmd := range req.Commands {
+		var name string
+		if len(cmd.Args) > 0 {
+			name = strings.ToUpper(cmd.Args[0])
+		}
+		names[i] = fmt.Sprintf("%d %s", i, name)
+	}
+	if req.Mode == model.RedisModeTransaction {
+		names = append(names, fmt.Sprintf("%d EXEC", len(req.Commands)))
+	}
+	return names
+}
+
+// redisConn is one connection of a pool
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// roundTrip writes commands as one pipeline, each with its own write, and
+// reads a reply to each. It returns each command's time from its write
+// until its reply was read; on failure, the replies read so far.
+func (c *redisConn) roundTrip(commands [][]string) ([]any, []time.Duration, error) {
+	sent := make([]time.Time, len(commands))
+	var msg []byte
+	for i, args := range commands {
+		msg = appendRESPCommand(msg[:0], args)
+		sent[i] = time.Now()
+		if _, err := c.conn.Write(msg); err != nil {
+			return nil, nil, err
+		}
+	}
+	replies := make([]any, 0, len(commands))
+	times := make([]time.Duration, 0, len(commands))
+	for i := range commands {
+		reply, err := readRESPReply(c.r)
+		if err != nil {
+			return replies, times, err
+		}
+		replies = append(replies, reply)
+		times = append(times, time.Since(sent[i]))
+	}
+	return replies, times, nil
+}
+
+// redisPool is the connection pool of a run's commands, shared by its VUs.
+// Connections are opened as the VUs first need them, up to the pool size,
+// and kept until the run ends; a connection that failed mid-send is closed
+// and frees its place.
+type redisPool struct {
+	req   *model.RedisRequest
+	creds *model.Credentials // Sent with AUTH on every new connection, nil if none
+	tls   *tls.Config        // nil for plain TCP
+	slots chan struct{}      // Holds one token per open connection
+	idle  chan *redisConn    // Open connections no VU is using
+	names []string           // Breakdown name of each of the plan's commands, and of EXEC for transactions
+}
+
+// newRedisPool creates the pool of a run's commands, sized for vus VUs
+// unless the request sets its own size. Connections authenticate with creds
+// when set, and TLS connections use tlsConfig, the plan's settings, when set.
+func newRedisPool(req *model.RedisRequest, creds *model.Credentials, tlsConfig *tls.Config, vus int) *redisPool {
+	size := req.PoolSize
+	if size <= 0 {
+		size = vus
+	}
+	size = max(size, 1)
+	p := &redisPool{
+		req:   req,
+		creds: creds,
+		slots: make(chan struct{}, size),
+		idle:  make(chan *redisConn, size),
+	}
+	p.names = redisCommandNames(req)
+
+	if req.TLS {
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		} else {
+			tlsConfig = tlsConfig.Clone()
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName, _, _ = net.SplitHostPort(req.Address)
+		}
+		p.tls = tlsConfig
+	}
+	return p
+}
+
+// get returns an idle connection, or opens one while the pool has room,
+// and otherwise waits for a VU to put one back. opened reports whether the
+// connection is new.
+func (p *redisPool) get(ctx context.Context, deadline time.Time) (c *redisConn, opened bool, err error) {
+	select {
+	case c := <-p.idle:
+		return c, false, nil
+	default:
+	}
+	select {
+	case c := <-p.idle:
+		return c, false, nil
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+	if c, err = p.dial(ctx, deadline); err != nil {
+		<-p.slots
+		return nil, false, err
+	}
+	return c, true, nil
+}
+
+// put hands a connection back to the pool, or closes it if it is unusable
+func (p *redisPool) put(c *redisConn, healthy bool) {
+	if healthy {
+		p.idle <- c
+		return
+	}
+	_ = c.conn.Close()
+	<-p.slots
+}
+
+// close closes the pool's idle connections, once no VU is running
+func (p *redisPool) close() {
+	for {
+		select {
+		case c := <-p.idle:
+			_ = c.conn.Close()
+		default:
+			return
+		}
+	}
+}
+
+// dial opens a connection, authenticates it and selects the request's
+// database
+func (p *redisPool) dial(ctx context.Context, deadline time.Time) (*redisConn, error) {
+	dialer := &net.Dialer{Deadline: deadline}
+	var conn net.Conn
+	var err error
+	if p.tls != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: p.tls}).DialContext(ctx, "tcp", p.req.Address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", p.req.Address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+
+	var setup [][]string
+	if p.creds != nil {
+		auth := []string{"AUTH"}
+		if p.creds.Username != "" {
+			auth = append(auth, p.creds.Username)
+		}
+		setup = append(setup, append(auth, p.creds.Password))
+	}
+	if p.req.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(p.req.DB)})
+	}
+	if len(setup) == 0 {
+		return c, nil
+	}
+	_ = conn.SetDeadline(deadline)
+	replies, _, err := c.roundTrip(setup)
+	for i := 0; err == nil && i < len(replies); i++ {
+		if reply, ok := replies[i].(redisErrorReply); ok {
+			err = redisReplyError(setup[i][0], reply)
+		}
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// redisResult is the outcome of one send
+type redisResult struct {
+	replies []any           // Reply to each command read before any failure: a string, int64, []any, nil or redisErrorReply
+	times   []time.Duration // Time each command took from its write until its reply was read, followed by EXEC's in a transaction
+	exec    any             // EXEC's reply in a transaction: a []any, nil or redisErrorReply
+	opened  bool            // The send opened a new connection
+}
+
+// firstError returns the index and reply of the first command answered
+// with an error reply
+func (r *redisResult) firstError() (int, redisErrorReply, bool) {
+	for i, reply := range r.replies {
+		if e, ok := reply.(redisErrorReply); ok {
+			return i, e, true
+		}
+	}
+	return 0, "", false
+}
+
+// response returns the result as an HTTP response, so the checks of HTTP
+// requests apply to it. Sends have no status code or headers.
+func (r *redisResult) response() *http.Response {
+	return &http.Response{Header: http.Header{}}
+}
+
+// body returns the replies as the JSON object checks see, keyed by the
+// position of their command among the plan's, given by indexes, e.g. $.1
+// for the second command's reply. Error replies are their message; nil
+// replies are null.
+func (r *redisResult) body(indexes []int) []byte {
+	replies := make(map[string]any, len(r.replies))
+	for i, reply := range r.replies {
+		replies[strconv.Itoa(indexes[i])] = reply
+	}
+	body, _ := json.Marshal(replies)
+	return body
+}
+
+// send sends commands as one pipeline on a connection of the pool, wrapped
+// in MULTI/EXEC for transactions, and reads every reply until the timeout.
+// On failure, the result holds the replies read so far.
+func (p *redisPool) send(ctx context.Context, commands [][]string, timeout time.Duration) (*redisResult, error) {
+	if timeout <= 0 {
+		timeout = redisDefaultTimeout
+	}
+	start := time.Now()
+	deadline := start.Add(timeout)
+	getCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	result := &redisResult{}
+	c, opened, err := p.get(getCtx, deadline)
+	if err != nil {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		return result, err
+	}
+	result.opened = opened
+
+	sent := commands
+	transaction := p.req.Mode == model.RedisModeTransaction
+	if transaction {
+		sent = make([][]string, 0, len(commands)+2)
+		sent = append(sent, []string{"MULTI"})
+		sent = append(sent, commands...)
+		sent = append(sent, []string{"EXEC"})
+	}
+
+	// A run that stops interrupts the send through the deadline
+	_ = c.conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { _ = c.conn.SetDeadline(time.Now()) })
+	replies, times, err := c.roundTrip(sent)
+	stop()
+	p.put(c, err == nil)
+
+	if transaction {
+		result.replies, result.times, result.exec = unwrapTransaction(replies, times, len(commands))
+	} else {
+		result.replies, result.times = replies, times
+	}
+	if err != nil && ctx.Err() != nil {
+		return result, ctx.Err()
+	}
+	return result, err
+}
+
+// unwrapTransaction returns the reply to each of n commands sent inside
+// MULTI/EXEC, the error a command was rejected with while queueing or its
+// reply within EXEC's, and EXEC's reply. Each command takes the time until
+// it was queued, followed by EXEC's time, when the commands ran together.
+// Without EXEC's reply no command has one.
+func unwrapTransaction(replies []any, times []time.Duration, n int) ([]any, []time.Duration, any) {
+	if len(replies) < n+2 {
+		return nil, nil, nil
+	}
+	exec := replies[n+1]
+	commandReplies := make([]any, n)
+	for i := range n {
+		if queued, ok := replies[1+i].(redisErrorReply); ok {
+			commandReplies[i] = queued
+			continue
+		}
+		switch exec := exec.(type) {
+		case []any:
+			if i < len(exec) {
+				commandReplies[i] = exec[i]
+			}
+		case redisErrorReply:
+			commandReplies[i] = exec
+		case nil:
+			commandReplies[i] = redisErrorReply(redisExecDiscarded)
+		}
+	}
+	return commandReplies, times[1 : n+2], exec
+}
+
+// appendRESPCommand appends a command encoded as an array of bulk strings
+func appendRESPCommand(msg []byte, args []string) []byte {
+	msg = append(msg, '*')
+	msg = strconv.AppendInt(msg, int64(len(args)), 10)
+	msg = append(msg, "\r\n"...)
+	for _, arg := range args {
+		msg = append(msg, '$')
+		msg = strconv.AppendInt(msg, int64(len(arg)), 10)
+		msg = append(msg, "\r\n"...)
+		msg = append(msg, arg...)
+		msg = append(msg, "\r\n"...)
+	}
+	return msg
+}
+
+// readRESPReply reads one RESP2 reply: a simple string, error, integer,
+// bulk string or array, where nil bulk strings and arrays are nil
+func readRESPReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errRESPMalformed
+	}
+	kind, text := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return text, nil
+	case '-':
+		return redisErrorReply(text), nil
+	case ':':
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, errRESPMalformed
+		}
+		return n, nil
+	case '$':
+		size, err := strconv.Atoi(text)
+		if err != nil || size < -1 || size > redisMaxBulkSize {
+			return nil, errRESPMalformed
+		}
+		if size == -1 {
+			return nil, nil
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		if buf[size] != '\r' || buf[size+1] != '\n' {
+			return nil, errRESPMalformed
+		}
+		return string(buf[:size]), nil
+	case '*':
+		size, err := strconv.Atoi(text)
+		if err != nil || size < -1 {
+			return nil, errRESPMalformed
+		}
+		if size == -1 {
+			return nil, nil
+		}
+		items := make([]any, 0, min(size, redisMaxArrayHint))
+		for range size {
+			item, err := readRESPReply(r)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return nil, errRESPMalformed
+}
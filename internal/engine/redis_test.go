@@ -0,0 +1,462 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// redisTestStatus is a simple string reply of redisTestServer
+type redisTestStatus string
+
+// redisTestCommands are the commands redisTestServer.execute knows
+var redisTestCommands = map[string]bool{
+	"PING": true, "SELECT": true, "SET": true, "GET": true, "INCR": true, "HSET": true, "HGET": true, "DEBUG": true,
+}
+
+// redisTestServer is a Redis stand-in speaking RESP2. It knows PING, GET,
+// SET, INCR, HSET, HGET, DEBUG SLEEP, MULTI/EXEC, SELECT and AUTH, with the
+// password it was started with, and BLPOP, which never replies.
+type redisTestServer struct {
+	addr     string
+	password string
+	conns    atomic.Int64 // Connections accepted
+
+	mu     sync.Mutex
+	values map[string]string
+	hashes map[string]map[string]string
+}
+
+// startRedisServer serves the stand-in on a local port until the test ends
+func startRedisServer(t *testing.T, password string) *redisTestServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	s := &redisTestServer{
+		addr:     ln.Addr().String(),
+		password: password,
+		values:   make(map[string]string),
+		hashes:   make(map[string]map[string]string),
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.conns.Add(1)
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// serve answers the commands of one connection, queueing them between
+// MULTI and EXEC
+func (s *redisTestServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	authed := s.password == ""
+	var queue [][]string
+	inMulti, discarded := false, false
+	for {
+		command, err := readRESPReply(r)
+		if err != nil {
+			return
+		}
+		items, _ := command.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i], _ = item.(string)
+		}
+		name := strings.ToUpper(args[0])
+
+		var reply any
+		switch {
+		case name == "AUTH":
+			authed = args[len(args)-1] == s.password
+			reply = redisTestStatus("OK")
+			if !authed {
+				reply = redisErrorReply("WRONGPASS invalid username-password pair or user is disabled.")
+			}
+		case !authed:
+			reply = redisErrorReply("NOAUTH Authentication required.")
+		case name == "BLPOP":
+			continue
+		case name == "MULTI":
+			inMulti, discarded, queue = true, false, nil
+			reply = redisTestStatus("OK")
+		case name == "EXEC":
+			replies := make([]any, len(queue))
+			for i, queued := range queue {
+				replies[i] = s.execute(queued)
+			}
+			reply = replies
+			if discarded {
+				reply = redisErrorReply("EXECABORT Transaction discarded because of previous errors.")
+			}
+			inMulti = false
+		case inMulti:
+			if !redisTestCommands[name] {
+				discarded = true
+				reply = redisErrorReply("ERR unknown command '" + args[0] + "'")
+			} else {
+				queue = append(queue, args)
+				reply = redisTestStatus("QUEUED")
+			}
+		default:
+			reply = s.execute(args)
+		}
+		if _, err := conn.Write(appendRedisTestReply(nil, reply)); err != nil {
+			return
+		}
+	}
+}
+
+// execute runs one command on the server's keys
+func (s *redisTestServer) execute(args []string) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wrongType := redisErrorReply("WRONGTYPE Operation against a key holding the wrong kind of value")
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return redisTestStatus("PONG")
+	case "SELECT":
+		return redisTestStatus("OK")
+	case "SET":
+		s.values[args[1]] = args[2]
+		return redisTestStatus("OK")
+	case "GET":
+		if _, ok := s.hashes[args[1]]; ok {
+			return wrongType
+		}
+		if v, ok := s.values[args[1]]; ok {
+			return v
+		}
+		return nil
+	case "INCR":
+		n, err := strconv.ParseInt(s.values[args[1]], 10, 64)
+		if err != nil && s.values[args[1]] != "" {
+			return redisErrorReply("ERR value is not an integer or out of range")
+		}
+		s.values[args[1]] = strconv.FormatInt(n+1, 10)
+		return n + 1
+	case "HSET":
+		if _, ok := s.values[args[1]]; ok {
+			return wrongType
+		}
+		if s.hashes[args[1]] == nil {
+			s.hashes[args[1]] = make(map[string]string)
+		}
+		s.hashes[args[1]][args[2]] = args[3]
+		return int64(1)
+	case "HGET":
+		if v, ok := s.hashes[args[1]][args[2]]; ok {
+			return v
+		}
+		return nil
+	case "DEBUG":
+		seconds, _ := strconv.ParseFloat(args[2], 64)
+		time.Sleep(time.Duration(seconds * float64(time.Second)))
+		return redisTestStatus("OK")
+	}
+	return redisErrorReply("ERR unknown command '" + args[0] + "'")
+}
+
+// appendRedisTestReply appends a reply of the stand-in, encoded
+func appendRedisTestReply(msg []byte, reply any) []byte {
+	switch reply := reply.(type) {
+	case nil:
+		return append(msg, "$-1\r\n"...)
+	case redisTestStatus:
+		return append(msg, "+"+string(reply)+"\r\n"...)
+	case redisErrorReply:
+		return append(msg, "-"+string(reply)+"\r\n"...)
+	case int64:
+		return append(msg, ":"+strconv.FormatInt(reply, 10)+"\r\n"...)
+	case string:
+		return append(msg, "$"+strconv.Itoa(len(reply))+"\r\n"+reply+"\r\n"...)
+	case []any:
+		msg = append(msg, "*"+strconv.Itoa(len(reply))+"\r\n"...)
+		for _, item := range reply {
+			msg = appendRedisTestReply(msg, item)
+		}
+	}
+	return msg
+}
+
+// redisCommands builds a plan's commands from space-separated commands
+func redisCommands(commands ...string) []model.RedisCommand {
+	pipeline := make([]model.RedisCommand, len(commands))
+	for i, cmd := range commands {
+		pipeline[i].Args = strings.Fields(cmd)
+	}
+	return pipeline
+}
+
+func TestWorkerRedis(t *testing.T) {
+	server := startRedisServer(t, "")
+
+	tests := []struct {
+		name         string
+		redis        model.RedisRequest
+		timeoutMs    int
+		assertions   []model.Assertion
+		success      bool
+		errorClass   string
+		nils         int64
+		errorReplies map[string]int64
+		sent         int64   // Commands sent
+		failed       []int64 // Failed count of each breakdown entry
+	}{
+		{"single command", model.RedisRequest{Commands: redisCommands("SET user:{{data.user_id}} alice")},
+			0, []model.Assertion{{Type: model.AssertionJSONPath, Target: "$.0", Value: "OK"}}, true, "", 0, nil, 1, []int64{0}},
+		{"pipeline", model.RedisRequest{Mode: model.RedisModePipeline, Commands: redisCommands("SET user:{{data.user_id}} alice", "get user:{{data.user_id}}", "HGET missing field")},
+			0, []model.Assertion{{Type: model.AssertionJSONPath, Target: "$.1", Value: "alice"}}, true, "", 1, nil, 3, []int64{0, 0, 0}},
+		{"repeated command", model.RedisRequest{Mode: model.RedisModePipeline, Commands: redisCommands("SET a 1", "SET b 2", "GET a")},
+			0, nil, true, "", 0, nil, 3, []int64{0, 0, 0}},
+		{"failed check", model.RedisRequest{Commands: redisCommands("GET nobody")},
+			0, []model.Assertion{{Type: model.AssertionBodyContains, Value: "alice"}}, false, model.ErrorClassCheckFailed, 1, nil, 1, []int64{0}},
+		{"error reply", model.RedisRequest{Mode: model.RedisModePipeline, Commands: redisCommands("HSET profile name alice", "GET profile", "HGET profile name")},
+			0, nil, false, model.ErrorClassRedis, 0, map[string]int64{"WRONGTYPE": 1}, 3, []int64{0, 1, 0}},
+		{"transaction", model.RedisRequest{Mode: model.RedisModeTransaction, Commands: redisCommands("SET counter 41", "INCR counter")},
+			0, []model.Assertion{{Type: model.AssertionJSONPath, Target: "$.1", Value: 42}}, true, "", 0, nil, 2, []int64{0, 0, 0}},
+		{"aborted transaction", model.RedisRequest{Mode: model.RedisModeTransaction, Commands: redisCommands("SET key value", "NOSUCHCOMMAND")},
+			0, nil, false, model.ErrorClassRedis, 0, map[string]int64{"EXECABORT": 1, "ERR": 1}, 2, []int64{1, 1, 1}},
+		{"timeout", model.RedisRequest{Mode: model.RedisModePipeline, Commands: redisCommands("SET key value", "BLPOP queue 0")},
+			50, nil, false, model.ErrorClassTimeout, 0, nil, 2, []int64{0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.redis.Address = server.addr
+			plan := &model.TestPlan{ID: "test-redis", TimeoutMs: tt.timeoutMs, Redis: &tt.redis, Assertions: tt.assertions}
+			m := model.NewMetrics("run-redis")
+			m.InitRedis()
+			if len(tt.assertions) > 0 {
+				m.InitChecks([]string{tt.assertions[0].Label()})
+			}
+			worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+			worker.redis = newRedisPool(&tt.redis, nil, nil, 1)
+			t.Cleanup(worker.redis.close)
+			m.InitRequests(worker.redis.names)
+			worker.row = map[string]string{"user_id": "42"}
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if (m.SuccessRequests == 1) != tt.success {
+				t.Errorf("Expected success %v, got %d successful and %d failed requests (errors %v)", tt.success, m.SuccessRequests, m.FailedRequests, m.ErrorSamples)
+			}
+			if tt.errorClass != "" && m.Errors[tt.errorClass] != 1 {
+				t.Errorf("Expected the failure to be counted as %s, got %v", tt.errorClass, m.Errors)
+			}
+			r := m.Redis
+			if r.Sends != 1 || r.Commands != tt.sent || r.NilReplies != tt.nils || r.Connections != 1 {
+				t.Errorf("Expected one send of %d commands with %d nil replies on a new connection, got %+v", tt.sent, tt.nils, r)
+			}
+			for prefix, n := range tt.errorReplies {
+				if r.ErrorReplies[prefix] != n {
+					t.Errorf("Expected %d %s error replies, got %v", n, prefix, r.ErrorReplies)
+				}
+			}
+			if len(m.RequestBreakdown) != len(tt.failed) {
+				t.Fatalf("Expected %d commands in the breakdown, got %+v", len(tt.failed), m.RequestBreakdown)
+			}
+			for i, failed := range tt.failed {
+				if b := m.RequestBreakdown[i]; b.Failed != failed {
+					t.Errorf("Expected %d failed %s commands, got %+v", failed, b.Name, b)
+				}
+			}
+		})
+	}
+}
+
+func TestWorkerRedisCommandLatencies(t *testing.T) {
+	server := startRedisServer(t, "")
+
+	tests := []struct {
+		name  string
+		redis model.RedisRequest
+		names []string
+		slow  []bool // Whether each breakdown entry waits for DEBUG SLEEP
+	}{
+		// Each iteration sends one command, so GET never waits for DEBUG SLEEP
+		{"single", model.RedisRequest{Commands: redisCommands("GET a", "DEBUG SLEEP 0.05")},
+			[]string{"0 GET", "1 DEBUG"}, []bool{false, true}},
+		// The GET after DEBUG SLEEP waits for it, but the GET before does not
+		// absorb the pipeline's round trip
+		{"pipeline", model.RedisRequest{Mode: model.RedisModePipeline, Commands: redisCommands("GET a", "DEBUG SLEEP 0.05", "GET b")},
+			[]string{"0 GET", "1 DEBUG", "2 GET"}, []bool{false, true, true}},
+		// Queued commands reply at once; they run when EXEC does
+		{"transaction", model.RedisRequest{Mode: model.RedisModeTransaction, Commands: redisCommands("GET a", "DEBUG SLEEP 0.05")},
+			[]string{"0 GET", "1 DEBUG", "2 EXEC"}, []bool{false, false, true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.redis.Address = server.addr
+			plan := &model.TestPlan{ID: "test-redis-latencies", Redis: &tt.redis}
+			m := model.NewMetrics("run-redis-latencies")
+			worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+			worker.redis = newRedisPool(&tt.redis, nil, nil, 1)
+			t.Cleanup(worker.redis.close)
+			if names := worker.redis.names; strings.Join(names, ",") != strings.Join(tt.names, ",") {
+				t.Fatalf("Expected the breakdown to have an entry per command %v, got %v", tt.names, names)
+			}
+			m.InitRequests(worker.redis.names)
+			worker.requestHists = newHistograms(len(worker.redis.names))
+
+			const iterations = 8
+			for range iterations {
+				worker.executeRequest(context.Background(), Arrival{})
+			}
+
+			var timed int64
+			for i, hist := range worker.requestHists {
+				timed += hist.TotalCount()
+				if hist.TotalCount() == 0 {
+					continue
+				}
+				if tt.slow[i] && hist.MinMs() < 40 {
+					t.Errorf("Expected %s to wait about 50ms, got %.1fms", tt.names[i], hist.MinMs())
+				}
+				if !tt.slow[i] && hist.MaxMs() >= 40 {
+					t.Errorf("Expected %s to be timed on its own, got %.1fms", tt.names[i], hist.MaxMs())
+				}
+			}
+			want := int64(iterations * len(tt.names))
+			if tt.redis.Mode == "" {
+				want = iterations
+			}
+			if timed != want {
+				t.Errorf("Expected %d command latencies, got %d", want, timed)
+			}
+			if m.SuccessRequests != iterations || m.Redis.Sends != iterations || m.Redis.Connections != 1 {
+				t.Errorf("Expected %d sends on one connection, got %d successful and %+v", iterations, m.SuccessRequests, m.Redis)
+			}
+		})
+	}
+}
+
+func TestWorkerRedisWeightedCommands(t *testing.T) {
+	server := startRedisServer(t, "")
+	req := &model.RedisRequest{Address: server.addr, Commands: redisCommands("GET a", "SET a 1")}
+	req.Commands[0].Weight = 9
+	plan := &model.TestPlan{ID: "test-redis-weights", Redis: req}
+	m := model.NewMetrics("run-redis-weights")
+	worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+	worker.redis = newRedisPool(req, nil, nil, 1)
+	defer worker.redis.close()
+	m.InitRequests(worker.redis.names)
+
+	for range 1000 {
+		worker.executeRequest(context.Background(), Arrival{})
+	}
+
+	gets, sets := m.RequestBreakdown[0].Requests, m.RequestBreakdown[1].Requests
+	if gets+sets != 1000 || gets < 800 || gets > 980 {
+		t.Errorf("Expected about 900 GETs and 100 SETs, got %d and %d", gets, sets)
+	}
+	if m.Redis.Sends != 1000 || m.Redis.Commands != 1000 {
+		t.Errorf("Expected 1000 sends of one command, got %+v", m.Redis)
+	}
+}
+
+func TestRedisPool(t *testing.T) {
+	server := startRedisServer(t, "secret")
+	req := &model.RedisRequest{Address: server.addr, DB: 2, PoolSize: 2}
+	pool := newRedisPool(req, &model.Credentials{Password: "secret"}, nil, 8)
+	defer pool.close()
+
+	var wg sync.WaitGroup
+	var failures atomic.Int64
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := pool.send(context.Background(), [][]string{{"PING"}}, 0)
+			if err != nil || result.replies[0] != "PONG" {
+				failures.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if failures.Load() != 0 {
+		t.Errorf("Expected every send to be answered, got %d failures", failures.Load())
+	}
+	if n := server.conns.Load(); n > 2 {
+		t.Errorf("Expected at most pool_size connections, got %d", n)
+	}
+
+	// A connection that cannot authenticate fails its send
+	bad := newRedisPool(&model.RedisRequest{Address: server.addr}, &model.Credentials{Password: "wrong"}, nil, 1)
+	_, err := bad.send(context.Background(), [][]string{{"PING"}}, 0)
+	if !errors.Is(err, model.ErrRedis) || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("Expected a WRONGPASS error, got %v", err)
+	}
+}
+
+func TestReadRESPReply(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // The reply formatted with %v
+	}{
+		{"+OK\r\n", "OK"},
+		{"-ERR unknown command\r\n", "ERR unknown command"},
+		{":-12\r\n", "-12"},
+		{"$5\r\nhello\r\n", "hello"},
+		{"$0\r\n\r\n", ""},
+		{"$-1\r\n", "<nil>"},
+		{"*-1\r\n", "<nil>"},
+		{"*3\r\n:1\r\n$-1\r\n*1\r\n+nested\r\n", "[1 <nil> [nested]]"},
+	}
+	for _, tt := range tests {
+		reply, err := readRESPReply(bufio.NewReader(strings.NewReader(tt.input)))
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", tt.input, err)
+			continue
+		}
+		if got := formatRESPTestReply(reply); got != tt.want {
+			t.Errorf("%q: expected %s, got %s", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"?x\r\n", "+OK\n", ":one\r\n", "$5\r\nhello!!\r\n", "$-2\r\n"} {
+		if _, err := readRESPReply(bufio.NewReader(strings.NewReader(input))); !errors.Is(err, errRESPMalformed) {
+			t.Errorf("%q: expected a malformed reply, got %v", input, err)
+		}
+	}
+}
+
+// formatRESPTestReply formats a reply read by readRESPReply
+func formatRESPTestReply(reply any) string {
+	switch reply := reply.(type) {
+	case nil:
+		return "<nil>"
+	case []any:
+		items := make([]string, len(reply))
+		for i, item := range reply {
+			items[i] = formatRESPTestReply(item)
+		}
+		return "[" + strings.Join(items, " ") + "]"
+	case int64:
+		return strconv.FormatInt(reply, 10)
+	case redisErrorReply:
+		return string(reply)
+	}
+	return reply.(string)
+}
@@ -0,0 +1,123 @@
+package engine
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// usesRetries reports whether a plan or any of its scenario steps has a
+// retry policy
+func usesRetries(plan *model.TestPlan) bool {
+	if plan.Retry != nil {
+		return true
+	}
+	if plan.Scenario != nil {
+		for i := range plan.Scenario.Steps {
+			if plan.Scenario.Steps[i].Retry != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// shouldRetry reports whether a failed attempt is sent again under a retry
+// policy, given the number of attempts made so far. A response that failed
+// a check is only retried when the policy lists its status or check_failed.
+func shouldRetry(policy *model.RetryPolicy, attempts int, class string, statusCode int) bool {
+	if policy == nil || attempts >= policy.MaxAttempts {
+		return false
+	}
+	retryOn, statusCodes := policy.RetryOn, policy.StatusCodes
+	if len(retryOn) == 0 && len(statusCodes) == 0 {
+		retryOn, statusCodes = model.DefaultRetryOn, model.DefaultRetryStatusCodes
+	}
+
+	if statusCode > 0 {
+		for _, code := range statusCodes {
+			if code == statusCode {
+				return true
+			}
+		}
+	}
+	for _, c := range retryOn {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// retryDelay returns the delay before the next attempt, after the given
+// number of attempts. A Retry-After header takes precedence over the
+// backoff unless the policy ignores it; every delay is capped at the
+// policy's maximum.
+func retryDelay(policy *model.RetryPolicy, attempts int, retryAfter string) time.Duration {
+	initial := time.Duration(policy.InitialDelayMs) * time.Millisecond
+	if policy.InitialDelayMs == 0 {
+		initial = model.DefaultRetryInitialDelayMs * time.Millisecond
+	}
+	maxDelay := time.Duration(policy.MaxDelayMs) * time.Millisecond
+	if policy.MaxDelayMs == 0 {
+		maxDelay = model.DefaultRetryMaxDelayMs * time.Millisecond
+	}
+
+	if !policy.IgnoreRetryAfter {
+		if delay, ok := parseRetryAfter(retryAfter, time.Now()); ok {
+			return min(delay, maxDelay)
+		}
+	}
+
+	if policy.Backoff == model.BackoffConstant {
+		return min(initial, maxDelay)
+	}
+	delay := initial
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxDelay)
+	if policy.Backoff == model.BackoffJittered {
+		delay = time.Duration(rand.Int63n(int64(delay) + 1))
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header, in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now), 0), true
+}
+
+// sleepContext waits for d, and reports false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
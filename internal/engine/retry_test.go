@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// flakyServer fails the first n requests of every path with status, then succeeds
+func flakyServer(n int64, status int, retryAfter string) (*httptest.Server, *atomic.Int64) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) <= n {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+		}
+	}))
+	return server, &requests
+}
+
+func TestWorkerRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int64
+		status    int
+		policy    *model.RetryPolicy
+		requests  int64 // Requests the server got
+		success   bool
+		recovered int64
+		exhausted int64
+	}{
+		{"recovered", 2, http.StatusServiceUnavailable, &model.RetryPolicy{MaxAttempts: 3, InitialDelayMs: 1}, 3, true, 1, 0},
+		{"exhausted", 5, http.StatusServiceUnavailable, &model.RetryPolicy{MaxAttempts: 3, InitialDelayMs: 1}, 3, false, 0, 1},
+		{"status not retried", 1, http.StatusNotFound, &model.RetryPolicy{MaxAttempts: 3, InitialDelayMs: 1}, 1, false, 0, 0},
+		{"listed status", 1, http.StatusNotFound, &model.RetryPolicy{MaxAttempts: 3, StatusCodes: []int{404}, InitialDelayMs: 1}, 2, true, 1, 0},
+		{"class replaces defaults", 1, http.StatusServiceUnavailable, &model.RetryPolicy{MaxAttempts: 3, RetryOn: []string{model.ErrorClassTimeout}, InitialDelayMs: 1}, 1, false, 0, 0},
+		{"status class", 1, http.StatusServiceUnavailable, &model.RetryPolicy{MaxAttempts: 3, RetryOn: []string{model.ErrorClassHTTP5xx}, InitialDelayMs: 1}, 2, true, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := flakyServer(tt.failures, tt.status, "")
+			defer server.Close()
+
+			plan := &model.TestPlan{ID: "test-retry", TargetURL: server.URL, Method: "GET", TimeoutMs: 5000, Retry: tt.policy}
+			m := model.NewMetrics("run-retry")
+			worker := NewWorker(1, plan, m, server.Client(), getSharedTestCollector())
+			worker.firstAttemptHist = model.NewHistogram()
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if requests.Load() != tt.requests {
+				t.Errorf("Expected %d attempts, got %d", tt.requests, requests.Load())
+			}
+			if m.TotalRequests != 1 || (m.SuccessRequests == 1) != tt.success {
+				t.Errorf("Expected one request with success %v, got %d requests and %d successful", tt.success, m.TotalRequests, m.SuccessRequests)
+			}
+			retry := m.Retry
+			if retry == nil || retry.Requests != 1 || retry.Retries != tt.requests-1 ||
+				retry.RecoveredRequests != tt.recovered || retry.ExhaustedRequests != tt.exhausted {
+				t.Errorf("Unexpected retry metrics %+v", retry)
+			}
+			if worker.firstAttemptHist.TotalCount() != 1 {
+				t.Errorf("Expected the first attempt to be recorded once, got %d", worker.firstAttemptHist.TotalCount())
+			}
+		})
+	}
+}
+
+func TestWorkerRetriesTransportErrors(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if requests.Add(1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{ID: "test-retry-timeout", TargetURL: server.URL, Method: "GET", TimeoutMs: 50, Retry: &model.RetryPolicy{MaxAttempts: 2, InitialDelayMs: 1}}
+	m := model.NewMetrics("run-retry-timeout")
+	client := &http.Client{Timeout: 50 * time.Millisecond}
+	worker := NewWorker(1, plan, m, client, getSharedTestCollector())
+	worker.firstAttemptHist = model.NewHistogram()
+
+	worker.executeRequest(context.Background(), Arrival{})
+
+	if m.SuccessRequests != 1 || len(m.Errors) != 0 || m.Retry.RecoveredRequests != 1 {
+		t.Errorf("Expected the timed out request to be recovered, got %d successful, errors %v, retry %+v", m.SuccessRequests, m.Errors, m.Retry)
+	}
+	if worker.firstAttemptHist.TotalCount() != 0 {
+		t.Error("Expected a first attempt without a response not to be recorded")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	exponential := &model.RetryPolicy{MaxAttempts: 5, InitialDelayMs: 100, MaxDelayMs: 300}
+	constant := &model.RetryPolicy{MaxAttempts: 5, Backoff: model.BackoffConstant, InitialDelayMs: 100}
+	ignoring := &model.RetryPolicy{MaxAttempts: 5, InitialDelayMs: 100, IgnoreRetryAfter: true}
+
+	tests := []struct {
+		name       string
+		policy     *model.RetryPolicy
+		attempts   int
+		retryAfter string
+		want       time.Duration
+	}{
+		{"first retry", exponential, 1, "", 100 * time.Millisecond},
+		{"doubles", exponential, 2, "", 200 * time.Millisecond},
+		{"capped", exponential, 4, "", 300 * time.Millisecond},
+		{"constant", constant, 3, "", 100 * time.Millisecond},
+		{"default delay", &model.RetryPolicy{MaxAttempts: 2}, 1, "", model.DefaultRetryInitialDelayMs * time.Millisecond},
+		{"retry after seconds", constant, 1, "2", 2 * time.Second},
+		{"retry after capped", exponential, 1, "2", 300 * time.Millisecond},
+		{"invalid retry after", constant, 1, "soon", 100 * time.Millisecond},
+		{"retry after ignored", ignoring, 1, "2", 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.policy, tt.attempts, tt.retryAfter); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	jittered := &model.RetryPolicy{MaxAttempts: 5, Backoff: model.BackoffJittered, InitialDelayMs: 100}
+	for i := 0; i < 100; i++ {
+		if got := retryDelay(jittered, 3, ""); got < 0 || got > 400*time.Millisecond {
+			t.Fatalf("Expected a jittered delay up to 400ms, got %v", got)
+		}
+	}
+}
+
+func TestParseRetryAfterDate(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	if d, ok := parseRetryAfter(now.Add(3*time.Second).Format(http.TimeFormat), now); !ok || d != 3*time.Second {
+		t.Errorf("Expected 3s, got %v (%v)", d, ok)
+	}
+	if d, ok := parseRetryAfter(now.Add(-time.Minute).Format(http.TimeFormat), now); !ok || d != 0 {
+		t.Errorf("Expected a past date to retry at once, got %v (%v)", d, ok)
+	}
+}
+
+func TestWorkerHonorsRetryAfter(t *testing.T) {
+	server, requests := flakyServer(1, http.StatusTooManyRequests, "1")
+	defer server.Close()
+
+	plan := &model.TestPlan{ID: "test-retry-after", TargetURL: server.URL, Method: "GET", TimeoutMs: 5000,
+		Retry: &model.RetryPolicy{MaxAttempts: 2, InitialDelayMs: 1}}
+	m := model.NewMetrics("run-retry-after")
+	worker := NewWorker(1, plan, m, server.Client(), getSharedTestCollector())
+
+	start := time.Now()
+	worker.executeRequest(context.Background(), Arrival{})
+
+	if elapsed := time.Since(start); requests.Load() != 2 || elapsed < time.Second {
+		t.Errorf("Expected the retry to wait for Retry-After, got %d requests after %v", requests.Load(), elapsed)
+	}
+	if m.MaxLatencyMs < 1000 {
+		t.Errorf("Expected the final latency to include the delay, got %.2fms", m.MaxLatencyMs)
+	}
+}
+
+func TestScenarioStepRetries(t *testing.T) {
+	server, requests := flakyServer(2, http.StatusBadGateway, "")
+	defer server.Close()
+
+	scenario := &model.Scenario{
+		ID: "scenario-retry",
+		Steps: []model.Step{
+			{Name: "flaky", Method: "GET", URL: server.URL,
+				Assertions: []model.Assertion{{Type: model.AssertionStatusCode, Value: float64(200)}},
+				Retry:      &model.RetryPolicy{MaxAttempts: 3, InitialDelayMs: 1}},
+		},
+	}
+
+	execution, err := NewScenarioExecutor().Execute(scenario, nil)
+	if err != nil {
+		t.Fatalf("Expected the step to recover, got %v", err)
+	}
+	result := execution.StepResults[0]
+	if requests.Load() != 3 || result.Attempts != 3 || result.StatusCode != http.StatusOK || len(result.AssertionsFailed) != 0 {
+		t.Errorf("Expected 3 attempts ending with a 200, got %+v", result)
+	}
+	if result.FirstAttemptMs <= 0 || result.ResponseTimeMs < result.FirstAttemptMs {
+		t.Errorf("Expected the response time to cover the first attempt, got %+v", result)
+	}
+}
+
+func TestWorkerScenarioStepsUsePlanRetry(t *testing.T) {
+	server, requests := flakyServer(1, http.StatusServiceUnavailable, "")
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:        "test-step-retry",
+		TimeoutMs: 5000,
+		Retry:     &model.RetryPolicy{MaxAttempts: 2, InitialDelayMs: 1},
+		Scenario: &model.Scenario{Steps: []model.Step{
+			{Name: "first", Method: "GET", URL: server.URL},
+			{Name: "second", Method: "GET", URL: server.URL, Retry: &model.RetryPolicy{MaxAttempts: 2, StatusCodes: []int{418}}},
+		}},
+	}
+	m := model.NewMetrics("run-step-retry")
+	m.InitSteps([]string{"first", "second"})
+	worker := NewWorker(1, plan, m, server.Client(), getSharedTestCollector())
+	worker.firstAttemptHist = model.NewHistogram()
+
+	worker.executeRequest(context.Background(), Arrival{})
+
+	if requests.Load() != 3 || m.Iterations != 1 || m.FailedIterations != 0 {
+		t.Errorf("Expected the first step to be retried once, got %d requests and %d failed iterations", requests.Load(), m.FailedIterations)
+	}
+	if m.Retry == nil || m.Retry.Requests != 2 || m.Retry.Retries != 1 || worker.firstAttemptHist.TotalCount() != 2 {
+		t.Errorf("Expected both steps under a retry policy, got %+v", m.Retry)
+	}
+}
@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// windowSpans are the rolling windows published in live metrics, in samples
+// of one second each
+var windowSpans = []struct {
+	name    string
+	samples int
+}{
+	{model.Window1s, 1},
+	{model.Window10s, 10},
+	{model.Window60s, 60},
+}
+
+// windowSample is the cumulative state of a run at one point in time
+type windowSample struct {
+	at       time.Time
+	latency  *model.Histogram
+	requests int64
+	failed   int64
+}
+
+// RollingWindows turns cumulative samples, taken once a second, into stats
+// over the trailing 1s, 10s and 60s of a run. Each window is the difference
+// between the newest sample and the one taken that many samples earlier.
+type RollingWindows struct {
+	samples []windowSample // Oldest first
+}
+
+// NewRollingWindows creates rolling windows for a run that started at start
+func NewRollingWindows(start time.Time) *RollingWindows {
+	maxSamples := windowSpans[len(windowSpans)-1].samples + 1
+	rw := &RollingWindows{samples: make([]windowSample, 0, maxSamples)}
+	rw.samples = append(rw.samples, windowSample{at: start})
+	return rw
+}
+
+// Add records the cumulative state of the run at time at and returns the
+// stats of every window ending there
+func (rw *RollingWindows) Add(at time.Time, latency *model.Histogram, requests, failed int64) map[string]model.WindowStats {
+	if len(rw.samples) == cap(rw.samples) {
+		copy(rw.samples, rw.samples[1:])
+		rw.samples = rw.samples[:len(rw.samples)-1]
+	}
+	rw.samples = append(rw.samples, windowSample{at: at, latency: latency, requests: requests, failed: failed})
+
+	newest := rw.samples[len(rw.samples)-1]
+	stats := make(map[string]model.WindowStats, len(windowSpans))
+	for _, span := range windowSpans {
+		baseIdx := len(rw.samples) - 1 - span.samples
+		if baseIdx < 0 {
+			baseIdx = 0
+		}
+		base := rw.samples[baseIdx]
+
+		window := model.WindowStats{
+			DurationSec: newest.at.Sub(base.at).Seconds(),
+			Requests:    newest.requests - base.requests,
+			Failed:      newest.failed - base.failed,
+		}
+		if window.DurationSec > 0 {
+			window.RPS = float64(window.Requests) / window.DurationSec
+		}
+		if window.Requests > 0 {
+			window.ErrorRate = float64(window.Failed) / float64(window.Requests) * 100
+		}
+		if newest.latency != nil {
+			hist := newest.latency.Since(base.latency)
+			window.P50LatencyMs = hist.ValueAtPercentile(50)
+			window.P95LatencyMs = hist.ValueAtPercentile(95)
+			window.P99LatencyMs = hist.ValueAtPercentile(99)
+		}
+		stats[span.name] = window
+	}
+
+	return stats
+}
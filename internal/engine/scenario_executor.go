@@ -3,6 +3,7 @@ package engine
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,11 +15,19 @@ import (
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 )
 
 // ScenarioExecutor executes multi-step scenarios
 type ScenarioExecutor struct {
-	client *http.Client
+	client    *http.Client
+	templates *TemplateEngine    // Optional; applies {{uuid}}-style functions after variable substitution
+	retry     *model.RetryPolicy // Optional; the plan's policy for steps without their own
+	files     model.BodyFiles    // Body files the steps' body sources send
+	protos    model.ProtoSources // Proto files describing the steps' gRPC calls
+	grpc      *grpcClient        // Sends the gRPC steps' calls; Execute creates one when unset
+	websocket *wsClient          // Opens the WebSocket steps' sessions; Execute creates one when unset
+	db        *sql.DB            // Runs the SQL steps' statements; only load test runs have one
 }
 
 const (
@@ -46,6 +55,22 @@ func NewScenarioExecutor() *ScenarioExecutor {
 	}
 }
 
+// WithBodyFiles returns a copy of the executor whose steps can send the
+// given body files
+func (e *ScenarioExecutor) WithBodyFiles(files model.BodyFiles) *ScenarioExecutor {
+	executor := *e
+	executor.files = files
+	return &executor
+}
+
+// WithProtos returns a copy of the executor whose gRPC steps are described
+// by the given proto files
+func (e *ScenarioExecutor) WithProtos(protos model.ProtoSources) *ScenarioExecutor {
+	executor := *e
+	executor.protos = protos
+	return &executor
+}
+
 // Execute runs a scenario and returns the execution result
 func (e *ScenarioExecutor) Execute(scenario *model.Scenario, initialVars model.Variables) (*model.ScenarioExecution, error) {
 	execution := &model.ScenarioExecution{
@@ -70,13 +95,34 @@ func (e *ScenarioExecutor) Execute(scenario *model.Scenario, initialVars model.V
 		zap.String("execution_id", execution.ID),
 		zap.Int("steps", len(scenario.Steps)))
 
+	// Cookies set by one step are sent by the following ones, as in a
+	// browser session, but never leak into other executions
+	session := &ScenarioExecutor{client: withCookieJar(e.client), templates: e.templates, retry: e.retry, files: e.files, protos: e.protos, grpc: e.grpc, websocket: e.websocket, db: e.db}
+	if session.grpc == nil && hasGRPCSteps(scenario) {
+		session.grpc = newGRPCClient(e.protos, nil)
+		defer session.grpc.close()
+	}
+	if session.websocket == nil {
+		if reqs := scenarioWebSocketRequests(scenario); len(reqs) > 0 {
+			client, err := newWSClient(nil, reqs...)
+			if err != nil {
+				execution.Status = model.StatusFailed
+				execution.Error = fmt.Sprintf("Invalid WebSocket step: %v", err)
+				now := time.Now()
+				execution.CompletedAt = &now
+				return execution, err
+			}
+			session.websocket = client
+		}
+	}
+
 	// Execute each step
 	for i, step := range scenario.Steps {
 		logger.Log.Debug("Executing step",
 			zap.Int("step_index", i+1),
 			zap.String("step_name", step.Name))
 
-		stepResult, err := e.executeStep(&step, execution.Variables)
+		stepResult, err := session.executeStep(context.Background(), &step, execution.Variables)
 		execution.StepResults = append(execution.StepResults, *stepResult)
 
 		if err != nil {
@@ -122,7 +168,7 @@ func (e *ScenarioExecutor) Execute(scenario *model.Scenario, initialVars model.V
 }
 
 // executeStep executes a single step and returns the result
-func (e *ScenarioExecutor) executeStep(step *model.Step, vars model.Variables) (*model.StepResult, error) {
+func (e *ScenarioExecutor) executeStep(ctx context.Context, step *model.Step, vars model.Variables) (*model.StepResult, error) {
 	result := &model.StepResult{
 		StepName:    step.Name,
 		ExecutedAt:  time.Now(),
@@ -144,89 +190,308 @@ func (e *ScenarioExecutor) executeStep(step *model.Step, vars model.Variables) (
 	for k, v := range step.Headers {
 		headers[k] = e.substituteVariables(v, vars)
 	}
-	body := e.substituteVariables(step.Body, vars)
+	process := func(s string) string {
+		s = e.substituteVariables(s, vars)
+		if e.templates != nil {
+			s = e.templates.Process(s)
+		}
+		return s
+	}
+	if step.GRPC != nil {
+		return e.executeGRPCStep(ctx, step, process, vars, result)
+	}
+	if step.WebSocket != nil {
+		return e.executeWebSocketStep(ctx, step, process, vars, result)
+	}
+	if step.SQL != nil {
+		return e.executeSQLStep(ctx, step, process, vars, result)
+	}
+	if e.templates != nil {
+		url = e.templates.Process(url)
+		headers = e.templates.ProcessMap(headers)
+	}
+	body, contentType, err := buildBody(process(step.Body), step.BodySource, e.files, process)
+	if err != nil {
+		result.Status = statusFailed
+		result.Error = fmt.Sprintf("failed to build request body: %v", err)
+		return result, err
+	}
+
+	// Send the request, again under the step's retry policy or the plan's
+	policy := e.retry
+	if step.Retry != nil {
+		policy = step.Retry
+	}
+	startTime := time.Now()
+	var (
+		sent     *stepAttempt
+		attempts int
+	)
+	for {
+		attempts++
+		result.Error, result.StatusCode, result.Timing, result.AssertionsFailed = "", 0, nil, nil
+		sent, err = e.sendStep(ctx, step, url, headers, body, contentType, result)
+		if attempts == 1 && policy != nil && err == nil {
+			result.FirstAttemptMs = durationMs(sent.doneAt.Sub(startTime))
+		}
+
+		class, retryAfter := "", ""
+		if err != nil {
+			class = model.ClassifyError(err, 0)
+		} else {
+			result.AssertionsFailed = failedAssertions(step.Assertions, sent.resp, sent.body, sent.elapsed.Milliseconds())
+			if len(result.AssertionsFailed) == 0 && sent.resp.StatusCode < 400 {
+				break
+			}
+			class = model.ClassifyError(nil, sent.resp.StatusCode)
+			if len(result.AssertionsFailed) > 0 {
+				class = model.ErrorClassCheckFailed
+			}
+			retryAfter = sent.resp.Header.Get("Retry-After")
+		}
+		if !shouldRetry(policy, attempts, class, result.StatusCode) ||
+			!sleepContext(ctx, retryDelay(policy, attempts, retryAfter)) {
+			break
+		}
+	}
+	if policy != nil {
+		result.Attempts = attempts
+	}
+	result.ResponseTimeMs = durationMs(sent.doneAt.Sub(startTime))
+	if err != nil {
+		return result, err
+	}
+
+	// Extract variables from the final response
+	e.extract(step, sent.resp, sent.body, vars, result)
+
+	if len(result.AssertionsFailed) > 0 {
+		result.Status = statusFailed
+	} else {
+		result.Status = statusSuccess
+	}
+
+	return result, nil
+}
+
+// executeGRPCStep sends a step's gRPC call. A call answered with a non-OK
+// status fails the step like an HTTP error status does, but still has its
+// assertions and extractions applied.
+func (e *ScenarioExecutor) executeGRPCStep(ctx context.Context, step *model.Step, process func(string) string, vars model.Variables, result *model.StepResult) (*model.StepResult, error) {
+	if e.grpc == nil {
+		result.Status = statusFailed
+		result.Error = errNoGRPCClient.Error()
+		return result, errNoGRPCClient
+	}
+	md := make(map[string]string, len(step.GRPC.Metadata))
+	for k, v := range step.GRPC.Metadata {
+		md[k] = process(v)
+	}
+
+	ctx, cancel := withCallTimeout(ctx, step.TimeoutMs, 30*time.Second)
+	defer cancel()
+	startTime := time.Now()
+	resp, err := e.grpc.call(ctx, step.GRPC, process(step.GRPC.Message), md)
+	elapsed := time.Since(startTime)
+	result.ResponseTimeMs = durationMs(elapsed)
+	if resp != nil {
+		result.GRPCStatus = grpcCodeName(resp.code)
+		err = resp.err()
+	}
+	if err != nil {
+		result.Status = statusFailed
+		result.Error = fmt.Sprintf("call failed: %v", err)
+		return result, err
+	}
+
+	httpResp := resp.response()
+	result.AssertionsFailed = failedAssertions(step.Assertions, httpResp, resp.body, elapsed.Milliseconds())
+	e.extract(step, httpResp, resp.body, vars, result)
+
+	result.Status = statusSuccess
+	if resp.code != codes.OK {
+		result.Error = resp.message
+		result.Status = statusFailed
+	} else if len(result.AssertionsFailed) > 0 {
+		result.Status = statusFailed
+	}
+	return result, nil
+}
+
+// executeWebSocketStep runs a step's WebSocket session. Its response time is
+// the handshake time, and assertions and extractions apply to the handshake
+// response and the last reply the session matched. A handshake the server
+// rejects fails the step like an HTTP error status does.
+func (e *ScenarioExecutor) executeWebSocketStep(ctx context.Context, step *model.Step, process func(string) string, vars model.Variables, result *model.StepResult) (*model.StepResult, error) {
+	sent := e.websocket.run(ctx, e.client.Jar, step.WebSocket, process, time.Duration(step.TimeoutMs)*time.Millisecond)
+	result.WebSocket = sent.session
+	result.StatusCode = sent.statusCode()
+	result.ResponseTimeMs = sent.session.ConnectMs
+
+	switch {
+	case !sent.connected && result.StatusCode > 0:
+		result.Status = statusFailed
+		result.Error = fmt.Sprintf("handshake rejected with status %d", result.StatusCode)
+		result.AssertionsFailed = failedAssertions(step.Assertions, sent.resp, nil, int64(result.ResponseTimeMs))
+		return result, nil
+	case sent.err != nil:
+		result.Status = statusFailed
+		result.Error = fmt.Sprintf("session failed: %v", sent.err)
+		return result, sent.err
+	}
+
+	result.AssertionsFailed = failedAssertions(step.Assertions, sent.resp, sent.reply, int64(result.ResponseTimeMs))
+	e.extract(step, sent.resp, sent.reply, vars, result)
+	result.Status = statusSuccess
+	if len(result.AssertionsFailed) > 0 {
+		result.Status = statusFailed
+	}
+	return result, nil
+}
+
+// executeSQLStep runs a step's statement on the plan's database, binding
+// its arguments from the variables, e.g. one an earlier step extracted.
+// Assertions and extractions apply to the statement's result, such as
+// $.row.id for the first row's id column.
+func (e *ScenarioExecutor) executeSQLStep(ctx context.Context, step *model.Step, process func(string) string, vars model.Variables, result *model.StepResult) (*model.StepResult, error) {
+	if e.db == nil {
+		result.Status = statusFailed
+		result.Error = errNoSQLDatabase.Error()
+		return result, errNoSQLDatabase
+	}
+
+	startTime := time.Now()
+	sent, err := runSQL(ctx, e.db, step.SQL, process, step.TimeoutMs)
+	elapsed := time.Since(startTime)
+	result.ResponseTimeMs = durationMs(elapsed)
+	if err != nil {
+		result.Status = statusFailed
+		result.Error = fmt.Sprintf("statement failed: %v", err)
+		return result, err
+	}
+	result.RowsReturned, result.RowsAffected = sent.returned, sent.affected
 
+	resp, body := sent.response(), sent.body()
+	result.AssertionsFailed = failedAssertions(step.Assertions, resp, body, elapsed.Milliseconds())
+	e.extract(step, resp, body, vars, result)
+	result.Status = statusSuccess
+	if len(result.AssertionsFailed) > 0 {
+		result.Status = statusFailed
+	}
+	return result, nil
+}
+
+// extract sets the variables a step extracts from its response
+func (e *ScenarioExecutor) extract(step *model.Step, resp *http.Response, body []byte, vars model.Variables, result *model.StepResult) {
+	for _, extraction := range step.Extractions {
+		value, err := e.extractVariable(&extraction, resp, body)
+		if err != nil {
+			logger.Log.Warn("Failed to extract variable",
+				zap.String("variable", extraction.Name),
+				zap.Error(err))
+			continue
+		}
+		vars[extraction.Name] = value
+		result.Extractions[extraction.Name] = value
+	}
+}
+
+// hasGRPCSteps reports whether any step of a scenario sends a gRPC call
+func hasGRPCSteps(scenario *model.Scenario) bool {
+	for i := range scenario.Steps {
+		if scenario.Steps[i].GRPC != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSQLSteps reports whether any step of a scenario runs an SQL statement
+func hasSQLSteps(scenario *model.Scenario) bool {
+	for i := range scenario.Steps {
+		if scenario.Steps[i].SQL != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// stepAttempt is the response to one attempt at a step's request. Its body
+// has been read and closed.
+type stepAttempt struct {
+	resp    *http.Response
+	body    []byte
+	elapsed time.Duration // Until the response headers arrived
+	doneAt  time.Time     // When the response headers arrived or the attempt failed
+}
+
+// sendStep sends a step's request once and reads its response. A failure is
+// recorded in result, which otherwise gets the status code and timing of
+// the response.
+func (e *ScenarioExecutor) sendStep(ctx context.Context, step *model.Step, url string, headers map[string]string, body []byte, contentType string, result *model.StepResult) (*stepAttempt, error) {
 	// Create HTTP request
 	var bodyReader io.Reader
-	if body != "" {
-		bodyReader = bytes.NewBufferString(body)
+	if len(body) > 0 {
+		bodyReader = bytes.NewReader(body)
 	}
 
 	req, err := http.NewRequest(step.Method, url, bodyReader)
 	if err != nil {
 		result.Status = statusFailed
 		result.Error = fmt.Sprintf("failed to create request: %v", err)
-		return result, err
+		return &stepAttempt{doneAt: time.Now()}, err
 	}
 
 	// Set headers
 	for k, v := range headers {
 		req.Header.Set(k, v)
 	}
+	setContentType(req.Header, contentType)
 
 	// Set timeout
 	timeout := 30 * time.Second
 	if step.TimeoutMs > 0 {
 		timeout = time.Duration(step.TimeoutMs) * time.Millisecond
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	ctx, trace := withRequestTrace(ctx)
 	req = req.WithContext(ctx)
 
 	// Execute request
 	startTime := time.Now()
 	resp, err := e.client.Do(req)
-	responseTime := time.Since(startTime).Milliseconds()
-	result.ResponseTimeMs = float64(responseTime)
+	sent := &stepAttempt{resp: resp, doneAt: time.Now()}
+	sent.elapsed = sent.doneAt.Sub(startTime)
 
 	if err != nil {
 		result.Status = statusFailed
 		result.Error = fmt.Sprintf("request failed: %v", err)
-		return result, err
+		return sent, err
 	}
 	defer resp.Body.Close()
+	result.StatusCode = resp.StatusCode
 
 	// Read response body
-	responseBody, err := io.ReadAll(resp.Body)
+	sent.body, err = io.ReadAll(resp.Body)
 	if err != nil {
 		result.Status = statusFailed
 		result.Error = fmt.Sprintf("failed to read response: %v", err)
-		return result, err
-	}
-
-	result.StatusCode = resp.StatusCode
-
-	// Extract variables
-	if len(step.Extractions) > 0 {
-		for _, extraction := range step.Extractions {
-			value, err := e.extractVariable(&extraction, resp, responseBody)
-			if err != nil {
-				logger.Log.Warn("Failed to extract variable",
-					zap.String("variable", extraction.Name),
-					zap.Error(err))
-				continue
-			}
-			vars[extraction.Name] = value
-			result.Extractions[extraction.Name] = value
-		}
+		return sent, err
 	}
+	result.Timing = trace.timing(time.Now())
+	return sent, nil
+}
 
-	// Run assertions
-	result.AssertionsFailed = make([]string, 0)
-	for _, assertion := range step.Assertions {
-		if !e.evaluateAssertion(&assertion, resp, responseBody, responseTime) {
-			failMsg := fmt.Sprintf("%s %s %v", assertion.Type, assertion.Operator, assertion.Value)
-			result.AssertionsFailed = append(result.AssertionsFailed, failMsg)
+// failedAssertions returns the labels of the assertions a response fails
+func failedAssertions(assertions []model.Assertion, resp *http.Response, body []byte, responseTimeMs int64) []string {
+	failed := make([]string, 0)
+	for i := range assertions {
+		if !evaluateAssertion(&assertions[i], resp, body, responseTimeMs) {
+			failed = append(failed, assertions[i].Label())
 		}
 	}
-
-	if len(result.AssertionsFailed) > 0 {
-		result.Status = statusFailed
-	} else {
-		result.Status = statusSuccess
-	}
-
-	return result, nil
+	return failed
 }
 
 // substituteVariables replaces {{variable}} placeholders with values
@@ -269,7 +534,7 @@ func (e *ScenarioExecutor) extractVariable(extraction *model.VariableExtraction,
 			return nil, fmt.Errorf("invalid JSON response: %w", err)
 		}
 		// Simple JSONPath implementation (supports basic dot notation)
-		value := e.extractJSONPath(data, extraction.Path)
+		value := extractJSONPath(data, extraction.Path)
 		if value == nil {
 			return nil, fmt.Errorf("JSONPath not found: %s", extraction.Path)
 		}
@@ -280,73 +545,6 @@ func (e *ScenarioExecutor) extractVariable(extraction *model.VariableExtraction,
 	}
 }
 
-// extractJSONPath extracts value using simple dot notation (e.g., "data.id")
-func (e *ScenarioExecutor) extractJSONPath(data interface{}, path string) interface{} {
-	if path == "" {
-		return data
-	}
-
-	parts := strings.Split(path, ".")
-	current := data
-
-	for _, part := range parts {
-		switch v := current.(type) {
-		case map[string]interface{}:
-			current = v[part]
-		default:
-			return nil
-		}
-		if current == nil {
-			return nil
-		}
-	}
-
-	return current
-}
-
-// evaluateAssertion checks if an assertion passes
-func (e *ScenarioExecutor) evaluateAssertion(assertion *model.Assertion, resp *http.Response, body []byte, responseTimeMs int64) bool {
-	switch assertion.Type {
-	case model.AssertionStatusCode:
-		expected, ok := assertion.Value.(float64) // JSON numbers are float64
-		if !ok {
-			return false
-		}
-		return resp.StatusCode == int(expected)
-
-	case model.AssertionResponseTime:
-		maxTime, ok := assertion.Value.(float64)
-		if !ok {
-			return false
-		}
-		return responseTimeMs <= int64(maxTime)
-
-	case model.AssertionBodyContains:
-		expected, ok := assertion.Value.(string)
-		if !ok {
-			return false
-		}
-		return strings.Contains(string(body), expected)
-
-	case model.AssertionHeader:
-		headerValue := resp.Header.Get(assertion.Target)
-		expected := fmt.Sprintf("%v", assertion.Value)
-		return e.compareValues(headerValue, assertion.Operator, expected)
-
-	case model.AssertionJSONPath:
-		var data interface{}
-		if err := json.Unmarshal(body, &data); err != nil {
-			return false
-		}
-		value := e.extractJSONPath(data, assertion.Target)
-		expected := assertion.Value
-		return e.compareValues(value, assertion.Operator, expected)
-
-	default:
-		return false
-	}
-}
-
 // evaluateCondition checks if a condition is met
 func (e *ScenarioExecutor) evaluateCondition(cond *model.Condition, vars model.Variables) bool {
 	value, exists := vars[cond.Variable]
@@ -360,33 +558,12 @@ func (e *ScenarioExecutor) evaluateCondition(cond *model.Condition, vars model.V
 		if !exists {
 			return false
 		}
-		return e.compareValues(value, "eq", cond.Value)
+		return compareValues(value, "eq", cond.Value)
 	case "ne":
 		if !exists {
 			return true
 		}
-		return !e.compareValues(value, "eq", cond.Value)
-	default:
-		return false
-	}
-}
-
-// compareValues compares two values using an operator
-func (e *ScenarioExecutor) compareValues(left interface{}, operator string, right interface{}) bool {
-	leftStr := fmt.Sprintf("%v", left)
-	rightStr := fmt.Sprintf("%v", right)
-
-	switch operator {
-	case "eq":
-		return leftStr == rightStr
-	case "ne":
-		return leftStr != rightStr
-	case "contains":
-		return strings.Contains(leftStr, rightStr)
-	case "gt":
-		return leftStr > rightStr
-	case "lt":
-		return leftStr < rightStr
+		return !compareValues(value, "eq", cond.Value)
 	default:
 		return false
 	}
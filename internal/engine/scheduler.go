@@ -2,8 +2,10 @@ package engine
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"math"
 	"net/http"
-	"sort"
 	"sync"
 	"time"
 
@@ -13,16 +15,54 @@ import (
 	"go.uber.org/zap"
 )
 
+// arrivalTick is the resolution at which scheduled arrivals are released
+const arrivalTick = time.Millisecond
+
+// vuControlInterval is how often the VU pool is resized to follow a load profile
+const vuControlInterval = 100 * time.Millisecond
+
 // Scheduler manages the execution of a test run with workers and rate control
 type Scheduler struct {
 	plan         *model.TestPlan
 	metrics      *model.Metrics
-	workers      []*Worker
+	workers      []*Worker            // Every worker started, including stopped ones
+	vus          []context.CancelFunc // Stops each running VU, most recently started last
+	stopped      bool                 // No VUs may be started once the test duration is over
+	workersMu    sync.Mutex
+	requestChan  chan Arrival
 	cancel       context.CancelFunc
-	wg           sync.WaitGroup
+	wg           sync.WaitGroup // Running VUs
+	tracking     sync.WaitGroup // Rolling-window tracker
 	ctx          context.Context
+	loadCtx      context.Context    // Ends with the test duration, before the graceful stop period
+	stopLoad     context.CancelFunc // Ends loadCtx early
+	rampDown     func(elapsed time.Duration) float64
 	sharedClient *http.Client
 	collector    *metrics.Collector
+	stepHists    []*model.Histogram       // Per-step service times for scenario plans
+	requestHists []*model.Histogram       // Per-request service times for request mix plans
+	phaseHists   []*model.Histogram       // Per-phase request timing, indexed like model.TimingPhases
+	feeder       *Feeder                  // Shared by all workers for plans with a data feed
+	grpc         *grpcClient              // Shared by all workers for plans and scenarios with gRPC calls
+	websocket    *wsClient                // Shared by all workers for plans and scenarios with WebSocket sessions
+	used         []protocol               // Registered protocols the plan uses
+	stats        model.ProtocolHistograms // Shared histograms of the protocols the plan uses
+	db           *sql.DB                  // Connection pool shared by all workers for plans and scenarios with SQL statements
+	redis        *redisPool               // Connection pool shared by all workers for plans with Redis commands
+	exhausted    sync.Once
+
+	// VU pool bounds, protected by workersMu. Control commands adjust them.
+	vuLimit   int  // Upper bound of the VU pool
+	manualVUs bool // A control command set the VU count; the load profile no longer sizes the pool
+
+	// Live control state, protected by controlMu
+	controlMu   sync.Mutex
+	paused      bool
+	resumed     chan struct{} // Closed when the current pause ends
+	rpsOverride *float64      // Fixed rate set by a control command, nil if none
+	started     time.Time
+	end         time.Time   // When the test duration is over
+	endTimer    *time.Timer // Ends loadCtx at end
 }
 
 // NewScheduler creates a new scheduler for a test plan
@@ -38,44 +78,318 @@ func NewScheduler(plan *model.TestPlan, metrics *model.Metrics, sharedClient *ht
 
 // Start begins the test execution
 func (s *Scheduler) Start() error {
-	s.ctx, s.cancel = context.WithTimeout(context.Background(), time.Duration(s.plan.DurationSec)*time.Second)
+	tlsConfig, err := buildTLSConfig(s.plan.TLS, s.plan.TLSMaterial)
+	if err != nil {
+		return fmt.Errorf("invalid TLS settings: %w", err)
+	}
+	s.vuLimit = s.maxVUs()
+
+	// Every protocol the plan uses gets its metrics section, and the clients
+	// and histograms its workers share
+	s.stats = make(model.ProtocolHistograms)
+	for _, p := range protocols {
+		hists, ok, err := p.open(s, tlsConfig)
+		if err != nil {
+			s.closeProtocols()
+			return err
+		}
+		if !ok {
+			continue
+		}
+		s.used = append(s.used, p)
+		if len(hists) > 0 {
+			s.stats[p.name()] = hists
+		}
+	}
+
+	// No new iterations start once the test duration is over, but those in
+	// flight get the graceful stop period to finish. The end of the test can
+	// be moved by control commands, so it is a timer rather than a deadline.
+	duration := time.Duration(s.plan.DurationSec) * time.Second
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.loadCtx, s.stopLoad = context.WithCancel(s.ctx)
+	s.started = time.Now()
+	s.end = s.started.Add(duration)
+	s.endTimer = time.AfterFunc(duration, s.stopLoad)
+	rampDown := time.Duration(s.plan.RampDownSec) * time.Second
+	s.rampDown = func(elapsed time.Duration) float64 {
+		return rampDownFactor(s.duration(), rampDown)(elapsed)
+	}
+
+	// Plans with a transport or TLS section get a client of their own
+	// instead of the generator's shared pool
+	if s.ownsClient() {
+		s.sharedClient = newPlanClient(s.plan.Transport, tlsConfig, s.vuLimit)
+	}
 
 	logger.Log.Info("Starting test execution",
 		zap.String("plan_id", s.plan.ID),
+		zap.String("executor", string(s.executor())),
 		zap.Int("users", s.plan.Users),
+		zap.Int("max_vus", s.maxVUs()),
 		zap.Int("duration_sec", s.plan.DurationSec),
-		zap.Int("ramp_up_sec", s.plan.RampUpSec))
+		zap.Int("ramp_up_sec", s.plan.RampUpSec),
+		zap.Int("ramp_down_sec", s.plan.RampDownSec),
+		zap.Int("graceful_stop_sec", s.plan.GracefulStopSec))
+
+	// Every request is broken down into timing phases
+	s.phaseHists = make([]*model.Histogram, len(model.TimingPhases))
+	for i := range s.phaseHists {
+		s.phaseHists[i] = model.NewHistogram()
+	}
 
-	// Create request channel for rate control
-	requestChan := make(chan struct{}, s.plan.Users*10)
+	// Scenario plans break metrics down per step
+	if s.plan.Scenario != nil {
+		names := make([]string, len(s.plan.Scenario.Steps))
+		s.stepHists = make([]*model.Histogram, len(s.plan.Scenario.Steps))
+		for i, step := range s.plan.Scenario.Steps {
+			names[i] = step.Name
+			s.stepHists[i] = model.NewHistogram()
+		}
+		s.metrics.InitSteps(names)
+	}
 
-	// Start workers with ramp-up
-	go s.startWorkersWithRampUp(requestChan)
+	// Request mix plans break metrics down per named request, SQL plans per
+	// named statement and Redis plans per command
+	var names []string
+	for _, r := range s.plan.Requests {
+		names = append(names, r.Name)
+	}
+	if s.plan.SQL != nil {
+		for _, stmt := range s.plan.SQL.Statements {
+			names = append(names, stmt.Name)
+		}
+	}
+	if s.redis != nil {
+		names = append(names, s.redis.names...)
+	}
+	if len(names) > 0 {
+		s.requestHists = make([]*model.Histogram, len(names))
+		for i := range names {
+			s.requestHists[i] = model.NewHistogram()
+		}
+		s.metrics.InitRequests(names)
+	}
+
+	// Plans with response or event checks report a pass rate per check
+	if checks := planChecks(s.plan); len(checks) > 0 {
+		names := make([]string, len(checks))
+		for i := range checks {
+			names[i] = checks[i].Label()
+		}
+		s.metrics.InitChecks(names)
+	}
+
+	// Plans with a data feed hand each iteration a data set row
+	if s.plan.DataSet != nil {
+		feed := s.plan.Data
+		if feed == nil && s.plan.Scenario != nil {
+			feed = s.plan.Scenario.Data
+		}
+		s.feeder = NewFeeder(s.plan.DataSet, feed)
+	}
+
+	// Create request channel for rate control. The open model hands arrivals
+	// directly to idle VUs, so it must not queue them behind busy ones.
+	if s.executor() == model.ExecutorArrivalRate {
+		s.requestChan = make(chan Arrival)
+	} else {
+		s.requestChan = make(chan Arrival, s.plan.Users*10)
+	}
+
+	// Start workers with ramp-up, or let the load profile size the pool
+	if s.controlsVUs() {
+		go s.controlVUs()
+	} else {
+		go s.startWorkersWithRampUp()
+	}
 
 	// Start request generator
-	go s.generateRequestsWithPattern(requestChan)
+	go s.generateRequestsWithPattern()
 
 	// Start metrics reporter
 	go s.reportMetrics()
 
+	// Start rolling-window tracker for live percentiles. It is waited on
+	// before the final metrics are computed so it cannot overwrite them.
+	s.tracking.Add(1)
+	go s.trackWindows()
+
 	return nil
 }
 
+// stopDataExhausted ends the test early because its data feed has no rows left
+func (s *Scheduler) stopDataExhausted() {
+	s.exhausted.Do(func() {
+		logger.Log.Info("Data set exhausted, stopping test",
+			zap.String("plan_id", s.plan.ID),
+			zap.String("data_set_id", s.plan.DataSet.ID))
+		s.stopLoad()
+	})
+}
+
+// executor returns the plan's executor, defaulting to the closed model
+func (s *Scheduler) executor() model.ExecutorType {
+	if s.plan.Executor == "" {
+		return model.ExecutorClosed
+	}
+	return s.plan.Executor
+}
+
+// maxVUs returns the upper bound of the VU pool
+func (s *Scheduler) maxVUs() int {
+	if s.executor() == model.ExecutorArrivalRate && s.plan.MaxVUs > s.plan.Users {
+		return s.plan.MaxVUs
+	}
+	maxVUs := s.plan.Users
+	if s.plan.RatePattern == model.RatePatternStages {
+		for _, stage := range s.plan.Stages {
+			if stage.TargetVUs != nil && *stage.TargetVUs > maxVUs {
+				maxVUs = *stage.TargetVUs
+			}
+		}
+	}
+	return maxVUs
+}
+
+// spawnWorker adds a worker to the pool and starts its request loop.
+// When first is set the worker executes that arrival before it starts
+// pulling from the request channel. It returns the new pool size, or 0 if
+// the pool is already at its maximum.
+func (s *Scheduler) spawnWorker(first *Arrival) int {
+	s.workersMu.Lock()
+	if s.stopped || len(s.vus) >= s.vuLimit {
+		s.workersMu.Unlock()
+		return 0
+	}
+	client := s.sharedClient
+	if s.plan.Transport != nil && s.plan.Transport.PoolPerVU {
+		client = withOwnPool(client)
+	}
+	worker := NewWorker(len(s.workers), s.plan, s.metrics, client, s.collector)
+	worker.stepHists = s.stepHists
+	worker.requestHists = s.requestHists
+	worker.phaseHists = s.phaseHists
+	for _, p := range s.used {
+		p.attach(s, worker, s.stats[p.name()])
+	}
+	if s.feeder != nil {
+		worker.feeder = s.feeder
+		worker.stop = s.stopDataExhausted
+	}
+	vuCtx, stopVU := context.WithCancel(s.loadCtx)
+	s.workers = append(s.workers, worker)
+	s.vus = append(s.vus, stopVU)
+	count := len(s.vus)
+	s.wg.Add(1)
+	s.workersMu.Unlock()
+
+	go func(w *Worker) {
+		defer s.wg.Done()
+		defer stopVU()
+		if first != nil {
+			w.executeRequest(s.ctx, *first)
+		}
+		w.RunUntil(s.ctx, vuCtx.Done(), s.requestChan)
+	}(worker)
+
+	return count
+}
+
+// stopWorkers stops the n most recently started VUs and returns the
+// resulting pool size. Stopped VUs finish their current iteration first.
+func (s *Scheduler) stopWorkers(n int) int {
+	s.workersMu.Lock()
+	if n > len(s.vus) {
+		n = len(s.vus)
+	}
+	for _, stopVU := range s.vus[len(s.vus)-n:] {
+		stopVU()
+	}
+	s.vus = s.vus[:len(s.vus)-n]
+	count := len(s.vus)
+	s.workersMu.Unlock()
+
+	s.metrics.SetActiveWorkers(count)
+	s.collector.SetActiveWorkers(count)
+	return count
+}
+
+// controlsVUs reports whether the load profile sizes the VU pool over time
+// instead of the one-off ramp-up. The arrival_rate executor always sizes its
+// pool from the arrival rate.
+func (s *Scheduler) controlsVUs() bool {
+	if s.executor() != model.ExecutorClosed {
+		return false
+	}
+	if s.plan.RatePattern == model.RatePatternStages && model.StagesSetVUs(s.plan.Stages) {
+		return true
+	}
+	return s.plan.RampDownSec > 0
+}
+
+// vuTarget returns the VU count the load profile asks for over time
+func (s *Scheduler) vuTarget() func(elapsed time.Duration) int {
+	users := float64(s.plan.Users)
+	rampUp := time.Duration(s.plan.RampUpSec) * time.Second
+	base := func(elapsed time.Duration) float64 {
+		if elapsed >= rampUp {
+			return users
+		}
+		return users * float64(elapsed) / float64(rampUp)
+	}
+	if s.plan.RatePattern == model.RatePatternStages && model.StagesSetVUs(s.plan.Stages) {
+		base = stagesVUs(s.plan.Stages, s.plan.Users)
+	}
+
+	return func(elapsed time.Duration) int {
+		return int(math.Round(base(elapsed) * s.rampDown(elapsed)))
+	}
+}
+
+// controlVUs starts and stops VUs so the pool follows the load profile
+func (s *Scheduler) controlVUs() {
+	target := s.vuTarget()
+	start := time.Now()
+	s.scaleVUs(target(0))
+
+	ticker := time.NewTicker(vuControlInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.loadCtx.Done():
+			return
+		case now := <-ticker.C:
+			if s.vusSetManually() {
+				return
+			}
+			s.scaleVUs(target(now.Sub(start)))
+		}
+	}
+}
+
+// scaleVUs grows or shrinks the VU pool to n running VUs
+func (s *Scheduler) scaleVUs(n int) {
+	s.workersMu.Lock()
+	running := len(s.vus)
+	s.workersMu.Unlock()
+
+	switch {
+	case n > running:
+		s.startWorkers(n - running)
+	case n < running:
+		count := s.stopWorkers(running - n)
+		logger.Log.Debug("Workers ramped down",
+			zap.Int("active_workers", count))
+	}
+}
+
 // startWorkersWithRampUp gradually spawns workers according to ramp-up time
-func (s *Scheduler) startWorkersWithRampUp(requestChan <-chan struct{}) {
+func (s *Scheduler) startWorkersWithRampUp() {
 	if s.plan.RampUpSec == 0 {
 		// No ramp-up, start all workers immediately
-		for i := 0; i < s.plan.Users; i++ {
-			worker := NewWorker(i, s.plan, s.metrics, s.sharedClient, s.collector)
-			s.workers = append(s.workers, worker)
-			s.wg.Add(1)
-			go func(w *Worker) {
-				defer s.wg.Done()
-				w.Run(s.ctx, requestChan)
-			}(worker)
-		}
-		s.metrics.SetActiveWorkers(s.plan.Users)
-		s.collector.SetActiveWorkers(s.plan.Users)
+		s.startWorkers(s.plan.Users)
 		logger.Log.Info("All workers started immediately",
 			zap.Int("workers", s.plan.Users))
 		return
@@ -92,17 +406,7 @@ func (s *Scheduler) startWorkersWithRampUp(requestChan <-chan struct{}) {
 	// If the calculation results in starting all workers in the first interval,
 	// start them immediately instead of waiting for the first ticker tick.
 	if workersPerInterval >= s.plan.Users {
-		for i := 0; i < s.plan.Users; i++ {
-			worker := NewWorker(i, s.plan, s.metrics, s.sharedClient, s.collector)
-			s.workers = append(s.workers, worker)
-			s.wg.Add(1)
-			go func(w *Worker) {
-				defer s.wg.Done()
-				w.Run(s.ctx, requestChan)
-			}(worker)
-		}
-		s.metrics.SetActiveWorkers(s.plan.Users)
-		s.collector.SetActiveWorkers(s.plan.Users)
+		s.startWorkers(s.plan.Users)
 		logger.Log.Info("All workers started immediately (ramp-up calculation)",
 			zap.Int("workers", s.plan.Users))
 		return
@@ -111,31 +415,29 @@ func (s *Scheduler) startWorkersWithRampUp(requestChan <-chan struct{}) {
 	ticker := time.NewTicker(rampUpInterval)
 	defer ticker.Stop()
 
-	workerCount := 0
+	started := 0
 	for {
 		select {
-		case <-s.ctx.Done():
+		case <-s.loadCtx.Done():
 			return
 		case <-ticker.C:
+			if s.vusSetManually() {
+				logger.Log.Info("Ramp-up ended by a control command")
+				return
+			}
+
 			// Start batch of workers
-			for i := 0; i < workersPerInterval && workerCount < s.plan.Users; i++ {
-				worker := NewWorker(workerCount, s.plan, s.metrics, s.sharedClient, s.collector)
-				s.workers = append(s.workers, worker)
-				s.wg.Add(1)
-				go func(w *Worker) {
-					defer s.wg.Done()
-					w.Run(s.ctx, requestChan)
-				}(worker)
-				workerCount++
+			batch := workersPerInterval
+			if started+batch > s.plan.Users {
+				batch = s.plan.Users - started
 			}
-			s.metrics.SetActiveWorkers(workerCount)
-			s.collector.SetActiveWorkers(workerCount)
-			s.metrics.SetActiveWorkers(workerCount)
+			workerCount := s.startWorkers(batch)
+			started += batch
 			logger.Log.Debug("Workers ramped up",
 				zap.Int("active_workers", workerCount),
 				zap.Int("total_workers", s.plan.Users))
 
-			if workerCount >= s.plan.Users {
+			if started >= s.plan.Users {
 				logger.Log.Info("All workers started after ramp-up",
 					zap.Int("workers", s.plan.Users))
 				return
@@ -144,98 +446,171 @@ func (s *Scheduler) startWorkersWithRampUp(requestChan <-chan struct{}) {
 	}
 }
 
+// startWorkers spawns n idle workers and returns the resulting pool size
+func (s *Scheduler) startWorkers(n int) int {
+	workerCount := 0
+	for i := 0; i < n; i++ {
+		count := s.spawnWorker(nil)
+		if count == 0 {
+			break
+		}
+		workerCount = count
+	}
+	if workerCount > 0 {
+		s.metrics.SetActiveWorkers(workerCount)
+		s.collector.SetActiveWorkers(workerCount)
+	}
+	return workerCount
+}
+
+// dispatch hands one scheduled arrival to the VU pool without ever blocking
+// the generator. Under the arrival-rate executor the pool grows on demand up
+// to max_vus; an arrival that still finds no free VU is counted as a dropped
+// iteration instead of being silently discarded. Arrivals still released
+// after the load has ended are not drops: the pool stops growing then.
+func (s *Scheduler) dispatch(arrival Arrival) {
+	select {
+	case s.requestChan <- arrival:
+		return
+	default:
+	}
+	if s.loadCtx.Err() != nil {
+		return
+	}
+
+	if s.executor() == model.ExecutorArrivalRate {
+		if count := s.spawnWorker(&arrival); count > 0 {
+			s.metrics.SetActiveWorkers(count)
+			s.collector.SetActiveWorkers(count)
+			logger.Log.Debug("VU pool grown on demand",
+				zap.Int("active_workers", count),
+				zap.Int("max_vus", s.maxVUs()))
+			return
+		}
+	}
+
+	s.metrics.RecordDroppedIteration()
+	s.collector.RecordDroppedIteration(s.metrics.RunID)
+}
+
 // generateRequestsWithPattern sends requests based on rate pattern
-func (s *Scheduler) generateRequestsWithPattern(requestChan chan<- struct{}) {
-	defer close(requestChan)
+func (s *Scheduler) generateRequestsWithPattern() {
+	defer close(s.requestChan)
 
 	switch s.plan.RatePattern {
 	case model.RatePatternStep:
-		s.generateStepPattern(requestChan)
+		s.generateStepPattern()
 	case model.RatePatternSpike:
-		s.generateSpikePattern(requestChan)
+		s.generateSpikePattern()
 	case model.RatePatternRamp:
-		s.generateRampPattern(requestChan)
+		s.generateRampPattern()
+	case model.RatePatternStages:
+		s.generateStages()
+	case model.RatePatternSine:
+		s.generateWave("sine", sineRate)
+	case model.RatePatternDiurnal:
+		s.generateWave("diurnal", diurnalRate)
 	case model.RatePatternFixed:
-		s.generateFixedRate(requestChan)
+		s.generateFixedRate()
 	default: // empty or unknown
-		s.generateFixedRate(requestChan)
+		s.generateFixedRate()
 	}
 }
 
-// generateFixedRate generates requests at a fixed rate
-func (s *Scheduler) generateFixedRate(requestChan chan<- struct{}) {
-	// Calculate request interval based on target RPS
-	var ticker *time.Ticker
-	if s.plan.TargetRPS > 0 {
-		// Calculate interval: 1 second / target RPS
-		intervalNs := int64(1e9) / int64(s.plan.TargetRPS)
-		ticker = time.NewTicker(time.Duration(intervalNs))
-		logger.Log.Info("Rate control enabled (fixed)",
-			zap.Int("target_rps", s.plan.TargetRPS),
-			zap.Duration("interval", time.Duration(intervalNs)))
-	} else {
-		// No rate limit: use 1ms ticker for fast generation
-		ticker = time.NewTicker(time.Millisecond)
-		logger.Log.Info("Rate control disabled (unlimited RPS)")
-	}
+// generateArrivals releases arrivals following rateAt, which returns the
+// target arrival rate (per second) for the time elapsed since generation
+// began. Arrivals accumulate fractionally on every tick, so rates above the
+// tick frequency and rates that change over time are honoured exactly. Each
+// arrival is stamped with the instant within the tick at which it fell due.
+func (s *Scheduler) generateArrivals(rateAt func(elapsed time.Duration) float64) {
+	ticker := time.NewTicker(arrivalTick)
 	defer ticker.Stop()
 
+	start := time.Now()
+	last := start
+	due := 0.0
+
 	for {
 		select {
-		case <-s.ctx.Done():
-			logger.Log.Info("Request generation stopped")
+		case <-s.loadCtx.Done():
 			return
-		case <-ticker.C:
-			// Non-blocking send to avoid goroutine buildup
+		case now := <-ticker.C:
+			rate := s.rateAt(rateAt, now.Sub(start))
+			prev := due
+			due += rate * now.Sub(last).Seconds()
+			for n := 1.0; due >= 1; n, due = n+1, due-1 {
+				s.dispatch(Arrival{IntendedAt: last.Add(time.Duration((n - prev) / rate * float64(time.Second)))})
+			}
+			last = now
+		}
+	}
+}
+
+// generateUnlimited keeps the request channel full so that every worker
+// runs back-to-back iterations. It waits while the test is paused, and hands
+// over to rate control once a control command sets a fixed rate.
+func (s *Scheduler) generateUnlimited() {
+	for {
+		paused, resumed, rps := s.controlState()
+		if paused {
 			select {
-			case requestChan <- struct{}{}:
-			default:
-				// Channel full, workers are busy
+			case <-s.loadCtx.Done():
+				return
+			case <-resumed:
 			}
+			continue
 		}
+		if rps != nil {
+			rate := *rps
+			s.generateArrivals(func(time.Duration) float64 { return rate })
+			return
+		}
+
+		select {
+		case <-s.loadCtx.Done():
+			return
+		case s.requestChan <- Arrival{}:
+		}
+	}
+}
+
+// generateFixedRate generates requests at a fixed rate
+func (s *Scheduler) generateFixedRate() {
+	if s.plan.TargetRPS <= 0 {
+		logger.Log.Info("Rate control disabled (unlimited RPS)")
+		s.generateUnlimited()
+		logger.Log.Info("Request generation stopped")
+		return
 	}
+
+	logger.Log.Info("Rate control enabled (fixed)",
+		zap.Int("target_rps", s.plan.TargetRPS))
+
+	rps := float64(s.plan.TargetRPS)
+	s.generateArrivals(func(time.Duration) float64 { return rps })
+	logger.Log.Info("Request generation stopped")
 }
 
 // generateStepPattern generates requests with step increases
-func (s *Scheduler) generateStepPattern(requestChan chan<- struct{}) {
+func (s *Scheduler) generateStepPattern() {
 	if len(s.plan.RateSteps) == 0 {
 		logger.Log.Warn("No rate steps defined, falling back to fixed rate")
-		s.generateFixedRate(requestChan)
+		s.generateFixedRate()
 		return
 	}
 
 	logger.Log.Info("Starting step rate pattern",
 		zap.Int("steps", len(s.plan.RateSteps)))
 
-	for stepIdx, step := range s.plan.RateSteps {
-		logger.Log.Info("Step rate change",
-			zap.Int("step", stepIdx+1),
-			zap.Int("rps", step.RPS),
-			zap.Int("duration_sec", step.DurationSec))
-
-		s.runRateForDuration(requestChan, step.RPS, step.DurationSec)
-
-		select {
-		case <-s.ctx.Done():
-			logger.Log.Info("Step pattern stopped early")
-			return
-		default:
-		}
-	}
-
-	logger.Log.Info("Step pattern completed, maintaining last rate")
-	// Maintain last rate for remaining duration
-	if len(s.plan.RateSteps) > 0 {
-		lastStep := s.plan.RateSteps[len(s.plan.RateSteps)-1]
-		s.runRateIndefinitely(requestChan, lastStep.RPS)
-	}
+	// The last step's rate is maintained for the remaining duration
+	s.generateArrivals(stepRate(s.plan.RateSteps))
 }
 
 // generateSpikePattern generates a spike then returns to base
-func (s *Scheduler) generateSpikePattern(requestChan chan<- struct{}) {
+func (s *Scheduler) generateSpikePattern() {
 	if len(s.plan.RateSteps) < 2 {
 		logger.Log.Warn("Spike pattern requires at least 2 steps (base, spike), falling back to fixed")
-		s.generateFixedRate(requestChan)
+		s.generateFixedRate()
 		return
 	}
 
@@ -247,28 +622,12 @@ func (s *Scheduler) generateSpikePattern(requestChan chan<- struct{}) {
 		zap.Int("spike_rps", spikeRate.RPS),
 		zap.Int("spike_duration_sec", spikeRate.DurationSec))
 
-	// Base rate
-	s.runRateForDuration(requestChan, baseRate.RPS, baseRate.DurationSec)
-
-	// Spike
-	select {
-	case <-s.ctx.Done():
-		return
-	default:
-	}
-	s.runRateForDuration(requestChan, spikeRate.RPS, spikeRate.DurationSec)
-
-	// Back to base
-	select {
-	case <-s.ctx.Done():
-		return
-	default:
-	}
-	s.runRateIndefinitely(requestChan, baseRate.RPS)
+	// Base rate, spike, then back to base for the remaining duration
+	s.generateArrivals(stepRate([]model.RateStep{baseRate, spikeRate, {RPS: baseRate.RPS, DurationSec: 1}}))
 }
 
 // generateRampPattern linearly increases rate over duration
-func (s *Scheduler) generateRampPattern(requestChan chan<- struct{}) {
+func (s *Scheduler) generateRampPattern() {
 	startRPS := 1
 	endRPS := s.plan.TargetRPS
 	if endRPS == 0 {
@@ -285,108 +644,67 @@ func (s *Scheduler) generateRampPattern(requestChan chan<- struct{}) {
 	if rampDuration < 1 {
 		rampDuration = 1
 	}
+	ramp := time.Duration(rampDuration) * time.Second
 
-	// Increase RPS every second
-	step := float64(endRPS-startRPS) / float64(rampDuration)
-	currentRPS := float64(startRPS)
-
-	ticker := time.NewTicker(time.Second)
-	defer ticker.Stop()
-	elapsed := 0
-
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			elapsed++
-			if elapsed <= rampDuration {
-				currentRPS += step
-				logger.Log.Debug("Ramp rate change",
-					zap.Float64("current_rps", currentRPS))
-			}
-
-			// Generate requests for this second
-			targetRPS := int(currentRPS)
-			if targetRPS > 0 {
-				interval := time.Second / time.Duration(targetRPS)
-				s.sendRequestsForInterval(requestChan, interval, time.Second)
-			}
+	s.generateArrivals(func(elapsed time.Duration) float64 {
+		if elapsed >= ramp {
+			return float64(endRPS)
 		}
-	}
+		return float64(startRPS) + float64(endRPS-startRPS)*elapsed.Seconds()/ramp.Seconds()
+	})
 }
 
-// runRateForDuration runs at specified RPS for duration
-func (s *Scheduler) runRateForDuration(requestChan chan<- struct{}, rps int, durationSec int) {
-	if rps <= 0 {
-		time.Sleep(time.Duration(durationSec) * time.Second)
+// generateStages follows the target rate of a stages profile. Stages that
+// only set VUs leave the rate at target_rps, or unlimited if that is 0.
+func (s *Scheduler) generateStages() {
+	if !model.StagesSetRPS(s.plan.Stages) {
+		s.generateFixedRate()
 		return
 	}
 
-	intervalNs := int64(1e9) / int64(rps)
-	ticker := time.NewTicker(time.Duration(intervalNs))
-	defer ticker.Stop()
+	logger.Log.Info("Starting stages pattern",
+		zap.Int("stages", len(s.plan.Stages)))
 
-	timeout := time.After(time.Duration(durationSec) * time.Second)
-
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-timeout:
-			return
-		case <-ticker.C:
-			select {
-			case requestChan <- struct{}{}:
-			default:
-			}
-		}
-	}
+	// The last stage's rate is maintained for the remaining duration
+	s.generateArrivals(stagesRate(s.plan.Stages, s.plan.TargetRPS))
 }
 
-// runRateIndefinitely runs at specified RPS until context done
-func (s *Scheduler) runRateIndefinitely(requestChan chan<- struct{}, rps int) {
-	if rps <= 0 {
-		<-s.ctx.Done()
+// generateWave follows a periodic rate curve between the wave's min and max
+// rate. The period defaults to the test duration.
+func (s *Scheduler) generateWave(name string, shape func(model.WaveConfig, time.Duration) func(time.Duration) float64) {
+	if s.plan.Wave == nil {
+		logger.Log.Warn("No wave defined, falling back to fixed rate",
+			zap.String("rate_pattern", name))
+		s.generateFixedRate()
 		return
 	}
 
-	intervalNs := int64(1e9) / int64(rps)
-	ticker := time.NewTicker(time.Duration(intervalNs))
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			select {
-			case requestChan <- struct{}{}:
-			default:
-			}
-		}
+	periodSec := s.plan.Wave.PeriodSec
+	if periodSec <= 0 {
+		periodSec = s.plan.DurationSec
 	}
-}
 
-// sendRequestsForInterval sends requests at interval for duration
-func (s *Scheduler) sendRequestsForInterval(requestChan chan<- struct{}, interval, duration time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	logger.Log.Info("Starting wave pattern",
+		zap.String("rate_pattern", name),
+		zap.Int("min_rps", s.plan.Wave.MinRPS),
+		zap.Int("max_rps", s.plan.Wave.MaxRPS),
+		zap.Int("period_sec", periodSec))
 
-	timeout := time.After(duration)
+	s.generateArrivals(shape(*s.plan.Wave, time.Duration(periodSec)*time.Second))
+}
 
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-timeout:
-			return
-		case <-ticker.C:
-			select {
-			case requestChan <- struct{}{}:
-			default:
+// stepRate returns a rate function that walks through steps in order and
+// holds the last step's rate once they are exhausted
+func stepRate(steps []model.RateStep) func(elapsed time.Duration) float64 {
+	return func(elapsed time.Duration) float64 {
+		var boundary time.Duration
+		for _, step := range steps {
+			boundary += time.Duration(step.DurationSec) * time.Second
+			if elapsed < boundary {
+				return float64(step.RPS)
 			}
 		}
+		return float64(steps[len(steps)-1].RPS)
 	}
 }
 
@@ -398,8 +716,6 @@ func (s *Scheduler) reportMetrics() {
 	for {
 		select {
 		case <-s.ctx.Done():
-			// Calculate final metrics
-			s.calculateFinalMetrics()
 			return
 		case <-ticker.C:
 			// Update live metrics (RPS and duration)
@@ -413,39 +729,158 @@ func (s *Scheduler) reportMetrics() {
 				zap.Int64("failed", snapshot.FailedRequests),
 				zap.Float64("avg_latency_ms", snapshot.AvgLatencyMs),
 				zap.Float64("current_rps", snapshot.CurrentRPS),
-				zap.Int("active_workers", snapshot.ActiveWorkers))
+				zap.Int("active_workers", snapshot.ActiveWorkers),
+				zap.Int64("dropped_iterations", snapshot.DroppedIterations))
 		}
 	}
 }
 
-// calculateFinalMetrics computes percentiles and final statistics
-func (s *Scheduler) calculateFinalMetrics() {
-	// Collect all latencies from all workers
-	allLatencies := make([]float64, 0)
+// trackWindows samples the run once a second and publishes rolling-window
+// stats and running percentiles to the live metrics
+func (s *Scheduler) trackWindows() {
+	defer s.tracking.Done()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	windows := NewRollingWindows(time.Now())
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			latency, response := s.Histograms()
+
+			s.metrics.Mu.RLock()
+			requests, failed := s.metrics.TotalRequests, s.metrics.FailedRequests
+			s.metrics.Mu.RUnlock()
+
+			stats := windows.Add(now, latency, requests, failed)
+
+			s.metrics.Mu.Lock()
+			s.metrics.Windows = stats
+			s.metrics.P50LatencyMs = latency.ValueAtPercentile(50)
+			s.metrics.P75LatencyMs = latency.ValueAtPercentile(75)
+			s.metrics.P95LatencyMs = latency.ValueAtPercentile(95)
+			s.metrics.P99LatencyMs = latency.ValueAtPercentile(99)
+			s.metrics.AvgLatencyMs = latency.MeanMs()
+			s.metrics.P95ResponseMs = response.ValueAtPercentile(95)
+			s.metrics.P99ResponseMs = response.ValueAtPercentile(99)
+			s.setStepLatencies()
+			s.setRequestLatencies()
+			s.metrics.Phases = model.NewPhaseMetrics(s.phaseHists)
+			SetProtocolStats(s.metrics, s.stats)
+			s.metrics.Mu.Unlock()
+		}
+	}
+}
+
+// setStepLatencies copies per-step latency stats into the metrics. The
+// caller must hold s.metrics.Mu.
+func (s *Scheduler) setStepLatencies() {
+	for i, hist := range s.stepHists {
+		if i >= len(s.metrics.Steps) {
+			break
+		}
+		step := &s.metrics.Steps[i]
+		step.AvgLatencyMs = hist.MeanMs()
+		step.P50LatencyMs = hist.ValueAtPercentile(50)
+		step.P95LatencyMs = hist.ValueAtPercentile(95)
+		step.P99LatencyMs = hist.ValueAtPercentile(99)
+		step.MaxLatencyMs = hist.MaxMs()
+	}
+}
+
+// setRequestLatencies copies per-request latency stats into the metrics.
+// The caller must hold s.metrics.Mu.
+func (s *Scheduler) setRequestLatencies() {
+	for i, hist := range s.requestHists {
+		if i >= len(s.metrics.RequestBreakdown) {
+			break
+		}
+		r := &s.metrics.RequestBreakdown[i]
+		r.AvgLatencyMs = hist.MeanMs()
+		r.P50LatencyMs = hist.ValueAtPercentile(50)
+		r.P95LatencyMs = hist.ValueAtPercentile(95)
+		r.P99LatencyMs = hist.ValueAtPercentile(99)
+		r.MaxLatencyMs = hist.MaxMs()
+	}
+}
+
+// Histograms merges the service and response time histograms of every
+// worker. Workers keep recording while the merge runs, so it can be called
+// on a live test.
+func (s *Scheduler) Histograms() (latency, response *model.Histogram) {
+	latency, response = model.NewHistogram(), model.NewHistogram()
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
 	for _, worker := range s.workers {
-		allLatencies = append(allLatencies, worker.GetLatencies()...)
+		latency.Merge(worker.LatencyHistogram())
+		response.Merge(worker.ResponseHistogram())
 	}
+	return latency, response
+}
+
+// BreakdownHistograms returns the live per-step and per-request service time
+// histograms, shared by every worker
+func (s *Scheduler) BreakdownHistograms() (steps, requests []*model.Histogram) {
+	return s.stepHists, s.requestHists
+}
+
+// PhaseHistograms returns the live per-phase request timing histograms,
+// shared by every worker and indexed like model.TimingPhases
+func (s *Scheduler) PhaseHistograms() []*model.Histogram {
+	return s.phaseHists
+}
+
+// ProtocolHistograms returns the live histograms of the registered metrics
+// sections the plan uses, shared by every worker
+func (s *Scheduler) ProtocolHistograms() model.ProtocolHistograms {
+	return s.stats
+}
 
-	if len(allLatencies) == 0 {
+// calculateFinalMetrics computes percentiles and final statistics
+func (s *Scheduler) calculateFinalMetrics() {
+	latency, response := s.Histograms()
+	if latency.TotalCount() == 0 {
 		return
 	}
 
-	// Sort for percentile calculation
-	sort.Float64s(allLatencies)
-
 	// Calculate percentiles
 	s.metrics.Mu.Lock()
-	s.metrics.P50LatencyMs = percentile(allLatencies, 0.50)
-	s.metrics.P75LatencyMs = percentile(allLatencies, 0.75)
-	s.metrics.P95LatencyMs = percentile(allLatencies, 0.95)
-	s.metrics.P99LatencyMs = percentile(allLatencies, 0.99)
-
-	// Calculate average
-	sum := 0.0
-	for _, lat := range allLatencies {
-		sum += lat
-	}
-	s.metrics.AvgLatencyMs = sum / float64(len(allLatencies))
+	s.metrics.P50LatencyMs = latency.ValueAtPercentile(50)
+	s.metrics.P75LatencyMs = latency.ValueAtPercentile(75)
+	s.metrics.P95LatencyMs = latency.ValueAtPercentile(95)
+	s.metrics.P99LatencyMs = latency.ValueAtPercentile(99)
+	s.metrics.P999LatencyMs = latency.ValueAtPercentile(99.9)
+	s.metrics.P9999LatencyMs = latency.ValueAtPercentile(99.99)
+	s.metrics.AvgLatencyMs = latency.MeanMs()
+
+	// Response times include the queueing delay before each request was sent
+	s.metrics.P50ResponseMs = response.ValueAtPercentile(50)
+	s.metrics.P75ResponseMs = response.ValueAtPercentile(75)
+	s.metrics.P95ResponseMs = response.ValueAtPercentile(95)
+	s.metrics.P99ResponseMs = response.ValueAtPercentile(99)
+	s.metrics.P999ResponseMs = response.ValueAtPercentile(99.9)
+	s.metrics.P9999ResponseMs = response.ValueAtPercentile(99.99)
+	s.metrics.AvgResponseMs = response.MeanMs()
+	s.metrics.MaxResponseMs = response.MaxMs()
+
+	// Keep the full distributions so the run can be re-queried later
+	s.metrics.LatencyHistogram = latency
+	s.metrics.ResponseHistogram = response
+	s.metrics.StepHistograms = s.stepHists
+	s.metrics.RequestHistograms = s.requestHists
+	s.metrics.PhaseHistograms = s.phaseHists
+	s.metrics.ProtocolHistograms = s.stats
+
+	// Rolling windows only describe a run while it is live
+	s.metrics.Windows = nil
+	s.setStepLatencies()
+	s.setRequestLatencies()
+	s.metrics.Phases = model.NewPhaseMetrics(s.phaseHists)
+	SetProtocolStats(s.metrics, s.stats)
 
 	// Calculate RPS
 	if s.metrics.TotalDurationMs > 0 {
@@ -454,26 +889,41 @@ func (s *Scheduler) calculateFinalMetrics() {
 
 	s.metrics.Mu.Unlock()
 
+	snapshot := s.metrics.GetSnapshot()
 	logger.Log.Info("Final metrics calculated",
-		zap.String("run_id", s.metrics.RunID),
-		zap.Int64("total_requests", s.metrics.TotalRequests),
-		zap.Float64("avg_latency_ms", s.metrics.AvgLatencyMs),
-		zap.Float64("p50", s.metrics.P50LatencyMs),
-		zap.Float64("p95", s.metrics.P95LatencyMs),
-		zap.Float64("p99", s.metrics.P99LatencyMs),
-		zap.Float64("rps", s.metrics.RequestsPerSec))
-}
-
-// percentile calculates the percentile value from sorted data
-func percentile(sortedData []float64, p float64) float64 {
-	if len(sortedData) == 0 {
-		return 0
+		zap.String("run_id", snapshot.RunID),
+		zap.Int64("total_requests", snapshot.TotalRequests),
+		zap.Float64("avg_latency_ms", snapshot.AvgLatencyMs),
+		zap.Float64("p50", snapshot.P50LatencyMs),
+		zap.Float64("p95", snapshot.P95LatencyMs),
+		zap.Float64("p99", snapshot.P99LatencyMs),
+		zap.Float64("p99.9", snapshot.P999LatencyMs),
+		zap.Float64("p99_response", snapshot.P99ResponseMs),
+		zap.Float64("rps", snapshot.RequestsPerSec),
+		zap.Int64("dropped_iterations", snapshot.DroppedIterations))
+}
+
+// ownsClient reports whether the plan runs on a client of its own
+func (s *Scheduler) ownsClient() bool {
+	return s.plan.Transport != nil || s.plan.TLS != nil
+}
+
+// closeConnections closes the idle connections of a plan's own transport
+// and of every VU's pool, which would otherwise stay open until they time out
+func (s *Scheduler) closeConnections() {
+	s.sharedClient.CloseIdleConnections()
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
+	for _, worker := range s.workers {
+		worker.client.CloseIdleConnections()
 	}
-	index := int(float64(len(sortedData)) * p)
-	if index >= len(sortedData) {
-		index = len(sortedData) - 1
+}
+
+// closeProtocols releases the shared clients of the protocols the plan uses
+func (s *Scheduler) closeProtocols() {
+	for _, p := range s.used {
+		p.close(s)
 	}
-	return sortedData[index]
 }
 
 // Stop cancels the test execution
@@ -484,8 +934,26 @@ func (s *Scheduler) Stop() {
 	}
 }
 
-// Wait waits for all workers to finish
+// Wait waits for the test duration to end and all workers to finish, then
+// computes the final metrics. In-flight iterations get up to the graceful
+// stop period to finish.
 func (s *Scheduler) Wait() {
+	<-s.loadCtx.Done()
+	s.endTimer.Stop()
+	s.workersMu.Lock()
+	s.stopped = true
+	s.workersMu.Unlock()
+
+	hardStop := time.AfterFunc(time.Duration(s.plan.GracefulStopSec)*time.Second, s.cancel)
 	s.wg.Wait()
+	hardStop.Stop()
+	s.cancel()
+	s.tracking.Wait()
+	s.metrics.SetPaused(false)
+	if s.ownsClient() {
+		s.closeConnections()
+	}
+	s.closeProtocols()
 	logger.Log.Info("All workers finished")
+	s.calculateFinalMetrics()
 }
@@ -3,6 +3,7 @@ package engine
 import (
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -326,3 +327,671 @@ func TestSchedulerWait(t *testing.T) {
 		t.Error("Wait timed out - should have completed within duration")
 	}
 }
+
+func TestSchedulerArrivalRateGrowsPool(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:          "test-arrival-grow",
+		Name:        "Arrival Rate Growth",
+		TargetURL:   server.URL,
+		Method:      "GET",
+		Users:       1,
+		MaxVUs:      20,
+		DurationSec: 2,
+		TargetRPS:   50,
+		TimeoutMs:   5000,
+		Executor:    model.ExecutorArrivalRate,
+	}
+	m := model.NewMetrics("run-arrival-grow")
+	collector := getSharedTestCollector()
+
+	scheduler := NewScheduler(plan, m, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	time.Sleep(1500 * time.Millisecond)
+	scheduler.Stop()
+	scheduler.Wait()
+
+	m.Mu.RLock()
+	activeWorkers := m.ActiveWorkers
+	dropped := m.DroppedIterations
+	total := m.TotalRequests
+	m.Mu.RUnlock()
+
+	t.Logf("Active workers: %d, total: %d, dropped: %d", activeWorkers, total, dropped)
+
+	// 50 arrivals/s at 100ms each needs ~5 concurrent VUs
+	if activeWorkers <= plan.Users {
+		t.Errorf("Expected VU pool to grow beyond %d, got %d", plan.Users, activeWorkers)
+	}
+	if activeWorkers > plan.MaxVUs {
+		t.Errorf("VU pool exceeded max_vus: %d > %d", activeWorkers, plan.MaxVUs)
+	}
+	if dropped != 0 {
+		t.Errorf("Expected no dropped iterations with spare VU capacity, got %d", dropped)
+	}
+}
+
+func TestSchedulerArrivalRateCountsDroppedIterations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:          "test-arrival-drop",
+		Name:        "Arrival Rate Saturation",
+		TargetURL:   server.URL,
+		Method:      "GET",
+		Users:       1,
+		MaxVUs:      2,
+		DurationSec: 10,
+		TargetRPS:   50,
+		TimeoutMs:   5000,
+		Executor:    model.ExecutorArrivalRate,
+	}
+	m := model.NewMetrics("run-arrival-drop")
+	collector := getSharedTestCollector()
+
+	scheduler := NewScheduler(plan, m, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+
+	time.Sleep(1 * time.Second)
+	scheduler.Stop()
+	scheduler.Wait()
+
+	m.Mu.RLock()
+	activeWorkers := m.ActiveWorkers
+	dropped := m.DroppedIterations
+	total := m.TotalRequests
+	m.Mu.RUnlock()
+
+	t.Logf("Active workers: %d, total: %d, dropped: %d", activeWorkers, total, dropped)
+
+	if activeWorkers != plan.MaxVUs {
+		t.Errorf("Expected VU pool to reach max_vus %d, got %d", plan.MaxVUs, activeWorkers)
+	}
+	// 2 VUs at 200ms serve ~10 of the ~50 scheduled arrivals
+	if dropped < 30 {
+		t.Errorf("Expected saturated pool to record dropped iterations, got %d", dropped)
+	}
+	if total+dropped < 40 {
+		t.Errorf("Expected arrivals to be scheduled at target rate, got %d started + %d dropped", total, dropped)
+	}
+}
+
+func TestSchedulerMergesWorkerHistograms(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:          "test-plan-histogram",
+		Name:        "Histogram Test",
+		TargetURL:   server.URL,
+		Method:      "GET",
+		Users:       4,
+		DurationSec: 1,
+		TargetRPS:   200,
+		TimeoutMs:   5000,
+	}
+	m := model.NewMetrics("run-histogram")
+	collector := getSharedTestCollector()
+
+	scheduler := NewScheduler(plan, m, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+
+	// Histograms can be read while the test is still running
+	time.Sleep(300 * time.Millisecond)
+	live, _ := scheduler.Histograms()
+	if live.TotalCount() == 0 {
+		t.Error("Expected live histogram to contain samples")
+	}
+
+	scheduler.Wait()
+
+	snapshot := m.GetSnapshot()
+	if snapshot.LatencyHistogram == nil || snapshot.ResponseHistogram == nil {
+		t.Fatal("Expected final metrics to keep the merged histograms")
+	}
+	if snapshot.LatencyHistogram.TotalCount() != snapshot.SuccessRequests {
+		t.Errorf("Expected %d samples in merged histogram, got %d",
+			snapshot.SuccessRequests, snapshot.LatencyHistogram.TotalCount())
+	}
+	if snapshot.P50LatencyMs < 2 {
+		t.Errorf("Expected P50 of at least 2ms, got %.3f", snapshot.P50LatencyMs)
+	}
+	if snapshot.P50LatencyMs > snapshot.P99LatencyMs || snapshot.P99LatencyMs > snapshot.P999LatencyMs ||
+		snapshot.P999LatencyMs > snapshot.P9999LatencyMs {
+		t.Errorf("Expected monotonic percentiles, got p50=%.3f p99=%.3f p99.9=%.3f p99.99=%.3f",
+			snapshot.P50LatencyMs, snapshot.P99LatencyMs, snapshot.P999LatencyMs, snapshot.P9999LatencyMs)
+	}
+	if snapshot.P9999LatencyMs > snapshot.LatencyHistogram.MaxMs() {
+		t.Errorf("P99.99 %.3f exceeds max %.3f", snapshot.P9999LatencyMs, snapshot.LatencyHistogram.MaxMs())
+	}
+
+	t.Logf("Histogram: %d samples, p50=%.3fms p99.9=%.3fms",
+		snapshot.LatencyHistogram.TotalCount(), snapshot.P50LatencyMs, snapshot.P999LatencyMs)
+}
+
+func TestSchedulerLiveWindows(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:          "test-plan-windows",
+		Name:        "Live Windows Test",
+		TargetURL:   server.URL,
+		Method:      "GET",
+		Users:       4,
+		DurationSec: 3,
+		TargetRPS:   100,
+		TimeoutMs:   5000,
+	}
+	m := model.NewMetrics("run-windows")
+	collector := getSharedTestCollector()
+
+	scheduler := NewScheduler(plan, m, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+
+	time.Sleep(2500 * time.Millisecond)
+	live := m.GetSnapshot()
+
+	for _, name := range []string{model.Window1s, model.Window10s, model.Window60s} {
+		window, ok := live.Windows[name]
+		if !ok {
+			t.Errorf("Expected live %s window", name)
+			continue
+		}
+		if window.Requests == 0 || window.RPS == 0 {
+			t.Errorf("Expected %s window to have throughput, got %+v", name, window)
+		}
+		if window.P50LatencyMs <= 0 {
+			t.Errorf("Expected %s window to have latency percentiles, got %+v", name, window)
+		}
+	}
+
+	// The 1s window covers only the last sample, the longer ones the whole run so far
+	if live.Windows[model.Window1s].Requests >= live.Windows[model.Window10s].Requests {
+		t.Errorf("Expected 1s window (%d requests) to be smaller than 10s window (%d requests)",
+			live.Windows[model.Window1s].Requests, live.Windows[model.Window10s].Requests)
+	}
+	if live.P95LatencyMs <= 0 {
+		t.Error("Expected running P95 to be populated while the test is live")
+	}
+
+	scheduler.Wait()
+
+	if final := m.GetSnapshot(); final.Windows != nil {
+		t.Error("Expected windows to be cleared from final metrics")
+	}
+}
+
+func TestSchedulerScenarioStepMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(5 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:          "test-plan-scenario",
+		Name:        "Scenario Load Test",
+		Users:       3,
+		DurationSec: 1,
+		TargetRPS:   30,
+		TimeoutMs:   5000,
+		Scenario: &model.Scenario{
+			ID: "scenario-steps",
+			Steps: []model.Step{
+				{Name: "fast", Method: "GET", URL: server.URL + "/fast"},
+				{Name: "slow", Method: "GET", URL: server.URL + "/slow"},
+			},
+		},
+	}
+	m := model.NewMetrics("run-scenario-steps")
+	collector := getSharedTestCollector()
+
+	scheduler := NewScheduler(plan, m, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	scheduler.Wait()
+
+	snapshot := m.GetSnapshot()
+	if len(snapshot.Steps) != 2 {
+		t.Fatalf("Expected 2 step breakdowns, got %d", len(snapshot.Steps))
+	}
+	if snapshot.Iterations == 0 {
+		t.Fatal("Expected scenario iterations to complete")
+	}
+
+	fast, slow := snapshot.Steps[0], snapshot.Steps[1]
+	if fast.Name != "fast" || slow.Name != "slow" {
+		t.Errorf("Expected steps in scenario order, got %s and %s", fast.Name, slow.Name)
+	}
+	if slow.P50LatencyMs < 5 || slow.P50LatencyMs <= fast.P50LatencyMs {
+		t.Errorf("Expected slow step P50 (%.3fms) to be at least 5ms and above fast step (%.3fms)",
+			slow.P50LatencyMs, fast.P50LatencyMs)
+	}
+
+	t.Logf("Iterations: %d, fast p50=%.3fms, slow p50=%.3fms", snapshot.Iterations, fast.P50LatencyMs, slow.P50LatencyMs)
+}
+
+func TestSchedulerRequestMixBreakdown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(5 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:          "test-plan-mix",
+		Name:        "Request Mix Load Test",
+		Users:       3,
+		DurationSec: 1,
+		TargetRPS:   60,
+		TimeoutMs:   5000,
+		Requests: []model.WeightedRequest{
+			{Name: "fast", Method: "GET", URL: server.URL + "/fast", Weight: 1},
+			{Name: "slow", Method: "GET", URL: server.URL + "/slow", Weight: 1},
+		},
+	}
+	m := model.NewMetrics("run-mix-breakdown")
+	collector := getSharedTestCollector()
+
+	scheduler := NewScheduler(plan, m, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	scheduler.Wait()
+
+	snapshot := m.GetSnapshot()
+	if len(snapshot.RequestBreakdown) != 2 {
+		t.Fatalf("Expected 2 request breakdowns, got %d", len(snapshot.RequestBreakdown))
+	}
+
+	fast, slow := snapshot.RequestBreakdown[0], snapshot.RequestBreakdown[1]
+	if fast.Requests == 0 || slow.Requests == 0 {
+		t.Fatalf("Expected both requests to run, got %d fast and %d slow", fast.Requests, slow.Requests)
+	}
+	if fast.Requests+slow.Requests != snapshot.TotalRequests {
+		t.Errorf("Expected breakdown to add up to %d requests, got %d", snapshot.TotalRequests, fast.Requests+slow.Requests)
+	}
+	if slow.P50LatencyMs < 5 || slow.P50LatencyMs <= fast.P50LatencyMs {
+		t.Errorf("Expected slow request P50 (%.3fms) to be at least 5ms and above fast request (%.3fms)",
+			slow.P50LatencyMs, fast.P50LatencyMs)
+	}
+
+	t.Logf("Requests: fast=%d slow=%d, fast p50=%.3fms, slow p50=%.3fms", fast.Requests, slow.Requests, fast.P50LatencyMs, slow.P50LatencyMs)
+}
+
+func TestSchedulerStopsWhenDataExhausted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rows := make([]map[string]string, 20)
+	for i := range rows {
+		rows[i] = map[string]string{"id": strconv.Itoa(i)}
+	}
+	plan := &model.TestPlan{
+		ID:          "test-plan-data-once",
+		Name:        "Data Once Load Test",
+		TargetURL:   server.URL + "/items/{{data.id}}",
+		Method:      "GET",
+		Users:       2,
+		DurationSec: 10,
+		TargetRPS:   100,
+		TimeoutMs:   5000,
+		Data:        &model.DataFeed{DataSetID: "items", Strategy: model.FeedOnce},
+		DataSet:     &model.DataSet{ID: "items", Columns: []string{"id"}, Rows: rows},
+	}
+	m := model.NewMetrics("run-data-once")
+	collector := getSharedTestCollector()
+
+	start := time.Now()
+	scheduler := NewScheduler(plan, m, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	scheduler.Wait()
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected test to stop once the data set was exhausted, ran for %v", elapsed)
+	}
+	if snapshot := m.GetSnapshot(); snapshot.TotalRequests != int64(len(rows)) {
+		t.Errorf("Expected one request per row (%d), got %d", len(rows), snapshot.TotalRequests)
+	}
+}
+
+func TestLoadProfileRates(t *testing.T) {
+	rps := func(v int) *int { return &v }
+	stages := []model.Stage{
+		{DurationSec: 10, TargetRPS: rps(100)},
+		{DurationSec: 10},
+		{DurationSec: 10, TargetRPS: rps(0)},
+	}
+	rate := stagesRate(stages, 20)
+
+	tests := []struct {
+		elapsed time.Duration
+		want    float64
+	}{
+		{0, 20},
+		{5 * time.Second, 60},
+		{15 * time.Second, 100}, // a stage without a target holds the previous value
+		{25 * time.Second, 50},
+		{40 * time.Second, 0}, // the last value is held after the stages end
+	}
+	for _, tc := range tests {
+		if got := rate(tc.elapsed); got != tc.want {
+			t.Errorf("stages rate at %v: expected %.1f, got %.1f", tc.elapsed, tc.want, got)
+		}
+	}
+
+	wave := model.WaveConfig{MinRPS: 10, MaxRPS: 110}
+	sine := sineRate(wave, time.Minute)
+	if got := sine(0); got != 10 {
+		t.Errorf("Expected sine wave to start at min_rps, got %.1f", got)
+	}
+	if got := sine(30 * time.Second); got != 110 {
+		t.Errorf("Expected sine wave to peak half way through the period, got %.1f", got)
+	}
+
+	diurnal := diurnalRate(wave, 24*time.Second)
+	if night, evening := diurnal(3*time.Second), diurnal(19*time.Second); night != 10 || evening != 110 {
+		t.Errorf("Expected a night trough at min_rps and evening peak at max_rps, got %.1f and %.1f", night, evening)
+	}
+
+	down := rampDownFactor(10*time.Second, 4*time.Second)
+	if down(5*time.Second) != 1 || down(8*time.Second) != 0.5 || down(10*time.Second) != 0 {
+		t.Errorf("Unexpected ramp-down factors: %.2f %.2f %.2f", down(5*time.Second), down(8*time.Second), down(10*time.Second))
+	}
+}
+
+func TestSchedulerStagesRampVUsDown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	vus := func(v int) *int { return &v }
+	plan := &model.TestPlan{
+		ID:              "test-plan-stages",
+		Name:            "Stages Load Test",
+		TargetURL:       server.URL,
+		Method:          "GET",
+		Users:           2,
+		DurationSec:     3,
+		TimeoutMs:       5000,
+		GracefulStopSec: 1,
+		RatePattern:     model.RatePatternStages,
+		Stages: []model.Stage{
+			{DurationSec: 1, TargetVUs: vus(6)},
+			{DurationSec: 1, TargetVUs: vus(1)},
+		},
+	}
+	m := model.NewMetrics("run-stages")
+	collector := getSharedTestCollector()
+
+	scheduler := NewScheduler(plan, m, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+
+	time.Sleep(1 * time.Second)
+	peak := m.GetSnapshot().ActiveWorkers
+	time.Sleep(1500 * time.Millisecond)
+	end := m.GetSnapshot().ActiveWorkers
+	scheduler.Wait()
+
+	if peak < 5 {
+		t.Errorf("Expected about 6 VUs at the end of the first stage, got %d", peak)
+	}
+	if end != 1 {
+		t.Errorf("Expected the pool to be ramped down to 1 VU, got %d", end)
+	}
+	if snapshot := m.GetSnapshot(); snapshot.FailedRequests != 0 {
+		t.Errorf("Expected stopped VUs to finish their iterations, got %d failures", snapshot.FailedRequests)
+	}
+}
+
+func TestSchedulerGracefulStop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(700 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	newPlan := func(gracefulStopSec int) *model.TestPlan {
+		return &model.TestPlan{
+			ID:              "test-plan-graceful",
+			Name:            "Graceful Stop Test",
+			TargetURL:       server.URL,
+			Method:          "GET",
+			Users:           2,
+			DurationSec:     1,
+			TimeoutMs:       5000,
+			GracefulStopSec: gracefulStopSec,
+		}
+	}
+	collector := getSharedTestCollector()
+
+	hard := model.NewMetrics("run-hard-stop")
+	scheduler := NewScheduler(newPlan(0), hard, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	scheduler.Wait()
+
+	graceful := model.NewMetrics("run-graceful-stop")
+	start := time.Now()
+	scheduler = NewScheduler(newPlan(5), graceful, http.DefaultClient, collector)
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	scheduler.Wait()
+	elapsed := time.Since(start)
+
+	if hard.GetSnapshot().FailedRequests == 0 {
+		t.Error("Expected in-flight requests to be cancelled without a graceful stop")
+	}
+	if failed := graceful.GetSnapshot().FailedRequests; failed != 0 {
+		t.Errorf("Expected in-flight requests to finish during the graceful stop, got %d failures", failed)
+	}
+	if elapsed > 3*time.Second {
+		t.Errorf("Expected the run to end once in-flight requests finished, took %v", elapsed)
+	}
+}
+
+func TestSchedulerControlPauseAndResume(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Unlimited rate, so pausing has to stop a generator that keeps the queue full
+	plan := &model.TestPlan{
+		ID:          "test-plan-pause",
+		Name:        "Pause Test",
+		TargetURL:   server.URL,
+		Method:      "GET",
+		Users:       2,
+		DurationSec: 3,
+		TimeoutMs:   5000,
+	}
+	metrics := model.NewMetrics("run-pause")
+	scheduler := NewScheduler(plan, metrics, http.DefaultClient, getSharedTestCollector())
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	defer scheduler.Stop()
+
+	time.Sleep(300 * time.Millisecond)
+	if err := scheduler.Control(model.ControlCommand{Action: model.ControlPause}); err != nil {
+		t.Fatalf("Failed to pause: %v", err)
+	}
+	if !metrics.GetSnapshot().Paused {
+		t.Error("Expected metrics to report the test as paused")
+	}
+
+	// Let in-flight iterations finish before counting
+	time.Sleep(100 * time.Millisecond)
+	paused := requests.Load()
+	time.Sleep(500 * time.Millisecond)
+	if sent := requests.Load() - paused; sent != 0 {
+		t.Errorf("Expected no requests while paused, got %d", sent)
+	}
+
+	if err := scheduler.Control(model.ControlCommand{Action: model.ControlResume}); err != nil {
+		t.Fatalf("Failed to resume: %v", err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	if requests.Load() == paused {
+		t.Error("Expected requests to start again after resuming")
+	}
+	if metrics.GetSnapshot().Paused {
+		t.Error("Expected metrics to no longer report the test as paused")
+	}
+}
+
+func TestSchedulerControlRetargetsLoad(t *testing.T) {
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:          "test-plan-retarget",
+		Name:        "Retarget Test",
+		TargetURL:   server.URL,
+		Method:      "GET",
+		Users:       2,
+		DurationSec: 1,
+		TimeoutMs:   5000,
+	}
+	metrics := model.NewMetrics("run-retarget")
+	start := time.Now()
+	scheduler := NewScheduler(plan, metrics, http.DefaultClient, getSharedTestCollector())
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	if err := scheduler.Control(model.ControlCommand{Action: model.ControlExtendDuration, Value: 2}); err != nil {
+		t.Fatalf("Failed to extend duration: %v", err)
+	}
+
+	if err := scheduler.Control(model.ControlCommand{Action: model.ControlAddVUs, Value: 3}); err != nil {
+		t.Fatalf("Failed to add VUs: %v", err)
+	}
+	if active := metrics.GetSnapshot().ActiveWorkers; active != 5 {
+		t.Errorf("Expected 5 active workers after adding 3, got %d", active)
+	}
+
+	if err := scheduler.Control(model.ControlCommand{Action: model.ControlRemoveVUs, Value: 4}); err != nil {
+		t.Fatalf("Failed to remove VUs: %v", err)
+	}
+	if active := metrics.GetSnapshot().ActiveWorkers; active != 1 {
+		t.Errorf("Expected 1 active worker after removing 4, got %d", active)
+	}
+	if err := scheduler.Control(model.ControlCommand{Action: model.ControlRemoveVUs, Value: 1}); err == nil {
+		t.Error("Expected removing the last VU to be rejected")
+	}
+
+	// The unlimited plan switches to rate control
+	if err := scheduler.Control(model.ControlCommand{Action: model.ControlSetRPS, Value: 20}); err != nil {
+		t.Fatalf("Failed to set RPS: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	before := requests.Load()
+	time.Sleep(time.Second)
+	if sent := requests.Load() - before; sent < 10 || sent > 30 {
+		t.Errorf("Expected about 20 requests in a second at 20 RPS, got %d", sent)
+	}
+
+	// A rate of zero throttles the test rather than restoring the unlimited plan
+	if err := scheduler.Control(model.ControlCommand{Action: model.ControlSetRPS, Value: 0}); err != nil {
+		t.Fatalf("Failed to set RPS to zero: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	before = requests.Load()
+	time.Sleep(500 * time.Millisecond)
+	if sent := requests.Load() - before; sent > 1 {
+		t.Errorf("Expected no requests at 0 RPS, got %d", sent)
+	}
+
+	scheduler.Wait()
+	if elapsed := time.Since(start); elapsed < 2500*time.Millisecond {
+		t.Errorf("Expected the extended test to run for about 3s, took %v", elapsed)
+	}
+
+	if err := scheduler.Control(model.ControlCommand{Action: model.ControlPause}); err == nil {
+		t.Error("Expected control commands to be rejected once the test has ended")
+	}
+}
+
+func TestSchedulerPoolPerVUOpensDistinctConnections(t *testing.T) {
+	var conns int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	countConnections(server, &conns)
+	server.Start()
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:          "test-pool-per-vu",
+		Name:        "Pool Per VU",
+		TargetURL:   server.URL,
+		Method:      "GET",
+		Users:       10,
+		DurationSec: 1,
+		TimeoutMs:   5000,
+		Transport:   &model.TransportConfig{PoolPerVU: true},
+	}
+	m := model.NewMetrics("run-pool-per-vu")
+	scheduler := NewScheduler(plan, m, http.DefaultClient, getSharedTestCollector())
+
+	if err := scheduler.Start(); err != nil {
+		t.Fatalf("Failed to start scheduler: %v", err)
+	}
+	scheduler.Wait()
+
+	// Every VU keeps one connection of its own alive for the whole run
+	if n := atomic.LoadInt64(&conns); n != int64(plan.Users) {
+		t.Errorf("Expected %d distinct connections, got %d", plan.Users, n)
+	}
+	if m.NewConnections != int64(plan.Users) || m.ReusedConnections == 0 {
+		t.Errorf("Expected %d new connections and the rest reused, got %d new and %d reused",
+			plan.Users, m.NewConnections, m.ReusedConnections)
+	}
+}
@@ -0,0 +1,249 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+const (
+	socketDefaultTimeout  = 30 * time.Second // Connect, send and response timeout of plans without one
+	socketDefaultMaxBytes = 64 * 1024        // Longest response read without max_bytes, also the largest UDP datagram
+	socketReadSize        = 4096
+)
+
+// errSocketResponseTooLarge fails a response that outgrew its max_bytes
+// before it was complete
+var errSocketResponseTooLarge = errors.New("socket response exceeds max_bytes")
+
+// socketMethodLabel returns the method TCP and UDP exchanges are counted
+// under in Prometheus, TCP or UDP
+func socketMethodLabel(network model.SocketNetwork) string {
+	return strings.ToUpper(string(network))
+}
+
+// decodePayload turns the string form of a payload or delimiter into bytes
+func decodePayload(s string, encoding model.PayloadEncoding) ([]byte, error) {
+	switch encoding {
+	case model.EncodingHex:
+		data, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex payload: %w", err)
+		}
+		return data, nil
+	case model.EncodingBase64:
+		data, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 payload: %w", err)
+		}
+		return data, nil
+	}
+	return []byte(s), nil
+}
+
+// socketResult is the outcome of one TCP or UDP exchange
+type socketResult struct {
+	connected bool          // A new connection was opened
+	connect   time.Duration // Time the new connection took to open
+	roundTrip time.Duration // From the payload being sent until the response arrived, zero without a response
+	sent      int64
+	received  int64
+	response  []byte // The response, which checks apply to
+	timedOut  bool   // The expected response did not arrive in time
+	err       error  // Why the exchange failed, nil if it succeeded
+}
+
+// socketClient sends the exchanges of one VU. A kept-alive connection stays
+// open between iterations until an exchange on it fails.
+type socketClient struct {
+	req       *model.SocketRequest
+	delimiter []byte
+	pattern   *regexp.Regexp
+	conn      net.Conn
+	addr      string // Address conn is connected to
+	pending   []byte // Bytes conn received after the last response, the start of the next one
+}
+
+// newSocketClient prepares a VU's exchanges of req
+func newSocketClient(req *model.SocketRequest) (*socketClient, error) {
+	c := &socketClient{req: req}
+	if resp := req.Response; resp != nil {
+		if resp.Delimiter != "" {
+			delimiter, err := decodePayload(resp.Delimiter, req.Encoding)
+			if err != nil {
+				return nil, fmt.Errorf("invalid response delimiter: %w", err)
+			}
+			c.delimiter = delimiter
+		}
+		if resp.Pattern != "" {
+			pattern, err := regexp.Compile(resp.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid response pattern: %w", err)
+			}
+			c.pattern = pattern
+		}
+	}
+	return c, nil
+}
+
+// exchange sends payload to address and reads the response the request
+// expects, connecting first unless a kept-alive connection to address is
+// open. timeout bounds the connect and send, and the response unless it has
+// a timeout of its own.
+func (c *socketClient) exchange(ctx context.Context, address string, payload []byte, timeout time.Duration) *socketResult {
+	result := &socketResult{}
+	if timeout <= 0 {
+		timeout = socketDefaultTimeout
+	}
+	if c.conn != nil && c.addr != address {
+		c.close()
+	}
+	if c.conn == nil {
+		dialer := net.Dialer{Timeout: timeout}
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, string(c.req.Network), address)
+		result.connect = time.Since(start)
+		if err != nil {
+			result.err = err
+			return result
+		}
+		result.connected = true
+		c.conn, c.addr = conn, address
+	}
+
+	// A run that stops interrupts the exchange through the deadline
+	conn := c.conn
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	result.err = c.roundTrip(conn, payload, timeout, result)
+	if result.err != nil {
+		if ctx.Err() != nil {
+			result.err = ctx.Err()
+		}
+		c.close()
+		return result
+	}
+	if !c.req.KeepAlive {
+		c.close()
+	}
+	return result
+}
+
+// roundTrip sends payload on conn and reads the response
+func (c *socketClient) roundTrip(conn net.Conn, payload []byte, timeout time.Duration, result *socketResult) error {
+	_ = conn.SetDeadline(time.Now().Add(timeout))
+	sentAt := time.Now()
+	n, err := conn.Write(payload)
+	result.sent = int64(n)
+	if err != nil || c.req.Response == nil {
+		return err
+	}
+
+	if c.req.Response.TimeoutMs > 0 {
+		timeout = time.Duration(c.req.Response.TimeoutMs) * time.Millisecond
+	}
+	_ = conn.SetReadDeadline(sentAt.Add(timeout))
+	maxBytes := c.req.Response.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = socketDefaultMaxBytes
+	}
+	read := c.readStream
+	if c.req.Network == model.SocketUDP {
+		read = c.readDatagram
+	}
+	response, err := read(conn, maxBytes, result)
+	if err != nil {
+		var netErr net.Error
+		result.timedOut = errors.As(err, &netErr) && netErr.Timeout()
+		return err
+	}
+	result.roundTrip = time.Since(sentAt)
+	result.response = response
+	return nil
+}
+
+// readStream reads a TCP response until it is complete, starting with the
+// bytes left over from the previous response. Bytes after the response are
+// kept for the next one.
+func (c *socketClient) readStream(conn net.Conn, maxBytes int, result *socketResult) ([]byte, error) {
+	buf := c.pending
+	c.pending = nil
+	chunk := make([]byte, socketReadSize)
+	var err error
+	for {
+		if len(buf) > 0 {
+			if response, ok := c.complete(buf); ok {
+				if len(response) < len(buf) {
+					c.pending = bytes.Clone(buf[len(response):])
+				}
+				return response, nil
+			}
+			if len(buf) > maxBytes {
+				return nil, errSocketResponseTooLarge
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		var n int
+		n, err = conn.Read(chunk)
+		result.received += int64(n)
+		buf = append(buf, chunk[:n]...)
+	}
+}
+
+// complete returns the response buf holds once it is complete: up to and
+// including the delimiter, once it matches the pattern, or as soon as
+// anything arrived when the request expects neither
+func (c *socketClient) complete(buf []byte) ([]byte, bool) {
+	switch {
+	case c.delimiter != nil:
+		i := bytes.Index(buf, c.delimiter)
+		if i < 0 {
+			return nil, false
+		}
+		return buf[:i+len(c.delimiter)], true
+	case c.pattern != nil:
+		return buf, c.pattern.Match(buf)
+	}
+	return buf, true
+}
+
+// readDatagram reads UDP datagrams until one matches the pattern, or returns
+// the first one without a pattern. The buffer holds one byte more than
+// maxBytes, so a longer datagram is detected rather than truncated.
+func (c *socketClient) readDatagram(conn net.Conn, maxBytes int, result *socketResult) ([]byte, error) {
+	buf := make([]byte, min(maxBytes, socketDefaultMaxBytes)+1)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		result.received += int64(n)
+		if n > maxBytes {
+			return nil, errSocketResponseTooLarge
+		}
+		if c.pattern == nil || c.pattern.Match(buf[:n]) {
+			return bytes.Clone(buf[:n]), nil
+		}
+	}
+}
+
+// close closes the kept-alive connection, if any, discarding the bytes it
+// received after the last response
+func (c *socketClient) close() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn, c.addr, c.pending = nil, "", nil
+	}
+}
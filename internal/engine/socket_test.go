@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// startTCPServer serves line commands: get answers with a value line and,
+// a moment later, END, ping answers PONG, pair answers two lines at once and
+// anything else goes unanswered.
+// It returns the server's address and the number of connections accepted.
+func startTCPServer(t *testing.T) (string, *atomic.Int64) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var accepted atomic.Int64
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted.Add(1)
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					switch strings.TrimSpace(line) {
+					case "get":
+						_, _ = conn.Write([]byte("VALUE 42\r\n"))
+						time.Sleep(5 * time.Millisecond)
+						_, _ = conn.Write([]byte("END\r\nVALUE"))
+					case "ping":
+						_, _ = conn.Write([]byte("PONG"))
+					case "pair":
+						_, _ = conn.Write([]byte("ONE\r\nTWO\r\n"))
+					}
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), &accepted
+}
+
+// startUDPServer answers every datagram with an unrelated datagram and then
+// the datagram's data prefixed with pong:
+func startUDPServer(t *testing.T) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { _ = pc.Close() })
+
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = pc.WriteTo([]byte("noise"), addr)
+			_, _ = pc.WriteTo(append([]byte("pong:"), buf[:n]...), addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func TestWorkerSocket(t *testing.T) {
+	tcpAddr, _ := startTCPServer(t)
+	udpAddr := startUDPServer(t)
+
+	tests := []struct {
+		name       string
+		socket     model.SocketRequest
+		assertions []model.Assertion
+		success    bool
+		errorClass string
+		received   int64
+		timeouts   int64
+	}{
+		{"tcp delimiter", model.SocketRequest{Network: model.SocketTCP, Address: tcpAddr, Payload: "get\n",
+			Response: &model.SocketResponse{Delimiter: "END\r\n"}}, nil, true, "", 20, 0},
+		{"tcp hex delimiter", model.SocketRequest{Network: model.SocketTCP, Address: tcpAddr, Payload: "67 65 74 0a", Encoding: model.EncodingHex,
+			Response: &model.SocketResponse{Delimiter: "454e440d0a"}}, nil, true, "", 20, 0},
+		{"tcp pattern", model.SocketRequest{Network: model.SocketTCP, Address: tcpAddr, Payload: "get\n",
+			Response: &model.SocketResponse{Pattern: "END"}}, nil, true, "", 20, 0},
+		{"tcp first bytes", model.SocketRequest{Network: model.SocketTCP, Address: tcpAddr, Payload: "ping\n",
+			Response: &model.SocketResponse{}}, []model.Assertion{{Type: model.AssertionBodyContains, Value: "PONG"}}, true, "", 4, 0},
+		{"tcp without response", model.SocketRequest{Network: model.SocketTCP, Address: tcpAddr, Payload: "ping\n"}, nil, true, "", 0, 0},
+		{"tcp response timeout", model.SocketRequest{Network: model.SocketTCP, Address: tcpAddr, Payload: "wait\n",
+			Response: &model.SocketResponse{TimeoutMs: 50}}, nil, false, model.ErrorClassTimeout, 0, 1},
+		{"tcp response too large", model.SocketRequest{Network: model.SocketTCP, Address: tcpAddr, Payload: "get\n",
+			Response: &model.SocketResponse{Delimiter: "never", MaxBytes: 8}}, nil, false, "", 10, 0},
+		{"tcp failed check", model.SocketRequest{Network: model.SocketTCP, Address: tcpAddr, Payload: "ping\n",
+			Response: &model.SocketResponse{}}, []model.Assertion{{Type: model.AssertionBodyContains, Value: "VALUE"}}, false, model.ErrorClassCheckFailed, 4, 0},
+		{"udp pattern ignores datagrams", model.SocketRequest{Network: model.SocketUDP, Address: udpAddr, Payload: "1",
+			Response: &model.SocketResponse{Pattern: "^pong:"}}, nil, true, "", 11, 0},
+		{"udp first datagram", model.SocketRequest{Network: model.SocketUDP, Address: udpAddr, Payload: "hi",
+			Response: &model.SocketResponse{}}, []model.Assertion{{Type: model.AssertionBodyContains, Value: "noise"}}, true, "", 5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &model.TestPlan{ID: "test-socket", TimeoutMs: 2000, Socket: &tt.socket, Assertions: tt.assertions}
+			m := model.NewMetrics("run-socket")
+			m.InitSocket()
+			if len(tt.assertions) > 0 {
+				m.InitChecks([]string{tt.assertions[0].Label()})
+			}
+			worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+			worker.socketConnHist = model.NewHistogram()
+			worker.socketRTTHist = model.NewHistogram()
+			defer worker.closeSocket()
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if (m.SuccessRequests == 1) != tt.success {
+				t.Errorf("Expected success %v, got %d successful and %d failed requests (errors %v)", tt.success, m.SuccessRequests, m.FailedRequests, m.ErrorSamples)
+			}
+			if tt.errorClass != "" && m.Errors[tt.errorClass] != 1 {
+				t.Errorf("Expected the failure to be counted as %s, got %v", tt.errorClass, m.Errors)
+			}
+			s := m.Socket
+			if s.Exchanges != 1 || s.Connections != 1 || s.BytesReceived != tt.received || s.ResponseTimeouts != tt.timeouts {
+				t.Errorf("Expected one exchange receiving %d bytes with %d timeouts, got %+v", tt.received, tt.timeouts, s)
+			}
+			if s.BytesSent == 0 {
+				t.Errorf("Expected the payload to be sent, got %+v", s)
+			}
+			// A response that arrived is timed whether or not it passes its checks
+			wantRTT := int64(0)
+			if tt.socket.Response != nil && (tt.success || tt.errorClass == model.ErrorClassCheckFailed) {
+				wantRTT = 1
+			}
+			if worker.socketConnHist.TotalCount() != 1 || worker.socketRTTHist.TotalCount() != wantRTT {
+				t.Errorf("Expected one connect and %d round trips, got %d and %d",
+					wantRTT, worker.socketConnHist.TotalCount(), worker.socketRTTHist.TotalCount())
+			}
+		})
+	}
+}
+
+func TestWorkerSocketKeepAlive(t *testing.T) {
+	for _, keepAlive := range []bool{true, false} {
+		addr, accepted := startTCPServer(t)
+		plan := &model.TestPlan{ID: "test-socket-keep-alive", TimeoutMs: 2000, Socket: &model.SocketRequest{
+			Network: model.SocketTCP, Address: addr, Payload: "get\n", KeepAlive: keepAlive,
+			Response: &model.SocketResponse{Delimiter: "END\r\n"},
+		}}
+		m := model.NewMetrics("run-socket-keep-alive")
+		m.InitSocket()
+		worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+
+		for i := 0; i < 3; i++ {
+			worker.executeRequest(context.Background(), Arrival{})
+		}
+		worker.closeSocket()
+
+		// Bytes after the delimiter are discarded with the connection, and a
+		// kept-alive connection keeps them as the start of the next response
+		wantConns := int64(3)
+		if keepAlive {
+			wantConns = 1
+		}
+		if m.SuccessRequests != 3 || m.Socket.Connections != wantConns || accepted.Load() != wantConns {
+			t.Errorf("keep_alive %v: expected 3 successful exchanges on %d connections, got %d on %d (%d accepted)",
+				keepAlive, wantConns, m.SuccessRequests, m.Socket.Connections, accepted.Load())
+		}
+	}
+}
+
+func TestSocketClientKeepsBytesAfterDelimiter(t *testing.T) {
+	addr, _ := startTCPServer(t)
+	client, err := newSocketClient(&model.SocketRequest{
+		Network: model.SocketTCP, Address: addr, KeepAlive: true,
+		Response: &model.SocketResponse{Delimiter: "\r\n"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.close()
+
+	// Both lines arrive in one read; the second answers the next exchange,
+	// whose payload the server ignores
+	for i, want := range []string{"ONE\r\n", "TWO\r\n"} {
+		payload := []byte("pair\n")
+		if i > 0 {
+			payload = []byte("noop\n")
+		}
+		result := client.exchange(context.Background(), addr, payload, time.Second)
+		if result.err != nil || string(result.response) != want {
+			t.Fatalf("Exchange %d: expected %q, got %q (%v)", i, want, result.response, result.err)
+		}
+	}
+}
+
+func TestWorkerSocketCanceled(t *testing.T) {
+	addr, _ := startTCPServer(t)
+	plan := &model.TestPlan{ID: "test-socket-canceled", TimeoutMs: 10000, Socket: &model.SocketRequest{
+		Network: model.SocketTCP, Address: addr, Payload: "wait\n", Response: &model.SocketResponse{},
+	}}
+	m := model.NewMetrics("run-socket-canceled")
+	m.InitSocket()
+	worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	worker.executeRequest(ctx, Arrival{})
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the exchange to stop with the run, took %v", elapsed)
+	}
+	if m.FailedRequests != 1 {
+		t.Errorf("Expected the canceled exchange to fail, got %d failed requests", m.FailedRequests)
+	}
+}
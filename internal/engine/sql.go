@@ -0,0 +1,239 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	// The postgres driver; plans naming another driver need it linked into the binary
+	_ "github.com/lib/pq"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+const (
+	sqlMethodLabel    = "SQL"            // Method SQL statements are counted under in Prometheus
+	sqlDefaultDriver  = "postgres"       // Driver of plans without one
+	sqlDefaultTimeout = 30 * time.Second // Statement timeout of plans and steps without one
+	sqlMaxResultRows  = 100              // Rows of a result kept for checks and extractions; every row is counted
+)
+
+// errNoSQLDatabase fails the statements of a worker or scenario the
+// scheduler gave no database
+var errNoSQLDatabase = errors.New("sql database not set")
+
+// sqlRowsPattern matches the statements whose results are read as rows:
+// queries, and writes with a RETURNING clause. Other statements only report
+// the rows they affected.
+var sqlRowsPattern = regexp.MustCompile(`(?is)^\s*(select|with|values|table|show|explain|pragma)\b|\breturning\b`)
+
+// sqlStateClasses names the SQLSTATE classes a statement's error is counted
+// under, by the first two characters of its SQLSTATE
+var sqlStateClasses = map[string]string{
+	"08": "connection_exception",
+	"0A": "feature_not_supported",
+	"22": "data_exception",
+	"23": "integrity_constraint_violation",
+	"25": "invalid_transaction_state",
+	"28": "invalid_authorization_specification",
+	"3D": "invalid_catalog_name",
+	"40": "transaction_rollback",
+	"42": "syntax_error_or_access_rule_violation",
+	"53": "insufficient_resources",
+	"54": "program_limit_exceeded",
+	"55": "object_not_in_prerequisite_state",
+	"57": "operator_intervention",
+	"58": "system_error",
+	"XX": "internal_error",
+}
+
+// sqlStateError is implemented by the errors of drivers that report an
+// SQLSTATE, such as lib/pq and pgx
+type sqlStateError interface {
+	SQLState() string
+}
+
+// openSQLDatabase opens the connection pool of a run's statements, sized
+// for vus VUs unless the request sets its own size. Connections are opened
+// as the VUs first need them, logging in with creds if the plan references
+// a credentials secret.
+func openSQLDatabase(req *model.SQLRequest, creds *model.Credentials, vus int) (*sql.DB, error) {
+	driver := req.Driver
+	if driver == "" {
+		driver = sqlDefaultDriver
+	}
+	dsn, err := sqlDSN(req.DSN, creds)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	conns := req.MaxOpenConns
+	if conns <= 0 {
+		conns = vus
+	}
+	db.SetMaxOpenConns(conns)
+	db.SetMaxIdleConns(conns)
+	return db, nil
+}
+
+// sqlDSN adds the username and password of creds to a URL or key=value DSN.
+// A URL keeps its own username when creds has none.
+func sqlDSN(dsn string, creds *model.Credentials) (string, error) {
+	if creds == nil {
+		return dsn, nil
+	}
+	if !strings.Contains(dsn, "://") {
+		var b strings.Builder
+		b.WriteString(dsn)
+		if creds.Username != "" {
+			b.WriteString(" user=" + quoteDSNValue(creds.Username))
+		}
+		b.WriteString(" password=" + quoteDSNValue(creds.Password))
+		return b.String(), nil
+	}
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", fmt.Errorf("invalid dsn: %w", err)
+	}
+	username := creds.Username
+	if username == "" && u.User != nil {
+		username = u.User.Username()
+	}
+	u.User = url.UserPassword(username, creds.Password)
+	return u.String(), nil
+}
+
+// quoteDSNValue quotes a key=value DSN value, escaping backslashes and quotes
+func quoteDSNValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// sqlErrorClass returns the class a failed statement is counted under in
+// the SQL metrics: its SQLSTATE class, or its error class without one
+func sqlErrorClass(err error) string {
+	var stateErr sqlStateError
+	if errors.As(err, &stateErr) {
+		state := stateErr.SQLState()
+		if len(state) >= 2 {
+			if name, ok := sqlStateClasses[state[:2]]; ok {
+				return name
+			}
+			return "sqlstate_" + state[:2]
+		}
+	}
+	return model.ClassifyError(err, 0)
+}
+
+// sqlResult is the outcome of one statement
+type sqlResult struct {
+	returned int64            // Rows read
+	affected int64            // Rows changed, for statements that return no rows
+	rows     []map[string]any // The first sqlMaxResultRows rows read, by column name
+}
+
+// response returns the result as an HTTP response, so the checks of HTTP
+// requests apply to it. Statements have no status code or headers.
+func (r *sqlResult) response() *http.Response {
+	return &http.Response{Header: http.Header{}}
+}
+
+// body returns the result as the JSON object checks and extractions see:
+// the row counts, the rows read and the first row on its own, e.g. for
+// $.row.id
+func (r *sqlResult) body() []byte {
+	result := map[string]any{
+		"rows_returned": r.returned,
+		"rows_affected": r.affected,
+		"rows":          r.rows,
+	}
+	if len(r.rows) > 0 {
+		result["row"] = r.rows[0]
+	}
+	body, _ := json.Marshal(result)
+	return body
+}
+
+// runSQL runs stmt on db with its query and arguments passed through
+// process, reading every row it returns until the timeout. A statement the
+// database rejects fails with an error wrapping model.ErrSQL.
+func runSQL(ctx context.Context, db *sql.DB, stmt *model.SQLStatement, process func(string) string, timeoutMs int) (*sqlResult, error) {
+	query := process(stmt.Query)
+	args := make([]any, len(stmt.Args))
+	for i, arg := range stmt.Args {
+		args[i] = process(arg)
+	}
+
+	callCtx, cancel := withCallTimeout(ctx, timeoutMs, sqlDefaultTimeout)
+	defer cancel()
+	result := &sqlResult{}
+	var err error
+	if sqlRowsPattern.MatchString(query) {
+		err = readSQLRows(callCtx, db, query, args, result)
+	} else {
+		var res sql.Result
+		if res, err = db.ExecContext(callCtx, query, args...); err == nil {
+			// Not every driver counts affected rows
+			result.affected, _ = res.RowsAffected()
+		}
+	}
+
+	switch {
+	case err == nil:
+		return result, nil
+	case callCtx.Err() != nil:
+		return nil, callCtx.Err()
+	}
+	var stateErr sqlStateError
+	if errors.As(err, &stateErr) {
+		return nil, fmt.Errorf("%w %s: %w", model.ErrSQL, stateErr.SQLState(), err)
+	}
+	return nil, err
+}
+
+// readSQLRows runs a query and reads its rows into result
+func readSQLRows(ctx context.Context, db *sql.DB, query string, args []any, result *sqlResult) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	values := make([]any, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		result.returned++
+		if len(result.rows) >= sqlMaxResultRows {
+			continue
+		}
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			// Drivers return text columns as bytes
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		result.rows = append(result.rows, row)
+	}
+	return rows.Err()
+}
@@ -0,0 +1,180 @@
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// streamChunkSize bounds the events of the chunks format
+const streamChunkSize = 32 * 1024
+
+// streamResult is what reading one streamed response found
+type streamResult struct {
+	events     int64
+	firstEvent time.Duration   // From the request being sent, zero without events
+	gaps       []time.Duration // Between consecutive events
+	duration   time.Duration   // From the request being sent until reading stopped
+	cut        bool            // Reading stopped at max_events or max_duration_ms
+	checks     []bool          // Result of every event check
+	body       []byte          // Data of every event, one per line, kept for the plan's checks
+	err        error           // Why reading failed, nil if the stream ended or was cut
+}
+
+// readStream reads the events of a streamed response until the server ends
+// the stream or one of cfg's limits is reached, timing them from start and
+// running cfg's event checks on them. The data of the events is only kept
+// when keepBody is set.
+func readStream(resp *http.Response, cfg *model.StreamConfig, start time.Time, keepBody bool) *streamResult {
+	result := &streamResult{checks: make([]bool, len(cfg.EventChecks))}
+	for i := range cfg.EventChecks {
+		result.checks[i] = cfg.EventChecks[i].Match != model.EventMatchAny
+	}
+
+	// Closing the body unblocks a read that waits for the next event
+	var expired atomic.Bool
+	if cfg.MaxDurationMs > 0 {
+		timer := time.AfterFunc(time.Until(start.Add(time.Duration(cfg.MaxDurationMs)*time.Millisecond)), func() {
+			expired.Store(true)
+			_ = resp.Body.Close()
+		})
+		defer timer.Stop()
+	}
+
+	next := eventSplitter(cfg.Format, resp.Body)
+	var last time.Time
+	for {
+		if cfg.MaxEvents > 0 && result.events >= int64(cfg.MaxEvents) {
+			result.cut = true
+			break
+		}
+		data, err := next()
+		if err != nil {
+			switch {
+			case expired.Load():
+				result.cut = true
+			case !errors.Is(err, io.EOF):
+				result.err = err
+			}
+			break
+		}
+		if cfg.EndData != "" && string(data) == cfg.EndData {
+			break
+		}
+
+		now := time.Now()
+		if result.events == 0 {
+			result.firstEvent = now.Sub(start)
+		} else {
+			result.gaps = append(result.gaps, now.Sub(last))
+		}
+		last = now
+		result.events++
+		result.checkEvent(cfg.EventChecks, resp, data)
+		if keepBody {
+			if len(result.body) > 0 {
+				result.body = append(result.body, '\n')
+			}
+			result.body = append(result.body, data...)
+		}
+	}
+	result.duration = time.Since(start)
+
+	// A stream without events fails the checks every event must pass
+	if result.events == 0 {
+		for i := range cfg.EventChecks {
+			result.checks[i] = false
+		}
+	}
+	return result
+}
+
+// checkEvent runs the event checks whose result an event can still change:
+// every-checks that have passed so far and any-checks that have not
+func (r *streamResult) checkEvent(checks []model.EventCheck, resp *http.Response, data []byte) {
+	for i := range checks {
+		every := checks[i].Match != model.EventMatchAny
+		if r.checks[i] == every {
+			r.checks[i] = evaluateAssertion(&checks[i].Assertion, resp, data, 0)
+		}
+	}
+}
+
+// eventCheckResults returns the results of a plan's event checks for one
+// response, all failed if the response was not read as a stream
+func eventCheckResults(cfg *model.StreamConfig, stream *streamResult) []bool {
+	if stream == nil {
+		return make([]bool, len(cfg.EventChecks))
+	}
+	return stream.checks
+}
+
+// eventSplitter returns a function that reads the next event of a stream in
+// format from r, or io.EOF once the server ended the stream
+func eventSplitter(format model.StreamFormat, r io.Reader) func() ([]byte, error) {
+	switch format {
+	case model.StreamLines:
+		br := bufio.NewReader(r)
+		return func() ([]byte, error) {
+			for {
+				line, err := br.ReadBytes('\n')
+				if line = bytes.TrimRight(line, "\r\n"); len(line) > 0 {
+					return line, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	case model.StreamChunks:
+		buf := make([]byte, streamChunkSize)
+		return func() ([]byte, error) {
+			for {
+				n, err := r.Read(buf)
+				if n > 0 {
+					return bytes.Clone(buf[:n]), nil
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	// Server-Sent Events dispatch their data lines at a blank line; other
+	// fields and comments are skipped, and an event cut off by the end of
+	// the stream is discarded
+	br := bufio.NewReader(r)
+	return func() ([]byte, error) {
+		var data []byte
+		hasData := false
+		for {
+			line, err := br.ReadBytes('\n')
+			if err != nil {
+				return nil, err
+			}
+			line = bytes.TrimRight(line, "\r\n")
+			if len(line) == 0 {
+				if hasData {
+					return data, nil
+				}
+				continue
+			}
+			field, value, _ := bytes.Cut(line, []byte(":"))
+			if string(field) != "data" {
+				continue
+			}
+			if hasData {
+				data = append(data, '\n')
+			}
+			data = append(data, bytes.TrimPrefix(value, []byte(" "))...)
+			hasData = true
+		}
+	}
+}
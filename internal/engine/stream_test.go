@@ -0,0 +1,184 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// startStreamServer serves /sse, three token events and a [DONE] event with
+// a comment and event names in between, /ndjson, the same tokens as JSON
+// lines, /slow, one event and then nothing until the client leaves, /broken,
+// one event and then an aborted connection, and /error, a 500.
+func startStreamServer(t *testing.T) string {
+	t.Helper()
+	send := func(w http.ResponseWriter, format string, args ...any) {
+		fmt.Fprintf(w, format, args...)
+		w.(http.Flusher).Flush()
+		time.Sleep(5 * time.Millisecond)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		send(w, ": keep-alive\n\n")
+		for _, token := range []string{"a", "b", "c"} {
+			send(w, "event: token\ndata: {\"token\": \"%s\"}\n\n", token)
+		}
+		send(w, "data: [DONE]\n\n")
+	})
+	mux.HandleFunc("/ndjson", func(w http.ResponseWriter, r *http.Request) {
+		for _, token := range []string{"a", "b", "c"} {
+			send(w, "{\"token\": \"%s\"}\r\n\n", token)
+		}
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		send(w, "data: first\n\n")
+		<-r.Context().Done()
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		send(w, "data: first\n\n")
+		panic(http.ErrAbortHandler)
+	})
+	mux.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "data: unavailable\n\n", http.StatusInternalServerError)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestWorkerStream(t *testing.T) {
+	base := startStreamServer(t)
+	tokenIs := func(token string, match model.EventMatch) model.EventCheck {
+		return model.EventCheck{Assertion: model.Assertion{Type: model.AssertionJSONPath, Target: "token", Value: token}, Match: match}
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		timeoutMs  int
+		stream     model.StreamConfig
+		success    bool
+		errorClass string
+		streams    int64
+		events     int64
+		cut        int64
+	}{
+		{"sse", "/sse", 5000, model.StreamConfig{EndData: "[DONE]"}, true, "", 1, 3, 0},
+		{"sse counts the end event without end data", "/sse", 5000, model.StreamConfig{}, true, "", 1, 4, 0},
+		{"max events", "/sse", 5000, model.StreamConfig{MaxEvents: 2}, true, "", 1, 2, 1},
+		{"lines", "/ndjson", 5000, model.StreamConfig{Format: model.StreamLines}, true, "", 1, 3, 0},
+		{"chunks", "/slow", 5000, model.StreamConfig{Format: model.StreamChunks, MaxEvents: 1}, true, "", 1, 1, 1},
+		{"max duration", "/slow", 5000, model.StreamConfig{MaxDurationMs: 100}, true, "", 1, 1, 1},
+		{"plan timeout", "/slow", 100, model.StreamConfig{}, false, model.ErrorClassTimeout, 1, 1, 0},
+		{"broken stream", "/broken", 5000, model.StreamConfig{}, false, model.ErrorClassEOF, 1, 1, 0},
+		{"error status is not streamed", "/error", 5000, model.StreamConfig{}, false, model.ErrorClassHTTP5xx, 0, 0, 0},
+		{"every event check", "/sse", 5000, model.StreamConfig{EndData: "[DONE]", EventChecks: []model.EventCheck{tokenIs("a", "")}}, false, model.ErrorClassCheckFailed, 1, 3, 0},
+		{"any event check", "/sse", 5000, model.StreamConfig{EndData: "[DONE]", EventChecks: []model.EventCheck{tokenIs("c", model.EventMatchAny)}}, true, "", 1, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &model.TestPlan{ID: "test-stream", Method: "GET", TargetURL: base + tt.path, TimeoutMs: tt.timeoutMs, Stream: &tt.stream}
+			m := model.NewMetrics("run-stream")
+			worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+			worker.streamHists = []*model.Histogram{model.NewHistogram(), model.NewHistogram(), model.NewHistogram()}
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if (m.SuccessRequests == 1) != tt.success {
+				t.Errorf("Expected success %v, got %d successful and %d failed requests (errors %v)", tt.success, m.SuccessRequests, m.FailedRequests, m.ErrorSamples)
+			}
+			if tt.errorClass != "" && m.Errors[tt.errorClass] != 1 {
+				t.Errorf("Expected the failure to be counted as %s, got %v", tt.errorClass, m.Errors)
+			}
+			if tt.streams == 0 {
+				if m.Stream != nil {
+					t.Errorf("Expected no stream, got %+v", m.Stream)
+				}
+				return
+			}
+			st := m.Stream
+			if st == nil || st.Streams != tt.streams || st.Events != tt.events || st.CutStreams != tt.cut {
+				t.Fatalf("Expected %d events and %d cut streams, got %+v", tt.events, tt.cut, st)
+			}
+			firstEvent, gaps, duration := worker.streamHists[0], worker.streamHists[1], worker.streamHists[2]
+			if firstEvent.TotalCount() != 1 || gaps.TotalCount() != tt.events-1 || duration.TotalCount() != 1 {
+				t.Errorf("Expected one first event, %d gaps and one duration, got %d, %d and %d",
+					tt.events-1, firstEvent.TotalCount(), gaps.TotalCount(), duration.TotalCount())
+			}
+		})
+	}
+}
+
+func TestWorkerStreamChecks(t *testing.T) {
+	base := startStreamServer(t)
+
+	plan := &model.TestPlan{
+		ID:         "test-stream-checks",
+		Method:     "GET",
+		TargetURL:  base + "/sse",
+		TimeoutMs:  5000,
+		Assertions: []model.Assertion{{Type: model.AssertionBodyContains, Value: `{"token": "b"}`}},
+		Stream: &model.StreamConfig{EndData: "[DONE]", EventChecks: []model.EventCheck{
+			{Assertion: model.Assertion{Type: model.AssertionBodySize, Operator: "lt", Value: float64(20)}},
+			{Assertion: model.Assertion{Name: "finished", Type: model.AssertionBodyContains, Value: "[DONE]"}, Match: model.EventMatchAny},
+		}},
+	}
+	m := model.NewMetrics("run-stream-checks")
+	checks := planChecks(plan)
+	names := make([]string, len(checks))
+	for i := range checks {
+		names[i] = checks[i].Label()
+	}
+	m.InitChecks(names)
+	worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+
+	worker.executeRequest(context.Background(), Arrival{})
+
+	if m.FailedRequests != 1 || m.Stream.EventCheckFailures != 1 {
+		t.Fatalf("Expected the end event to fail its check, got %d failed requests and %+v", m.FailedRequests, m.Stream)
+	}
+	want := []string{"body_contains {\"token\": \"b\"} 1/0", "every event body_size lt 20 1/0", "finished 0/1"}
+	for i, check := range m.Checks {
+		if got := fmt.Sprintf("%s %d/%d", check.Name, check.Passes, check.Fails); got != want[i] {
+			t.Errorf("Expected check %s, got %s", want[i], got)
+		}
+	}
+	if sample := strings.Join(m.ErrorSamples[model.ErrorClassCheckFailed], ""); !strings.Contains(sample, "finished") {
+		t.Errorf("Expected the failed event check to be named, got %q", sample)
+	}
+}
+
+func TestEventSplitter(t *testing.T) {
+	tests := []struct {
+		format model.StreamFormat
+		body   string
+		want   []string
+	}{
+		{model.StreamSSE, "id: 1\ndata: one\ndata: two\n\n:comment\n\ndata:three\r\n\r\ndata: cut off", []string{"one\ntwo", "three"}},
+		{model.StreamSSE, "data\n\nevent: ping\n\n", []string{""}},
+		{model.StreamLines, "one\n\ntwo\r\nthree", []string{"one", "two", "three"}},
+	}
+
+	for _, tt := range tests {
+		next := eventSplitter(tt.format, strings.NewReader(tt.body))
+		var got []string
+		for {
+			data, err := next()
+			if err != nil {
+				break
+			}
+			got = append(got, string(data))
+		}
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.want) {
+			t.Errorf("%s: expected events %q, got %q", tt.format, tt.want, got)
+		}
+	}
+}
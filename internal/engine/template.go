@@ -17,6 +17,7 @@ var (
 	randomStringPattern = regexp.MustCompile(`\{\{random_string:(\d+)\}\}`)
 	envPattern          = regexp.MustCompile(`\{\{env:(\w+)\}\}`)
 	datePattern         = regexp.MustCompile(`\{\{date:([^}]+)\}\}`)
+	dataPattern         = regexp.MustCompile(`\{\{data\.([^}]+)\}\}`)
 )
 
 // TemplateEngine handles variable substitution in strings
@@ -95,6 +96,35 @@ func (t *TemplateEngine) Process(input string) string {
 	return result
 }
 
+// ProcessData substitutes {{data.column}} placeholders with values from a
+// data set row. Placeholders for columns the row does not have are kept.
+func (t *TemplateEngine) ProcessData(input string, row map[string]string) string {
+	if row == nil || !strings.Contains(input, "{{data.") {
+		return input
+	}
+
+	return dataPattern.ReplaceAllStringFunc(input, func(match string) string {
+		matches := dataPattern.FindStringSubmatch(match)
+		if value, ok := row[matches[1]]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// ProcessDataMap applies data row substitution to all values in a map
+func (t *TemplateEngine) ProcessDataMap(input map[string]string, row map[string]string) map[string]string {
+	if input == nil || row == nil {
+		return input
+	}
+
+	result := make(map[string]string, len(input))
+	for key, value := range input {
+		result[key] = t.ProcessData(value, row)
+	}
+	return result
+}
+
 // ProcessMap applies template substitution to all values in a map
 func (t *TemplateEngine) ProcessMap(input map[string]string) map[string]string {
 	if input == nil {
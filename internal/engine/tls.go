@@ -0,0 +1,61 @@
+package engine
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// buildTLSConfig builds the client TLS settings of a plan from its TLS
+// section and the certificates resolved from its secrets. It returns nil
+// for plans without a TLS section.
+func buildTLSConfig(cfg *model.TLSConfig, material *model.TLSMaterial) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // Opt-in for self-signed staging targets
+		MinVersion:         tls.VersionTLS12,
+	}
+	if cfg.MinVersion != "" {
+		tlsConfig.MinVersion = model.TLSVersions[cfg.MinVersion]
+	}
+	if cfg.MaxVersion != "" {
+		tlsConfig.MaxVersion = model.TLSVersions[cfg.MaxVersion]
+	}
+	for _, name := range cfg.CipherSuites {
+		id, ok := model.TLSCipherSuite(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite: %s", name)
+		}
+		tlsConfig.CipherSuites = append(tlsConfig.CipherSuites, id)
+	}
+
+	if cfg.ClientCertSecretID != "" {
+		if material == nil || material.ClientCert == "" {
+			return nil, fmt.Errorf("client certificate secret %s was not resolved", cfg.ClientCertSecretID)
+		}
+		cert, err := tls.X509KeyPair([]byte(material.ClientCert), []byte(material.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CASecretID != "" {
+		if material == nil || material.CACerts == "" {
+			return nil, fmt.Errorf("CA bundle secret %s was not resolved", cfg.CASecretID)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(material.CACerts)) {
+			return nil, errors.New("CA bundle contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,174 @@
+package engine
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// testCA signs certificates for TLS tests
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate CA key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create CA certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+// issue returns the PEM certificate and key of a leaf certificate for name
+func (ca *testCA) issue(t *testing.T, name string, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestPlanClientMutualTLS(t *testing.T) {
+	ca := newTestCA(t)
+	serverCert, serverKey := ca.issue(t, "api.internal", x509.ExtKeyUsageServerAuth)
+	clientCert, clientKey := ca.issue(t, "load-tester", x509.ExtKeyUsageClientAuth)
+
+	pair, err := tls.X509KeyPair([]byte(serverCert), []byte(serverKey))
+	if err != nil {
+		t.Fatalf("Failed to load server certificate: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	// The server only has a certificate for api.internal and insists on a
+	// client certificate signed by the CA
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MaxVersion:   tls.VersionTLS12,
+	}
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // Failed handshakes are expected
+	server.StartTLS()
+	defer server.Close()
+
+	material := &model.TLSMaterial{ClientCert: clientCert, ClientKey: clientKey, CACerts: ca.pem}
+	mtls := model.TLSConfig{ClientCertSecretID: "client", CASecretID: "ca", ServerName: "api.internal"}
+
+	tests := []struct {
+		name    string
+		cfg     model.TLSConfig
+		success bool
+	}{
+		{"client certificate and CA", mtls, true},
+		{"skip verify", model.TLSConfig{ClientCertSecretID: "client", InsecureSkipVerify: true}, true},
+		{"no client certificate", model.TLSConfig{CASecretID: "ca", ServerName: "api.internal"}, false},
+		{"unknown CA", model.TLSConfig{ClientCertSecretID: "client", ServerName: "api.internal"}, false},
+		{"wrong server name", model.TLSConfig{ClientCertSecretID: "client", CASecretID: "ca"}, false},
+		{"version mismatch", model.TLSConfig{ClientCertSecretID: "client", CASecretID: "ca", ServerName: "api.internal", MinVersion: "1.3"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tlsConfig, err := buildTLSConfig(&tt.cfg, material)
+			if err != nil {
+				t.Fatalf("Failed to build TLS config: %v", err)
+			}
+			plan := &model.TestPlan{ID: "test-mtls", TargetURL: server.URL, Method: "GET", TimeoutMs: 5000, TLS: &tt.cfg}
+			m := model.NewMetrics("run-mtls")
+			worker := NewWorker(1, plan, m, newPlanClient(nil, tlsConfig, 1), getSharedTestCollector())
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if tt.success {
+				if m.SuccessRequests != 1 {
+					t.Errorf("Expected the request to succeed, got errors %v", m.Errors)
+				}
+				return
+			}
+			if m.FailedRequests != 1 || m.Errors[model.ErrorClassTLS] != 1 {
+				t.Fatalf("Expected one failed request counted as a TLS error, got %d with errors %v", m.FailedRequests, m.Errors)
+			}
+			if samples := m.ErrorSamples[model.ErrorClassTLS]; len(samples) != 1 || !strings.Contains(samples[0], "tls: ") && !strings.Contains(samples[0], "x509: ") {
+				t.Errorf("Expected the handshake error as a sample, got %q", samples)
+			}
+		})
+	}
+}
+
+func TestBuildTLSConfig(t *testing.T) {
+	if cfg, err := buildTLSConfig(nil, nil); cfg != nil || err != nil {
+		t.Errorf("Expected no TLS config for a plan without TLS settings, got %v (%v)", cfg, err)
+	}
+
+	cfg, err := buildTLSConfig(&model.TLSConfig{
+		MinVersion:   "1.2",
+		MaxVersion:   "1.2",
+		CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to build TLS config: %v", err)
+	}
+	if cfg.MinVersion != tls.VersionTLS12 || cfg.MaxVersion != tls.VersionTLS12 ||
+		len(cfg.CipherSuites) != 1 || cfg.CipherSuites[0] != tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("Unexpected TLS config: %+v", cfg)
+	}
+
+	if _, err := buildTLSConfig(&model.TLSConfig{ClientCertSecretID: "missing"}, nil); err == nil {
+		t.Error("Expected an unresolved client certificate to fail")
+	}
+}
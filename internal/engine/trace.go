@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// requestTrace collects the timing phases of one HTTP request through
+// httptrace hooks. Dials run on their own goroutine and can still report
+// after the request was handed a different connection, so every hook takes
+// the lock.
+type requestTrace struct {
+	mu  sync.Mutex
+	hop traceHop
+}
+
+// traceHop holds the events of a single round trip
+type traceHop struct {
+	gotConn      bool
+	reused       bool
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+// withRequestTrace returns a context that traces every request made with it.
+// Redirects start a new hop, so the trace describes the last response.
+func withRequestTrace(ctx context.Context) (context.Context, *requestTrace) {
+	t := &requestTrace{}
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.hop = traceHop{}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.hop.gotConn = true
+			t.hop.reused = info.Reused
+		},
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.hop.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.mark(&t.hop.dnsDone) },
+		// Dual-stack dials race several addresses; the first to connect wins
+		ConnectStart: func(_, _ string) { t.mark(&t.hop.connectStart) },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.mark(&t.hop.connectDone)
+			}
+		},
+		TLSHandshakeStart: func() { t.mark(&t.hop.tlsStart) },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				t.mark(&t.hop.tlsDone)
+			}
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.hop.wroteRequest) },
+		GotFirstResponseByte: func() { t.mark(&t.hop.firstByte) },
+	}), t
+}
+
+// mark records the first time an event happens during the current hop
+func (t *requestTrace) mark(at *time.Time) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.IsZero() {
+		*at = now
+	}
+}
+
+// timing returns the phase breakdown of a request whose body finished
+// reading at end, or nil if the request never got a connection
+func (t *requestTrace) timing(end time.Time) *model.RequestTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	hop := t.hop
+	if !hop.gotConn {
+		return nil
+	}
+
+	timing := &model.RequestTiming{
+		TTFBMs:           spanMs(hop.wroteRequest, hop.firstByte),
+		TransferMs:       spanMs(hop.firstByte, end),
+		ConnectionReused: hop.reused,
+	}
+	if !hop.reused {
+		timing.DNSMs = spanMs(hop.dnsStart, hop.dnsDone)
+		timing.ConnectMs = spanMs(hop.connectStart, hop.connectDone)
+		timing.TLSMs = spanMs(hop.tlsStart, hop.tlsDone)
+	}
+	return timing
+}
+
+// spanMs returns the time between two events in milliseconds, or zero if
+// either of them did not happen
+func spanMs(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return durationMs(end.Sub(start))
+}
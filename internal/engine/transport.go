@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// defaultMaxRedirects matches the limit of Go's default redirect policy
+const defaultMaxRedirects = 10
+
+// newPlanClient builds an HTTP client with its own transport for a plan
+// with a transport or TLS section. cfg may be nil for the defaults. vus
+// sizes the idle pool when the plan sets no connection limits, so every VU
+// can keep its connection alive.
+func newPlanClient(cfg *model.TransportConfig, tlsConfig *tls.Config, vus int) *http.Client {
+	if cfg == nil {
+		cfg = &model.TransportConfig{}
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if cfg.ConnectTimeoutMs > 0 {
+		dialer.Timeout = time.Duration(cfg.ConnectTimeoutMs) * time.Millisecond
+	}
+
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		DialContext:         dialer.DialContext,
+		MaxConnsPerHost:     cfg.MaxConnsPerHost,
+		MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		DisableKeepAlives:   cfg.DisableKeepAlives,
+		TLSClientConfig:     tlsConfig,
+	}
+	if transport.MaxIdleConnsPerHost == 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxConnsPerHost
+		if transport.MaxIdleConnsPerHost == 0 {
+			transport.MaxIdleConnsPerHost = max(vus, http.DefaultMaxIdleConnsPerHost)
+		}
+	}
+	if cfg.TLSHandshakeTimeoutMs > 0 {
+		transport.TLSHandshakeTimeout = time.Duration(cfg.TLSHandshakeTimeoutMs) * time.Millisecond
+	}
+	if cfg.ResponseHeaderTimeoutMs > 0 {
+		transport.ResponseHeaderTimeout = time.Duration(cfg.ResponseHeaderTimeoutMs) * time.Millisecond
+	}
+
+	var protocols http.Protocols
+	switch cfg.HTTPVersion {
+	case model.HTTPVersionHTTP1:
+		protocols.SetHTTP1(true)
+	case model.HTTPVersionHTTP2:
+		protocols.SetHTTP2(true)
+	case model.HTTPVersionH2C:
+		protocols.SetUnencryptedHTTP2(true)
+	default:
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+	}
+	transport.Protocols = &protocols
+
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: redirectPolicy(cfg),
+	}
+}
+
+// withOwnPool returns a copy of a plan client with a connection pool of its
+// own, so that each VU looks like a distinct client to the target
+func withOwnPool(client *http.Client) *http.Client {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return client
+	}
+	return &http.Client{
+		Transport:     transport.Clone(),
+		CheckRedirect: client.CheckRedirect,
+	}
+}
+
+// redirectPolicy returns the CheckRedirect function for a transport config
+func redirectPolicy(cfg *model.TransportConfig) func(req *http.Request, via []*http.Request) error {
+	limit := cfg.MaxRedirects
+	if limit == 0 {
+		limit = defaultMaxRedirects
+	}
+
+	switch cfg.Redirects {
+	case model.RedirectNone:
+		return func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	case model.RedirectSameHost:
+		return func(req *http.Request, via []*http.Request) error {
+			if req.URL.Host != via[0].URL.Host {
+				return http.ErrUseLastResponse
+			}
+			return checkRedirectLimit(via, limit)
+		}
+	default:
+		return func(_ *http.Request, via []*http.Request) error {
+			return checkRedirectLimit(via, limit)
+		}
+	}
+}
+
+// checkRedirectLimit stops following redirects after limit hops
+func checkRedirectLimit(via []*http.Request, limit int) error {
+	if len(via) >= limit {
+		return fmt.Errorf("stopped after %d redirects", limit)
+	}
+	return nil
+}
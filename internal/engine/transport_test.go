@@ -0,0 +1,120 @@
+package engine
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// countConnections makes the server count every connection it accepts
+func countConnections(server *httptest.Server, conns *int64) {
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(conns, 1)
+		}
+	}
+}
+
+// get sends a GET request and drains the response so its connection can be reused
+func get(t *testing.T, client *http.Client, url string) *http.Response {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp
+}
+
+func TestPlanClientConnectionPools(t *testing.T) {
+	var conns int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	countConnections(server, &conns)
+	server.Start()
+	defer server.Close()
+
+	client := newPlanClient(&model.TransportConfig{}, nil, 2)
+	get(t, client, server.URL)
+	get(t, client, server.URL)
+	if n := atomic.LoadInt64(&conns); n != 1 {
+		t.Errorf("Expected a shared pool to reuse its connection, got %d connections", n)
+	}
+
+	// A VU with its own pool opens a connection of its own
+	get(t, withOwnPool(client), server.URL)
+	if n := atomic.LoadInt64(&conns); n != 2 {
+		t.Errorf("Expected a separate pool to open a new connection, got %d connections", n)
+	}
+
+	atomic.StoreInt64(&conns, 0)
+	noKeepAlive := newPlanClient(&model.TransportConfig{DisableKeepAlives: true}, nil, 1)
+	for i := 0; i < 3; i++ {
+		get(t, noKeepAlive, server.URL)
+	}
+	if n := atomic.LoadInt64(&conns); n != 3 {
+		t.Errorf("Expected a connection per request without keep-alive, got %d", n)
+	}
+}
+
+func TestPlanClientHTTPVersionsAndRedirects(t *testing.T) {
+	var protoMajor int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.StoreInt64(&protoMajor, int64(r.ProtoMajor))
+		switch r.URL.Path {
+		case "/same":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/away":
+			http.Redirect(w, r, "http://other.invalid/ok", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	get(t, newPlanClient(&model.TransportConfig{HTTPVersion: model.HTTPVersionH2C}, nil, 1), server.URL)
+	if atomic.LoadInt64(&protoMajor) != 2 {
+		t.Error("Expected h2c to speak HTTP/2 over cleartext")
+	}
+	get(t, newPlanClient(&model.TransportConfig{HTTPVersion: model.HTTPVersionHTTP1}, nil, 1), server.URL)
+	if atomic.LoadInt64(&protoMajor) != 1 {
+		t.Error("Expected http1 to speak HTTP/1.1")
+	}
+
+	tests := []struct {
+		name   string
+		cfg    model.TransportConfig
+		path   string
+		status int
+	}{
+		{"follow", model.TransportConfig{}, "/same", http.StatusOK},
+		{"none", model.TransportConfig{Redirects: model.RedirectNone}, "/same", http.StatusFound},
+		{"same host", model.TransportConfig{Redirects: model.RedirectSameHost}, "/away", http.StatusFound},
+		{"same host follows", model.TransportConfig{Redirects: model.RedirectSameHost}, "/same", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			if resp := get(t, newPlanClient(&cfg, nil, 1), server.URL+tt.path); resp.StatusCode != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+
+	if _, err := newPlanClient(&model.TransportConfig{MaxRedirects: 3}, nil, 1).Get(server.URL + "/loop"); err == nil {
+		t.Error("Expected a redirect loop to stop at max_redirects")
+	}
+}
@@ -0,0 +1,338 @@
+package engine
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// wsMethodLabel is the method WebSocket sessions are counted under in
+// Prometheus, where their status label is the handshake status
+const wsMethodLabel = "WS"
+
+const (
+	wsDefaultTimeout = 30 * time.Second // Handshake and reply timeout of requests without one
+	wsCloseTimeout   = time.Second      // Bounds the closing handshake at the end of a session
+)
+
+// errNoReplyMatchers is returned for a session whose request the client was
+// not built with
+var errNoReplyMatchers = errors.New("websocket request has no compiled reply matchers")
+
+// wsClient opens the WebSocket sessions of a run. The reply matchers of its
+// requests are compiled once, when the client is built.
+type wsClient struct {
+	dialer   *websocket.Dialer
+	matchers map[*model.WebSocketRequest][]func([]byte) bool
+}
+
+// newWSClient creates the client the sessions of reqs connect with. TLS
+// connections use the plan's tls settings.
+func newWSClient(tlsConfig *tls.Config, reqs ...*model.WebSocketRequest) (*wsClient, error) {
+	c := &wsClient{
+		dialer: &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			TLSClientConfig:  tlsConfig,
+			HandshakeTimeout: wsDefaultTimeout,
+		},
+		matchers: make(map[*model.WebSocketRequest][]func([]byte) bool, len(reqs)),
+	}
+	for _, req := range reqs {
+		matchers := make([]func([]byte) bool, len(req.Messages))
+		for i := range req.Messages {
+			match, err := replyMatcher(req.Messages[i].Reply)
+			if err != nil {
+				return nil, err
+			}
+			matchers[i] = match
+		}
+		c.matchers[req] = matchers
+	}
+	return c, nil
+}
+
+// webSocketRequests returns the WebSocket requests of a plan and its
+// scenario steps
+func webSocketRequests(plan *model.TestPlan) []*model.WebSocketRequest {
+	var reqs []*model.WebSocketRequest
+	if plan.WebSocket != nil {
+		reqs = append(reqs, plan.WebSocket)
+	}
+	if plan.Scenario != nil {
+		reqs = append(reqs, scenarioWebSocketRequests(plan.Scenario)...)
+	}
+	return reqs
+}
+
+// scenarioWebSocketRequests returns the WebSocket requests of a scenario's steps
+func scenarioWebSocketRequests(scenario *model.Scenario) []*model.WebSocketRequest {
+	var reqs []*model.WebSocketRequest
+	for i := range scenario.Steps {
+		if scenario.Steps[i].WebSocket != nil {
+			reqs = append(reqs, scenario.Steps[i].WebSocket)
+		}
+	}
+	return reqs
+}
+
+// wsResult is the outcome of one WebSocket session
+type wsResult struct {
+	session   *model.WebSocketSession
+	resp      *http.Response // Handshake response; nil if the server could not be reached
+	connected bool           // The handshake completed
+	reply     []byte         // Last reply matched, which checks and extractions apply to
+	err       error          // Why the session failed, nil if it succeeded
+}
+
+// statusCode returns the status the handshake was answered with, 0 without
+// a response
+func (r *wsResult) statusCode() int {
+	if r.resp == nil {
+		return 0
+	}
+	return r.resp.StatusCode
+}
+
+// run connects, sends req's messages, waits for their replies and holds the
+// connection before closing it. A session succeeds if the handshake
+// completes, every expected reply arrives in time and the server does not
+// close the connection abnormally. The cookies of jar, if set, are sent with
+// the handshake.
+func (c *wsClient) run(ctx context.Context, jar http.CookieJar, req *model.WebSocketRequest, process func(string) string, timeout time.Duration) *wsResult {
+	result := &wsResult{session: &model.WebSocketSession{}}
+	matchers, ok := c.matchers[req]
+	if !ok {
+		result.err = errNoReplyMatchers
+		return result
+	}
+
+	d := *c.dialer
+	d.Jar = jar
+	d.Subprotocols = req.Subprotocols
+	if timeout <= 0 {
+		timeout = wsDefaultTimeout
+	}
+	d.HandshakeTimeout = timeout
+	header := make(http.Header, len(req.Headers))
+	for k, v := range req.Headers {
+		header.Set(k, process(v))
+	}
+
+	startTime := time.Now()
+	conn, resp, err := d.DialContext(ctx, process(req.URL), header)
+	result.session.ConnectMs = durationMs(time.Since(startTime))
+	result.resp = resp
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.connected = true
+
+	s := newWSSession(conn, result.session)
+	defer s.close()
+	result.err = s.run(ctx, req, matchers, process, timeout, result)
+	return result
+}
+
+// replyMatcher returns whether a received message is the expected reply,
+// nil if the message expects none
+func replyMatcher(reply *model.WebSocketReply) (func([]byte) bool, error) {
+	switch {
+	case reply == nil:
+		return nil, nil
+	case reply.Regex != "":
+		re, err := regexp.Compile(reply.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reply regex: %w", err)
+		}
+		return re.Match, nil
+	}
+	return func(data []byte) bool {
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return false
+		}
+		value := extractJSONPath(v, reply.JSONPath)
+		if value == nil {
+			return false
+		}
+		return reply.Value == "" || compareValues(value, "eq", reply.Value)
+	}, nil
+}
+
+// wsSession is an open WebSocket connection. A reader goroutine hands the
+// received messages over until the connection closes.
+type wsSession struct {
+	conn     *websocket.Conn
+	stats    *model.WebSocketSession
+	received chan []byte
+	closed   chan struct{} // Closed once the connection can no longer be read
+	readErr  error         // Why reading stopped, set before closed is closed
+	done     chan struct{} // Closed when the session ends, stops the reader
+}
+
+func newWSSession(conn *websocket.Conn, stats *model.WebSocketSession) *wsSession {
+	s := &wsSession{
+		conn:     conn,
+		stats:    stats,
+		received: make(chan []byte),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go s.read()
+	return s
+}
+
+// read hands every received message over until the connection fails or
+// the session ends
+func (s *wsSession) read() {
+	defer close(s.closed)
+	for {
+		_, data, err := s.conn.ReadMessage()
+		if err != nil {
+			s.readErr = err
+			return
+		}
+		select {
+		case s.received <- data:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// run sends the messages of a session and holds it open
+func (s *wsSession) run(ctx context.Context, req *model.WebSocketRequest, matchers []func([]byte) bool, process func(string) string, timeout time.Duration, result *wsResult) error {
+	for i := range req.Messages {
+		msg := &req.Messages[i]
+		if _, err := s.await(ctx, time.Duration(msg.DelayMs)*time.Millisecond, nil); err != nil {
+			return err
+		}
+		repeat := max(msg.Repeat, 1)
+		for n := 0; n < repeat; n++ {
+			if n > 0 {
+				if _, err := s.await(ctx, time.Duration(msg.IntervalMs)*time.Millisecond, nil); err != nil {
+					return err
+				}
+			}
+			sentAt := time.Now()
+			if err := s.conn.WriteMessage(websocket.TextMessage, []byte(process(msg.Data))); err != nil {
+				return err
+			}
+			s.stats.MessagesSent++
+			if matchers[i] == nil {
+				continue
+			}
+
+			wait := timeout
+			if msg.Reply.TimeoutMs > 0 {
+				wait = time.Duration(msg.Reply.TimeoutMs) * time.Millisecond
+			}
+			reply, err := s.await(ctx, wait, matchers[i])
+			if err != nil {
+				return err
+			}
+			if reply == nil {
+				s.stats.ReplyTimeouts++
+				return fmt.Errorf("%w: no reply to message %d within %s", context.DeadlineExceeded, i+1, wait)
+			}
+			s.stats.RoundTripsMs = append(s.stats.RoundTripsMs, durationMs(time.Since(sentAt)))
+			result.reply = reply
+		}
+	}
+
+	// Neither the run stopping nor the server ending the session normally
+	// while it is held is an error
+	_, err := s.await(ctx, time.Duration(req.HoldMs)*time.Millisecond, nil)
+	var closeErr *websocket.CloseError
+	if errors.Is(err, context.Canceled) || (errors.As(err, &closeErr) && !abnormalClose(closeErr.Code)) {
+		return nil
+	}
+	return err
+}
+
+// await consumes received messages for up to d. It returns the first
+// message match accepts, or nil once d has passed; without match it only
+// waits. An error means the run stopped or the server closed the
+// connection.
+func (s *wsSession) await(ctx context.Context, d time.Duration, match func([]byte) bool) ([]byte, error) {
+	if d <= 0 && match == nil {
+		return nil, nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	for {
+		select {
+		case data := <-s.received:
+			s.stats.MessagesReceived++
+			if match != nil && match(data) {
+				return data, nil
+			}
+		case <-s.closed:
+			return nil, s.closeError()
+		case <-timer.C:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// closeError returns the error of a connection the server closed, and
+// records the close code of an abnormal close. A connection dropped without
+// a close frame has code 1006.
+func (s *wsSession) closeError() error {
+	var closeErr *websocket.CloseError
+	if !errors.As(s.readErr, &closeErr) {
+		closeErr = &websocket.CloseError{Code: websocket.CloseAbnormalClosure, Text: s.readErr.Error()}
+	}
+	if abnormalClose(closeErr.Code) {
+		s.stats.CloseCode = closeErr.Code
+	}
+	return fmt.Errorf("%w: %w", model.ErrWebSocketClosed, closeErr)
+}
+
+// abnormalClose reports whether a close code ends a session abnormally,
+// anything but a normal closure or the server going away
+func abnormalClose(code int) bool {
+	return code != websocket.CloseNormalClosure && code != websocket.CloseGoingAway
+}
+
+// close ends the session with a normal closure, waiting briefly for the
+// server to answer the close frame
+func (s *wsSession) close() {
+	defer s.conn.Close()
+	defer close(s.done)
+	select {
+	case <-s.closed:
+		return
+	default:
+	}
+
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	if err := s.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsCloseTimeout)); err != nil {
+		return
+	}
+	timer := time.NewTimer(wsCloseTimeout)
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.received:
+		case <-s.closed:
+			return
+		case <-timer.C:
+			return
+		}
+	}
+}
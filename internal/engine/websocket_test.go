@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+// startWebSocketServer serves a chat endpoint that answers {"type": "ping",
+// "user": name} with an ack and then a pong greeting the user, ignores
+// anything else but "crash", which it answers by closing the connection
+// with code 1011. Upgrades of /forbidden are rejected with 403.
+func startWebSocketServer(t *testing.T) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/forbidden", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+	})
+	mux.HandleFunc("/chat", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(data) == "crash" {
+				msg := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "boom")
+				_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+				return
+			}
+			var in struct{ Type, User string }
+			if json.Unmarshal(data, &in) != nil || in.Type != "ping" {
+				continue
+			}
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "ack"}`))
+			_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"type": "pong", "greeting": "hello `+in.User+`"}`))
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestWorkerWebSocket(t *testing.T) {
+	base := startWebSocketServer(t)
+	pong := &model.WebSocketReply{JSONPath: "$.type", Value: "pong"}
+	ping := model.WebSocketMessage{Data: `{"type": "ping", "user": "{{data.user}}"}`, Repeat: 2, IntervalMs: 10, Reply: pong}
+
+	tests := []struct {
+		name       string
+		path       string
+		messages   []model.WebSocketMessage
+		assertions []model.Assertion
+		success    bool
+		errorClass string
+		sent       int64
+		received   int64
+		roundTrips int
+	}{
+		{"replies", "/chat", []model.WebSocketMessage{ping}, nil, true, "", 2, 4, 2},
+		{"regex reply", "/chat", []model.WebSocketMessage{{Data: `{"type": "ping", "user": "bob"}`, Reply: &model.WebSocketReply{Regex: `hello \w+`}}}, nil, true, "", 1, 2, 1},
+		{"check on reply", "/chat", []model.WebSocketMessage{ping}, []model.Assertion{{Type: model.AssertionJSONPath, Target: "greeting", Value: "hello carol"}}, false, model.ErrorClassCheckFailed, 2, 4, 2},
+		{"reply timeout", "/chat", []model.WebSocketMessage{{Data: "hello", Reply: &model.WebSocketReply{Regex: "pong", TimeoutMs: 50}}}, nil, false, model.ErrorClassTimeout, 1, 0, 0},
+		{"abnormal close", "/chat", []model.WebSocketMessage{{Data: "crash", Reply: pong}}, nil, false, model.ErrorClassWebSocketClose, 1, 0, 0},
+		{"rejected handshake", "/forbidden", []model.WebSocketMessage{ping}, nil, false, model.ErrorClassHTTP4xx, 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := &model.TestPlan{ID: "test-ws", TimeoutMs: 5000, Assertions: tt.assertions, WebSocket: &model.WebSocketRequest{
+				URL:      base + tt.path,
+				Messages: tt.messages,
+			}}
+			m := model.NewMetrics("run-ws")
+			worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+			worker.wsRoundTripHist = model.NewHistogram()
+			worker.row = map[string]string{"user": "alice"}
+
+			worker.executeRequest(context.Background(), Arrival{})
+
+			if (m.SuccessRequests == 1) != tt.success {
+				t.Errorf("Expected success %v, got %d successful and %d failed sessions (errors %v)", tt.success, m.SuccessRequests, m.FailedRequests, m.ErrorSamples)
+			}
+			if tt.errorClass != "" && m.Errors[tt.errorClass] != 1 {
+				t.Errorf("Expected the failure to be counted as %s, got %v", tt.errorClass, m.Errors)
+			}
+			ws := m.WebSocket
+			if ws == nil || ws.Sessions != 1 || ws.MessagesSent != tt.sent || ws.MessagesReceived != tt.received {
+				t.Fatalf("Expected %d messages sent and %d received, got %+v", tt.sent, tt.received, ws)
+			}
+			if got := worker.wsRoundTripHist.TotalCount(); got != int64(tt.roundTrips) {
+				t.Errorf("Expected %d round trips, got %d", tt.roundTrips, got)
+			}
+		})
+	}
+}
+
+func TestWorkerWebSocketCloseCodes(t *testing.T) {
+	base := startWebSocketServer(t)
+
+	plan := &model.TestPlan{ID: "test-ws-close", TimeoutMs: 5000, WebSocket: &model.WebSocketRequest{
+		URL:      base + "/chat",
+		Messages: []model.WebSocketMessage{{Data: "crash"}},
+		HoldMs:   2000,
+	}}
+	m := model.NewMetrics("run-ws-close")
+	worker := NewWorker(1, plan, m, http.DefaultClient, getSharedTestCollector())
+
+	worker.executeRequest(context.Background(), Arrival{})
+
+	if m.FailedRequests != 1 || m.WebSocket.CloseCodes[websocket.CloseInternalServerErr] != 1 {
+		t.Errorf("Expected the held session to fail with close code 1011, got %d failed and %v", m.FailedRequests, m.WebSocket.CloseCodes)
+	}
+	if m.WebSocket.Connections != 1 || m.StatusCodes[http.StatusSwitchingProtocols] != 1 {
+		t.Errorf("Expected the handshake to be counted, got %d connections and statuses %v", m.WebSocket.Connections, m.StatusCodes)
+	}
+}
+
+func TestWSClientCompilesMatchersOnce(t *testing.T) {
+	invalid := &model.WebSocketRequest{URL: "ws://localhost", Messages: []model.WebSocketMessage{{Data: "hi", Reply: &model.WebSocketReply{Regex: "("}}}}
+	if _, err := newWSClient(nil, invalid); err == nil {
+		t.Error("Expected an invalid reply regex to be rejected when the client is built")
+	}
+
+	req := &model.WebSocketRequest{URL: "ws://localhost", Messages: []model.WebSocketMessage{{Data: "hi", Reply: &model.WebSocketReply{Regex: "pong"}}}}
+	client, err := newWSClient(nil, req)
+	if err != nil {
+		t.Fatalf("Failed to build client: %v", err)
+	}
+	if got := client.matchers[req]; len(got) != 1 || !got[0]([]byte("pong")) {
+		t.Errorf("Expected the reply matcher to be compiled with the client, got %v", got)
+	}
+	other := &model.WebSocketRequest{URL: "ws://localhost"}
+	if result := client.run(context.Background(), nil, other, func(s string) string { return s }, time.Second); !errors.Is(result.err, errNoReplyMatchers) {
+		t.Errorf("Expected a request the client was not built with to fail, got %v", result.err)
+	}
+}
+
+func TestScenarioExecutorWebSocket(t *testing.T) {
+	base := startWebSocketServer(t)
+
+	scenario := &model.Scenario{
+		ID: "scenario-ws",
+		Steps: []model.Step{
+			{
+				Name: "greet",
+				WebSocket: &model.WebSocketRequest{
+					URL:      base + "/chat",
+					Messages: []model.WebSocketMessage{{Data: `{"type": "ping", "user": "{{user}}"}`, Reply: &model.WebSocketReply{JSONPath: "type", Value: "pong"}}},
+				},
+				Extractions: []model.VariableExtraction{{Name: "greeting", Source: "body", Type: model.ExtractionJSONPath, Path: "greeting"}},
+				Assertions:  []model.Assertion{{Type: model.AssertionStatusCode, Value: float64(http.StatusSwitchingProtocols)}},
+			},
+			{
+				Name: "forbidden",
+				WebSocket: &model.WebSocketRequest{
+					URL: base + "/forbidden",
+				},
+			},
+		},
+	}
+
+	execution, err := NewScenarioExecutor().Execute(scenario, model.Variables{"user": "bob"})
+	if err != nil {
+		t.Fatalf("Failed to execute scenario: %v (%+v)", err, execution.StepResults)
+	}
+
+	greet, forbidden := execution.StepResults[0], execution.StepResults[1]
+	if greet.Status != statusSuccess || greet.WebSocket == nil || len(greet.WebSocket.RoundTripsMs) != 1 {
+		t.Errorf("Expected the greeting session to succeed with one round trip, got %+v", greet)
+	}
+	if greeting := execution.Variables["greeting"]; greeting != "hello bob" {
+		t.Errorf("Expected the greeting to be extracted from the reply, got %v", greeting)
+	}
+	if forbidden.Status != statusFailed || forbidden.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected the rejected handshake to fail the step with its status, got %+v", forbidden)
+	}
+}
@@ -3,124 +3,1029 @@ package engine
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/logger"
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/metrics"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
 )
 
+// Arrival is a scheduled iteration handed to a worker
+type Arrival struct {
+	// IntendedAt is when the scheduler meant the request to be sent. A zero
+	// value means the request had no schedule and starts whenever it is picked up.
+	IntendedAt time.Time
+}
+
+// queueDelay returns how long the arrival waited past its intended send time
+func (a Arrival) queueDelay(now time.Time) time.Duration {
+	if a.IntendedAt.IsZero() || !a.IntendedAt.Before(now) {
+		return 0
+	}
+	return now.Sub(a.IntendedAt)
+}
+
 // Worker represents a single worker that executes HTTP requests
 type Worker struct {
-	ID             int
-	plan           *model.TestPlan
-	client         *http.Client
-	metrics        *model.Metrics
-	latencyBuffer  *RingBuffer
-	collector      *metrics.Collector
-	templateEngine *TemplateEngine
+	ID               int
+	plan             *model.TestPlan
+	client           *http.Client
+	metrics          *model.Metrics
+	latencyHist      *model.Histogram
+	responseHist     *model.Histogram
+	collector        *metrics.Collector
+	templateEngine   *TemplateEngine
+	phaseHists       []*model.Histogram // Shared with the other workers, indexed like model.TimingPhases
+	firstAttemptHist *model.Histogram   // Shared with the other workers, for plans with a retry policy
+	jarReady         bool               // Whether the VU's cookie jar has been seeded
+	grpc             *grpcClient        // Shared with the other workers, for plans and scenarios with gRPC calls
+	websocket        *wsClient          // Shared with the other workers, for plans and scenarios with WebSocket sessions
+	wsConnectHist    *model.Histogram   // Shared with the other workers, for plans and scenarios with WebSocket sessions
+	wsRoundTripHist  *model.Histogram   // Shared with the other workers, for plans and scenarios with WebSocket sessions
+	streamHists      []*model.Histogram // Shared with the other workers, for streaming plans, indexed like model.StreamPhases
+	socket           *socketClient      // The VU's own, for plans with TCP or UDP requests
+	socketConnHist   *model.Histogram   // Shared with the other workers, for plans with TCP or UDP requests
+	socketRTTHist    *model.Histogram   // Shared with the other workers, for plans with TCP or UDP requests
+	db               *sql.DB            // Shared with the other workers, for plans and scenarios with SQL statements
+	redis            *redisPool         // Shared with the other workers, for plans with Redis commands
+
+	// Scenario plans only
+	stepRunner *ScenarioExecutor
+	stepHists  []*model.Histogram // Shared with the other workers, indexed by step
+	vars       model.Variables    // Per-VU variables, kept across iterations
+	iteration  int64
+
+	// Request mix, SQL and Redis plans only
+	requestHists []*model.Histogram // Shared with the other workers, indexed by request, statement or command name
+	rng          *rand.Rand
+
+	// Plans with a data feed only
+	feeder *Feeder
+	row    map[string]string // Data set row for the current iteration
+	stop   func()            // Ends the test once a once-strategy feed is exhausted
 }
 
 // NewWorker creates a new worker instance
 func NewWorker(id int, plan *model.TestPlan, metrics *model.Metrics, sharedClient *http.Client, collector *metrics.Collector) *Worker {
 	// Use the shared client but create a wrapper with timeout for this plan
 	client := &http.Client{
-		Transport: sharedClient.Transport,
-		Timeout:   time.Duration(plan.TimeoutMs) * time.Millisecond,
+		Transport:     sharedClient.Transport,
+		CheckRedirect: sharedClient.CheckRedirect,
+		Timeout:       time.Duration(plan.TimeoutMs) * time.Millisecond,
+	}
+	// Every VU keeps its own cookies, like a separate browser session
+	if cookiesEnabled(plan) {
+		client.Jar = newCookieJar()
 	}
 
-	// Create ring buffer: store last 10,000 latencies per worker
-	latencyBuffer := NewRingBuffer(10000)
-
-	return &Worker{
+	w := &Worker{
 		ID:             id,
 		plan:           plan,
 		client:         client,
 		metrics:        metrics,
-		latencyBuffer:  latencyBuffer,
+		latencyHist:    model.NewHistogram(),
+		responseHist:   model.NewHistogram(),
 		collector:      collector,
 		templateEngine: NewTemplateEngine(),
 	}
+
+	if plan.Scenario != nil {
+		w.stepRunner = &ScenarioExecutor{client: client, templates: w.templateEngine, retry: plan.Retry, files: plan.BodyFiles, protos: plan.Protos}
+	}
+
+	return w
 }
 
 // Run executes the worker's request loop until context is cancelled
-func (w *Worker) Run(ctx context.Context, requestChan <-chan struct{}) {
+func (w *Worker) Run(ctx context.Context, requestChan <-chan Arrival) {
+	w.RunUntil(ctx, ctx.Done(), requestChan)
+}
+
+// RunUntil executes the worker's request loop until stop is closed. Requests
+// run under ctx, so an iteration that is in flight when stop closes is allowed
+// to finish unless ctx is cancelled as well.
+func (w *Worker) RunUntil(ctx context.Context, stop <-chan struct{}, requestChan <-chan Arrival) {
 	logger.Log.Debug("Worker started",
 		zap.Int("worker_id", w.ID),
 		zap.String("target_url", w.plan.TargetURL))
+	defer w.closeSocket()
 
 	for {
 		select {
-		case <-ctx.Done():
+		case <-stop:
 			logger.Log.Debug("Worker stopped",
 				zap.Int("worker_id", w.ID))
 			return
-		case _, ok := <-requestChan:
+		case arrival, ok := <-requestChan:
 			if !ok {
 				return
 			}
-			w.executeRequest(ctx)
+			// Never start a new iteration once the worker has been stopped
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			w.executeRequest(ctx, arrival)
 		}
 	}
 }
 
-// executeRequest performs a single HTTP request and records metrics
-func (w *Worker) executeRequest(ctx context.Context) {
-	startTime := time.Now()
+// executeRequest performs a single HTTP request and records metrics.
+// Service time is measured from the moment the request is actually started,
+// response time from the arrival's intended send time, so that delays spent
+// waiting for a free worker are not omitted from the reported latencies.
+func (w *Worker) executeRequest(ctx context.Context, arrival Arrival) {
+	if w.feeder != nil {
+		row, ok := w.feeder.Next(w.ID)
+		if !ok {
+			if w.stop != nil {
+				w.stop()
+			}
+			return
+		}
+		w.row = row
+	}
 
-	// Apply template substitution to body and headers
-	processedBody := w.templateEngine.Process(w.plan.Body)
-	processedHeaders := w.templateEngine.ProcessMap(w.plan.Headers)
+	if w.stepRunner != nil {
+		w.executeIteration(ctx, arrival)
+		return
+	}
+	if w.plan.GRPC != nil {
+		w.sendGRPC(ctx, arrival, w.plan.GRPC)
+		return
+	}
+	if w.plan.WebSocket != nil {
+		w.sendWebSocket(ctx, arrival, w.plan.WebSocket)
+		return
+	}
+	if w.plan.Socket != nil {
+		w.sendSocket(ctx, arrival, w.plan.Socket)
+		return
+	}
+	if w.plan.DNS != nil {
+		w.sendDNS(ctx, arrival, w.plan.DNS)
+		return
+	}
+	if w.plan.SQL != nil {
+		w.sendSQL(ctx, arrival, w.plan.SQL)
+		return
+	}
+	if w.plan.Redis != nil {
+		w.sendRedis(ctx, arrival, w.plan.Redis)
+		return
+	}
 
-	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, w.plan.Method, w.plan.TargetURL, bytes.NewBufferString(processedBody))
+	w.prepareCookieJar(w.process)
+
+	if len(w.plan.Requests) > 0 {
+		index := w.pickRequest()
+		r := &w.plan.Requests[index]
+		w.sendRequest(ctx, arrival, r.Method, r.URL, r.Headers, r.Body, r.BodySource, index)
+		return
+	}
+
+	w.sendRequest(ctx, arrival, w.plan.Method, w.plan.TargetURL, w.plan.Headers, w.plan.Body, w.plan.BodySource, -1)
+}
+
+// pickRequest chooses an index into the plan's request mix with probability
+// proportional to each request's weight
+func (w *Worker) pickRequest() int {
+	return w.pickWeighted(len(w.plan.Requests), func(i int) int { return w.plan.Requests[i].Weight })
+}
+
+// pickStatement chooses an index into the plan's SQL statements with
+// probability proportional to each statement's weight, which defaults to 1
+func (w *Worker) pickStatement() int {
+	statements := w.plan.SQL.Statements
+	return w.pickWeighted(len(statements), func(i int) int { return max(statements[i].Weight, 1) })
+}
+
+// pickRedisCommand chooses an index into the plan's Redis commands with
+// probability proportional to each command's weight, which defaults to 1
+func (w *Worker) pickRedisCommand() int {
+	commands := w.plan.Redis.Commands
+	return w.pickWeighted(len(commands), func(i int) int { return max(commands[i].Weight, 1) })
+}
+
+// pickWeighted chooses an index below n with probability proportional to
+// the index's weight
+func (w *Worker) pickWeighted(n int, weight func(i int) int) int {
+	if w.rng == nil {
+		w.rng = rand.New(rand.NewSource(time.Now().UnixNano() + int64(w.ID)))
+	}
+
+	total := 0
+	for i := range n {
+		total += weight(i)
+	}
+	if total <= 0 {
+		return 0
+	}
+
+	pick := w.rng.Intn(total)
+	for i := range n {
+		if pick < weight(i) {
+			return i
+		}
+		pick -= weight(i)
+	}
+	return n - 1
+}
+
+// sendRequest performs one HTTP request, and retries it under the plan's
+// retry policy. A body source replaces the plain body. A non-negative index
+// also records the result in the plan's per-request breakdown.
+func (w *Worker) sendRequest(ctx context.Context, arrival Arrival, method, url string, headers map[string]string, body string, source *model.BodySource, index int) {
+	startTime := time.Now()
+	queueDelay := arrival.queueDelay(startTime)
+
+	// Apply data row and template substitution to URL, body and headers
+	url = w.process(url)
+	processedHeaders := w.templateEngine.ProcessMap(w.templateEngine.ProcessDataMap(headers, w.row))
+	payload, contentType, err := buildBody(w.process(body), source, w.plan.BodyFiles, w.process)
 	if err != nil {
-		latency := float64(time.Since(startTime).Milliseconds())
+		latency := durationMs(time.Since(startTime))
 		w.metrics.RecordRequest(false, latency, 0, err)
-		logger.Log.Error("Failed to create request",
+		w.metrics.RecordNamedRequest(index, false, 0, err)
+		logger.Log.Error("Failed to build request body",
 			zap.Int("worker_id", w.ID),
 			zap.Error(err))
 		return
 	}
 
-	// Add headers with template substitution
-	for key, value := range processedHeaders {
+	policy := w.plan.Retry
+	var result *attemptResult
+	attempts := 0
+	for {
+		attempts++
+		result = w.attempt(ctx, method, url, processedHeaders, payload, contentType)
+		if attempts == 1 && result.err == nil && w.firstAttemptHist != nil {
+			w.firstAttemptHist.Record(result.doneAt.Sub(startTime))
+		}
+		if result.success || !shouldRetry(policy, attempts, result.errorClass(), result.statusCode) {
+			break
+		}
+		if !sleepContext(ctx, retryDelay(policy, attempts, result.retryAfter)) {
+			break
+		}
+	}
+	if policy != nil {
+		w.metrics.RecordAttempts(attempts, result.success)
+	}
+
+	// Final latency covers every attempt, including the backoff delays
+	elapsed := result.doneAt.Sub(startTime)
+	latency := durationMs(elapsed)
+
+	if result.err != nil {
+		w.metrics.RecordRequest(false, latency, 0, result.err)
+		w.metrics.RecordNamedRequest(index, false, 0, result.err)
+		w.collector.RecordFailure(w.metrics.RunID, method, model.ClassifyError(result.err, 0))
+		logger.Log.Debug("Request failed",
+			zap.Int("worker_id", w.ID),
+			zap.Int("attempts", attempts),
+			zap.Error(result.err))
+		return
+	}
+
+	if result.checks != nil {
+		w.metrics.RecordChecks(result.checks)
+	}
+	w.metrics.RecordRequest(result.success, latency, result.statusCode, result.checkErr)
+	w.metrics.RecordNamedRequest(index, result.success, result.statusCode, result.checkErr)
+
+	// Store service and response time in histograms for percentile calculation
+	w.latencyHist.Record(elapsed)
+	w.responseHist.Record(elapsed + queueDelay)
+	if index >= 0 && index < len(w.requestHists) {
+		w.requestHists[index].Record(elapsed)
+	}
+
+	// Record to Prometheus
+	status := fmt.Sprintf("%d", result.statusCode)
+	w.collector.RecordRequest(w.metrics.RunID, method, status, latency/1000.0, failureClass(result.success, result.checkErr, result.statusCode))
+}
+
+// attemptResult is the outcome of one attempt at sending a request
+type attemptResult struct {
+	err        error     // Transport error, or failure to create the request
+	statusCode int       // Zero when err is set
+	success    bool      // Status accepted and every check passed
+	checks     []bool    // Result of every plan check, nil without checks
+	checkErr   error     // Names the failed checks of an accepted status
+	retryAfter string    // Retry-After header of the response
+	doneAt     time.Time // When the response headers arrived or the attempt failed
+}
+
+// errorClass returns the error class a failed attempt is counted under
+func (r *attemptResult) errorClass() string {
+	err := r.err
+	if err == nil {
+		err = r.checkErr
+	}
+	return model.ClassifyError(err, r.statusCode)
+}
+
+// attempt sends a request once, reads its response and runs the plan's checks
+func (w *Worker) attempt(ctx context.Context, method, url string, headers map[string]string, body []byte, contentType string) *attemptResult {
+	attemptStart := time.Now()
+
+	// Create HTTP request, traced to break its latency down into phases
+	traceCtx, trace := withRequestTrace(ctx)
+	req, err := http.NewRequestWithContext(traceCtx, method, url, bytes.NewReader(body))
+	if err != nil {
+		logger.Log.Error("Failed to create request",
+			zap.Int("worker_id", w.ID),
+			zap.Error(err))
+		return &attemptResult{err: err, doneAt: time.Now()}
+	}
+	for key, value := range headers {
 		req.Header.Set(key, value)
 	}
+	setContentType(req.Header, contentType)
 
 	// Execute request
 	resp, err := w.client.Do(req)
-	latency := float64(time.Since(startTime).Milliseconds())
+	result := &attemptResult{doneAt: time.Now()}
+	if err != nil {
+		result.err = err
+		return result
+	}
+	defer resp.Body.Close()
+	result.statusCode = resp.StatusCode
+	result.retryAfter = resp.Header.Get("Retry-After")
+
+	// Read successful responses of streaming plans event by event. Read
+	// other response bodies when checks need them, discard them otherwise,
+	// so the connection can be reused.
+	var respBody []byte
+	var stream *streamResult
+	switch {
+	case w.plan.Stream != nil && resp.StatusCode >= 200 && resp.StatusCode < 300:
+		stream = readStream(resp, w.plan.Stream, attemptStart, len(w.plan.Assertions) > 0)
+		respBody = stream.body
+	case len(w.plan.Assertions) > 0:
+		respBody, _ = io.ReadAll(resp.Body)
+	default:
+		_, _ = io.Copy(io.Discard, resp.Body)
+	}
+	w.recordTiming(trace.timing(time.Now()))
+
+	// Succeed based on the plan's checks, or on the status code when no
+	// check covers it
+	result.success = resp.StatusCode >= 200 && resp.StatusCode < 400
+	checks := planChecks(w.plan)
+	if len(checks) > 0 {
+		elapsed := result.doneAt.Sub(attemptStart)
+		results, passed, statusChecked := runChecks(w.plan.Assertions, resp, respBody, elapsed.Milliseconds())
+		if w.plan.Stream != nil {
+			events := eventCheckResults(w.plan.Stream, stream)
+			results = append(results, events...)
+			passed = passed && !slices.Contains(events, false)
+		}
+		if (statusChecked || result.success) && !passed {
+			result.checkErr = failedChecksError(checks, results)
+		}
+		result.success = (statusChecked || result.success) && passed
+		result.checks = results
+	}
+
+	// A stream the server broke off, or that outlived the plan's timeout,
+	// fails the request
+	if stream != nil {
+		w.recordStream(stream)
+		if stream.err != nil {
+			result.success = false
+			result.checkErr = fmt.Errorf("stream failed after %d events: %w", stream.events, stream.err)
+		}
+	}
+	return result
+}
+
+// process fills in the VU's data row and the template functions of s
+func (w *Worker) process(s string) string {
+	return w.templateEngine.Process(w.templateEngine.ProcessData(s, w.row))
+}
+
+// protocolResult is the response of a non-HTTP request, checked and
+// recorded by recordResult
+type protocolResult struct {
+	method       string        // Label the request is counted under in Prometheus
+	status       string        // Status label, e.g. a gRPC code or OK
+	statusCode   int           // Status the metrics count, only set for WebSocket handshakes
+	elapsed      time.Duration // Latency of the request
+	queueDelay   time.Duration // Time the arrival waited before the request was sent
+	index        int           // Entry of the per-request breakdown, -1 if none
+	ok           bool          // Whether the response succeeds without checks
+	err          error         // Why a response that is not ok failed, nil if its status says it all
+	statusChecks bool          // Whether a status_code check decides the outcome instead of ok, e.g. to accept a gRPC NOT_FOUND
+}
+
+// recordResult runs the plan's checks on a response and records it in the
+// metrics, histograms and Prometheus. checks returns what the checks see,
+// and is nil when the response cannot be checked.
+func (w *Worker) recordResult(result protocolResult, checks func() (*http.Response, []byte)) {
+	success := result.ok
+	var recordErr error
+	if !result.ok {
+		recordErr = result.err
+	}
+	if checks != nil && len(w.plan.Assertions) > 0 {
+		resp, body := checks()
+		results, passed, statusChecked := runChecks(w.plan.Assertions, resp, body, result.elapsed.Milliseconds())
+		if result.ok || (result.statusChecks && statusChecked) {
+			recordErr = nil
+			if !passed {
+				recordErr = failedChecksError(w.plan.Assertions, results)
+			}
+			success = passed
+		}
+		w.metrics.RecordChecks(results)
+	}
+
+	latency := durationMs(result.elapsed)
+	w.metrics.RecordRequest(success, latency, result.statusCode, recordErr)
+	if result.index >= 0 {
+		w.metrics.RecordNamedRequest(result.index, success, result.statusCode, recordErr)
+	}
+
+	w.latencyHist.Record(result.elapsed)
+	w.responseHist.Record(result.elapsed + result.queueDelay)
+	if result.index >= 0 && result.index < len(w.requestHists) {
+		w.requestHists[result.index].Record(result.elapsed)
+	}
+	w.collector.RecordRequest(w.metrics.RunID, result.method, result.status, latency/1000.0, failureClass(success, recordErr, result.statusCode))
+}
+
+// recordFailure records a non-HTTP request that failed without a response,
+// index being its entry of the per-request breakdown or -1
+func (w *Worker) recordFailure(method string, index int, elapsed time.Duration, err error) {
+	latency := durationMs(elapsed)
+	w.metrics.RecordRequest(false, latency, 0, err)
+	if index >= 0 {
+		w.metrics.RecordNamedRequest(index, false, 0, err)
+	}
+	w.collector.RecordFailure(w.metrics.RunID, method, model.ClassifyError(err, 0))
+}
+
+// sendGRPC performs one gRPC call and records metrics. A call answered with
+// a non-OK status is a response, counted like an HTTP error status unless a
+// status_code check accepts its code.
+func (w *Worker) sendGRPC(ctx context.Context, arrival Arrival, req *model.GRPCRequest) {
+	startTime := time.Now()
+	queueDelay := arrival.queueDelay(startTime)
+	if w.grpc == nil {
+		w.metrics.RecordRequest(false, 0, 0, errNoGRPCClient)
+		logger.Log.Error("gRPC client not set", zap.Int("worker_id", w.ID))
+		return
+	}
+
+	message := w.process(req.Message)
+	md := w.templateEngine.ProcessMap(w.templateEngine.ProcessDataMap(req.Metadata, w.row))
+	callCtx, cancel := withCallTimeout(ctx, w.plan.TimeoutMs, 0)
+	resp, err := w.grpc.call(callCtx, req, message, md)
+	cancel()
+	elapsed := time.Since(startTime)
+	if resp != nil {
+		w.metrics.RecordGRPCStatus(grpcCodeName(resp.code))
+		err = resp.err()
+	}
 
 	if err != nil {
-		w.metrics.RecordRequest(false, latency, 0, err)
-		logger.Log.Debug("Request failed",
+		w.recordFailure(grpcMethodLabel, -1, elapsed, err)
+		logger.Log.Debug("gRPC call failed",
 			zap.Int("worker_id", w.ID),
+			zap.String("method", req.Method),
 			zap.Error(err))
 		return
 	}
-	defer resp.Body.Close()
 
-	// Read and discard response body to allow connection reuse
-	_, _ = io.Copy(io.Discard, resp.Body)
+	code := grpcCodeName(resp.code)
+	w.recordResult(protocolResult{
+		method:       grpcMethodLabel,
+		status:       code,
+		elapsed:      elapsed,
+		queueDelay:   queueDelay,
+		index:        -1,
+		ok:           resp.code == codes.OK,
+		err:          grpcStatusError(code, resp.message),
+		statusChecks: true,
+	}, func() (*http.Response, []byte) {
+		return resp.response(), resp.body
+	})
+}
 
-	// Record success/failure based on status code
-	success := resp.StatusCode >= 200 && resp.StatusCode < 400
-	w.metrics.RecordRequest(success, latency, resp.StatusCode, nil)
+// sendWebSocket runs one WebSocket session and records metrics. Its latency
+// is the handshake time, and the plan's checks apply to the handshake
+// response and the last reply the session matched. A handshake the server
+// rejects is counted by its status, like an HTTP response.
+func (w *Worker) sendWebSocket(ctx context.Context, arrival Arrival, req *model.WebSocketRequest) {
+	startTime := time.Now()
+	queueDelay := arrival.queueDelay(startTime)
+	if w.websocket == nil {
+		client, err := newWSClient(nil, req)
+		if err != nil {
+			w.metrics.RecordRequest(false, durationMs(time.Since(startTime)), 0, err)
+			logger.Log.Error("Failed to prepare WebSocket session",
+				zap.Int("worker_id", w.ID),
+				zap.Error(err))
+			return
+		}
+		w.websocket = client
+	}
+	result := w.websocket.run(ctx, w.client.Jar, req, w.process, time.Duration(w.plan.TimeoutMs)*time.Millisecond)
+	w.recordWebSocket(result.session, result.connected)
+	elapsed := time.Duration(result.session.ConnectMs * float64(time.Millisecond))
+	statusCode := result.statusCode()
 
-	// Store latency in ring buffer for percentile calculation
-	w.latencyBuffer.Add(latency)
+	if !result.connected && statusCode == 0 {
+		w.recordFailure(wsMethodLabel, -1, elapsed, result.err)
+		logger.Log.Debug("WebSocket connection failed",
+			zap.Int("worker_id", w.ID),
+			zap.Error(result.err))
+		return
+	}
 
-	// Record to Prometheus
-	status := fmt.Sprintf("%d", resp.StatusCode)
-	w.collector.RecordRequest(w.metrics.RunID, w.plan.Method, status, latency/1000.0, !success)
+	// A rejected handshake is counted by its status alone
+	ok := result.connected && result.err == nil
+	recordErr := result.err
+	if !result.connected && statusCode >= 400 {
+		recordErr = nil
+	}
+	var checks func() (*http.Response, []byte)
+	if ok {
+		checks = func() (*http.Response, []byte) {
+			return result.resp, result.reply
+		}
+	}
+	w.recordResult(protocolResult{
+		method:     wsMethodLabel,
+		status:     fmt.Sprintf("%d", statusCode),
+		statusCode: statusCode,
+		elapsed:    elapsed,
+		queueDelay: queueDelay,
+		index:      -1,
+		ok:         ok,
+		err:        recordErr,
+	}, checks)
+}
+
+// recordWebSocket counts the messages of a WebSocket session and adds its
+// handshake and round trip times to the shared histograms
+func (w *Worker) recordWebSocket(session *model.WebSocketSession, connected bool) {
+	w.metrics.RecordWebSocketSession(session, connected)
+	if connected && w.wsConnectHist != nil {
+		w.wsConnectHist.Record(time.Duration(session.ConnectMs * float64(time.Millisecond)))
+	}
+	if w.wsRoundTripHist != nil {
+		for _, ms := range session.RoundTripsMs {
+			w.wsRoundTripHist.Record(time.Duration(ms * float64(time.Millisecond)))
+		}
+	}
+}
+
+// sendSocket performs one TCP or UDP exchange on the VU's socket client
+func (w *Worker) sendSocket(ctx context.Context, arrival Arrival, req *model.SocketRequest) {
+	startTime := time.Now()
+	queueDelay := arrival.queueDelay(startTime)
+	method := socketMethodLabel(req.Network)
+
+	payload, err := decodePayload(w.process(req.Payload), req.Encoding)
+	if err == nil && w.socket == nil {
+		w.socket, err = newSocketClient(req)
+	}
+	if err != nil {
+		w.metrics.RecordRequest(false, durationMs(time.Since(startTime)), 0, err)
+		logger.Log.Error("Failed to prepare socket exchange",
+			zap.Int("worker_id", w.ID),
+			zap.Error(err))
+		return
+	}
+
+	result := w.socket.exchange(ctx, w.process(req.Address), payload, time.Duration(w.plan.TimeoutMs)*time.Millisecond)
+	w.recordSocket(result)
+	elapsed := time.Since(startTime)
+
+	if result.err != nil {
+		w.recordFailure(method, -1, elapsed, result.err)
+		logger.Log.Debug("Socket exchange failed",
+			zap.Int("worker_id", w.ID),
+			zap.String("network", string(req.Network)),
+			zap.Error(result.err))
+		return
+	}
+
+	w.recordResult(protocolResult{
+		method:     method,
+		status:     "OK",
+		elapsed:    elapsed,
+		queueDelay: queueDelay,
+		index:      -1,
+		ok:         true,
+	}, func() (*http.Response, []byte) {
+		return &http.Response{Header: http.Header{}}, result.response
+	})
+}
+
+// recordSocket counts an exchange's connection and bytes and adds its
+// connect and round trip times to the shared histograms
+func (w *Worker) recordSocket(result *socketResult) {
+	w.metrics.RecordSocketExchange(result.connected, result.sent, result.received, result.timedOut)
+	if result.connected && w.socketConnHist != nil {
+		w.socketConnHist.Record(result.connect)
+	}
+	if result.response != nil && w.socketRTTHist != nil {
+		w.socketRTTHist.Record(result.roundTrip)
+	}
+}
+
+// closeSocket closes the VU's kept-alive TCP or UDP connection, if any
+func (w *Worker) closeSocket() {
+	if w.socket != nil {
+		w.socket.close()
+	}
+}
+
+// sendDNS sends one DNS query and records metrics. A query answered with a
+// response code other than NOERROR is a response, counted like an HTTP
+// error status unless a status_code check accepts its code.
+func (w *Worker) sendDNS(ctx context.Context, arrival Arrival, req *model.DNSRequest) {
+	startTime := time.Now()
+	queueDelay := arrival.queueDelay(startTime)
+
+	name := w.process(req.Name)
+	answer, err := queryDNS(ctx, req, w.process(req.Server), name, time.Duration(w.plan.TimeoutMs)*time.Millisecond)
+	elapsed := time.Since(startTime)
+
+	if err != nil {
+		w.metrics.RecordDNSQuery("", 0, false)
+		w.recordFailure(dnsMethodLabel, -1, elapsed, err)
+		logger.Log.Debug("DNS query failed",
+			zap.Int("worker_id", w.ID),
+			zap.String("name", name),
+			zap.Error(err))
+		return
+	}
+
+	code := dnsRcodeName(answer.rcode)
+	w.metrics.RecordDNSQuery(code, len(answer.records), answer.truncated)
+	w.recordResult(protocolResult{
+		method:       dnsMethodLabel,
+		status:       code,
+		elapsed:      elapsed,
+		queueDelay:   queueDelay,
+		index:        -1,
+		ok:           answer.rcode == 0,
+		err:          dnsRcodeError(code, name),
+		statusChecks: true,
+	}, func() (*http.Response, []byte) {
+		return answer.response(), answer.body()
+	})
+}
+
+// sendSQL runs one statement of the plan's mix on the run's database and
+// records metrics. A statement that ran succeeds unless it fails one of the
+// plan's checks.
+func (w *Worker) sendSQL(ctx context.Context, arrival Arrival, req *model.SQLRequest) {
+	startTime := time.Now()
+	queueDelay := arrival.queueDelay(startTime)
+	index := w.pickStatement()
+	if w.db == nil {
+		w.metrics.RecordRequest(false, 0, 0, errNoSQLDatabase)
+		w.metrics.RecordNamedRequest(index, false, 0, errNoSQLDatabase)
+		logger.Log.Error("SQL database not set", zap.Int("worker_id", w.ID))
+		return
+	}
+	stmt := &req.Statements[index]
+
+	result, err := runSQL(ctx, w.db, stmt, w.process, w.plan.TimeoutMs)
+	elapsed := time.Since(startTime)
+
+	if err != nil {
+		w.metrics.RecordSQLQuery(0, 0, sqlErrorClass(err))
+		w.recordFailure(sqlMethodLabel, index, elapsed, err)
+		logger.Log.Debug("SQL statement failed",
+			zap.Int("worker_id", w.ID),
+			zap.String("statement", stmt.Name),
+			zap.Error(err))
+		return
+	}
+	w.metrics.RecordSQLQuery(result.returned, result.affected, "")
+
+	w.recordResult(protocolResult{
+		method:     sqlMethodLabel,
+		status:     "OK",
+		elapsed:    elapsed,
+		queueDelay: queueDelay,
+		index:      index,
+		ok:         true,
+	}, func() (*http.Response, []byte) {
+		return result.response(), result.body()
+	})
+}
+
+// sendRedis sends one of the plan's commands, picked by weight, or all of
+// them as a pipeline or transaction, on a connection of the run's pool and
+// records metrics. A send succeeds unless one of its commands is answered
+// with an error reply or it fails one of the plan's checks.
+func (w *Worker) sendRedis(ctx context.Context, arrival Arrival, req *model.RedisRequest) {
+	startTime := time.Now()
+	queueDelay := arrival.queueDelay(startTime)
+	if w.redis == nil {
+		w.metrics.RecordRequest(false, 0, 0, errNoRedisPool)
+		logger.Log.Error("Redis pool not set", zap.Int("worker_id", w.ID))
+		return
+	}
+	// indexes holds the position among the plan's commands of each command sent
+	var indexes []int
+	switch req.Mode {
+	case model.RedisModePipeline, model.RedisModeTransaction:
+		indexes = make([]int, len(req.Commands))
+		for i := range indexes {
+			indexes[i] = i
+		}
+	default:
+		indexes = []int{w.pickRedisCommand()}
+	}
+	commands := make([][]string, len(indexes))
+	for i, index := range indexes {
+		args := req.Commands[index].Args
+		commands[i] = make([]string, len(args))
+		for j, arg := range args {
+			commands[i][j] = w.process(arg)
+		}
+	}
+
+	result, err := w.redis.send(ctx, commands, time.Duration(w.plan.TimeoutMs)*time.Millisecond)
+	elapsed := time.Since(startTime)
+	w.recordRedis(indexes, result, err)
+
+	if err != nil {
+		w.recordFailure(redisMethodLabel, -1, elapsed, err)
+		logger.Log.Debug("Redis send failed",
+			zap.Int("worker_id", w.ID),
+			zap.Error(err))
+		return
+	}
+
+	status := "OK"
+	var replyErr error
+	i, reply, failed := result.firstError()
+	if failed {
+		status = reply.prefix()
+		replyErr = redisReplyError(w.redis.names[indexes[i]], reply)
+	}
+	w.recordResult(protocolResult{
+		method:     redisMethodLabel,
+		status:     status,
+		elapsed:    elapsed,
+		queueDelay: queueDelay,
+		index:      -1,
+		ok:         !failed,
+		err:        replyErr,
+	}, func() (*http.Response, []byte) {
+		return result.response(), result.body(indexes)
+	})
+}
+
+// recordRedis counts a send's commands and replies, and records each
+// command, given by its position among the plan's, and a transaction's EXEC
+// in the per-command breakdown. A command without a reply failed with the
+// send's error.
+func (w *Worker) recordRedis(indexes []int, result *redisResult, err error) {
+	var nils int
+	var errorReplies []string
+	for i, index := range indexes {
+		if i >= len(result.replies) {
+			w.metrics.RecordNamedRequest(index, false, 0, err)
+			continue
+		}
+		name := w.redis.names[index]
+		var replyErr error
+		switch reply := result.replies[i].(type) {
+		case nil:
+			nils++
+		case redisErrorReply:
+			errorReplies = append(errorReplies, reply.prefix())
+			replyErr = redisReplyError(name, reply)
+		}
+		w.metrics.RecordNamedRequest(index, replyErr == nil, 0, replyErr)
+		w.recordRedisTime(index, result.times[i])
+	}
+	if w.plan.Redis.Mode == model.RedisModeTransaction {
+		w.recordRedisExec(len(indexes), result, err)
+	}
+	w.metrics.RecordRedisSend(len(indexes), nils, errorReplies, result.opened)
+}
+
+// recordRedisExec records a transaction's EXEC, the breakdown entry after
+// its n commands. EXEC fails when the server discarded the transaction, or
+// with the send's error if it has no reply.
+func (w *Worker) recordRedisExec(n int, result *redisResult, err error) {
+	if n >= len(result.times) {
+		w.metrics.RecordNamedRequest(n, false, 0, err)
+		return
+	}
+	var execErr error
+	switch exec := result.exec.(type) {
+	case redisErrorReply:
+		execErr = redisReplyError(w.redis.names[n], exec)
+	case nil:
+		execErr = redisReplyError(w.redis.names[n], redisExecDiscarded)
+	}
+	w.metrics.RecordNamedRequest(n, execErr == nil, 0, execErr)
+	w.recordRedisTime(n, result.times[n])
+}
+
+// recordRedisTime records the latency of the breakdown entry at index
+func (w *Worker) recordRedisTime(index int, d time.Duration) {
+	if index < len(w.requestHists) {
+		w.requestHists[index].Record(d)
+	}
+}
+
+// executeIteration runs the plan's scenario once as a single VU iteration.
+// Variables are kept per VU across iterations, so a step can use skip_if to
+// run only once per VU (e.g. login). A transport error or failed assertion
+// aborts the rest of the iteration, since later steps usually depend on it.
+func (w *Worker) executeIteration(ctx context.Context, arrival Arrival) {
+	scenario := w.plan.Scenario
+	if w.vars == nil {
+		w.vars = make(model.Variables, len(scenario.Variables)+2)
+		for k, v := range scenario.Variables {
+			w.vars[k] = v
+		}
+		w.vars["vu_id"] = w.ID
+	}
+	w.iteration++
+	w.vars["iteration"] = w.iteration
+	for column, value := range w.row {
+		w.vars["data."+column] = value
+	}
+	w.prepareCookieJar(func(s string) string {
+		return w.templateEngine.Process(w.stepRunner.substituteVariables(s, w.vars))
+	})
+
+	// Only the first request of an iteration can have waited in the queue
+	queueDelay := arrival.queueDelay(time.Now())
+
+	for i := range scenario.Steps {
+		step := &scenario.Steps[i]
+
+		result, err := w.stepRunner.executeStep(ctx, step, w.vars)
+		if result.Skipped {
+			continue
+		}
+
+		assertionFailed := len(result.AssertionsFailed) > 0
+		success := err == nil && !assertionFailed && result.StatusCode >= 200 && result.StatusCode < 400
+		method, status := step.Method, fmt.Sprintf("%d", result.StatusCode)
+		if step.GRPC != nil {
+			success = err == nil && !assertionFailed && result.GRPCStatus == grpcCodeName(codes.OK)
+			method, status = grpcMethodLabel, result.GRPCStatus
+			if result.GRPCStatus != "" {
+				w.metrics.RecordGRPCStatus(result.GRPCStatus)
+			}
+		}
+		if step.WebSocket != nil {
+			success = err == nil && !assertionFailed && result.StatusCode == http.StatusSwitchingProtocols
+			method = wsMethodLabel
+			if result.WebSocket != nil {
+				w.recordWebSocket(result.WebSocket, result.StatusCode == http.StatusSwitchingProtocols)
+			}
+		}
+		if step.SQL != nil {
+			success = err == nil && !assertionFailed
+			method, status = sqlMethodLabel, "OK"
+			errorClass := ""
+			if err != nil {
+				errorClass = sqlErrorClass(err)
+			}
+			w.metrics.RecordSQLQuery(result.RowsReturned, result.RowsAffected, errorClass)
+		}
+
+		recordErr := err
+		if err == nil && assertionFailed {
+			recordErr = fmt.Errorf("step %s: %w: %s", step.Name, model.ErrCheckFailed, strings.Join(result.AssertionsFailed, ", "))
+		} else if err == nil && !success && step.GRPC != nil {
+			recordErr = fmt.Errorf("step %s: %w", step.Name, grpcStatusError(result.GRPCStatus, result.Error))
+		}
+		w.metrics.RecordRequest(success, result.ResponseTimeMs, result.StatusCode, recordErr)
+		w.metrics.RecordStep(i, success, assertionFailed)
+		if result.Attempts > 0 {
+			w.metrics.RecordAttempts(result.Attempts, success)
+			if w.firstAttemptHist != nil && result.FirstAttemptMs > 0 {
+				w.firstAttemptHist.Record(time.Duration(result.FirstAttemptMs * float64(time.Millisecond)))
+			}
+		}
+
+		if err == nil {
+			elapsed := time.Duration(result.ResponseTimeMs * float64(time.Millisecond))
+			w.latencyHist.Record(elapsed)
+			w.responseHist.Record(elapsed + queueDelay)
+			if i < len(w.stepHists) {
+				w.stepHists[i].Record(elapsed)
+			}
+			w.recordTiming(result.Timing)
+
+			w.collector.RecordRequest(w.metrics.RunID, method, status, result.ResponseTimeMs/1000.0, failureClass(success, recordErr, result.StatusCode))
+		} else {
+			w.collector.RecordFailure(w.metrics.RunID, method, model.ClassifyError(err, 0))
+			logger.Log.Debug("Step failed",
+				zap.Int("worker_id", w.ID),
+				zap.String("step", step.Name),
+				zap.Error(err))
+		}
+		queueDelay = 0
+
+		if err != nil || assertionFailed {
+			w.metrics.RecordIteration(false)
+			return
+		}
+
+		// Think time between steps
+		if step.ThinkTimeMs > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Duration(step.ThinkTimeMs) * time.Millisecond):
+			}
+		}
+	}
+
+	w.metrics.RecordIteration(true)
+}
+
+// recordStream counts a streamed response's events and adds their timing to
+// the shared stream histograms
+func (w *Worker) recordStream(stream *streamResult) {
+	w.metrics.RecordStream(stream.events, stream.cut, slices.Contains(stream.checks, false))
+	if len(w.streamHists) != len(model.StreamPhases) {
+		return
+	}
+	firstEvent, gaps, duration := w.streamHists[0], w.streamHists[1], w.streamHists[2]
+	if stream.events > 0 {
+		firstEvent.Record(stream.firstEvent)
+	}
+	for _, gap := range stream.gaps {
+		gaps.Record(gap)
+	}
+	duration.Record(stream.duration)
+}
+
+// recordTiming adds a request's phase breakdown to the shared per-phase
+// histograms and counts whether it reused a connection. TTFB and transfer
+// are recorded for every response, connection phases only when they happened.
+func (w *Worker) recordTiming(timing *model.RequestTiming) {
+	if timing == nil {
+		return
+	}
+	w.metrics.RecordConnection(timing.ConnectionReused)
+
+	if len(w.phaseHists) != len(model.TimingPhases) {
+		return
+	}
+	phases := [...]float64{timing.DNSMs, timing.ConnectMs, timing.TLSMs, timing.TTFBMs, timing.TransferMs}
+	for i, ms := range phases {
+		name := model.TimingPhases[i]
+		if ms > 0 || name == model.PhaseTTFB || name == model.PhaseTransfer {
+			w.phaseHists[i].Record(time.Duration(ms * float64(time.Millisecond)))
+		}
+	}
+}
+
+// LatencyHistogram returns the histogram of service times recorded by this worker
+func (w *Worker) LatencyHistogram() *model.Histogram {
+	return w.latencyHist
+}
+
+// ResponseHistogram returns the histogram of response times (service time
+// plus queueing delay) recorded by this worker
+func (w *Worker) ResponseHistogram() *model.Histogram {
+	return w.responseHist
+}
+
+// failureClass returns the error class a request is counted under in
+// Prometheus, or "" if it succeeded
+func failureClass(success bool, err error, statusCode int) string {
+	if success {
+		return ""
+	}
+	return model.ClassifyError(err, statusCode)
 }
 
-// GetLatencies returns all recorded latencies from the ring buffer
-func (w *Worker) GetLatencies() []float64 {
-	return w.latencyBuffer.GetAll()
+// durationMs converts a duration to fractional milliseconds, keeping
+// microsecond resolution
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000.0
 }
@@ -74,8 +74,8 @@ func TestWorkerExecuteRequest(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	requestChan := make(chan struct{}, 1)
-	requestChan <- struct{}{}
+	requestChan := make(chan Arrival, 1)
+	requestChan <- Arrival{}
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -151,8 +151,8 @@ func TestWorkerPOSTWithBody(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	requestChan := make(chan struct{}, 1)
-	requestChan <- struct{}{}
+	requestChan := make(chan Arrival, 1)
+	requestChan <- Arrival{}
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -210,8 +210,8 @@ func TestWorkerCustomHeaders(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	requestChan := make(chan struct{}, 1)
-	requestChan <- struct{}{}
+	requestChan := make(chan Arrival, 1)
+	requestChan <- Arrival{}
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -262,8 +262,8 @@ func TestWorkerTimeout(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	requestChan := make(chan struct{}, 1)
-	requestChan <- struct{}{}
+	requestChan := make(chan Arrival, 1)
+	requestChan <- Arrival{}
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -307,8 +307,8 @@ func TestWorkerServerError(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	requestChan := make(chan struct{}, 1)
-	requestChan <- struct{}{}
+	requestChan := make(chan Arrival, 1)
+	requestChan <- Arrival{}
 
 	var wg sync.WaitGroup
 	wg.Add(1)
@@ -354,9 +354,9 @@ func TestWorkerMultipleRequests(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	requestChan := make(chan struct{}, 10)
+	requestChan := make(chan Arrival, 10)
 	for i := 0; i < 10; i++ {
-		requestChan <- struct{}{}
+		requestChan <- Arrival{}
 	}
 
 	var wg sync.WaitGroup
@@ -400,9 +400,9 @@ func TestWorkerLatencyRecording(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	requestChan := make(chan struct{}, 5)
+	requestChan := make(chan Arrival, 5)
 	for i := 0; i < 5; i++ {
-		requestChan <- struct{}{}
+		requestChan <- Arrival{}
 	}
 
 	var wg sync.WaitGroup
@@ -435,6 +435,102 @@ func TestWorkerLatencyRecording(t *testing.T) {
 	}
 }
 
+func TestWorkerRecordsTimingPhases(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(20 * time.Millisecond) // Server think time
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:        "test-phases",
+		Name:      "Timing Phases Test",
+		TargetURL: server.URL,
+		Method:    "GET",
+		TimeoutMs: 5000,
+	}
+	m := model.NewMetrics("run-phases")
+	worker := NewWorker(1, plan, m, server.Client(), getSharedTestCollector())
+	worker.phaseHists = make([]*model.Histogram, len(model.TimingPhases))
+	for i := range worker.phaseHists {
+		worker.phaseHists[i] = model.NewHistogram()
+	}
+
+	for i := 0; i < 3; i++ {
+		worker.executeRequest(context.Background(), Arrival{})
+	}
+
+	if m.NewConnections != 1 || m.ReusedConnections != 2 {
+		t.Errorf("Expected 1 new and 2 reused connections, got %d and %d", m.NewConnections, m.ReusedConnections)
+	}
+
+	phases := model.NewPhaseMetrics(worker.phaseHists)
+	counts := make(map[string]int64, len(phases))
+	for _, p := range phases {
+		counts[p.Phase] = p.Count
+	}
+	// The server listens on an IP address, so there is no DNS lookup
+	want := map[string]int64{
+		model.PhaseDNS:      0,
+		model.PhaseConnect:  1,
+		model.PhaseTLS:      1,
+		model.PhaseTTFB:     3,
+		model.PhaseTransfer: 3,
+	}
+	for phase, n := range want {
+		if counts[phase] != n {
+			t.Errorf("Expected %d %s timings, got %d", n, phase, counts[phase])
+		}
+	}
+	if ttfb := phases[3]; ttfb.P50Ms < 15 {
+		t.Errorf("Expected TTFB to include the server think time, got %.2fms", ttfb.P50Ms)
+	}
+}
+
+func TestWorkerRecordsQueueingDelay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:        "test-queueing",
+		Name:      "Queueing Delay Test",
+		TargetURL: server.URL,
+		Method:    "GET",
+		TimeoutMs: 5000,
+	}
+	m := model.NewMetrics("run-queueing")
+	collector := getSharedTestCollector()
+
+	worker := NewWorker(1, plan, m, http.DefaultClient, collector)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Arrivals that were due 200ms ago, as if the worker had been stalled
+	requestChan := make(chan Arrival, 3)
+	for i := 0; i < 3; i++ {
+		requestChan <- Arrival{IntendedAt: time.Now().Add(-200 * time.Millisecond)}
+	}
+	close(requestChan)
+
+	worker.Run(ctx, requestChan)
+
+	latency := worker.LatencyHistogram()
+	response := worker.ResponseHistogram()
+	if latency.TotalCount() != 3 || response.TotalCount() != 3 {
+		t.Fatalf("Expected 3 recorded samples, got %d service and %d response", latency.TotalCount(), response.TotalCount())
+	}
+
+	if min := response.ValueAtPercentile(0.01); min < 200 {
+		t.Errorf("Response time %.3fms should include the 200ms queueing delay", min)
+	}
+	if max := latency.MaxMs(); max >= 200 {
+		t.Errorf("Service time %.3fms should exclude the queueing delay", max)
+	}
+}
+
 func TestWorkerContextCancellation(t *testing.T) {
 	var requestCount int64
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -458,9 +554,9 @@ func TestWorkerContextCancellation(t *testing.T) {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	requestChan := make(chan struct{}, 100)
+	requestChan := make(chan Arrival, 100)
 	for i := 0; i < 100; i++ {
-		requestChan <- struct{}{}
+		requestChan <- Arrival{}
 	}
 
 	var wg sync.WaitGroup
@@ -483,3 +579,267 @@ func TestWorkerContextCancellation(t *testing.T) {
 		t.Error("Worker should have stopped relatively quickly after cancellation")
 	}
 }
+
+func TestWorkerScenarioIteration(t *testing.T) {
+	var logins, browses, checkouts int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			atomic.AddInt64(&logins, 1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"token":"vu-token"}`))
+		case "/browse":
+			atomic.AddInt64(&browses, 1)
+			if r.Header.Get("Authorization") != "Bearer vu-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/checkout":
+			atomic.AddInt64(&checkouts, 1)
+			_, _ = w.Write([]byte("order for vu " + r.URL.Query().Get("vu")))
+		}
+	}))
+	defer server.Close()
+
+	scenario := &model.Scenario{
+		ID:   "scenario-checkout",
+		Name: "Checkout",
+		Steps: []model.Step{
+			{
+				Name:   "login",
+				Method: "POST",
+				URL:    server.URL + "/login",
+				// Log in once per VU; the token is kept across iterations
+				SkipIf:      &model.Condition{Variable: "token", Operator: "exists"},
+				Extractions: []model.VariableExtraction{{Name: "token", Source: "body", Type: model.ExtractionJSONPath, Path: "token"}},
+			},
+			{
+				Name:       "browse",
+				Method:     "GET",
+				URL:        server.URL + "/browse",
+				Headers:    map[string]string{"Authorization": "Bearer {{token}}"},
+				Assertions: []model.Assertion{{Type: model.AssertionStatusCode, Value: float64(200)}},
+			},
+			{
+				Name:       "checkout",
+				Method:     "POST",
+				URL:        server.URL + "/checkout?vu={{vu_id}}",
+				Assertions: []model.Assertion{{Type: model.AssertionBodyContains, Value: "order for vu 7"}},
+			},
+		},
+	}
+	plan := &model.TestPlan{
+		ID:        "test-scenario",
+		Name:      "Scenario Test",
+		Scenario:  scenario,
+		TimeoutMs: 5000,
+	}
+	m := model.NewMetrics("run-scenario")
+	m.InitSteps([]string{"login", "browse", "checkout"})
+	collector := getSharedTestCollector()
+
+	worker := NewWorker(7, plan, m, http.DefaultClient, collector)
+
+	requestChan := make(chan Arrival, 3)
+	for i := 0; i < 3; i++ {
+		requestChan <- Arrival{}
+	}
+	close(requestChan)
+
+	worker.Run(context.Background(), requestChan)
+
+	gotLogins, gotBrowses, gotCheckouts := atomic.LoadInt64(&logins), atomic.LoadInt64(&browses), atomic.LoadInt64(&checkouts)
+	if gotLogins != 1 || gotBrowses != 3 || gotCheckouts != 3 {
+		t.Errorf("Expected 1 login, 3 browses and 3 checkouts, got %d, %d and %d", gotLogins, gotBrowses, gotCheckouts)
+	}
+
+	snapshot := m.GetSnapshot()
+	if snapshot.Iterations != 3 || snapshot.FailedIterations != 0 {
+		t.Errorf("Expected 3 successful iterations, got %d ok and %d failed", snapshot.Iterations, snapshot.FailedIterations)
+	}
+	if snapshot.TotalRequests != 7 {
+		t.Errorf("Expected 7 requests across all steps, got %d", snapshot.TotalRequests)
+	}
+	for i, expected := range []int64{1, 3, 3} {
+		if snapshot.Steps[i].Requests != expected || snapshot.Steps[i].Failed != 0 {
+			t.Errorf("Step %s: expected %d requests and no failures, got %+v", snapshot.Steps[i].Name, expected, snapshot.Steps[i])
+		}
+	}
+}
+
+func TestWorkerScenarioAssertionAbortsIteration(t *testing.T) {
+	var secondStep int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/second" {
+			atomic.AddInt64(&secondStep, 1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:        "test-scenario-abort",
+		Name:      "Scenario Abort Test",
+		TimeoutMs: 5000,
+		Scenario: &model.Scenario{
+			ID: "scenario-abort",
+			Steps: []model.Step{
+				{Name: "first", Method: "GET", URL: server.URL + "/first",
+					Assertions: []model.Assertion{{Type: model.AssertionStatusCode, Value: float64(201)}}},
+				{Name: "second", Method: "GET", URL: server.URL + "/second"},
+			},
+		},
+	}
+	m := model.NewMetrics("run-scenario-abort")
+	m.InitSteps([]string{"first", "second"})
+	collector := getSharedTestCollector()
+
+	worker := NewWorker(1, plan, m, http.DefaultClient, collector)
+	worker.executeRequest(context.Background(), Arrival{})
+
+	snapshot := m.GetSnapshot()
+	if n := atomic.LoadInt64(&secondStep); n != 0 {
+		t.Errorf("Expected the failed assertion to skip the second step, it ran %d times", n)
+	}
+	if snapshot.FailedIterations != 1 || snapshot.Iterations != 0 {
+		t.Errorf("Expected 1 failed iteration, got %d ok and %d failed", snapshot.Iterations, snapshot.FailedIterations)
+	}
+	if snapshot.Steps[0].AssertionFailures != 1 || snapshot.FailedRequests != 1 {
+		t.Errorf("Expected the assertion failure to be recorded, got step %+v and %d failed requests",
+			snapshot.Steps[0], snapshot.FailedRequests)
+	}
+}
+
+func TestWorkerWeightedRequestMix(t *testing.T) {
+	var browses, checkouts int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/browse":
+			atomic.AddInt64(&browses, 1)
+			w.WriteHeader(http.StatusOK)
+		case "/checkout":
+			atomic.AddInt64(&checkouts, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	plan := &model.TestPlan{
+		ID:        "test-mix",
+		Name:      "Request Mix Test",
+		TimeoutMs: 5000,
+		Requests: []model.WeightedRequest{
+			{Name: "browse", Method: "GET", URL: server.URL + "/browse", Weight: 80},
+			{Name: "checkout", Method: "POST", URL: server.URL + "/checkout", Weight: 20},
+		},
+	}
+	m := model.NewMetrics("run-mix")
+	m.InitRequests([]string{"browse", "checkout"})
+	collector := getSharedTestCollector()
+
+	worker := NewWorker(1, plan, m, http.DefaultClient, collector)
+	const total = 500
+	for i := 0; i < total; i++ {
+		worker.executeRequest(context.Background(), Arrival{})
+	}
+
+	snapshot := m.GetSnapshot()
+	browse, checkout := snapshot.RequestBreakdown[0], snapshot.RequestBreakdown[1]
+	if browse.Requests+checkout.Requests != total {
+		t.Fatalf("Expected %d requests across the mix, got %d", total, browse.Requests+checkout.Requests)
+	}
+	if browse.Requests != atomic.LoadInt64(&browses) || checkout.Requests != atomic.LoadInt64(&checkouts) {
+		t.Errorf("Breakdown %d/%d does not match requests seen by the server %d/%d",
+			browse.Requests, checkout.Requests, atomic.LoadInt64(&browses), atomic.LoadInt64(&checkouts))
+	}
+
+	// 20% of 500 is 100; allow a wide margin for randomness
+	if checkout.Requests < 60 || checkout.Requests > 140 {
+		t.Errorf("Expected about 100 checkout requests for weight 20/100, got %d", checkout.Requests)
+	}
+	if browse.Failed != 0 || checkout.Failed != checkout.Requests {
+		t.Errorf("Expected only checkout to fail, got browse %+v and checkout %+v", browse, checkout)
+	}
+	if checkout.StatusCodes[http.StatusServiceUnavailable] != checkout.Requests {
+		t.Errorf("Expected checkout status codes to be broken down, got %v", checkout.StatusCodes)
+	}
+}
+
+func TestWorkerDataFeed(t *testing.T) {
+	var mu sync.Mutex
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.URL.Path+" "+r.Header.Get("X-User"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dataSet := &model.DataSet{
+		ID:      "users",
+		Columns: []string{"id", "name"},
+		Rows: []map[string]string{
+			{"id": "1", "name": "alice"},
+			{"id": "2", "name": "bob"},
+		},
+	}
+	plan := &model.TestPlan{
+		ID:        "test-data-feed",
+		Name:      "Data Feed Test",
+		TargetURL: server.URL + "/users/{{data.id}}",
+		Method:    "GET",
+		Headers:   map[string]string{"X-User": "{{data.name}}"},
+		TimeoutMs: 5000,
+		Data:      &model.DataFeed{DataSetID: "users", Strategy: model.FeedUnique},
+		DataSet:   dataSet,
+	}
+	m := model.NewMetrics("run-data-feed")
+	collector := getSharedTestCollector()
+	feeder := NewFeeder(dataSet, plan.Data)
+
+	for id := 0; id < 2; id++ {
+		worker := NewWorker(id, plan, m, http.DefaultClient, collector)
+		worker.feeder = feeder
+		worker.executeRequest(context.Background(), Arrival{})
+		worker.executeRequest(context.Background(), Arrival{})
+	}
+
+	expected := []string{"/users/1 alice", "/users/1 alice", "/users/2 bob", "/users/2 bob"}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(seen) != len(expected) {
+		t.Fatalf("Expected %d requests, got %v", len(expected), seen)
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Errorf("Request %d: expected %q, got %q", i, expected[i], seen[i])
+		}
+	}
+}
+
+func TestFeederOnceStrategy(t *testing.T) {
+	dataSet := &model.DataSet{Rows: []map[string]string{{"n": "1"}, {"n": "2"}}}
+
+	stop := NewFeeder(dataSet, &model.DataFeed{Strategy: model.FeedOnce})
+	for i := 0; i < 2; i++ {
+		if _, ok := stop.Next(0); !ok {
+			t.Fatalf("Expected row %d to be available", i)
+		}
+	}
+	if _, ok := stop.Next(0); ok {
+		t.Error("Expected once feeder to be exhausted after every row was used")
+	}
+
+	recycle := NewFeeder(dataSet, &model.DataFeed{Strategy: model.FeedOnce, OnExhausted: model.FeedExhaustedRecycle})
+	for i := 0; i < 3; i++ {
+		row, ok := recycle.Next(0)
+		if !ok {
+			t.Fatalf("Expected recycling feeder to keep handing out rows")
+		}
+		if want := dataSet.Rows[i%2]["n"]; row["n"] != want {
+			t.Errorf("Row %d: expected n=%s, got %s", i, want, row["n"])
+		}
+	}
+}
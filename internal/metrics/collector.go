@@ -12,11 +12,12 @@ import (
 // Collector holds Prometheus metrics for the stress test tool
 type Collector struct {
 	// Test execution metrics
-	RequestDuration *prometheus.HistogramVec
-	RequestsTotal   *prometheus.CounterVec
-	RequestsFailed  *prometheus.CounterVec
-	ActiveTests     prometheus.Gauge
-	ActiveWorkers   prometheus.Gauge
+	RequestDuration   *prometheus.HistogramVec
+	RequestsTotal     *prometheus.CounterVec
+	RequestsFailed    *prometheus.CounterVec
+	ActiveTests       prometheus.Gauge
+	ActiveWorkers     prometheus.Gauge
+	DroppedIterations *prometheus.CounterVec
 
 	// API server metrics
 	HTTPRequestDuration  *prometheus.HistogramVec
@@ -66,9 +67,9 @@ func NewCollector() *Collector {
 			RequestsFailed: promauto.NewCounterVec(
 				prometheus.CounterOpts{
 					Name: "http_requests_failed_total",
-					Help: "Total number of failed HTTP requests",
+					Help: "Total number of failed HTTP requests by error class",
 				},
-				[]string{"run_id", "method"},
+				[]string{"run_id", "method", "error_class"},
 			),
 			ActiveTests: promauto.NewGauge(
 				prometheus.GaugeOpts{
@@ -82,6 +83,13 @@ func NewCollector() *Collector {
 					Help: "Number of currently active workers across all tests",
 				},
 			),
+			DroppedIterations: promauto.NewCounterVec(
+				prometheus.CounterOpts{
+					Name: "stress_test_dropped_iterations_total",
+					Help: "Total number of scheduled iterations dropped because no VU was available",
+				},
+				[]string{"run_id"},
+			),
 
 			// API server metrics
 			HTTPRequestDuration: promauto.NewHistogramVec(
@@ -187,16 +195,27 @@ func ResetForTesting() {
 	defaultCollector = nil
 }
 
-// RecordRequest records a request metric
-func (c *Collector) RecordRequest(runID, method, status string, durationSec float64, failed bool) {
+// RecordRequest records a request metric. errorClass is the class a failed
+// request is counted under, empty for a successful one.
+func (c *Collector) RecordRequest(runID, method, status string, durationSec float64, errorClass string) {
 	c.RequestDuration.WithLabelValues(runID, method, status).Observe(durationSec)
 	c.RequestsTotal.WithLabelValues(runID, method, status).Inc()
 
-	if failed {
-		c.RequestsFailed.WithLabelValues(runID, method).Inc()
+	if errorClass != "" {
+		c.RecordFailure(runID, method, errorClass)
 	}
 }
 
+// RecordFailure records a failed request, including one that got no response
+func (c *Collector) RecordFailure(runID, method, errorClass string) {
+	c.RequestsFailed.WithLabelValues(runID, method, errorClass).Inc()
+}
+
+// RecordDroppedIteration records a scheduled iteration that could not be started
+func (c *Collector) RecordDroppedIteration(runID string) {
+	c.DroppedIterations.WithLabelValues(runID).Inc()
+}
+
 // SetActiveTests sets the number of active tests
 func (c *Collector) SetActiveTests(count int) {
 	c.ActiveTests.Set(float64(count))
@@ -21,18 +21,59 @@ type ComparisonResult struct {
 
 // MetricDifferences represents the differences between two test runs
 type MetricDifferences struct {
-	TotalRequests      DiffValue `json:"total_requests"`
-	SuccessfulRequests DiffValue `json:"successful_requests"`
-	FailedRequests     DiffValue `json:"failed_requests"`
-	SuccessRate        DiffValue `json:"success_rate"`
-	AvgResponseTime    DiffValue `json:"avg_response_time"`
-	MinResponseTime    DiffValue `json:"min_response_time"`
-	MaxResponseTime    DiffValue `json:"max_response_time"`
-	P50                DiffValue `json:"p50"`
-	P75                DiffValue `json:"p75"`
-	P95                DiffValue `json:"p95"`
-	P99                DiffValue `json:"p99"`
-	RequestsPerSecond  DiffValue `json:"requests_per_second"`
+	TotalRequests      DiffValue     `json:"total_requests"`
+	SuccessfulRequests DiffValue     `json:"successful_requests"`
+	FailedRequests     DiffValue     `json:"failed_requests"`
+	SuccessRate        DiffValue     `json:"success_rate"`
+	AvgResponseTime    DiffValue     `json:"avg_response_time"`
+	MinResponseTime    DiffValue     `json:"min_response_time"`
+	MaxResponseTime    DiffValue     `json:"max_response_time"`
+	P50                DiffValue     `json:"p50"`
+	P75                DiffValue     `json:"p75"`
+	P95                DiffValue     `json:"p95"`
+	P99                DiffValue     `json:"p99"`
+	P999               DiffValue     `json:"p999"`
+	P9999              DiffValue     `json:"p9999"`
+	AvgResponse        DiffValue     `json:"avg_response"`
+	P50Response        DiffValue     `json:"p50_response"`
+	P95Response        DiffValue     `json:"p95_response"`
+	P99Response        DiffValue     `json:"p99_response"`
+	P999Response       DiffValue     `json:"p999_response"`
+	RequestsPerSecond  DiffValue     `json:"requests_per_second"`
+	DroppedIterations  DiffValue     `json:"dropped_iterations"`
+	CheckFailures      DiffValue     `json:"check_failures"`      // Responses that failed one of the plan's checks
+	Retries            DiffValue     `json:"retries"`             // Attempts sent after the first under a retry policy
+	RetryAmplification DiffValue     `json:"retry_amplification"` // Attempts sent per request under a retry policy
+	FirstAttemptP95    DiffValue     `json:"first_attempt_p95"`   // P95 of first attempts, before any retry
+	NewConnections     DiffValue     `json:"new_connections"`
+	ConnectionReuse    DiffValue     `json:"connection_reuse"`   // Percentage of requests sent on a kept-alive connection
+	Phases             []PhaseDiff   `json:"phases,omitempty"`   // Timing phases measured in both runs, in request order
+	Requests           []RequestDiff `json:"requests,omitempty"` // Named requests present in both runs
+	Errors             []ErrorDiff   `json:"errors,omitempty"`   // Error classes seen in either run, in ErrorClasses order
+}
+
+// ErrorDiff compares the failed requests of one error class across two runs
+type ErrorDiff struct {
+	Class string    `json:"class"`
+	Count DiffValue `json:"count"`
+}
+
+// PhaseDiff compares one request timing phase across two runs
+type PhaseDiff struct {
+	Phase string    `json:"phase"`
+	Avg   DiffValue `json:"avg"`
+	P50   DiffValue `json:"p50"`
+	P95   DiffValue `json:"p95"`
+	P99   DiffValue `json:"p99"`
+}
+
+// RequestDiff compares one named request of a request mix across two runs
+type RequestDiff struct {
+	Name      string    `json:"name"`
+	Requests  DiffValue `json:"requests"`
+	ErrorRate DiffValue `json:"error_rate"`
+	P95       DiffValue `json:"p95"`
+	P99       DiffValue `json:"p99"`
 }
 
 // DiffValue represents a difference between two metric values
@@ -87,6 +128,8 @@ func (c *Comparator) calculateDifferences(baseline, comparison *model.Metrics) *
 		compareSuccessRate = float64(comparison.SuccessRequests) / float64(comparison.TotalRequests) * 100
 	}
 
+	baselineRetry, compareRetry := retryMetrics(baseline), retryMetrics(comparison)
+
 	return &MetricDifferences{
 		TotalRequests:      c.diff(float64(baseline.TotalRequests), float64(comparison.TotalRequests), true),
 		SuccessfulRequests: c.diff(float64(baseline.SuccessRequests), float64(comparison.SuccessRequests), true),
@@ -99,8 +142,119 @@ func (c *Comparator) calculateDifferences(baseline, comparison *model.Metrics) *
 		P75:                c.diff(baseline.P75LatencyMs, comparison.P75LatencyMs, false),
 		P95:                c.diff(baseline.P95LatencyMs, comparison.P95LatencyMs, false),
 		P99:                c.diff(baseline.P99LatencyMs, comparison.P99LatencyMs, false),
+		P999:               c.diff(baseline.P999LatencyMs, comparison.P999LatencyMs, false),
+		P9999:              c.diff(baseline.P9999LatencyMs, comparison.P9999LatencyMs, false),
+		AvgResponse:        c.diff(baseline.AvgResponseMs, comparison.AvgResponseMs, false),
+		P50Response:        c.diff(baseline.P50ResponseMs, comparison.P50ResponseMs, false),
+		P95Response:        c.diff(baseline.P95ResponseMs, comparison.P95ResponseMs, false),
+		P99Response:        c.diff(baseline.P99ResponseMs, comparison.P99ResponseMs, false),
+		P999Response:       c.diff(baseline.P999ResponseMs, comparison.P999ResponseMs, false),
 		RequestsPerSecond:  c.diff(baseline.RequestsPerSec, comparison.RequestsPerSec, true),
+		DroppedIterations:  c.diff(float64(baseline.DroppedIterations), float64(comparison.DroppedIterations), false),
+		CheckFailures:      c.diff(float64(baseline.CheckFailures), float64(comparison.CheckFailures), false),
+		Retries:            c.diff(float64(baselineRetry.Retries), float64(compareRetry.Retries), false),
+		RetryAmplification: c.diff(baselineRetry.Amplification, compareRetry.Amplification, false),
+		FirstAttemptP95:    c.diff(baselineRetry.FirstAttemptP95Ms, compareRetry.FirstAttemptP95Ms, false),
+		NewConnections:     c.diff(float64(baseline.NewConnections), float64(comparison.NewConnections), false),
+		ConnectionReuse:    c.diff(connectionReuse(baseline), connectionReuse(comparison), true),
+		Phases:             c.phaseDiffs(baseline.Phases, comparison.Phases),
+		Requests:           c.requestDiffs(baseline.RequestBreakdown, comparison.RequestBreakdown),
+		Errors:             c.errorDiffs(baseline.Errors, comparison.Errors),
+	}
+}
+
+// phaseDiffs matches timing phases by name. Phases that were not measured in
+// one of the runs, such as TLS when only one of them used HTTPS, are left out.
+func (c *Comparator) phaseDiffs(baseline, comparison []model.PhaseMetrics) []PhaseDiff {
+	byPhase := make(map[string]model.PhaseMetrics, len(comparison))
+	for _, p := range comparison {
+		byPhase[p.Phase] = p
+	}
+
+	var diffs []PhaseDiff
+	for _, b := range baseline {
+		cmp, ok := byPhase[b.Phase]
+		if !ok || b.Count == 0 || cmp.Count == 0 {
+			continue
+		}
+		diffs = append(diffs, PhaseDiff{
+			Phase: b.Phase,
+			Avg:   c.diff(b.AvgMs, cmp.AvgMs, false),
+			P50:   c.diff(b.P50Ms, cmp.P50Ms, false),
+			P95:   c.diff(b.P95Ms, cmp.P95Ms, false),
+			P99:   c.diff(b.P99Ms, cmp.P99Ms, false),
+		})
+	}
+	return diffs
+}
+
+// connectionReuse returns the percentage of connections that were kept-alive
+// ones rather than newly opened
+func connectionReuse(m *model.Metrics) float64 {
+	total := m.NewConnections + m.ReusedConnections
+	if total == 0 {
+		return 0
+	}
+	return float64(m.ReusedConnections) / float64(total) * 100
+}
+
+// retryMetrics returns the retry metrics of a run, all zero for a run
+// without a retry policy
+func retryMetrics(m *model.Metrics) model.RetryMetrics {
+	if m.Retry == nil {
+		return model.RetryMetrics{}
+	}
+	return *m.Retry
+}
+
+// requestDiffs matches per-request breakdowns by name. Requests that only
+// appear in one of the runs are left out.
+func (c *Comparator) requestDiffs(baseline, comparison []model.RequestMetrics) []RequestDiff {
+	byName := make(map[string]model.RequestMetrics, len(comparison))
+	for _, r := range comparison {
+		byName[r.Name] = r
+	}
+
+	var diffs []RequestDiff
+	for _, b := range baseline {
+		cmp, ok := byName[b.Name]
+		if !ok {
+			continue
+		}
+		diffs = append(diffs, RequestDiff{
+			Name:      b.Name,
+			Requests:  c.diff(float64(b.Requests), float64(cmp.Requests), true),
+			ErrorRate: c.diff(requestErrorRate(b), requestErrorRate(cmp), false),
+			P95:       c.diff(b.P95LatencyMs, cmp.P95LatencyMs, false),
+			P99:       c.diff(b.P99LatencyMs, cmp.P99LatencyMs, false),
+		})
+	}
+	return diffs
+}
+
+// errorDiffs compares failed requests per error class. Classes neither run
+// failed with are left out.
+func (c *Comparator) errorDiffs(baseline, comparison map[string]int64) []ErrorDiff {
+	var diffs []ErrorDiff
+	for _, class := range model.ErrorClasses {
+		b, cmp := baseline[class], comparison[class]
+		if b == 0 && cmp == 0 {
+			continue
+		}
+		diffs = append(diffs, ErrorDiff{
+			Class: class,
+			Count: c.diff(float64(b), float64(cmp), false),
+		})
+	}
+	return diffs
+}
+
+// requestErrorRate returns the percentage of failed requests for one named request
+func requestErrorRate(r model.RequestMetrics) float64 {
+	if r.Requests == 0 {
+		return 0
 	}
+	return float64(r.Failed) / float64(r.Requests) * 100
 }
 
 // diff calculates difference between two values
@@ -143,6 +297,8 @@ func (c *Comparator) generateSummary(diff *MetricDifferences) string {
 		diff.AvgResponseTime,
 		diff.P95,
 		diff.P99,
+		diff.P95Response,
+		diff.P99Response,
 		diff.RequestsPerSecond,
 	}
 
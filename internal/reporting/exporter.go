@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
@@ -77,8 +79,12 @@ func (e *Exporter) exportCSV(writer io.Writer, data *TestRunExport) error {
 		"Started At", "Ended At", "Duration (s)",
 		"Total Requests", "Successful Requests", "Failed Requests", "Success Rate (%)",
 		"Min Response Time (ms)", "Max Response Time (ms)", "Avg Response Time (ms)",
-		"P50 (ms)", "P75 (ms)", "P95 (ms)", "P99 (ms)",
-		"Requests/Second", "Concurrent Users",
+		"P50 (ms)", "P75 (ms)", "P95 (ms)", "P99 (ms)", "P99.9 (ms)", "P99.99 (ms)",
+		"Avg Response incl. Queueing (ms)", "Max Response incl. Queueing (ms)",
+		"P50 Response (ms)", "P75 Response (ms)", "P95 Response (ms)", "P99 Response (ms)",
+		"P99.9 Response (ms)", "P99.99 Response (ms)",
+		"Requests/Second", "Concurrent Users", "Dropped Iterations",
+		"Iterations", "Failed Iterations", "New Connections", "Reused Connections",
 	}
 	if err := csvWriter.Write(headers); err != nil {
 		return err
@@ -120,14 +126,471 @@ func (e *Exporter) exportCSV(writer io.Writer, data *TestRunExport) error {
 			fmt.Sprintf("%.2f", data.Metrics.P75LatencyMs),
 			fmt.Sprintf("%.2f", data.Metrics.P95LatencyMs),
 			fmt.Sprintf("%.2f", data.Metrics.P99LatencyMs),
+			fmt.Sprintf("%.2f", data.Metrics.P999LatencyMs),
+			fmt.Sprintf("%.2f", data.Metrics.P9999LatencyMs),
+			fmt.Sprintf("%.2f", data.Metrics.AvgResponseMs),
+			fmt.Sprintf("%.2f", data.Metrics.MaxResponseMs),
+			fmt.Sprintf("%.2f", data.Metrics.P50ResponseMs),
+			fmt.Sprintf("%.2f", data.Metrics.P75ResponseMs),
+			fmt.Sprintf("%.2f", data.Metrics.P95ResponseMs),
+			fmt.Sprintf("%.2f", data.Metrics.P99ResponseMs),
+			fmt.Sprintf("%.2f", data.Metrics.P999ResponseMs),
+			fmt.Sprintf("%.2f", data.Metrics.P9999ResponseMs),
 			fmt.Sprintf("%.2f", data.Metrics.RequestsPerSec),
 			fmt.Sprintf("%d", data.TestPlan.Users),
+			fmt.Sprintf("%d", data.Metrics.DroppedIterations),
+			fmt.Sprintf("%d", data.Metrics.Iterations),
+			fmt.Sprintf("%d", data.Metrics.FailedIterations),
+			fmt.Sprintf("%d", data.Metrics.NewConnections),
+			fmt.Sprintf("%d", data.Metrics.ReusedConnections),
 		)
 	} else {
-		row = append(row, "N/A", "N/A", "N/A", "N/A", "N/A", "N/A", "N/A", "N/A", "N/A", "N/A", "N/A", "N/A", "N/A")
+		for i := 7; i < len(headers); i++ {
+			row = append(row, "N/A")
+		}
 	}
 
-	return csvWriter.Write(row)
+	if err := csvWriter.Write(row); err != nil {
+		return err
+	}
+
+	if data.Metrics != nil && len(data.Metrics.Phases) > 0 {
+		if err := writePhasesCSV(csvWriter, data.Metrics.Phases); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && len(data.Metrics.Checks) > 0 {
+		if err := writeChecksCSV(csvWriter, data.Metrics.Checks); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && data.Metrics.Retry != nil {
+		if err := writeRetryCSV(csvWriter, data.Metrics.Retry); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && data.Metrics.WebSocket != nil {
+		if err := writeWebSocketCSV(csvWriter, data.Metrics.WebSocket); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && data.Metrics.Stream != nil {
+		if err := writeStreamCSV(csvWriter, data.Metrics.Stream); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && data.Metrics.Socket != nil {
+		if err := writeSocketCSV(csvWriter, data.Metrics.Socket); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && data.Metrics.DNS != nil {
+		if err := writeDNSCSV(csvWriter, data.Metrics.DNS); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && data.Metrics.SQL != nil {
+		if err := writeSQLCSV(csvWriter, data.Metrics.SQL); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && data.Metrics.Redis != nil {
+		if err := writeRedisCSV(csvWriter, data.Metrics.Redis); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && len(data.Metrics.Errors) > 0 {
+		if err := writeErrorsCSV(csvWriter, data.Metrics); err != nil {
+			return err
+		}
+	}
+	if data.Metrics != nil && len(data.Metrics.RequestBreakdown) > 0 {
+		return writeRequestBreakdownCSV(csvWriter, data.Metrics.RequestBreakdown)
+	}
+	return nil
+}
+
+// writeRetryCSV appends the retries of a plan with a retry policy as a
+// separate section
+func writeRetryCSV(csvWriter *csv.Writer, retry *model.RetryMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	headers := []string{
+		"Retried Requests", "Retries", "Recovered", "Exhausted", "Amplification",
+		"First Attempt Avg (ms)", "First Attempt P50 (ms)", "First Attempt P95 (ms)",
+		"First Attempt P99 (ms)", "First Attempt Max (ms)",
+	}
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+
+	return csvWriter.Write([]string{
+		fmt.Sprintf("%d", retry.RetriedRequests),
+		fmt.Sprintf("%d", retry.Retries),
+		fmt.Sprintf("%d", retry.RecoveredRequests),
+		fmt.Sprintf("%d", retry.ExhaustedRequests),
+		fmt.Sprintf("%.3f", retry.Amplification),
+		fmt.Sprintf("%.2f", retry.FirstAttemptAvgMs),
+		fmt.Sprintf("%.2f", retry.FirstAttemptP50Ms),
+		fmt.Sprintf("%.2f", retry.FirstAttemptP95Ms),
+		fmt.Sprintf("%.2f", retry.FirstAttemptP99Ms),
+		fmt.Sprintf("%.2f", retry.FirstAttemptMaxMs),
+	})
+}
+
+// writeWebSocketCSV appends the sessions of a plan with WebSocket requests
+// as a separate section
+func writeWebSocketCSV(csvWriter *csv.Writer, ws *model.WebSocketMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	headers := []string{
+		"Sessions", "Connections", "Messages Sent", "Messages Received", "Reply Timeouts", "Abnormal Closes",
+		"Connect Avg (ms)", "Connect P95 (ms)", "Connect P99 (ms)",
+		"Round Trip Avg (ms)", "Round Trip P95 (ms)", "Round Trip P99 (ms)",
+	}
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+
+	var closes int64
+	for _, n := range ws.CloseCodes {
+		closes += n
+	}
+	return csvWriter.Write([]string{
+		fmt.Sprintf("%d", ws.Sessions),
+		fmt.Sprintf("%d", ws.Connections),
+		fmt.Sprintf("%d", ws.MessagesSent),
+		fmt.Sprintf("%d", ws.MessagesReceived),
+		fmt.Sprintf("%d", ws.ReplyTimeouts),
+		fmt.Sprintf("%d", closes),
+		fmt.Sprintf("%.2f", ws.ConnectAvgMs),
+		fmt.Sprintf("%.2f", ws.ConnectP95Ms),
+		fmt.Sprintf("%.2f", ws.ConnectP99Ms),
+		fmt.Sprintf("%.2f", ws.RoundTripAvgMs),
+		fmt.Sprintf("%.2f", ws.RoundTripP95Ms),
+		fmt.Sprintf("%.2f", ws.RoundTripP99Ms),
+	})
+}
+
+// writeStreamCSV appends the events of a streaming plan as a separate
+// section, followed by their timing phases
+func writeStreamCSV(csvWriter *csv.Writer, stream *model.StreamMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	headers := []string{
+		"Streams", "Events", "Empty Streams", "Cut Streams", "Event Check Failures",
+		"Avg Events per Stream", "Max Events per Stream",
+	}
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+
+	err := csvWriter.Write([]string{
+		fmt.Sprintf("%d", stream.Streams),
+		fmt.Sprintf("%d", stream.Events),
+		fmt.Sprintf("%d", stream.EmptyStreams),
+		fmt.Sprintf("%d", stream.CutStreams),
+		fmt.Sprintf("%d", stream.EventCheckFailures),
+		fmt.Sprintf("%.2f", stream.AvgEventsPerStream),
+		fmt.Sprintf("%d", stream.MaxEventsPerStream),
+	})
+	if err != nil || len(stream.Phases) == 0 {
+		return err
+	}
+	return writePhasesCSV(csvWriter, stream.Phases)
+}
+
+// writeSocketCSV appends the exchanges of a plan with TCP or UDP requests
+// as a separate section
+func writeSocketCSV(csvWriter *csv.Writer, socket *model.SocketMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	headers := []string{
+		"Exchanges", "Connections", "Bytes Sent", "Bytes Received", "Response Timeouts",
+		"Connect Avg (ms)", "Connect P95 (ms)", "Connect P99 (ms)",
+		"Round Trip Avg (ms)", "Round Trip P95 (ms)", "Round Trip P99 (ms)",
+	}
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+
+	return csvWriter.Write([]string{
+		fmt.Sprintf("%d", socket.Exchanges),
+		fmt.Sprintf("%d", socket.Connections),
+		fmt.Sprintf("%d", socket.BytesSent),
+		fmt.Sprintf("%d", socket.BytesReceived),
+		fmt.Sprintf("%d", socket.ResponseTimeouts),
+		fmt.Sprintf("%.2f", socket.ConnectAvgMs),
+		fmt.Sprintf("%.2f", socket.ConnectP95Ms),
+		fmt.Sprintf("%.2f", socket.ConnectP99Ms),
+		fmt.Sprintf("%.2f", socket.RoundTripAvgMs),
+		fmt.Sprintf("%.2f", socket.RoundTripP95Ms),
+		fmt.Sprintf("%.2f", socket.RoundTripP99Ms),
+	})
+}
+
+// writeDNSCSV appends the queries of a plan with DNS requests as a separate
+// section, followed by the answered queries per response code
+func writeDNSCSV(csvWriter *csv.Writer, dns *model.DNSMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	if err := csvWriter.Write([]string{"Queries", "Answers", "Empty Answers", "Truncated"}); err != nil {
+		return err
+	}
+	err := csvWriter.Write([]string{
+		fmt.Sprintf("%d", dns.Queries),
+		fmt.Sprintf("%d", dns.Answers),
+		fmt.Sprintf("%d", dns.EmptyAnswers),
+		fmt.Sprintf("%d", dns.Truncated),
+	})
+	if err != nil || len(dns.ResponseCodes) == 0 {
+		return err
+	}
+
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+	if err := csvWriter.Write([]string{"Response Code", "Count"}); err != nil {
+		return err
+	}
+	codes := make([]string, 0, len(dns.ResponseCodes))
+	for code := range dns.ResponseCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		if err := csvWriter.Write([]string{code, fmt.Sprintf("%d", dns.ResponseCodes[code])}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSQLCSV appends the statements of a plan with SQL requests as a
+// separate section, followed by the failed statements per error class
+func writeSQLCSV(csvWriter *csv.Writer, sql *model.SQLMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	if err := csvWriter.Write([]string{"Statements", "Rows Returned", "Rows Affected"}); err != nil {
+		return err
+	}
+	err := csvWriter.Write([]string{
+		fmt.Sprintf("%d", sql.Queries),
+		fmt.Sprintf("%d", sql.RowsReturned),
+		fmt.Sprintf("%d", sql.RowsAffected),
+	})
+	if err != nil || len(sql.Errors) == 0 {
+		return err
+	}
+
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+	if err := csvWriter.Write([]string{"SQL Error Class", "Count"}); err != nil {
+		return err
+	}
+	classes := make([]string, 0, len(sql.Errors))
+	for class := range sql.Errors {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		if err := csvWriter.Write([]string{class, fmt.Sprintf("%d", sql.Errors[class])}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRedisCSV appends the sends of a plan with Redis requests as a
+// separate section, followed by the error replies per prefix
+func writeRedisCSV(csvWriter *csv.Writer, redis *model.RedisMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	if err := csvWriter.Write([]string{"Sends", "Commands", "Nil Replies", "Connections"}); err != nil {
+		return err
+	}
+	err := csvWriter.Write([]string{
+		fmt.Sprintf("%d", redis.Sends),
+		fmt.Sprintf("%d", redis.Commands),
+		fmt.Sprintf("%d", redis.NilReplies),
+		fmt.Sprintf("%d", redis.Connections),
+	})
+	if err != nil || len(redis.ErrorReplies) == 0 {
+		return err
+	}
+
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+	if err := csvWriter.Write([]string{"Redis Error Reply", "Count"}); err != nil {
+		return err
+	}
+	prefixes := make([]string, 0, len(redis.ErrorReplies))
+	for prefix := range redis.ErrorReplies {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+	for _, prefix := range prefixes {
+		if err := csvWriter.Write([]string{prefix, fmt.Sprintf("%d", redis.ErrorReplies[prefix])}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeErrorsCSV appends the failed requests per error class, with their
+// sample messages, as a separate section
+func writeErrorsCSV(csvWriter *csv.Writer, metrics *model.Metrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	headers := []string{"Error Class", "Count", "Samples"}
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+
+	for _, class := range errorClasses(metrics.Errors) {
+		row := []string{
+			class,
+			fmt.Sprintf("%d", metrics.Errors[class]),
+			strings.Join(metrics.ErrorSamples[class], " | "),
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorClasses returns the error classes in errors in ErrorClasses order,
+// followed by any other keys, such as the raw messages of older runs
+func errorClasses(errors map[string]int64) []string {
+	classes := make([]string, 0, len(errors))
+	known := make(map[string]bool, len(model.ErrorClasses))
+	for _, class := range model.ErrorClasses {
+		known[class] = true
+		if _, ok := errors[class]; ok {
+			classes = append(classes, class)
+		}
+	}
+	var other []string
+	for key := range errors {
+		if !known[key] {
+			other = append(other, key)
+		}
+	}
+	sort.Strings(other)
+	return append(classes, other...)
+}
+
+// writeChecksCSV appends the pass rate of every response check as a
+// separate section
+func writeChecksCSV(csvWriter *csv.Writer, checks []model.CheckMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	headers := []string{"Check", "Passes", "Fails", "Pass Rate (%)"}
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+
+	for _, c := range checks {
+		row := []string{
+			c.Name,
+			fmt.Sprintf("%d", c.Passes),
+			fmt.Sprintf("%d", c.Fails),
+			fmt.Sprintf("%.2f", c.PassRate),
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePhasesCSV appends the request timing phases as a separate section,
+// one row per phase so they can be compared side by side
+func writePhasesCSV(csvWriter *csv.Writer, phases []model.PhaseMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	headers := []string{"Phase", "Count", "Avg (ms)", "P50 (ms)", "P95 (ms)", "P99 (ms)", "Max (ms)"}
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+
+	for _, p := range phases {
+		row := []string{
+			p.Phase,
+			fmt.Sprintf("%d", p.Count),
+			fmt.Sprintf("%.2f", p.AvgMs),
+			fmt.Sprintf("%.2f", p.P50Ms),
+			fmt.Sprintf("%.2f", p.P95Ms),
+			fmt.Sprintf("%.2f", p.P99Ms),
+			fmt.Sprintf("%.2f", p.MaxMs),
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRequestBreakdownCSV appends the per-request breakdown of a request
+// mix plan as a second section, separated from the summary by a blank line
+func writeRequestBreakdownCSV(csvWriter *csv.Writer, requests []model.RequestMetrics) error {
+	if err := csvWriter.Write([]string{}); err != nil {
+		return err
+	}
+
+	headers := []string{
+		"Request", "Total Requests", "Successful Requests", "Failed Requests", "Error Rate (%)",
+		"Avg (ms)", "P50 (ms)", "P95 (ms)", "P99 (ms)", "Max (ms)",
+	}
+	if err := csvWriter.Write(headers); err != nil {
+		return err
+	}
+
+	for _, r := range requests {
+		errorRate := float64(0)
+		if r.Requests > 0 {
+			errorRate = float64(r.Failed) / float64(r.Requests) * 100
+		}
+		row := []string{
+			r.Name,
+			fmt.Sprintf("%d", r.Requests),
+			fmt.Sprintf("%d", r.Success),
+			fmt.Sprintf("%d", r.Failed),
+			fmt.Sprintf("%.2f", errorRate),
+			fmt.Sprintf("%.2f", r.AvgLatencyMs),
+			fmt.Sprintf("%.2f", r.P50LatencyMs),
+			fmt.Sprintf("%.2f", r.P95LatencyMs),
+			fmt.Sprintf("%.2f", r.P99LatencyMs),
+			fmt.Sprintf("%.2f", r.MaxLatencyMs),
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // exportHTML exports test run as HTML report
@@ -293,6 +756,12 @@ func (e *Exporter) exportHTML(writer io.Writer, data *TestRunExport) error {
                 <th>Ramp-Up Period</th>
                 <td>{{.TestPlan.RampUpSec}} seconds</td>
             </tr>
+            {{if .TestPlan.RampDownSec}}
+            <tr>
+                <th>Ramp-Down Period</th>
+                <td>{{.TestPlan.RampDownSec}} seconds</td>
+            </tr>
+            {{end}}
             <tr>
                 <th>Test Duration</th>
                 <td>{{.TestPlan.DurationSec}} seconds</td>
@@ -337,6 +806,10 @@ func (e *Exporter) exportHTML(writer io.Writer, data *TestRunExport) error {
                 <div class="metric-label">Requests/Second</div>
                 <div class="metric-value">{{printf "%.2f" .Metrics.RequestsPerSec}}</div>
             </div>
+            <div class="metric-card{{if gt .Metrics.DroppedIterations 0}} warning{{end}}">
+                <div class="metric-label">Dropped Iterations</div>
+                <div class="metric-value">{{.Metrics.DroppedIterations}}</div>
+            </div>
         </div>
 
         <div class="metric-card">
@@ -369,40 +842,297 @@ func (e *Exporter) exportHTML(writer io.Writer, data *TestRunExport) error {
             <thead>
                 <tr>
                     <th>Percentile</th>
-                    <th>Response Time (ms)</th>
+                    <th>Service Time (ms)</th>
+                    <th>Response Time incl. Queueing (ms)</th>
                 </tr>
             </thead>
             <tbody>
                 <tr>
                     <td>P50 (Median)</td>
                     <td>{{printf "%.2f" .Metrics.P50LatencyMs}}</td>
+                    <td>{{printf "%.2f" .Metrics.P50ResponseMs}}</td>
                 </tr>
                 <tr>
                     <td>P75</td>
                     <td>{{printf "%.2f" .Metrics.P75LatencyMs}}</td>
+                    <td>{{printf "%.2f" .Metrics.P75ResponseMs}}</td>
                 </tr>
                 <tr>
                     <td>P95</td>
                     <td>{{printf "%.2f" .Metrics.P95LatencyMs}}</td>
+                    <td>{{printf "%.2f" .Metrics.P95ResponseMs}}</td>
                 </tr>
                 <tr>
                     <td>P99</td>
                     <td>{{printf "%.2f" .Metrics.P99LatencyMs}}</td>
+                    <td>{{printf "%.2f" .Metrics.P99ResponseMs}}</td>
+                </tr>
+                <tr>
+                    <td>P99.9</td>
+                    <td>{{printf "%.2f" .Metrics.P999LatencyMs}}</td>
+                    <td>{{printf "%.2f" .Metrics.P999ResponseMs}}</td>
+                </tr>
+                <tr>
+                    <td>P99.99</td>
+                    <td>{{printf "%.2f" .Metrics.P9999LatencyMs}}</td>
+                    <td>{{printf "%.2f" .Metrics.P9999ResponseMs}}</td>
+                </tr>
+                <tr>
+                    <td>Average</td>
+                    <td>{{printf "%.2f" .Metrics.AvgLatencyMs}}</td>
+                    <td>{{printf "%.2f" .Metrics.AvgResponseMs}}</td>
                 </tr>
             </tbody>
         </table>
 
-        {{if .Metrics.Errors}}
-        <h2>Error Distribution</h2>
+        {{if .Metrics.Phases}}
+        <h2>Request Timing Phases</h2>
+        <p>New connections: {{.Metrics.NewConnections}} &middot; reused connections: {{.Metrics.ReusedConnections}}</p>
+        <table>
+            <thead>
+                <tr>
+                    <th>Phase</th>
+                    <th>Count</th>
+                    <th>Avg (ms)</th>
+                    <th>P50 (ms)</th>
+                    <th>P95 (ms)</th>
+                    <th>P99 (ms)</th>
+                    <th>Max (ms)</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Metrics.Phases}}
+                <tr>
+                    <td>{{.Phase}}</td>
+                    <td>{{.Count}}</td>
+                    <td>{{printf "%.2f" .AvgMs}}</td>
+                    <td>{{printf "%.2f" .P50Ms}}</td>
+                    <td>{{printf "%.2f" .P95Ms}}</td>
+                    <td>{{printf "%.2f" .P99Ms}}</td>
+                    <td>{{printf "%.2f" .MaxMs}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+
+        {{if .Metrics.Checks}}
+        <h2>Response Checks</h2>
+        <p>Responses failing a check: {{.Metrics.CheckFailures}}</p>
+        <table>
+            <thead>
+                <tr>
+                    <th>Check</th>
+                    <th>Passes</th>
+                    <th>Fails</th>
+                    <th>Pass Rate (%)</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Metrics.Checks}}
+                <tr>
+                    <td>{{.Name}}</td>
+                    <td>{{.Passes}}</td>
+                    <td>{{.Fails}}</td>
+                    <td>{{printf "%.2f" .PassRate}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+
+        {{with .Metrics.Retry}}
+        <h2>Retries</h2>
+        <p>Attempts per request: {{printf "%.3f" .Amplification}} &middot; retried requests: {{.RetriedRequests}} &middot; recovered: {{.RecoveredRequests}} &middot; exhausted: {{.ExhaustedRequests}}</p>
+        <table>
+            <thead>
+                <tr>
+                    <th>Latency</th>
+                    <th>Avg (ms)</th>
+                    <th>P50 (ms)</th>
+                    <th>P95 (ms)</th>
+                    <th>P99 (ms)</th>
+                    <th>Max (ms)</th>
+                </tr>
+            </thead>
+            <tbody>
+                <tr>
+                    <td>First attempt</td>
+                    <td>{{printf "%.2f" .FirstAttemptAvgMs}}</td>
+                    <td>{{printf "%.2f" .FirstAttemptP50Ms}}</td>
+                    <td>{{printf "%.2f" .FirstAttemptP95Ms}}</td>
+                    <td>{{printf "%.2f" .FirstAttemptP99Ms}}</td>
+                    <td>{{printf "%.2f" .FirstAttemptMaxMs}}</td>
+                </tr>
+                <tr>
+                    <td>Final, with retries</td>
+                    <td>{{printf "%.2f" $.Metrics.AvgLatencyMs}}</td>
+                    <td>{{printf "%.2f" $.Metrics.P50LatencyMs}}</td>
+                    <td>{{printf "%.2f" $.Metrics.P95LatencyMs}}</td>
+                    <td>{{printf "%.2f" $.Metrics.P99LatencyMs}}</td>
+                    <td>{{printf "%.2f" $.Metrics.MaxLatencyMs}}</td>
+                </tr>
+            </tbody>
+        </table>
+        {{end}}
+
+        {{with .Metrics.WebSocket}}
+        <h2>WebSocket Sessions</h2>
+        <p>Sessions: {{.Sessions}} &middot; connected: {{.Connections}} &middot; messages sent: {{.MessagesSent}} &middot; received: {{.MessagesReceived}} &middot; reply timeouts: {{.ReplyTimeouts}}</p>
+        <table>
+            <thead>
+                <tr>
+                    <th>Latency</th>
+                    <th>Avg (ms)</th>
+                    <th>P50 (ms)</th>
+                    <th>P95 (ms)</th>
+                    <th>P99 (ms)</th>
+                    <th>Max (ms)</th>
+                </tr>
+            </thead>
+            <tbody>
+                <tr>
+                    <td>Connect</td>
+                    <td>{{printf "%.2f" .ConnectAvgMs}}</td>
+                    <td>{{printf "%.2f" .ConnectP50Ms}}</td>
+                    <td>{{printf "%.2f" .ConnectP95Ms}}</td>
+                    <td>{{printf "%.2f" .ConnectP99Ms}}</td>
+                    <td>{{printf "%.2f" .ConnectMaxMs}}</td>
+                </tr>
+                <tr>
+                    <td>Message round trip</td>
+                    <td>{{printf "%.2f" .RoundTripAvgMs}}</td>
+                    <td>{{printf "%.2f" .RoundTripP50Ms}}</td>
+                    <td>{{printf "%.2f" .RoundTripP95Ms}}</td>
+                    <td>{{printf "%.2f" .RoundTripP99Ms}}</td>
+                    <td>{{printf "%.2f" .RoundTripMaxMs}}</td>
+                </tr>
+            </tbody>
+        </table>
+        {{if .CloseCodes}}
+        <table>
+            <thead>
+                <tr>
+                    <th>Abnormal Close Code</th>
+                    <th>Count</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range $key, $value := .CloseCodes}}
+                <tr>
+                    <td>{{$key}}</td>
+                    <td>{{$value}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+        {{end}}
+
+        {{with .Metrics.Stream}}
+        <h2>Stream Events</h2>
+        <p>Streams: {{.Streams}} &middot; events: {{.Events}} &middot; events per stream: {{printf "%.2f" .AvgEventsPerStream}} (max {{.MaxEventsPerStream}}) &middot; empty: {{.EmptyStreams}} &middot; cut: {{.CutStreams}} &middot; event check failures: {{.EventCheckFailures}}</p>
+        {{if .Phases}}
+        <table>
+            <thead>
+                <tr>
+                    <th>Phase</th>
+                    <th>Count</th>
+                    <th>Avg (ms)</th>
+                    <th>P50 (ms)</th>
+                    <th>P95 (ms)</th>
+                    <th>P99 (ms)</th>
+                    <th>Max (ms)</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Phases}}
+                <tr>
+                    <td>{{.Phase}}</td>
+                    <td>{{.Count}}</td>
+                    <td>{{printf "%.2f" .AvgMs}}</td>
+                    <td>{{printf "%.2f" .P50Ms}}</td>
+                    <td>{{printf "%.2f" .P95Ms}}</td>
+                    <td>{{printf "%.2f" .P99Ms}}</td>
+                    <td>{{printf "%.2f" .MaxMs}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+        {{end}}
+
+        {{with .Metrics.Socket}}
+        <h2>TCP/UDP Exchanges</h2>
+        <p>Exchanges: {{.Exchanges}} &middot; connections: {{.Connections}} &middot; bytes sent: {{.BytesSent}} &middot; received: {{.BytesReceived}} &middot; response timeouts: {{.ResponseTimeouts}}</p>
+        <table>
+            <thead>
+                <tr>
+                    <th>Latency</th>
+                    <th>Avg (ms)</th>
+                    <th>P50 (ms)</th>
+                    <th>P95 (ms)</th>
+                    <th>P99 (ms)</th>
+                    <th>Max (ms)</th>
+                </tr>
+            </thead>
+            <tbody>
+                <tr>
+                    <td>Connect</td>
+                    <td>{{printf "%.2f" .ConnectAvgMs}}</td>
+                    <td>{{printf "%.2f" .ConnectP50Ms}}</td>
+                    <td>{{printf "%.2f" .ConnectP95Ms}}</td>
+                    <td>{{printf "%.2f" .ConnectP99Ms}}</td>
+                    <td>{{printf "%.2f" .ConnectMaxMs}}</td>
+                </tr>
+                <tr>
+                    <td>Round trip</td>
+                    <td>{{printf "%.2f" .RoundTripAvgMs}}</td>
+                    <td>{{printf "%.2f" .RoundTripP50Ms}}</td>
+                    <td>{{printf "%.2f" .RoundTripP95Ms}}</td>
+                    <td>{{printf "%.2f" .RoundTripP99Ms}}</td>
+                    <td>{{printf "%.2f" .RoundTripMaxMs}}</td>
+                </tr>
+            </tbody>
+        </table>
+        {{end}}
+
+        {{with .Metrics.DNS}}
+        <h2>DNS Queries</h2>
+        <p>Queries: {{.Queries}} &middot; answer records: {{.Answers}} &middot; empty answers: {{.EmptyAnswers}} &middot; truncated: {{.Truncated}}</p>
+        {{if .ResponseCodes}}
+        <table>
+            <thead>
+                <tr>
+                    <th>Response Code</th>
+                    <th>Count</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range $key, $value := .ResponseCodes}}
+                <tr>
+                    <td>{{$key}}</td>
+                    <td>{{$value}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+        {{end}}
+
+        {{with .Metrics.SQL}}
+        <h2>SQL Statements</h2>
+        <p>Statements: {{.Queries}} &middot; rows returned: {{.RowsReturned}} &middot; rows affected: {{.RowsAffected}}</p>
+        {{if .Errors}}
         <table>
             <thead>
                 <tr>
-                    <th>Error Type</th>
+                    <th>SQL Error Class</th>
                     <th>Count</th>
                 </tr>
             </thead>
             <tbody>
-                {{range $key, $value := .Metrics.Errors}}
+                {{range $key, $value := .Errors}}
                 <tr>
                     <td>{{$key}}</td>
                     <td>{{$value}}</td>
@@ -411,6 +1141,117 @@ func (e *Exporter) exportHTML(writer io.Writer, data *TestRunExport) error {
             </tbody>
         </table>
         {{end}}
+        {{end}}
+
+        {{with .Metrics.Redis}}
+        <h2>Redis Commands</h2>
+        <p>Sends: {{.Sends}} &middot; commands: {{.Commands}} &middot; nil replies: {{.NilReplies}} &middot; connections opened: {{.Connections}}</p>
+        {{if .ErrorReplies}}
+        <table>
+            <thead>
+                <tr>
+                    <th>Redis Error Reply</th>
+                    <th>Count</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range $key, $value := .ErrorReplies}}
+                <tr>
+                    <td>{{$key}}</td>
+                    <td>{{$value}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+        {{end}}
+
+        {{if .Metrics.Steps}}
+        <h2>Scenario Steps</h2>
+        <p>Iterations completed: {{.Metrics.Iterations}} &middot; aborted: {{.Metrics.FailedIterations}}</p>
+        <table>
+            <thead>
+                <tr>
+                    <th>Step</th>
+                    <th>Requests</th>
+                    <th>Failed</th>
+                    <th>Assertion Failures</th>
+                    <th>Avg (ms)</th>
+                    <th>P50 (ms)</th>
+                    <th>P95 (ms)</th>
+                    <th>P99 (ms)</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Metrics.Steps}}
+                <tr>
+                    <td>{{.Name}}</td>
+                    <td>{{.Requests}}</td>
+                    <td>{{.Failed}}</td>
+                    <td>{{.AssertionFailures}}</td>
+                    <td>{{printf "%.2f" .AvgLatencyMs}}</td>
+                    <td>{{printf "%.2f" .P50LatencyMs}}</td>
+                    <td>{{printf "%.2f" .P95LatencyMs}}</td>
+                    <td>{{printf "%.2f" .P99LatencyMs}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+
+        {{if .Metrics.RequestBreakdown}}
+        <h2>Request Breakdown</h2>
+        <table>
+            <thead>
+                <tr>
+                    <th>Request</th>
+                    <th>Requests</th>
+                    <th>Failed</th>
+                    <th>Avg (ms)</th>
+                    <th>P50 (ms)</th>
+                    <th>P95 (ms)</th>
+                    <th>P99 (ms)</th>
+                    <th>Max (ms)</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range .Metrics.RequestBreakdown}}
+                <tr>
+                    <td>{{.Name}}</td>
+                    <td>{{.Requests}}</td>
+                    <td>{{.Failed}}</td>
+                    <td>{{printf "%.2f" .AvgLatencyMs}}</td>
+                    <td>{{printf "%.2f" .P50LatencyMs}}</td>
+                    <td>{{printf "%.2f" .P95LatencyMs}}</td>
+                    <td>{{printf "%.2f" .P99LatencyMs}}</td>
+                    <td>{{printf "%.2f" .MaxLatencyMs}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
+
+        {{if .Metrics.Errors}}
+        <h2>Error Distribution</h2>
+        <table>
+            <thead>
+                <tr>
+                    <th>Error Class</th>
+                    <th>Count</th>
+                    <th>Samples</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range $class := errorClasses .Metrics.Errors}}
+                <tr>
+                    <td>{{$class}}</td>
+                    <td>{{index $.Metrics.Errors $class}}</td>
+                    <td>{{range index $.Metrics.ErrorSamples $class}}<div>{{.}}</div>{{end}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
 
         {{if .Metrics.StatusCodes}}
         <h2>HTTP Status Code Distribution</h2>
@@ -431,6 +1272,26 @@ func (e *Exporter) exportHTML(writer io.Writer, data *TestRunExport) error {
             </tbody>
         </table>
         {{end}}
+
+        {{if .Metrics.GRPCStatusCodes}}
+        <h2>gRPC Status Code Distribution</h2>
+        <table>
+            <thead>
+                <tr>
+                    <th>Status Code</th>
+                    <th>Count</th>
+                </tr>
+            </thead>
+            <tbody>
+                {{range $key, $value := .Metrics.GRPCStatusCodes}}
+                <tr>
+                    <td>{{$key}}</td>
+                    <td>{{$value}}</td>
+                </tr>
+                {{end}}
+            </tbody>
+        </table>
+        {{end}}
         {{end}}
 
         <div class="footer">
@@ -448,7 +1309,8 @@ func (e *Exporter) exportHTML(writer io.Writer, data *TestRunExport) error {
 			}
 			return a / b
 		},
-		"mul": func(a, b float64) float64 { return a * b },
+		"mul":          func(a, b float64) float64 { return a * b },
+		"errorClasses": errorClasses,
 	}).Parse(tmpl)
 	if err != nil {
 		return err
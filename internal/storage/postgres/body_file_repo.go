@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+)
+
+//nolint:revive // exported name intentionally includes package name for clarity
+type PostgresBodyFileRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresBodyFileRepository(db *sql.DB) *PostgresBodyFileRepository {
+	return &PostgresBodyFileRepository{db: db}
+}
+
+func (r *PostgresBodyFileRepository) Create(bodyFile *model.BodyFile) error {
+	query := `
+		INSERT INTO body_files (id, name, content_type, size, content, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(query,
+		bodyFile.ID, bodyFile.Name, bodyFile.ContentType, bodyFile.Size, bodyFile.Content, bodyFile.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *PostgresBodyFileRepository) GetByID(id string) (*model.BodyFile, error) {
+	query := `
+		SELECT id, name, content_type, size, content, created_at
+		FROM body_files WHERE id = $1
+	`
+
+	bodyFile, err := scanBodyFile(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrBodyFileNotFound
+	}
+	return bodyFile, err
+}
+
+func (r *PostgresBodyFileRepository) GetAll() ([]*model.BodyFile, error) {
+	query := `
+		SELECT id, name, content_type, size, content, created_at
+		FROM body_files
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bodyFiles := make([]*model.BodyFile, 0)
+	for rows.Next() {
+		bodyFile, err := scanBodyFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		bodyFiles = append(bodyFiles, bodyFile)
+	}
+
+	return bodyFiles, rows.Err()
+}
+
+func (r *PostgresBodyFileRepository) Delete(id string) error {
+	return deleteByID(r.db, `DELETE FROM body_files WHERE id = $1`, id, repository.ErrBodyFileNotFound)
+}
+
+// scanBodyFile reads a body file selected with every column of body_files
+func scanBodyFile(row rowScanner) (*model.BodyFile, error) {
+	bodyFile := &model.BodyFile{}
+	var contentType sql.NullString
+
+	err := row.Scan(
+		&bodyFile.ID, &bodyFile.Name, &contentType, &bodyFile.Size, &bodyFile.Content, &bodyFile.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	bodyFile.ContentType = contentType.String
+
+	return bodyFile, nil
+}
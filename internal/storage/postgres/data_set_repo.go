@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+)
+
+//nolint:revive // exported name intentionally includes package name for clarity
+type PostgresDataSetRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresDataSetRepository(db *sql.DB) *PostgresDataSetRepository {
+	return &PostgresDataSetRepository{db: db}
+}
+
+func (r *PostgresDataSetRepository) Create(dataSet *model.DataSet) error {
+	columns, err := json.Marshal(dataSet.Columns)
+	if err != nil {
+		return err
+	}
+
+	rows, err := json.Marshal(dataSet.Rows)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO data_sets (id, name, format, columns, row_count, rows, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.Exec(query,
+		dataSet.ID, dataSet.Name, dataSet.Format, columns, dataSet.RowCount, rows, dataSet.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *PostgresDataSetRepository) GetByID(id string) (*model.DataSet, error) {
+	query := `
+		SELECT id, name, format, columns, row_count, rows, created_at
+		FROM data_sets WHERE id = $1
+	`
+
+	dataSet, err := scanDataSet(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrDataSetNotFound
+	}
+	return dataSet, err
+}
+
+func (r *PostgresDataSetRepository) GetAll() ([]*model.DataSet, error) {
+	query := `
+		SELECT id, name, format, columns, row_count, rows, created_at
+		FROM data_sets
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dataSets := make([]*model.DataSet, 0)
+	for rows.Next() {
+		dataSet, err := scanDataSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		dataSets = append(dataSets, dataSet)
+	}
+
+	return dataSets, rows.Err()
+}
+
+func (r *PostgresDataSetRepository) Delete(id string) error {
+	return deleteByID(r.db, `DELETE FROM data_sets WHERE id = $1`, id, repository.ErrDataSetNotFound)
+}
+
+// scanDataSet reads a data set selected with every column of data_sets
+func scanDataSet(row rowScanner) (*model.DataSet, error) {
+	dataSet := &model.DataSet{}
+	var columnsJSON, rowsJSON []byte
+
+	err := row.Scan(
+		&dataSet.ID, &dataSet.Name, &dataSet.Format, &columnsJSON, &dataSet.RowCount, &rowsJSON, &dataSet.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(columnsJSON) > 0 {
+		if err := json.Unmarshal(columnsJSON, &dataSet.Columns); err != nil {
+			return nil, err
+		}
+	}
+	if len(rowsJSON) > 0 {
+		if err := json.Unmarshal(rowsJSON, &dataSet.Rows); err != nil {
+			return nil, err
+		}
+	}
+
+	return dataSet, nil
+}
@@ -93,3 +93,28 @@ func NewDB(cfg DBConfig, logger *zap.Logger) (*sql.DB, error) {
 	db.Close()
 	return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", maxRetries, lastErr)
 }
+
+// rowScanner is a single row of a query, either *sql.Row or *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// deleteByID runs a delete query whose only argument is id, returning
+// notFound if it deleted nothing
+func deleteByID(db *sql.DB, query, id string, notFound error) error {
+	result, err := db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return notFound
+	}
+
+	return nil
+}
@@ -27,13 +27,100 @@ func (r *PostgresMetricsRepository) Save(metrics *model.Metrics) error {
 		return err
 	}
 
+	latencyHistogram, err := marshalHistogram(metrics.LatencyHistogram)
+	if err != nil {
+		return err
+	}
+
+	responseHistogram, err := marshalHistogram(metrics.ResponseHistogram)
+	if err != nil {
+		return err
+	}
+
+	steps, err := json.Marshal(metrics.Steps)
+	if err != nil {
+		return err
+	}
+
+	requestBreakdown, err := json.Marshal(metrics.RequestBreakdown)
+	if err != nil {
+		return err
+	}
+
+	agents, err := json.Marshal(metrics.Agents)
+	if err != nil {
+		return err
+	}
+
+	phases, err := json.Marshal(metrics.Phases)
+	if err != nil {
+		return err
+	}
+
+	checks, err := json.Marshal(metrics.Checks)
+	if err != nil {
+		return err
+	}
+
+	errorSamples, err := json.Marshal(metrics.ErrorSamples)
+	if err != nil {
+		return err
+	}
+
+	retry, err := json.Marshal(metrics.Retry)
+	if err != nil {
+		return err
+	}
+
+	grpcStatusCodes, err := json.Marshal(metrics.GRPCStatusCodes)
+	if err != nil {
+		return err
+	}
+
+	webSocket, err := json.Marshal(metrics.WebSocket)
+	if err != nil {
+		return err
+	}
+
+	stream, err := json.Marshal(metrics.Stream)
+	if err != nil {
+		return err
+	}
+
+	socket, err := json.Marshal(metrics.Socket)
+	if err != nil {
+		return err
+	}
+
+	dns, err := json.Marshal(metrics.DNS)
+	if err != nil {
+		return err
+	}
+
+	sqlMetrics, err := json.Marshal(metrics.SQL)
+	if err != nil {
+		return err
+	}
+
+	redis, err := json.Marshal(metrics.Redis)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO final_metrics (
 			run_id, total_requests, successful_requests, failed_requests,
 			total_duration_ms, avg_response_time_ms, min_response_time_ms, max_response_time_ms,
 			p50_ms, p95_ms, p99_ms, requests_per_sec, error_rate,
-			status_codes, errors
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			status_codes, errors, dropped_iterations,
+			avg_response_ms, max_response_ms, p50_response_ms, p95_response_ms, p99_response_ms,
+			p999_ms, p9999_ms, p999_response_ms, p9999_response_ms,
+			latency_histogram, response_histogram,
+			iterations, failed_iterations, steps, request_breakdown, agents,
+			phases, new_connections, reused_connections, checks, check_failures, error_samples, retry,
+			grpc_status_codes, websocket, stream, socket, dns, sql, redis
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21,
+			$22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46)
 		ON CONFLICT (run_id) DO UPDATE SET
 			total_requests = EXCLUDED.total_requests,
 			successful_requests = EXCLUDED.successful_requests,
@@ -48,7 +135,38 @@ func (r *PostgresMetricsRepository) Save(metrics *model.Metrics) error {
 			requests_per_sec = EXCLUDED.requests_per_sec,
 			error_rate = EXCLUDED.error_rate,
 			status_codes = EXCLUDED.status_codes,
-			errors = EXCLUDED.errors
+			errors = EXCLUDED.errors,
+			dropped_iterations = EXCLUDED.dropped_iterations,
+			avg_response_ms = EXCLUDED.avg_response_ms,
+			max_response_ms = EXCLUDED.max_response_ms,
+			p50_response_ms = EXCLUDED.p50_response_ms,
+			p95_response_ms = EXCLUDED.p95_response_ms,
+			p99_response_ms = EXCLUDED.p99_response_ms,
+			p999_ms = EXCLUDED.p999_ms,
+			p9999_ms = EXCLUDED.p9999_ms,
+			p999_response_ms = EXCLUDED.p999_response_ms,
+			p9999_response_ms = EXCLUDED.p9999_response_ms,
+			latency_histogram = COALESCE(EXCLUDED.latency_histogram, final_metrics.latency_histogram),
+			response_histogram = COALESCE(EXCLUDED.response_histogram, final_metrics.response_histogram),
+			iterations = EXCLUDED.iterations,
+			failed_iterations = EXCLUDED.failed_iterations,
+			steps = EXCLUDED.steps,
+			request_breakdown = EXCLUDED.request_breakdown,
+			agents = EXCLUDED.agents,
+			phases = EXCLUDED.phases,
+			new_connections = EXCLUDED.new_connections,
+			reused_connections = EXCLUDED.reused_connections,
+			checks = EXCLUDED.checks,
+			check_failures = EXCLUDED.check_failures,
+			error_samples = EXCLUDED.error_samples,
+			retry = EXCLUDED.retry,
+			grpc_status_codes = EXCLUDED.grpc_status_codes,
+			websocket = EXCLUDED.websocket,
+			stream = EXCLUDED.stream,
+			socket = EXCLUDED.socket,
+			dns = EXCLUDED.dns,
+			sql = EXCLUDED.sql,
+			redis = EXCLUDED.redis
 	`
 
 	// Calculate error rate
@@ -61,7 +179,13 @@ func (r *PostgresMetricsRepository) Save(metrics *model.Metrics) error {
 		metrics.RunID, metrics.TotalRequests, metrics.SuccessRequests, metrics.FailedRequests,
 		metrics.TotalDurationMs, metrics.AvgLatencyMs, metrics.MinLatencyMs, metrics.MaxLatencyMs,
 		metrics.P50LatencyMs, metrics.P95LatencyMs, metrics.P99LatencyMs, metrics.RequestsPerSec, errorRate,
-		statusCodes, errors,
+		statusCodes, errors, metrics.DroppedIterations,
+		metrics.AvgResponseMs, metrics.MaxResponseMs, metrics.P50ResponseMs, metrics.P95ResponseMs, metrics.P99ResponseMs,
+		metrics.P999LatencyMs, metrics.P9999LatencyMs, metrics.P999ResponseMs, metrics.P9999ResponseMs,
+		latencyHistogram, responseHistogram,
+		metrics.Iterations, metrics.FailedIterations, steps, requestBreakdown, agents,
+		phases, metrics.NewConnections, metrics.ReusedConnections, checks, metrics.CheckFailures, errorSamples, retry,
+		grpcStatusCodes, webSocket, stream, socket, dns, sqlMetrics, redis,
 	)
 
 	return err
@@ -72,19 +196,32 @@ func (r *PostgresMetricsRepository) GetByRunID(runID string) (*model.Metrics, er
 		SELECT run_id, total_requests, successful_requests, failed_requests,
 		       total_duration_ms, avg_response_time_ms, min_response_time_ms, max_response_time_ms,
 		       p50_ms, p95_ms, p99_ms, requests_per_sec, error_rate,
-		       status_codes, errors
+		       status_codes, errors, dropped_iterations,
+		       avg_response_ms, max_response_ms, p50_response_ms, p95_response_ms, p99_response_ms,
+		       p999_ms, p9999_ms, p999_response_ms, p9999_response_ms,
+		       latency_histogram, response_histogram,
+		       iterations, failed_iterations, steps, request_breakdown, agents,
+		       phases, new_connections, reused_connections, checks, check_failures, error_samples, retry,
+		       grpc_status_codes, websocket, stream, socket, dns, sql, redis
 		FROM final_metrics WHERE run_id = $1
 	`
 
 	metrics := &model.Metrics{}
 	var statusCodesJSON, errorsJSON []byte
+	var latencyHistogramJSON, responseHistogramJSON, stepsJSON, requestBreakdownJSON, agentsJSON, phasesJSON, checksJSON, errorSamplesJSON, retryJSON, grpcStatusCodesJSON, webSocketJSON, streamJSON, socketJSON, dnsJSON, sqlJSON, redisJSON []byte
 
 	var errorRate float64
 	err := r.db.QueryRow(query, runID).Scan(
 		&metrics.RunID, &metrics.TotalRequests, &metrics.SuccessRequests, &metrics.FailedRequests,
 		&metrics.TotalDurationMs, &metrics.AvgLatencyMs, &metrics.MinLatencyMs, &metrics.MaxLatencyMs,
 		&metrics.P50LatencyMs, &metrics.P95LatencyMs, &metrics.P99LatencyMs, &metrics.RequestsPerSec, &errorRate,
-		&statusCodesJSON, &errorsJSON,
+		&statusCodesJSON, &errorsJSON, &metrics.DroppedIterations,
+		&metrics.AvgResponseMs, &metrics.MaxResponseMs, &metrics.P50ResponseMs, &metrics.P95ResponseMs, &metrics.P99ResponseMs,
+		&metrics.P999LatencyMs, &metrics.P9999LatencyMs, &metrics.P999ResponseMs, &metrics.P9999ResponseMs,
+		&latencyHistogramJSON, &responseHistogramJSON,
+		&metrics.Iterations, &metrics.FailedIterations, &stepsJSON, &requestBreakdownJSON, &agentsJSON,
+		&phasesJSON, &metrics.NewConnections, &metrics.ReusedConnections, &checksJSON, &metrics.CheckFailures, &errorSamplesJSON, &retryJSON,
+		&grpcStatusCodesJSON, &webSocketJSON, &streamJSON, &socketJSON, &dnsJSON, &sqlJSON, &redisJSON,
 	)
 
 	if err == sql.ErrNoRows {
@@ -106,6 +243,98 @@ func (r *PostgresMetricsRepository) GetByRunID(runID string) (*model.Metrics, er
 		}
 	}
 
+	if len(stepsJSON) > 0 {
+		if err := json.Unmarshal(stepsJSON, &metrics.Steps); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(requestBreakdownJSON) > 0 {
+		if err := json.Unmarshal(requestBreakdownJSON, &metrics.RequestBreakdown); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(agentsJSON) > 0 {
+		if err := json.Unmarshal(agentsJSON, &metrics.Agents); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(phasesJSON) > 0 {
+		if err := json.Unmarshal(phasesJSON, &metrics.Phases); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(checksJSON) > 0 {
+		if err := json.Unmarshal(checksJSON, &metrics.Checks); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(errorSamplesJSON) > 0 {
+		if err := json.Unmarshal(errorSamplesJSON, &metrics.ErrorSamples); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(retryJSON) > 0 {
+		if err := json.Unmarshal(retryJSON, &metrics.Retry); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(grpcStatusCodesJSON) > 0 {
+		if err := json.Unmarshal(grpcStatusCodesJSON, &metrics.GRPCStatusCodes); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(webSocketJSON) > 0 {
+		if err := json.Unmarshal(webSocketJSON, &metrics.WebSocket); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(streamJSON) > 0 {
+		if err := json.Unmarshal(streamJSON, &metrics.Stream); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(socketJSON) > 0 {
+		if err := json.Unmarshal(socketJSON, &metrics.Socket); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(dnsJSON) > 0 {
+		if err := json.Unmarshal(dnsJSON, &metrics.DNS); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(sqlJSON) > 0 {
+		if err := json.Unmarshal(sqlJSON, &metrics.SQL); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(redisJSON) > 0 {
+		if err := json.Unmarshal(redisJSON, &metrics.Redis); err != nil {
+			return nil, err
+		}
+	}
+
+	if metrics.LatencyHistogram, err = unmarshalHistogram(latencyHistogramJSON); err != nil {
+		return nil, err
+	}
+
+	if metrics.ResponseHistogram, err = unmarshalHistogram(responseHistogramJSON); err != nil {
+		return nil, err
+	}
+
 	return metrics, nil
 }
 
@@ -114,3 +343,23 @@ func (r *PostgresMetricsRepository) Delete(runID string) error {
 	_, err := r.db.Exec(query, runID)
 	return err
 }
+
+// marshalHistogram encodes a histogram for a JSONB column, or NULL if unset
+func marshalHistogram(h *model.Histogram) ([]byte, error) {
+	if h == nil {
+		return nil, nil
+	}
+	return json.Marshal(h)
+}
+
+// unmarshalHistogram decodes a histogram stored by marshalHistogram
+func unmarshalHistogram(data []byte) (*model.Histogram, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	h := model.NewHistogram()
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
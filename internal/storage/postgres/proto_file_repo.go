@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+)
+
+//nolint:revive // exported name intentionally includes package name for clarity
+type PostgresProtoFileRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresProtoFileRepository(db *sql.DB) *PostgresProtoFileRepository {
+	return &PostgresProtoFileRepository{db: db}
+}
+
+func (r *PostgresProtoFileRepository) Create(protoFile *model.ProtoFile) error {
+	services, err := json.Marshal(protoFile.Services)
+	if err != nil {
+		return err
+	}
+
+	methods, err := json.Marshal(protoFile.Methods)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO proto_files (id, name, services, methods, size, content, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err = r.db.Exec(query,
+		protoFile.ID, protoFile.Name, services, methods, protoFile.Size, protoFile.Content, protoFile.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *PostgresProtoFileRepository) GetByID(id string) (*model.ProtoFile, error) {
+	query := `
+		SELECT id, name, services, methods, size, content, created_at
+		FROM proto_files WHERE id = $1
+	`
+
+	protoFile, err := scanProtoFile(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrProtoFileNotFound
+	}
+	return protoFile, err
+}
+
+func (r *PostgresProtoFileRepository) GetAll() ([]*model.ProtoFile, error) {
+	query := `
+		SELECT id, name, services, methods, size, content, created_at
+		FROM proto_files
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	protoFiles := make([]*model.ProtoFile, 0)
+	for rows.Next() {
+		protoFile, err := scanProtoFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		protoFiles = append(protoFiles, protoFile)
+	}
+
+	return protoFiles, rows.Err()
+}
+
+func (r *PostgresProtoFileRepository) Delete(id string) error {
+	return deleteByID(r.db, `DELETE FROM proto_files WHERE id = $1`, id, repository.ErrProtoFileNotFound)
+}
+
+// scanProtoFile reads a proto file selected with every column of proto_files
+func scanProtoFile(row rowScanner) (*model.ProtoFile, error) {
+	protoFile := &model.ProtoFile{}
+	var servicesJSON, methodsJSON []byte
+
+	err := row.Scan(
+		&protoFile.ID, &protoFile.Name, &servicesJSON, &methodsJSON, &protoFile.Size, &protoFile.Content, &protoFile.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(servicesJSON) > 0 {
+		if err := json.Unmarshal(servicesJSON, &protoFile.Services); err != nil {
+			return nil, err
+		}
+	}
+	if len(methodsJSON) > 0 {
+		if err := json.Unmarshal(methodsJSON, &protoFile.Methods); err != nil {
+			return nil, err
+		}
+	}
+
+	return protoFile, nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/storage/repository"
+)
+
+//nolint:revive // exported name intentionally includes package name for clarity
+type PostgresSecretRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSecretRepository(db *sql.DB) *PostgresSecretRepository {
+	return &PostgresSecretRepository{db: db}
+}
+
+func (r *PostgresSecretRepository) Create(secret *model.Secret) error {
+	query := `
+		INSERT INTO secrets (
+			id, name, type, subject, not_after, username,
+			certificate, private_key, password, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.Exec(query,
+		secret.ID, secret.Name, secret.Type, secret.Subject, timeOrNil(secret.NotAfter), secret.Username,
+		secret.Certificate, secret.PrivateKey, secret.Password, secret.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *PostgresSecretRepository) GetByID(id string) (*model.Secret, error) {
+	query := `
+		SELECT id, name, type, subject, not_after, username,
+		       certificate, private_key, password, created_at
+		FROM secrets WHERE id = $1
+	`
+
+	secret, err := scanSecret(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, repository.ErrSecretNotFound
+	}
+	return secret, err
+}
+
+func (r *PostgresSecretRepository) GetAll() ([]*model.Secret, error) {
+	query := `
+		SELECT id, name, type, subject, not_after, username,
+		       certificate, private_key, password, created_at
+		FROM secrets
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	secrets := make([]*model.Secret, 0)
+	for rows.Next() {
+		secret, err := scanSecret(rows)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+
+	return secrets, rows.Err()
+}
+
+func (r *PostgresSecretRepository) Delete(id string) error {
+	return deleteByID(r.db, `DELETE FROM secrets WHERE id = $1`, id, repository.ErrSecretNotFound)
+}
+
+// scanSecret reads a secret selected with every column of secrets
+func scanSecret(row rowScanner) (*model.Secret, error) {
+	secret := &model.Secret{}
+	var subject, username, certificate, privateKey, password sql.NullString
+	var notAfter sql.NullTime
+
+	err := row.Scan(
+		&secret.ID, &secret.Name, &secret.Type, &subject, &notAfter, &username,
+		&certificate, &privateKey, &password, &secret.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	secret.Subject = subject.String
+	if notAfter.Valid {
+		secret.NotAfter = &notAfter.Time
+	}
+	secret.Username = username.String
+	secret.Certificate = certificate.String
+	secret.PrivateKey = privateKey.String
+	secret.Password = password.String
+
+	return secret, nil
+}
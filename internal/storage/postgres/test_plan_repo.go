@@ -36,12 +36,101 @@ func (r *PostgresTestPlanRepository) Create(plan *model.TestPlan) error {
 		return err
 	}
 
+	requests, err := json.Marshal(plan.Requests)
+	if err != nil {
+		return err
+	}
+
+	dataFeed, err := json.Marshal(plan.Data)
+	if err != nil {
+		return err
+	}
+
+	stages, err := json.Marshal(plan.Stages)
+	if err != nil {
+		return err
+	}
+
+	wave, err := json.Marshal(plan.Wave)
+	if err != nil {
+		return err
+	}
+
+	transport, err := json.Marshal(plan.Transport)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := json.Marshal(plan.TLS)
+	if err != nil {
+		return err
+	}
+
+	cookies, err := json.Marshal(plan.Cookies)
+	if err != nil {
+		return err
+	}
+
+	assertions, err := json.Marshal(plan.Assertions)
+	if err != nil {
+		return err
+	}
+
+	retry, err := json.Marshal(plan.Retry)
+	if err != nil {
+		return err
+	}
+
+	bodySource, err := json.Marshal(plan.BodySource)
+	if err != nil {
+		return err
+	}
+
+	grpcRequest, err := json.Marshal(plan.GRPC)
+	if err != nil {
+		return err
+	}
+
+	webSocket, err := json.Marshal(plan.WebSocket)
+	if err != nil {
+		return err
+	}
+
+	stream, err := json.Marshal(plan.Stream)
+	if err != nil {
+		return err
+	}
+
+	socket, err := json.Marshal(plan.Socket)
+	if err != nil {
+		return err
+	}
+
+	dns, err := json.Marshal(plan.DNS)
+	if err != nil {
+		return err
+	}
+
+	sqlRequest, err := json.Marshal(plan.SQL)
+	if err != nil {
+		return err
+	}
+
+	redis, err := json.Marshal(plan.Redis)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO test_plans (
 			id, name, target_url, http_method, headers, body,
 			concurrent_users, duration_seconds, target_rps, timeout_ms,
-			rate_pattern, rate_steps, sla_config, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			rate_pattern, rate_steps, sla_config, created_at, updated_at,
+			executor, max_vus, scenario_id, requests, data_feed,
+			stages, wave, ramp_down_sec, graceful_stop_sec, transport, tls, cookies, assertions, retry,
+			body_source, grpc, websocket, stream, socket, dns, sql, redis
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+			$21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37)
 	`
 
 	now := time.Now()
@@ -49,6 +138,9 @@ func (r *PostgresTestPlanRepository) Create(plan *model.TestPlan) error {
 		plan.ID, plan.Name, plan.TargetURL, plan.Method, headers, plan.Body,
 		plan.Users, plan.DurationSec, plan.TargetRPS, plan.TimeoutMs,
 		plan.RatePattern, rateSteps, slaConfig, now, now,
+		plan.Executor, plan.MaxVUs, plan.ScenarioID, requests, dataFeed,
+		stages, wave, plan.RampDownSec, plan.GracefulStopSec, transport, tlsConfig, cookies, assertions, retry,
+		bodySource, grpcRequest, webSocket, stream, socket, dns, sqlRequest, redis,
 	)
 
 	return err
@@ -58,18 +150,22 @@ func (r *PostgresTestPlanRepository) GetByID(id string) (*model.TestPlan, error)
 	query := `
 		SELECT id, name, target_url, http_method, headers, body,
 		       concurrent_users, duration_seconds, target_rps, timeout_ms,
-		       rate_pattern, rate_steps, sla_config, created_at, updated_at
+		       rate_pattern, rate_steps, sla_config, created_at, updated_at,
+		       executor, max_vus, scenario_id, requests, data_feed,
+		       stages, wave, ramp_down_sec, graceful_stop_sec, transport, tls, cookies, assertions, retry, body_source, grpc, websocket, stream, socket, dns, sql, redis
 		FROM test_plans WHERE id = $1
 	`
 
 	plan := &model.TestPlan{}
-	var headersJSON, rateStepsJSON, slaConfigJSON []byte
+	var headersJSON, rateStepsJSON, slaConfigJSON, requestsJSON, dataFeedJSON, stagesJSON, waveJSON, transportJSON, tlsJSON, cookiesJSON, assertionsJSON, retryJSON, bodySourceJSON, grpcJSON, webSocketJSON, streamJSON, socketJSON, dnsJSON, sqlJSON, redisJSON []byte
 	var createdAt, updatedAt time.Time
 
 	err := r.db.QueryRow(query, id).Scan(
 		&plan.ID, &plan.Name, &plan.TargetURL, &plan.Method, &headersJSON, &plan.Body,
 		&plan.Users, &plan.DurationSec, &plan.TargetRPS, &plan.TimeoutMs,
 		&plan.RatePattern, &rateStepsJSON, &slaConfigJSON, &createdAt, &updatedAt,
+		&plan.Executor, &plan.MaxVUs, &plan.ScenarioID, &requestsJSON, &dataFeedJSON,
+		&stagesJSON, &waveJSON, &plan.RampDownSec, &plan.GracefulStopSec, &transportJSON, &tlsJSON, &cookiesJSON, &assertionsJSON, &retryJSON, &bodySourceJSON, &grpcJSON, &webSocketJSON, &streamJSON, &socketJSON, &dnsJSON, &sqlJSON, &redisJSON,
 	)
 
 	if err == sql.ErrNoRows {
@@ -106,6 +202,134 @@ func (r *PostgresTestPlanRepository) GetByID(id string) (*model.TestPlan, error)
 		}
 	}
 
+	if len(requestsJSON) > 0 {
+		if err := json.Unmarshal(requestsJSON, &plan.Requests); err != nil {
+			logger.Log.Warn("Failed to unmarshal requests JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			// continue without a request mix
+			plan.Requests = nil
+		}
+	}
+
+	if len(dataFeedJSON) > 0 {
+		if err := json.Unmarshal(dataFeedJSON, &plan.Data); err != nil {
+			logger.Log.Warn("Failed to unmarshal data feed JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			// continue without a data feed
+			plan.Data = nil
+		}
+	}
+
+	if err := unmarshalLoadProfile(plan, stagesJSON, waveJSON); err != nil {
+		logger.Log.Warn("Failed to unmarshal load profile JSON for test plan",
+			zap.String("plan_id", id), zap.Error(err))
+	}
+
+	if len(transportJSON) > 0 {
+		if err := json.Unmarshal(transportJSON, &plan.Transport); err != nil {
+			logger.Log.Warn("Failed to unmarshal transport JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			// continue with the shared transport
+			plan.Transport = nil
+		}
+	}
+
+	if len(tlsJSON) > 0 {
+		if err := json.Unmarshal(tlsJSON, &plan.TLS); err != nil {
+			logger.Log.Warn("Failed to unmarshal TLS JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.TLS = nil
+		}
+	}
+
+	if len(cookiesJSON) > 0 {
+		if err := json.Unmarshal(cookiesJSON, &plan.Cookies); err != nil {
+			logger.Log.Warn("Failed to unmarshal cookies JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.Cookies = nil
+		}
+	}
+
+	if len(assertionsJSON) > 0 {
+		if err := json.Unmarshal(assertionsJSON, &plan.Assertions); err != nil {
+			logger.Log.Warn("Failed to unmarshal assertions JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.Assertions = nil
+		}
+	}
+
+	if len(retryJSON) > 0 {
+		if err := json.Unmarshal(retryJSON, &plan.Retry); err != nil {
+			logger.Log.Warn("Failed to unmarshal retry JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.Retry = nil
+		}
+	}
+
+	if len(bodySourceJSON) > 0 {
+		if err := json.Unmarshal(bodySourceJSON, &plan.BodySource); err != nil {
+			logger.Log.Warn("Failed to unmarshal body source JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.BodySource = nil
+		}
+	}
+
+	if len(grpcJSON) > 0 {
+		if err := json.Unmarshal(grpcJSON, &plan.GRPC); err != nil {
+			logger.Log.Warn("Failed to unmarshal gRPC JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.GRPC = nil
+		}
+	}
+
+	if len(webSocketJSON) > 0 {
+		if err := json.Unmarshal(webSocketJSON, &plan.WebSocket); err != nil {
+			logger.Log.Warn("Failed to unmarshal WebSocket JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.WebSocket = nil
+		}
+	}
+
+	if len(streamJSON) > 0 {
+		if err := json.Unmarshal(streamJSON, &plan.Stream); err != nil {
+			logger.Log.Warn("Failed to unmarshal stream JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.Stream = nil
+		}
+	}
+
+	if len(socketJSON) > 0 {
+		if err := json.Unmarshal(socketJSON, &plan.Socket); err != nil {
+			logger.Log.Warn("Failed to unmarshal socket JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.Socket = nil
+		}
+	}
+
+	if len(dnsJSON) > 0 {
+		if err := json.Unmarshal(dnsJSON, &plan.DNS); err != nil {
+			logger.Log.Warn("Failed to unmarshal dns JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.DNS = nil
+		}
+	}
+
+	if len(sqlJSON) > 0 {
+		if err := json.Unmarshal(sqlJSON, &plan.SQL); err != nil {
+			logger.Log.Warn("Failed to unmarshal sql JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.SQL = nil
+		}
+	}
+
+	if len(redisJSON) > 0 {
+		if err := json.Unmarshal(redisJSON, &plan.Redis); err != nil {
+			logger.Log.Warn("Failed to unmarshal redis JSON for test plan",
+				zap.String("plan_id", id), zap.Error(err))
+			plan.Redis = nil
+		}
+	}
+
 	return plan, nil
 }
 
@@ -113,7 +337,9 @@ func (r *PostgresTestPlanRepository) GetAll() ([]*model.TestPlan, error) {
 	query := `
 		SELECT id, name, target_url, http_method, headers, body,
 		       concurrent_users, duration_seconds, target_rps, timeout_ms,
-		       rate_pattern, rate_steps, sla_config, created_at, updated_at
+		       rate_pattern, rate_steps, sla_config, created_at, updated_at,
+		       executor, max_vus, scenario_id, requests, data_feed,
+		       stages, wave, ramp_down_sec, graceful_stop_sec, transport, tls, cookies, assertions, retry, body_source, grpc, websocket, stream, socket, dns, sql, redis
 		FROM test_plans
 		ORDER BY created_at DESC
 	`
@@ -127,13 +353,15 @@ func (r *PostgresTestPlanRepository) GetAll() ([]*model.TestPlan, error) {
 	var plans []*model.TestPlan
 	for rows.Next() {
 		plan := &model.TestPlan{}
-		var headersJSON, rateStepsJSON, slaConfigJSON []byte
+		var headersJSON, rateStepsJSON, slaConfigJSON, requestsJSON, dataFeedJSON, stagesJSON, waveJSON, transportJSON, tlsJSON, cookiesJSON, assertionsJSON, retryJSON, bodySourceJSON, grpcJSON, webSocketJSON, streamJSON, socketJSON, dnsJSON, sqlJSON, redisJSON []byte
 		var createdAt, updatedAt time.Time
 
 		err := rows.Scan(
 			&plan.ID, &plan.Name, &plan.TargetURL, &plan.Method, &headersJSON, &plan.Body,
 			&plan.Users, &plan.DurationSec, &plan.TargetRPS, &plan.TimeoutMs,
 			&plan.RatePattern, &rateStepsJSON, &slaConfigJSON, &createdAt, &updatedAt,
+			&plan.Executor, &plan.MaxVUs, &plan.ScenarioID, &requestsJSON, &dataFeedJSON,
+			&stagesJSON, &waveJSON, &plan.RampDownSec, &plan.GracefulStopSec, &transportJSON, &tlsJSON, &cookiesJSON, &assertionsJSON, &retryJSON, &bodySourceJSON, &grpcJSON, &webSocketJSON, &streamJSON, &socketJSON, &dnsJSON, &sqlJSON, &redisJSON,
 		)
 		if err != nil {
 			return nil, err
@@ -163,6 +391,131 @@ func (r *PostgresTestPlanRepository) GetAll() ([]*model.TestPlan, error) {
 			}
 		}
 
+		if len(requestsJSON) > 0 {
+			if err := json.Unmarshal(requestsJSON, &plan.Requests); err != nil {
+				logger.Log.Warn("Failed to unmarshal requests JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.Requests = nil
+			}
+		}
+
+		if len(dataFeedJSON) > 0 {
+			if err := json.Unmarshal(dataFeedJSON, &plan.Data); err != nil {
+				logger.Log.Warn("Failed to unmarshal data feed JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.Data = nil
+			}
+		}
+
+		if err := unmarshalLoadProfile(plan, stagesJSON, waveJSON); err != nil {
+			logger.Log.Warn("Failed to unmarshal load profile JSON for test plan",
+				zap.String("plan_id", plan.ID), zap.Error(err))
+		}
+
+		if len(transportJSON) > 0 {
+			if err := json.Unmarshal(transportJSON, &plan.Transport); err != nil {
+				logger.Log.Warn("Failed to unmarshal transport JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.Transport = nil
+			}
+		}
+
+		if len(tlsJSON) > 0 {
+			if err := json.Unmarshal(tlsJSON, &plan.TLS); err != nil {
+				logger.Log.Warn("Failed to unmarshal TLS JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.TLS = nil
+			}
+		}
+
+		if len(cookiesJSON) > 0 {
+			if err := json.Unmarshal(cookiesJSON, &plan.Cookies); err != nil {
+				logger.Log.Warn("Failed to unmarshal cookies JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.Cookies = nil
+			}
+		}
+
+		if len(assertionsJSON) > 0 {
+			if err := json.Unmarshal(assertionsJSON, &plan.Assertions); err != nil {
+				logger.Log.Warn("Failed to unmarshal assertions JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.Assertions = nil
+			}
+		}
+
+		if len(retryJSON) > 0 {
+			if err := json.Unmarshal(retryJSON, &plan.Retry); err != nil {
+				logger.Log.Warn("Failed to unmarshal retry JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.Retry = nil
+			}
+		}
+
+		if len(bodySourceJSON) > 0 {
+			if err := json.Unmarshal(bodySourceJSON, &plan.BodySource); err != nil {
+				logger.Log.Warn("Failed to unmarshal body source JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.BodySource = nil
+			}
+		}
+
+		if len(grpcJSON) > 0 {
+			if err := json.Unmarshal(grpcJSON, &plan.GRPC); err != nil {
+				logger.Log.Warn("Failed to unmarshal gRPC JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.GRPC = nil
+			}
+		}
+
+		if len(webSocketJSON) > 0 {
+			if err := json.Unmarshal(webSocketJSON, &plan.WebSocket); err != nil {
+				logger.Log.Warn("Failed to unmarshal WebSocket JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.WebSocket = nil
+			}
+		}
+
+		if len(streamJSON) > 0 {
+			if err := json.Unmarshal(streamJSON, &plan.Stream); err != nil {
+				logger.Log.Warn("Failed to unmarshal stream JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.Stream = nil
+			}
+		}
+
+		if len(socketJSON) > 0 {
+			if err := json.Unmarshal(socketJSON, &plan.Socket); err != nil {
+				logger.Log.Warn("Failed to unmarshal socket JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.Socket = nil
+			}
+		}
+
+		if len(dnsJSON) > 0 {
+			if err := json.Unmarshal(dnsJSON, &plan.DNS); err != nil {
+				logger.Log.Warn("Failed to unmarshal dns JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.DNS = nil
+			}
+		}
+
+		if len(sqlJSON) > 0 {
+			if err := json.Unmarshal(sqlJSON, &plan.SQL); err != nil {
+				logger.Log.Warn("Failed to unmarshal sql JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.SQL = nil
+			}
+		}
+
+		if len(redisJSON) > 0 {
+			if err := json.Unmarshal(redisJSON, &plan.Redis); err != nil {
+				logger.Log.Warn("Failed to unmarshal redis JSON for test plan",
+					zap.String("plan_id", plan.ID), zap.Error(err))
+				plan.Redis = nil
+			}
+		}
+
 		plans = append(plans, plan)
 	}
 
@@ -187,3 +540,21 @@ func (r *PostgresTestPlanRepository) Delete(id string) error {
 
 	return nil
 }
+
+// unmarshalLoadProfile decodes a plan's stages and wave. On error the plan
+// continues without them.
+func unmarshalLoadProfile(plan *model.TestPlan, stagesJSON, waveJSON []byte) error {
+	if len(stagesJSON) > 0 {
+		if err := json.Unmarshal(stagesJSON, &plan.Stages); err != nil {
+			plan.Stages = nil
+			return err
+		}
+	}
+	if len(waveJSON) > 0 {
+		if err := json.Unmarshal(waveJSON, &plan.Wave); err != nil {
+			plan.Wave = nil
+			return err
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
@@ -34,18 +35,19 @@ func (r *PostgresTestRunRepository) Create(run *model.TestRun) error {
 
 func (r *PostgresTestRunRepository) GetByID(id string) (*model.TestRun, error) {
 	query := `
-		SELECT id, plan_id, status, stop_reason, started_at, completed_at, created_at
+		SELECT id, plan_id, status, stop_reason, started_at, completed_at, events, created_at
 		FROM test_runs WHERE id = $1
 	`
 
 	run := &model.TestRun{}
 	var stopReason sql.NullString
 	var startedAt, completedAt sql.NullTime
+	var eventsJSON []byte
 	var createdAt time.Time
 
 	err := r.db.QueryRow(query, id).Scan(
 		&run.ID, &run.PlanID, &run.Status, &stopReason,
-		&startedAt, &completedAt, &createdAt,
+		&startedAt, &completedAt, &eventsJSON, &createdAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -65,13 +67,18 @@ func (r *PostgresTestRunRepository) GetByID(id string) (*model.TestRun, error) {
 	if completedAt.Valid {
 		run.EndAt = &completedAt.Time
 	}
+	if len(eventsJSON) > 0 {
+		if err := json.Unmarshal(eventsJSON, &run.Events); err != nil {
+			return nil, err
+		}
+	}
 
 	return run, nil
 }
 
 func (r *PostgresTestRunRepository) GetAll() ([]*model.TestRun, error) {
 	query := `
-		SELECT id, plan_id, status, stop_reason, started_at, completed_at, created_at
+		SELECT id, plan_id, status, stop_reason, started_at, completed_at, events, created_at
 		FROM test_runs
 		ORDER BY created_at DESC
 	`
@@ -87,11 +94,12 @@ func (r *PostgresTestRunRepository) GetAll() ([]*model.TestRun, error) {
 		run := &model.TestRun{}
 		var stopReason sql.NullString
 		var startedAt, completedAt sql.NullTime
+		var eventsJSON []byte
 		var createdAt time.Time
 
 		err := rows.Scan(
 			&run.ID, &run.PlanID, &run.Status, &stopReason,
-			&startedAt, &completedAt, &createdAt,
+			&startedAt, &completedAt, &eventsJSON, &createdAt,
 		)
 		if err != nil {
 			return nil, err
@@ -107,6 +115,11 @@ func (r *PostgresTestRunRepository) GetAll() ([]*model.TestRun, error) {
 		if completedAt.Valid {
 			run.EndAt = &completedAt.Time
 		}
+		if len(eventsJSON) > 0 {
+			if err := json.Unmarshal(eventsJSON, &run.Events); err != nil {
+				return nil, err
+			}
+		}
 
 		runs = append(runs, run)
 	}
@@ -142,6 +155,37 @@ func (r *PostgresTestRunRepository) Update(run *model.TestRun) error {
 	return nil
 }
 
+// AddEvent appends a control event to a test run. The append happens in
+// the database so it cannot be lost to a concurrent status update.
+func (r *PostgresTestRunRepository) AddEvent(runID string, event model.ControlEvent) error {
+	eventJSON, err := json.Marshal([]model.ControlEvent{event})
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE test_runs
+		SET events = COALESCE(events, '[]'::jsonb) || $2::jsonb
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(query, runID, eventJSON)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return repository.ErrTestRunNotFound
+	}
+
+	return nil
+}
+
 func (r *PostgresTestRunRepository) Delete(id string) error {
 	query := `DELETE FROM test_runs WHERE id = $1`
 	result, err := r.db.Exec(query, id)
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+var ErrBodyFileNotFound = domain.NewNotFoundError("body file", "")
+
+// MemoryBodyFileRepository implements BodyFileRepository using in-memory storage
+type MemoryBodyFileRepository struct {
+	bodyFiles map[string]*model.BodyFile
+	mu        sync.RWMutex
+}
+
+// NewMemoryBodyFileRepository creates a new in-memory body file repository
+func NewMemoryBodyFileRepository() *MemoryBodyFileRepository {
+	return &MemoryBodyFileRepository{
+		bodyFiles: make(map[string]*model.BodyFile),
+	}
+}
+
+func (r *MemoryBodyFileRepository) Create(bodyFile *model.BodyFile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodyFiles[bodyFile.ID] = bodyFile
+	return nil
+}
+
+func (r *MemoryBodyFileRepository) GetByID(id string) (*model.BodyFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bodyFile, exists := r.bodyFiles[id]
+	if !exists {
+		return nil, ErrBodyFileNotFound
+	}
+	return bodyFile, nil
+}
+
+func (r *MemoryBodyFileRepository) GetAll() ([]*model.BodyFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bodyFiles := make([]*model.BodyFile, 0, len(r.bodyFiles))
+	for _, bodyFile := range r.bodyFiles {
+		bodyFiles = append(bodyFiles, bodyFile)
+	}
+	return bodyFiles, nil
+}
+
+func (r *MemoryBodyFileRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.bodyFiles[id]; !exists {
+		return ErrBodyFileNotFound
+	}
+	delete(r.bodyFiles, id)
+	return nil
+}
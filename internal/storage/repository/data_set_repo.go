@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+var ErrDataSetNotFound = domain.NewNotFoundError("data set", "")
+
+// MemoryDataSetRepository implements DataSetRepository using in-memory storage
+type MemoryDataSetRepository struct {
+	dataSets map[string]*model.DataSet
+	mu       sync.RWMutex
+}
+
+// NewMemoryDataSetRepository creates a new in-memory data set repository
+func NewMemoryDataSetRepository() *MemoryDataSetRepository {
+	return &MemoryDataSetRepository{
+		dataSets: make(map[string]*model.DataSet),
+	}
+}
+
+func (r *MemoryDataSetRepository) Create(dataSet *model.DataSet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dataSets[dataSet.ID] = dataSet
+	return nil
+}
+
+func (r *MemoryDataSetRepository) GetByID(id string) (*model.DataSet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	dataSet, exists := r.dataSets[id]
+	if !exists {
+		return nil, ErrDataSetNotFound
+	}
+	return dataSet, nil
+}
+
+func (r *MemoryDataSetRepository) GetAll() ([]*model.DataSet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	dataSets := make([]*model.DataSet, 0, len(r.dataSets))
+	for _, dataSet := range r.dataSets {
+		dataSets = append(dataSets, dataSet)
+	}
+	return dataSets, nil
+}
+
+func (r *MemoryDataSetRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.dataSets[id]; !exists {
+		return ErrDataSetNotFound
+	}
+	delete(r.dataSets, id)
+	return nil
+}
@@ -108,6 +108,19 @@ func (r *MemoryTestRunRepository) Update(run *model.TestRun) error {
 	return nil
 }
 
+// AddEvent appends a control event to a test run
+func (r *MemoryTestRunRepository) AddEvent(runID string, event model.ControlEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	run, exists := r.runs[runID]
+	if !exists {
+		return ErrTestRunNotFound
+	}
+	run.Events = append(run.Events, event)
+	return nil
+}
+
 func (r *MemoryTestRunRepository) Delete(id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
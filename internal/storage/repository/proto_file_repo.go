@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+var ErrProtoFileNotFound = domain.NewNotFoundError("proto file", "")
+
+// MemoryProtoFileRepository implements ProtoFileRepository using in-memory storage
+type MemoryProtoFileRepository struct {
+	protoFiles map[string]*model.ProtoFile
+	mu         sync.RWMutex
+}
+
+// NewMemoryProtoFileRepository creates a new in-memory proto file repository
+func NewMemoryProtoFileRepository() *MemoryProtoFileRepository {
+	return &MemoryProtoFileRepository{
+		protoFiles: make(map[string]*model.ProtoFile),
+	}
+}
+
+func (r *MemoryProtoFileRepository) Create(protoFile *model.ProtoFile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.protoFiles[protoFile.ID] = protoFile
+	return nil
+}
+
+func (r *MemoryProtoFileRepository) GetByID(id string) (*model.ProtoFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	protoFile, exists := r.protoFiles[id]
+	if !exists {
+		return nil, ErrProtoFileNotFound
+	}
+	return protoFile, nil
+}
+
+func (r *MemoryProtoFileRepository) GetAll() ([]*model.ProtoFile, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	protoFiles := make([]*model.ProtoFile, 0, len(r.protoFiles))
+	for _, protoFile := range r.protoFiles {
+		protoFiles = append(protoFiles, protoFile)
+	}
+	return protoFiles, nil
+}
+
+func (r *MemoryProtoFileRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.protoFiles[id]; !exists {
+		return ErrProtoFileNotFound
+	}
+	delete(r.protoFiles, id)
+	return nil
+}
@@ -24,6 +24,7 @@ type TestRunRepository interface {
 	GetByID(id string) (*model.TestRun, error)
 	GetAll() ([]*model.TestRun, error)
 	Update(run *model.TestRun) error
+	AddEvent(runID string, event model.ControlEvent) error
 	Delete(id string) error
 }
 
@@ -49,3 +50,35 @@ type ScenarioExecutionRepository interface {
 	GetByScenarioID(scenarioID string) ([]*model.ScenarioExecution, error)
 	Update(execution *model.ScenarioExecution) error
 }
+
+// DataSetRepository defines interface for data set storage
+type DataSetRepository interface {
+	Create(dataSet *model.DataSet) error
+	GetByID(id string) (*model.DataSet, error)
+	GetAll() ([]*model.DataSet, error)
+	Delete(id string) error
+}
+
+// BodyFileRepository defines interface for body file storage
+type BodyFileRepository interface {
+	Create(bodyFile *model.BodyFile) error
+	GetByID(id string) (*model.BodyFile, error)
+	GetAll() ([]*model.BodyFile, error)
+	Delete(id string) error
+}
+
+// ProtoFileRepository defines interface for proto file storage
+type ProtoFileRepository interface {
+	Create(protoFile *model.ProtoFile) error
+	GetByID(id string) (*model.ProtoFile, error)
+	GetAll() ([]*model.ProtoFile, error)
+	Delete(id string) error
+}
+
+// SecretRepository defines interface for secret storage
+type SecretRepository interface {
+	Create(secret *model.Secret) error
+	GetByID(id string) (*model.Secret, error)
+	GetAll() ([]*model.Secret, error)
+	Delete(id string) error
+}
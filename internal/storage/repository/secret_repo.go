@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain"
+	"github.com/volcanion-company/volcanion-stress-test-tool/internal/domain/model"
+)
+
+var ErrSecretNotFound = domain.NewNotFoundError("secret", "")
+
+// MemorySecretRepository implements SecretRepository using in-memory storage
+type MemorySecretRepository struct {
+	secrets map[string]*model.Secret
+	mu      sync.RWMutex
+}
+
+// NewMemorySecretRepository creates a new in-memory secret repository
+func NewMemorySecretRepository() *MemorySecretRepository {
+	return &MemorySecretRepository{
+		secrets: make(map[string]*model.Secret),
+	}
+}
+
+func (r *MemorySecretRepository) Create(secret *model.Secret) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.secrets[secret.ID] = secret
+	return nil
+}
+
+func (r *MemorySecretRepository) GetByID(id string) (*model.Secret, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	secret, exists := r.secrets[id]
+	if !exists {
+		return nil, ErrSecretNotFound
+	}
+	return secret, nil
+}
+
+func (r *MemorySecretRepository) GetAll() ([]*model.Secret, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	secrets := make([]*model.Secret, 0, len(r.secrets))
+	for _, secret := range r.secrets {
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+func (r *MemorySecretRepository) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.secrets[id]; !exists {
+		return ErrSecretNotFound
+	}
+	delete(r.secrets, id)
+	return nil
+}
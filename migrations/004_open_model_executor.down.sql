@@ -0,0 +1,7 @@
+-- Rollback: Remove open-model executor columns
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS dropped_iterations;
+
+ALTER TABLE test_plans DROP COLUMN IF EXISTS max_vus;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS executor;
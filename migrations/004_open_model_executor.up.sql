@@ -0,0 +1,7 @@
+-- Migration: Open-model executor and dropped iteration tracking
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS executor VARCHAR(20) NOT NULL DEFAULT 'closed';
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS max_vus INT NOT NULL DEFAULT 0;
+
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS dropped_iterations BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,8 @@
+-- Rollback: Remove response time metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS p99_response_ms;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS p95_response_ms;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS p50_response_ms;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS max_response_ms;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS avg_response_ms;
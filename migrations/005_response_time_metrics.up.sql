@@ -0,0 +1,8 @@
+-- Migration: Coordinated-omission-corrected response time metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS avg_response_ms FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS max_response_ms FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS p50_response_ms FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS p95_response_ms FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS p99_response_ms FLOAT NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- Rollback: Remove latency histograms and tail percentiles
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS response_histogram;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS latency_histogram;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS p9999_response_ms;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS p999_response_ms;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS p9999_ms;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS p999_ms;
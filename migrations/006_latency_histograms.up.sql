@@ -0,0 +1,11 @@
+-- Migration: HDR latency histograms and tail percentiles
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS p999_ms FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS p9999_ms FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS p999_response_ms FLOAT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS p9999_response_ms FLOAT NOT NULL DEFAULT 0;
+
+-- Sparse histograms (non-empty buckets only) so runs can be re-queried at any percentile
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS latency_histogram JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS response_histogram JSONB;
@@ -0,0 +1,8 @@
+-- Rollback: Remove scenario-driven load test columns
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS steps;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS failed_iterations;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS iterations;
+
+ALTER TABLE test_plans DROP COLUMN IF EXISTS scenario_id;
@@ -0,0 +1,8 @@
+-- Migration: Scenario-driven load tests with per-step metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS scenario_id VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS iterations BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS failed_iterations BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS steps JSONB;
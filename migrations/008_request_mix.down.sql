@@ -0,0 +1,6 @@
+-- Rollback: Remove weighted request mix columns
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS request_breakdown;
+
+ALTER TABLE test_plans DROP COLUMN IF EXISTS requests;
@@ -0,0 +1,6 @@
+-- Migration: Weighted request mix with per-request metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS requests JSONB;
+
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS request_breakdown JSONB;
@@ -0,0 +1,4 @@
+-- Rollback: Remove data feed column
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans DROP COLUMN IF EXISTS data_feed;
@@ -0,0 +1,4 @@
+-- Migration: Data feeds for parameterizing requests
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS data_feed JSONB;
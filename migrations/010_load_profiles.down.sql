@@ -0,0 +1,7 @@
+-- Rollback: Remove load profile columns
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans DROP COLUMN IF EXISTS graceful_stop_sec;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS ramp_down_sec;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS wave;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS stages;
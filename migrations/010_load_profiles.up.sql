@@ -0,0 +1,7 @@
+-- Migration: Stages, wave, ramp-down and graceful stop load profiles
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS stages JSONB;
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS wave JSONB;
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS ramp_down_sec INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS graceful_stop_sec INTEGER NOT NULL DEFAULT 0;
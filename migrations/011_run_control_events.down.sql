@@ -0,0 +1,4 @@
+-- Rollback: Remove live control events from test runs
+-- Created: 2026-10-16
+
+ALTER TABLE test_runs DROP COLUMN IF EXISTS events;
@@ -0,0 +1,4 @@
+-- Migration: Live control events on test runs
+-- Created: 2026-10-16
+
+ALTER TABLE test_runs ADD COLUMN IF NOT EXISTS events JSONB;
@@ -0,0 +1,4 @@
+-- Rollback: Remove the per-agent breakdown of distributed test runs
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS agents;
@@ -0,0 +1,4 @@
+-- Migration: Per-agent breakdown of distributed test runs
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS agents JSONB;
@@ -0,0 +1,6 @@
+-- Rollback: Remove request timing phases and connection reuse counters
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS reused_connections;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS new_connections;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS phases;
@@ -0,0 +1,6 @@
+-- Migration: Request timing phases and connection reuse counters
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS phases JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS new_connections BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS reused_connections BIGINT NOT NULL DEFAULT 0;
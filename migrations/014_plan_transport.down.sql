@@ -0,0 +1,4 @@
+-- Rollback: Remove per-plan HTTP client and transport configuration
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans DROP COLUMN IF EXISTS transport;
@@ -0,0 +1,4 @@
+-- Migration: Per-plan TLS and mTLS settings for target connections
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS tls JSONB;
@@ -0,0 +1,4 @@
+-- Rollback: Remove per-plan cookie jar settings
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans DROP COLUMN IF EXISTS cookies;
@@ -0,0 +1,4 @@
+-- Migration: Per-plan cookie jar settings
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS cookies JSONB;
@@ -0,0 +1,6 @@
+-- Rollback: Remove response checks on test plans and per-check results
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS check_failures;
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS checks;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS assertions;
@@ -0,0 +1,6 @@
+-- Migration: Response checks on test plans and per-check results
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS assertions JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS checks JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS check_failures BIGINT NOT NULL DEFAULT 0;
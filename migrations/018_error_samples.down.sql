@@ -0,0 +1,4 @@
+-- Rollback: Remove sample error messages per error class
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS error_samples;
@@ -0,0 +1,4 @@
+-- Migration: Sample error messages per error class
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS error_samples JSONB;
@@ -0,0 +1,5 @@
+-- Rollback: Remove retry policies on test plans and retry metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS retry;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS retry;
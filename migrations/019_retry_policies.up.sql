@@ -0,0 +1,5 @@
+-- Migration: Retry policies on test plans and retry metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS retry JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS retry JSONB;
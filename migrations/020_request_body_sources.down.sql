@@ -0,0 +1,4 @@
+-- Rollback: Remove body sources on test plans
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans DROP COLUMN IF EXISTS body_source;
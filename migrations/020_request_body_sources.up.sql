@@ -0,0 +1,4 @@
+-- Migration: Body sources on test plans
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS body_source JSONB;
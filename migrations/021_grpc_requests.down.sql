@@ -0,0 +1,5 @@
+-- Rollback: Remove gRPC calls on test plans and gRPC status codes in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS grpc_status_codes;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS grpc;
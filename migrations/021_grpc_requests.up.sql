@@ -0,0 +1,5 @@
+-- Migration: gRPC calls on test plans and gRPC status codes in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS grpc JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS grpc_status_codes JSONB;
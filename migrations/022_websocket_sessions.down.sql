@@ -0,0 +1,5 @@
+-- Rollback: Remove WebSocket sessions on test plans and WebSocket metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS websocket;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS websocket;
@@ -0,0 +1,5 @@
+-- Migration: WebSocket sessions on test plans and WebSocket metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS websocket JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS websocket JSONB;
@@ -0,0 +1,5 @@
+-- Rollback: Remove streaming response settings on test plans and stream metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS stream;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS stream;
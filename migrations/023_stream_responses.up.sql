@@ -0,0 +1,5 @@
+-- Migration: Streaming response settings on test plans and stream metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS stream JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS stream JSONB;
@@ -0,0 +1,5 @@
+-- Rollback: Remove raw TCP and UDP requests on test plans and socket metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS socket;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS socket;
@@ -0,0 +1,5 @@
+-- Migration: Raw TCP and UDP requests on test plans and socket metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS socket JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS socket JSONB;
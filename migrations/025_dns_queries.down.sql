@@ -0,0 +1,5 @@
+-- Rollback: Remove DNS queries on test plans and DNS metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS dns;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS dns;
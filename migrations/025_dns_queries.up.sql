@@ -0,0 +1,5 @@
+-- Migration: DNS queries on test plans and DNS metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS dns JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS dns JSONB;
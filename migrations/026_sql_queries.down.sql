@@ -0,0 +1,5 @@
+-- Rollback: Remove SQL statements on test plans and SQL metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS sql;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS sql;
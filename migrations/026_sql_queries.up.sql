@@ -0,0 +1,5 @@
+-- Migration: SQL statements on test plans and SQL metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS sql JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS sql JSONB;
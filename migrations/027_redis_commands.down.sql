@@ -0,0 +1,5 @@
+-- Rollback: Remove Redis commands on test plans and Redis metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE final_metrics DROP COLUMN IF EXISTS redis;
+ALTER TABLE test_plans DROP COLUMN IF EXISTS redis;
@@ -0,0 +1,5 @@
+-- Migration: Redis commands on test plans and Redis metrics in final metrics
+-- Created: 2026-10-16
+
+ALTER TABLE test_plans ADD COLUMN IF NOT EXISTS redis JSONB;
+ALTER TABLE final_metrics ADD COLUMN IF NOT EXISTS redis JSONB;
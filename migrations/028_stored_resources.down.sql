@@ -0,0 +1,7 @@
+-- Rollback: Remove data sets, secrets, body files and proto files
+-- Created: 2026-10-16
+
+DROP TABLE IF EXISTS proto_files;
+DROP TABLE IF EXISTS body_files;
+DROP TABLE IF EXISTS secrets;
+DROP TABLE IF EXISTS data_sets;
@@ -0,0 +1,52 @@
+-- Migration: Data sets, secrets, body files and proto files that test plans reference
+-- Created: 2026-10-16
+
+CREATE TABLE IF NOT EXISTS data_sets (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    columns JSONB,
+    row_count INT NOT NULL DEFAULT 0,
+    rows JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_data_sets_created_at ON data_sets(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS secrets (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    subject TEXT,
+    not_after TIMESTAMP,
+    username VARCHAR(255),
+    certificate TEXT,
+    private_key TEXT,
+    password TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_secrets_created_at ON secrets(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS body_files (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255),
+    size BIGINT NOT NULL DEFAULT 0,
+    content BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_body_files_created_at ON body_files(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS proto_files (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    services JSONB,
+    methods JSONB,
+    size BIGINT NOT NULL DEFAULT 0,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_proto_files_created_at ON proto_files(created_at DESC);